@@ -66,6 +66,14 @@ func main() {
 	// Initialize JWT secret
 	auth.SetJWTSecret(cfg.JWTSecret)
 
+	// Load the keys that sign tokens; without them tokens are signed with the secret
+	if err := auth.SetupSigningKeys(cfg.JWT.Keys, cfg.JWT.HS256AcceptUntil); err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+	if keys := auth.PublicKeys().Keys; len(keys) > 0 {
+		log.Printf("Loaded %d JWT signing keys", len(keys))
+	}
+
 	// Initialize database
 	db, err := database.Initialize(cfg)
 	if err != nil {
@@ -79,6 +87,11 @@ func main() {
 		}
 		log.Println("Database migrations completed successfully")
 
+		// Create the configured admin on first start
+		if err := database.SeedAdminUser(db, cfg.Admin); err != nil {
+			log.Printf("Warning: Failed to create admin user: %v", err)
+		}
+
 		// Seed database with demo data
 		if err := database.Seed(db); err != nil {
 			log.Printf("Warning: Failed to seed database: %v", err)
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // JWTSecret is the secret key used to sign JWT tokens
@@ -17,56 +18,98 @@ func SetJWTSecret(secret string) {
 	JWTSecret = []byte(secret)
 }
 
-// Claims represents the JWT claims
+// Claims represents the JWT claims. StudentID is set for student accounts and
+// identifies the student the requests act for; InstructorID does the same for
+// instructor accounts. Every token has a unique ID (jti) and belongs to the
+// session (sid) started when the user logged in, which its refresh tokens carry on.
+// MustChangePassword is set for users who may only change their password.
 type Claims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	UserID             string `json:"user_id"`
+	Username           string `json:"username"`
+	Role               string `json:"role"`
+	StudentID          string `json:"student_id,omitempty"`
+	InstructorID       string `json:"instructor_id,omitempty"`
+	MustChangePassword bool   `json:"must_change_password,omitempty"`
+	TokenType          string `json:"token_type"`
+	SessionID          string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken creates a JWT token for the user
-func GenerateToken(userID, username, role string) (string, error) {
+// Identity is the user a token is issued to. StudentID is empty for users that
+// are not students and InstructorID for users that are not instructors.
+type Identity struct {
+	UserID             string
+	Username           string
+	Role               string
+	StudentID          string
+	InstructorID       string
+	MustChangePassword bool
+}
+
+// GenerateToken creates a token of the type, constants.TokenTypeAccess or
+// constants.TokenTypeRefresh, for the identity in the session. Every token gets
+// a new ID.
+func GenerateToken(identity Identity, tokenType, sessionID string) (string, error) {
+	expiry := constants.AccessTokenExpiry
+	if tokenType == constants.TokenTypeRefresh {
+		expiry = constants.RefreshTokenExpiry
+	}
+	now := time.Now()
+
 	// Create claims
-	claims := Claims{
-		UserID:   userID,
-		Username: username,
-		Role:     role,
+	claims := &Claims{
+		UserID:             identity.UserID,
+		Username:           identity.Username,
+		Role:               identity.Role,
+		StudentID:          identity.StudentID,
+		InstructorID:       identity.InstructorID,
+		MustChangePassword: identity.MustChangePassword,
+		TokenType:          tokenType,
+		SessionID:          sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(constants.JWTTokenExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    constants.JWTIssuer,
-			Subject:   userID,
+			Subject:   identity.UserID,
 		},
 	}
 
-	// Create token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// Sign token with secret
-	tokenString, err := token.SignedString(JWTSecret)
-	if err != nil {
-		return "", err
+	// Sign with the active signing key, or with the secret when there is none
+	key := signingKey(now)
+	if key == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(JWTSecret)
 	}
 
-	return tokenString, nil
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
 }
 
-// ValidateToken validates a JWT token and returns the claims
+// ValidateToken validates a JWT token and returns the claims. Tokens signed
+// with a configured key are verified with the key named by their kid header;
+// HS256 tokens are accepted during the migration window.
 func ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, jwt.ErrSignatureInvalid
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+			if !acceptsHS256(time.Now()) {
+				return nil, errHS256NotAccepted
+			}
+			return JWTSecret, nil
 		}
-		return JWTSecret, nil
-	})
+		return verificationKey(token)
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), AlgorithmRS256, AlgorithmEdDSA}))
 
 	if err != nil {
 		return nil, err
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		// Tokens issued before sessions were introduced cannot be revoked
+		if claims.ID == "" || claims.SessionID == "" {
+			return nil, errors.New("invalid token")
+		}
 		return claims, nil
 	}
 
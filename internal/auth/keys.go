@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signing algorithms of asymmetric keys, in the alg header and JWK
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// errHS256NotAccepted is returned for HS256 tokens after the migration window
+var errHS256NotAccepted = errors.New("HS256 tokens are no longer accepted")
+
+// SigningKey is an RSA or Ed25519 key that signs tokens, identified by the kid
+// header of the tokens it signs. A key signs new tokens from ActiveFrom until a
+// key with a later ActiveFrom takes over, and verifies tokens for as long as it
+// is configured.
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	ActiveFrom time.Time
+	private    crypto.Signer
+}
+
+// Public returns the public half of the key
+func (k *SigningKey) Public() crypto.PublicKey {
+	return k.private.Public()
+}
+
+// keyring holds the configured signing keys, sorted by ActiveFrom, and the end
+// of the window in which HS256 tokens are still accepted
+var keyring struct {
+	sync.RWMutex
+	keys       []SigningKey
+	hs256Until time.Time
+}
+
+// SetupSigningKeys loads the signing keys in spec and sets the end of the HS256
+// migration window. spec is a comma-separated list of kid=path entries, each
+// optionally followed by @ and the RFC 3339 time the key starts signing, such
+// as "2026-q1=/keys/q1.pem,2026-q2=/keys/q2.pem@2026-04-01T00:00:00Z".
+// hs256AcceptUntil is an RFC 3339 time, or empty to stop accepting HS256 as
+// soon as a signing key is configured. Every kid must be unique.
+func SetupSigningKeys(spec, hs256AcceptUntil string) error {
+	var keys []SigningKey
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, rest, ok := strings.Cut(entry, "=")
+		if !ok || kid == "" {
+			return fmt.Errorf("signing key %q must be kid=path", entry)
+		}
+		if seen[kid] {
+			return fmt.Errorf("duplicate signing key id %q", kid)
+		}
+		seen[kid] = true
+		path, from, scheduled := strings.Cut(rest, "@")
+
+		var activeFrom time.Time
+		if scheduled {
+			var err error
+			if activeFrom, err = time.Parse(time.RFC3339, from); err != nil {
+				return fmt.Errorf("signing key %s: invalid activation time: %w", kid, err)
+			}
+		}
+
+		key, err := LoadSigningKey(kid, path, activeFrom)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, *key)
+	}
+
+	var hs256Until time.Time
+	if hs256AcceptUntil != "" {
+		var err error
+		if hs256Until, err = time.Parse(time.RFC3339, hs256AcceptUntil); err != nil {
+			return fmt.Errorf("invalid HS256 migration window end: %w", err)
+		}
+	}
+
+	SetSigningKeys(keys, hs256Until)
+	return nil
+}
+
+// LoadSigningKey reads a PEM encoded RSA or Ed25519 private key. RSA keys sign
+// with RS256 and Ed25519 keys with EdDSA.
+func LoadSigningKey(kid, path string, activeFrom time.Time) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("signing key %s: %w", kid, err)
+	}
+	if block, _ := pem.Decode(data); block == nil {
+		return nil, fmt.Errorf("signing key %s: %s is not PEM encoded", kid, path)
+	}
+
+	if key, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return &SigningKey{ID: kid, Algorithm: AlgorithmRS256, ActiveFrom: activeFrom, private: key}, nil
+	}
+	if key, err := jwt.ParseEdPrivateKeyFromPEM(data); err == nil {
+		if signer, ok := key.(ed25519.PrivateKey); ok {
+			return &SigningKey{ID: kid, Algorithm: AlgorithmEdDSA, ActiveFrom: activeFrom, private: signer}, nil
+		}
+	}
+	return nil, fmt.Errorf("signing key %s: %s is not an RSA or Ed25519 private key", kid, path)
+}
+
+// SetSigningKeys replaces the signing keys and the end of the HS256 migration
+// window. Without keys tokens are signed with HS256 and the window is ignored.
+func SetSigningKeys(keys []SigningKey, hs256Until time.Time) {
+	sorted := append([]SigningKey(nil), keys...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ActiveFrom.Before(sorted[j].ActiveFrom)
+	})
+
+	keyring.Lock()
+	defer keyring.Unlock()
+	keyring.keys = sorted
+	keyring.hs256Until = hs256Until
+}
+
+// signingKey returns the key that signs tokens at now: the one activated most
+// recently. It returns nil when tokens are signed with HS256, because no key is
+// configured or none is active yet.
+func signingKey(now time.Time) *SigningKey {
+	keyring.RLock()
+	defer keyring.RUnlock()
+
+	var current *SigningKey
+	for i := range keyring.keys {
+		if keyring.keys[i].ActiveFrom.After(now) {
+			break
+		}
+		current = &keyring.keys[i]
+	}
+	return current
+}
+
+// verificationKey returns the public key for a token signed by the key with the kid
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	keyring.RLock()
+	defer keyring.RUnlock()
+	for _, key := range keyring.keys {
+		if key.ID == kid {
+			if token.Method.Alg() != key.Algorithm {
+				return nil, jwt.ErrSignatureInvalid
+			}
+			return key.Public(), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// acceptsHS256 reports whether HS256 tokens are valid at now. They are while no
+// asymmetric key signs tokens, and until the end of the migration window after.
+func acceptsHS256(now time.Time) bool {
+	if signingKey(now) == nil {
+		return true
+	}
+
+	keyring.RLock()
+	defer keyring.RUnlock()
+	return now.Before(keyring.hs256Until)
+}
+
+// JWK is the public half of a signing key in JSON Web Key format
+type JWK struct {
+	KeyType   string `json:"kty" example:"RSA"`
+	KeyID     string `json:"kid" example:"2026-q1"`
+	Use       string `json:"use" example:"sig"`
+	Algorithm string `json:"alg" example:"RS256"`
+	N         string `json:"n,omitempty"`   // RSA modulus
+	E         string `json:"e,omitempty"`   // RSA exponent
+	Curve     string `json:"crv,omitempty"` // Ed25519
+	X         string `json:"x,omitempty"`   // Ed25519 public key
+}
+
+// JWKSet is a JSON Web Key Set
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicKeys returns the public halves of every configured key, including keys
+// scheduled to sign later, so that verifiers know them before they are used
+func PublicKeys() JWKSet {
+	keyring.RLock()
+	defer keyring.RUnlock()
+
+	set := JWKSet{Keys: make([]JWK, 0, len(keyring.keys))}
+	for _, key := range keyring.keys {
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: key.Algorithm}
+		switch public := key.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
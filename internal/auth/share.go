@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"time"
+)
+
+// shareLinkPurpose separates share link signatures from anything else signed
+// with the same secret
+const shareLinkPurpose = "student-records-share"
+
+// SignShareLink signs a link to the records of the student with the email that
+// is valid until expiresAt. The email must be normalized.
+func SignShareLink(email string, expiresAt time.Time) string {
+	return shareLinkSignature(email, expiresAt.Unix())
+}
+
+// VerifyShareLink reports whether signature was made by SignShareLink for the
+// email and expiry, and the link has not expired at now. expires is the expiry
+// as Unix seconds, as it appears in the link.
+func VerifyShareLink(email, expires, signature string, now time.Time) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() >= expiresAt {
+		return false
+	}
+
+	expected := shareLinkSignature(email, expiresAt)
+	return hmac.Equal([]byte(signature), []byte(expected))
+}
+
+// shareLinkSignature computes the URL-safe HMAC of a share link
+func shareLinkSignature(email string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, JWTSecret)
+	mac.Write([]byte(shareLinkPurpose + "\n" + email + "\n" + strconv.FormatInt(expiresAt, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -14,6 +15,51 @@ type Config struct {
 	Database      DatabaseConfig `mapstructure:"database"`
 	Redis         RedisConfig    `mapstructure:"redis"`
 	JWTSecret     string         `mapstructure:"JWT_SECRET"`
+	JWT           JWTConfig      `mapstructure:"jwt"`
+	Login         LoginConfig    `mapstructure:"login"`
+	Password      PasswordConfig `mapstructure:"password"`
+	Admin         AdminConfig    `mapstructure:"admin"`
+}
+
+// JWTConfig holds the asymmetric keys that sign tokens. Keys is a comma-separated
+// list of kid=path entries, optionally followed by @ and the RFC 3339 time the
+// key starts signing. HS256 tokens signed with JWT_SECRET are accepted until
+// HS256AcceptUntil.
+type JWTConfig struct {
+	Keys             string `mapstructure:"keys"`
+	HS256AcceptUntil string `mapstructure:"hs256_accept_until"`
+}
+
+// LoginConfig holds the brute-force protection of logins. After a second failed
+// attempt for a username or client IP, logins for it are refused for BaseDelay,
+// doubling with every further failure; after MaxFailures failures for a
+// username, or IPMaxFailures for an IP, they are locked for LockoutDuration.
+// A threshold of 0 turns its lockout off.
+type LoginConfig struct {
+	MaxFailures     int           `mapstructure:"max_failures"`
+	IPMaxFailures   int           `mapstructure:"ip_max_failures"`
+	LockoutDuration time.Duration `mapstructure:"lockout_duration"`
+	BaseDelay       time.Duration `mapstructure:"base_delay"`
+}
+
+// PasswordConfig holds the strength policy for new passwords: at least
+// MinLength characters, never fewer than constants.MinPasswordLength, with the
+// kinds of characters the Require fields ask for. Password reset tokens work
+// for ResetTokenTTL.
+type PasswordConfig struct {
+	MinLength     int           `mapstructure:"min_length"`
+	RequireUpper  bool          `mapstructure:"require_upper"`
+	RequireLower  bool          `mapstructure:"require_lower"`
+	RequireDigit  bool          `mapstructure:"require_digit"`
+	RequireSymbol bool          `mapstructure:"require_symbol"`
+	ResetTokenTTL time.Duration `mapstructure:"reset_token_ttl"`
+}
+
+// AdminConfig holds the credentials of the super-admin created on first start.
+// The password must be changed at the first login.
+type AdminConfig struct {
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
 }
 
 // DatabaseConfig holds database configuration
@@ -49,6 +95,18 @@ func Load() *Config {
 	viper.SetDefault("redis.password", "")
 	viper.SetDefault("redis.db", 0)
 	viper.SetDefault("JWT_SECRET", "your-default-jwt-secret-change-this")
+	viper.SetDefault("jwt.keys", "")
+	viper.SetDefault("jwt.hs256_accept_until", "")
+	viper.SetDefault("login.max_failures", 5)
+	viper.SetDefault("login.ip_max_failures", 20)
+	viper.SetDefault("login.lockout_duration", "15m")
+	viper.SetDefault("login.base_delay", "1s")
+	viper.SetDefault("password.min_length", 8)
+	viper.SetDefault("password.require_upper", false)
+	viper.SetDefault("password.require_lower", false)
+	viper.SetDefault("password.require_digit", false)
+	viper.SetDefault("password.require_symbol", false)
+	viper.SetDefault("password.reset_token_ttl", "24h")
 	viper.SetDefault("SKIP_MIGRATION", false)
 	viper.SetDefault("admin.username", "admin")
 	viper.SetDefault("admin.password", "admin!dev")
@@ -81,6 +139,42 @@ func Load() *Config {
 	if jwtSecret := os.Getenv("JWT_SECRET"); jwtSecret != "" {
 		viper.Set("JWT_SECRET", jwtSecret)
 	}
+	if jwtKeys := os.Getenv("JWT_KEYS"); jwtKeys != "" {
+		viper.Set("jwt.keys", jwtKeys)
+	}
+	if hs256AcceptUntil := os.Getenv("JWT_HS256_ACCEPT_UNTIL"); hs256AcceptUntil != "" {
+		viper.Set("jwt.hs256_accept_until", hs256AcceptUntil)
+	}
+	if maxFailures := os.Getenv("LOGIN_MAX_FAILURES"); maxFailures != "" {
+		viper.Set("login.max_failures", maxFailures)
+	}
+	if ipMaxFailures := os.Getenv("LOGIN_IP_MAX_FAILURES"); ipMaxFailures != "" {
+		viper.Set("login.ip_max_failures", ipMaxFailures)
+	}
+	if lockoutDuration := os.Getenv("LOGIN_LOCKOUT_DURATION"); lockoutDuration != "" {
+		viper.Set("login.lockout_duration", lockoutDuration)
+	}
+	if baseDelay := os.Getenv("LOGIN_BASE_DELAY"); baseDelay != "" {
+		viper.Set("login.base_delay", baseDelay)
+	}
+	if minLength := os.Getenv("PASSWORD_MIN_LENGTH"); minLength != "" {
+		viper.Set("password.min_length", minLength)
+	}
+	if requireUpper := os.Getenv("PASSWORD_REQUIRE_UPPER"); requireUpper != "" {
+		viper.Set("password.require_upper", requireUpper == "true")
+	}
+	if requireLower := os.Getenv("PASSWORD_REQUIRE_LOWER"); requireLower != "" {
+		viper.Set("password.require_lower", requireLower == "true")
+	}
+	if requireDigit := os.Getenv("PASSWORD_REQUIRE_DIGIT"); requireDigit != "" {
+		viper.Set("password.require_digit", requireDigit == "true")
+	}
+	if requireSymbol := os.Getenv("PASSWORD_REQUIRE_SYMBOL"); requireSymbol != "" {
+		viper.Set("password.require_symbol", requireSymbol == "true")
+	}
+	if resetTokenTTL := os.Getenv("PASSWORD_RESET_TOKEN_TTL"); resetTokenTTL != "" {
+		viper.Set("password.reset_token_ttl", resetTokenTTL)
+	}
 	if skipMigration := os.Getenv("SKIP_MIGRATION"); skipMigration != "" {
 		viper.Set("SKIP_MIGRATION", skipMigration == "true")
 	}
@@ -25,11 +25,19 @@ const (
 	HTTPInternalServerError = "Internal Server Error"
 
 	// Authentication Messages
-	MsgInvalidCredentials  = "Invalid username or password"
-	MsgAuthHeaderRequired  = "Authorization header is required"
-	MsgInvalidTokenFormat  = "Invalid token format"
-	MsgJWTTokenInvalid     = "JWT token is invalid or expired"
-	MsgAdminAccessRequired = "Admin access required"
+	MsgInvalidCredentials     = "Invalid username or password"
+	MsgAuthHeaderRequired     = "Authorization header is required"
+	MsgInvalidTokenFormat     = "Invalid token format"
+	MsgJWTTokenInvalid        = "JWT token is invalid or expired"
+	MsgJWTTokenRevoked        = "JWT token has been revoked"
+	MsgAPIKeyInvalid          = "API key is invalid, expired or revoked"
+	MsgPermissionRequired     = "Permission %s is required"
+	MsgAccountDisabled        = "This account has been disabled"
+	MsgLoginThrottled         = "Too many failed login attempts, try again in %d seconds"
+	MsgPasswordChangeRequired = "Change your password at /api/v1/auth/change-password before using the API"
+	MsgStudentAccountOnly     = "Only student accounts can use this endpoint"
+	MsgStudentRecordsAccess   = "Sign in as this student or use a share link to view these records"
+	MsgCourseAccessRequired   = "Only admins and instructors of this course can manage it"
 
 	// Course Messages
 	MsgCourseNotFound        = "The requested course does not exist"
@@ -37,24 +45,55 @@ const (
 	MsgInvalidCourseIDFormat = "Invalid course ID format"
 
 	// Enrollment Messages
-	MsgEnrollmentCreated      = "Student enrolled successfully"
-	MsgStudentAlreadyEnrolled = "Student is already enrolled in this course"
-	MsgCourseNotExist         = "The specified course does not exist"
-	MsgInvalidEmailFormat     = "Invalid email format"
+	MsgEnrollmentCreated       = "Student enrolled successfully"
+	MsgStudentAlreadyEnrolled  = "Student is already enrolled in this course"
+	MsgCourseNotExist          = "The specified course does not exist"
+	MsgInvalidEmailFormat      = "Invalid email format"
+	MsgInvalidEnrollmentStatus = "Status must be one of: pending, active, completed, dropped, withdrawn"
 
 	// Validation Messages
 	MsgTitleRequired       = "Title is required"
 	MsgDescriptionRequired = "Description is required"
 	MsgDifficultyRequired  = "Difficulty is required"
-	MsgDifficultyInvalid   = "Difficulty must be one of: Beginner, Intermediate, Advanced"
+	MsgDifficultyInvalid   = "Difficulty must be one of: %s"
 	MsgEmailRequired       = "Student email is required"
 	MsgCourseIDRequired    = "Course ID is required"
+
+	MsgEnrollmentWindowInvalid = "Enrollment must close after it opens"
 )
 
 // JWT Constants
 const (
-	JWTTokenExpiry = 24 * time.Hour
-	JWTIssuer      = "sonic-labs-course-enrollment"
+	AccessTokenExpiry  = 15 * time.Minute
+	RefreshTokenExpiry = 7 * 24 * time.Hour
+	JWTIssuer          = "sonic-labs-course-enrollment"
+)
+
+// Token types, in the token_type claim
+const (
+	TokenTypeAccess  = "access"  // sent in the Authorization header
+	TokenTypeRefresh = "refresh" // exchanged for a new token pair at /auth/refresh
+)
+
+// API Key Constants
+const (
+	APIKeyPrefix           = "cek_"      // starts every API key, to recognise leaked keys
+	APIKeyDisplayLength    = 12          // leading characters of a key kept to tell keys apart
+	APIKeyLastUsedInterval = time.Minute // how often the last-used time of a key is written
+)
+
+// Login Protection Constants
+const (
+	LoginFailureWindow = time.Hour        // failed logins are forgotten after this long without another
+	LoginMaxDelay      = 30 * time.Second // longest delay between failed logins before a lockout
+)
+
+// Password Constants
+const (
+	PasswordResetTokenPrefix = "pwr_"             // starts every password reset token
+	PasswordResetTokenExpiry = 24 * time.Hour     // how long a reset token works when none is configured
+	StudentInviteTokenPrefix = "inv_"             // starts every student account invite token
+	StudentInviteTokenExpiry = 7 * 24 * time.Hour // how long an account invite token works
 )
 
 // Cache Constants
@@ -65,12 +104,123 @@ const (
 	RateLimitRequests = 60
 )
 
+// Share Link Constants
+const (
+	DefaultShareLinkTTL = 72 * time.Hour
+	MaxShareLinkTTL     = 30 * 24 * time.Hour
+)
+
+// Idempotency Constants
+const (
+	IdempotencyKeyTTL       = 24 * time.Hour  // how long a response is replayed
+	IdempotencyLockTTL      = 5 * time.Minute // how long a request in progress holds its key
+	MaxIdempotencyKeyLength = 255
+	MaxIdempotentBodySize   = 10 << 20 // room for a 5MB course image or import file plus the other form fields
+)
+
 // User Roles
 const (
-	RoleAdmin = "admin"
-	RoleUser  = "user"
+	RoleSuperAdmin = "super_admin" // holds every permission and edits the permissions of the other roles
+	RoleAdmin      = "admin"
+	RoleUser       = "user"       // a student signed in to their own account
+	RoleInstructor = "instructor" // manages the courses they teach
+)
+
+// Permissions granted to roles in role_permissions
+const (
+	PermissionCourseCreate      = "course:create"
+	PermissionCourseWrite       = "course:write" // course details, modules and lessons
+	PermissionCourseDelete      = "course:delete"
+	PermissionCourseAssign      = "course:assign" // choose the instructors of a course
+	PermissionPrerequisiteWrite = "prerequisite:write"
+	PermissionScheduleWrite     = "schedule:write" // terms, offerings and sections
+	PermissionCatalogWrite      = "catalog:write"  // categories, tags and difficulty levels
+	PermissionEnrollmentRead    = "enrollment:read"
+	PermissionEnrollmentWrite   = "enrollment:write" // enrollments, their status and progress, and waitlists
+	PermissionEnrollmentDelete  = "enrollment:delete"
+	PermissionStudentRead       = "student:read"
+	PermissionStudentWrite      = "student:write"
+	PermissionStudentDelete     = "student:delete"
+	PermissionInstructorRead    = "instructor:read"
+	PermissionInstructorWrite   = "instructor:write"
+	PermissionRoleManage        = "role:manage"   // held only by super-admins, never granted
+	PermissionUserManage        = "user:manage"   // held only by super-admins, never granted
+	PermissionAPIKeyManage      = "apikey:manage" // held only by super-admins, never granted
 )
 
+// GrantablePermissions lists the permissions super-admins can grant to roles
+var GrantablePermissions = []string{
+	PermissionCourseCreate,
+	PermissionCourseWrite,
+	PermissionCourseDelete,
+	PermissionCourseAssign,
+	PermissionPrerequisiteWrite,
+	PermissionScheduleWrite,
+	PermissionCatalogWrite,
+	PermissionEnrollmentRead,
+	PermissionEnrollmentWrite,
+	PermissionEnrollmentDelete,
+	PermissionStudentRead,
+	PermissionStudentWrite,
+	PermissionStudentDelete,
+	PermissionInstructorRead,
+	PermissionInstructorWrite,
+}
+
+// ReservedPermissions lists the permissions only super-admins hold
+var ReservedPermissions = []string{PermissionRoleManage, PermissionUserManage, PermissionAPIKeyManage}
+
+// ManagedRoles lists the roles whose permissions super-admins can edit
+var ManagedRoles = []string{RoleAdmin, RoleInstructor, RoleUser}
+
+// MinPasswordLength is the shortest password ever accepted; the configured
+// password policy may ask for longer ones
+const MinPasswordLength = 8
+
+// Enrollment Statuses
+const (
+	EnrollmentStatusPending   = "pending"
+	EnrollmentStatusActive    = "active"
+	EnrollmentStatusCompleted = "completed"
+	EnrollmentStatusDropped   = "dropped"
+	EnrollmentStatusWithdrawn = "withdrawn"
+)
+
+// Lesson Progress Statuses
+const (
+	LessonProgressNotStarted = "not_started" // reported for lessons without a progress record
+	LessonProgressStarted    = "started"
+	LessonProgressCompleted  = "completed"
+)
+
+// Enrollment Window States, also used as machine-readable error codes
+const (
+	EnrollmentWindowOpen    = "enrollment_open"
+	EnrollmentWindowNotOpen = "enrollment_not_open"
+	EnrollmentWindowClosed  = "enrollment_closed"
+)
+
+// Enrollment Import Row Outcomes
+const (
+	ImportRowCreated         = "created"
+	ImportRowWaitlisted      = "waitlisted"
+	ImportRowAlreadyEnrolled = "already_enrolled"
+	ImportRowInvalidEmail    = "invalid_email"
+	ImportRowUnknownCourse   = "unknown_course"
+	ImportRowRejected        = "rejected"
+	ImportRowMalformed       = "malformed"
+)
+
+// Enrollment import limits
+const (
+	MaxImportFileSize = 5 << 20
+	MaxImportRows     = 5000
+)
+
+// SystemActor is recorded as the author of changes the service makes on its own,
+// such as promoting a student from a waitlist
+const SystemActor = "system"
+
 // Database Table Names
 const (
 	TableUsers       = "users"
@@ -78,11 +228,20 @@ const (
 	TableEnrollments = "enrollments"
 )
 
-// Course Difficulty Levels
+// Course list sorts; a leading "-" sorts descending
 const (
-	DifficultyBeginner     = "Beginner"
-	DifficultyIntermediate = "Intermediate"
-	DifficultyAdvanced     = "Advanced"
+	CourseSortCreatedAt  = "created_at"
+	CourseSortTitle      = "title"
+	CourseSortDifficulty = "difficulty"
+)
+
+// MaxDifficultyNameLength is the longest difficulty level name
+const MaxDifficultyNameLength = 50
+
+// Course taxonomy limits
+const (
+	MaxCategoryNameLength = 100
+	MaxTagLength          = 50
 )
 
 // Pagination settings
@@ -95,7 +254,11 @@ const (
 // HTTP Headers
 const (
 	HeaderAuthorization = "Authorization"
+	HeaderAPIKey        = "X-API-Key"
 	HeaderContentType   = "Content-Type"
+
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotencyReplayed = "Idempotency-Replayed"
 )
 
 // Content Types
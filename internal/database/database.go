@@ -60,6 +60,27 @@ func Migrate(db *gorm.DB) error {
 		"003_seed_demo_courses.sql",
 		"004_create_admin_user.sql",
 		"005_add_image_url_to_courses.sql",
+		"006_add_capacity_and_waitlist.sql",
+		"007_add_enrollment_status.sql",
+		"008_create_course_prerequisites.sql",
+		"009_add_enrollment_window_to_courses.sql",
+		"010_create_idempotency_keys.sql",
+		"011_create_course_modules_and_lessons.sql",
+		"012_create_lesson_progress.sql",
+		"013_create_terms_and_course_offerings.sql",
+		"014_create_course_sections.sql",
+		"015_create_students.sql",
+		"016_normalize_student_emails.sql",
+		"017_add_student_accounts.sql",
+		"018_create_categories_and_tags.sql",
+		"019_create_difficulty_levels.sql",
+		"020_create_instructors.sql",
+		"021_create_role_permissions.sql",
+		"022_add_user_disabled_at.sql",
+		"023_create_revoked_tokens.sql",
+		"024_create_api_keys.sql",
+		"025_add_user_password_management.sql",
+		"026_add_student_account_invites.sql",
 	}
 
 	for _, filename := range migrationFiles {
@@ -3,6 +3,7 @@ package database
 import (
 	"log"
 
+	"sonic-labs/course-enrollment-service/internal/config"
 	"sonic-labs/course-enrollment-service/internal/constants"
 	"sonic-labs/course-enrollment-service/internal/models"
 	"sonic-labs/course-enrollment-service/internal/service"
@@ -10,12 +11,16 @@ import (
 	"gorm.io/gorm"
 )
 
-// SeedAdminUser creates the default admin user if it doesn't exist
+// SeedAdminUser creates the configured admin user, a super-admin, unless the
+// username is taken or there already is a super-admin. The admin must change
+// the configured password before doing anything else.
 // Only runs after migration is complete
-func SeedAdminUser(db *gorm.DB) error {
+func SeedAdminUser(db *gorm.DB, admin config.AdminConfig) error {
 	// Simple check if admin user already exists
 	var count int64
-	err := db.Model(&models.User{}).Where("username = ?", "admin").Count(&count).Error
+	err := db.Model(&models.User{}).
+		Where("username = ? OR role = ?", admin.Username, constants.RoleSuperAdmin).
+		Count(&count).Error
 	if err != nil {
 		return err
 	}
@@ -25,17 +30,18 @@ func SeedAdminUser(db *gorm.DB) error {
 		return nil
 	}
 
-	// Hash the default password using bcrypt
-	hashedPassword, err := service.HashPassword("admin!dev")
+	// Hash the configured password using bcrypt
+	hashedPassword, err := service.HashPassword(admin.Password)
 	if err != nil {
 		return err
 	}
 
 	// Simple SQL insert for admin user
 	adminUser := models.User{
-		Username: "admin",
-		Password: hashedPassword,
-		Role:     constants.RoleAdmin,
+		Username:           admin.Username,
+		Password:           hashedPassword,
+		Role:               constants.RoleSuperAdmin,
+		MustChangePassword: true,
 	}
 
 	err = db.Create(&adminUser).Error
@@ -43,6 +49,6 @@ func SeedAdminUser(db *gorm.DB) error {
 		return err
 	}
 
-	log.Printf("Admin user created successfully with ID: %s", adminUser.ID.String())
+	log.Printf("Admin user %s created successfully with ID: %s; its password must be changed at first login", adminUser.Username, adminUser.ID.String())
 	return nil
 }
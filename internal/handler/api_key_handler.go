@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// APIKeyHandler handles API key HTTP requests
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// GetAPIKeys retrieves every API key
+// @Summary Get API keys
+// @Description Get every API key with its owner, scopes, expiry and last use, newest first. Keys themselves are never returned (Super-admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.APIKeyListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/api-keys [get]
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.GetAPIKeys()
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve API keys")
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// GetAPIKey retrieves an API key by ID
+// @Summary Get API key
+// @Description Get an API key with its owner, scopes, expiry and last use (Super-admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} models.APIKeyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/api-keys/{id} [get]
+func (h *APIKeyHandler) GetAPIKey(c *gin.Context) {
+	id, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+
+	key, err := h.apiKeyService.GetAPIKey(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve API key")
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
+
+// CreateAPIKey creates an API key
+// @Summary Create API key
+// @Description Create a key other systems send in the X-API-Key header to act for its owner, the signed-in user unless owner_id is set. The key may only use its scopes, each a permission the owner's role holds. The key is in this response only (Super-admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param key body models.CreateAPIKeyRequest true "API key details"
+// @Success 201 {object} models.CreateAPIKeyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	creatorID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Authentication required",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	key, err := h.apiKeyService.CreateAPIKey(creatorID, req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create API key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+// DeleteAPIKey revokes an API key
+// @Summary Delete API key
+// @Description Revoke an API key; requests sending it are rejected from now on (Super-admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) DeleteAPIKey(c *gin.Context) {
+	id, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.apiKeyService.DeleteAPIKey(id); err != nil {
+		h.handleError(c, err, "Failed to delete API key")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleError maps API key errors to HTTP responses
+func (h *APIKeyHandler) handleError(c *gin.Context, err error, failure string) {
+	switch err.Error() {
+	case "api key not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "API key not found",
+		})
+	case "api key name is required":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Name is required",
+		})
+	case "expiry must be in the future":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Expiry must be in the future",
+		})
+	case "owner not found":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Owner not found",
+		})
+	case "owner is disabled":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Owner is disabled",
+		})
+	case "scopes are required":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "At least one scope is required",
+		})
+	case "unknown permission":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Unknown permission in scopes",
+		})
+	case "scope not held by owner":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Every scope must be a permission the owner's role holds",
+		})
+	default:
+		log.Printf("%s: %v", failure, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: failure,
+		})
+	}
+}
+
+// parseAPIKeyID parses the API key ID path parameter. It writes a 400 response
+// and returns false if it is invalid.
+func parseAPIKeyID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid API key ID format",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -1,8 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 
+	"sonic-labs/course-enrollment-service/internal/auth"
+	"sonic-labs/course-enrollment-service/internal/constants"
 	"sonic-labs/course-enrollment-service/internal/models"
 	"sonic-labs/course-enrollment-service/internal/service"
 
@@ -24,7 +31,7 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 
 // Login authenticates a user and returns a JWT token
 // @Summary User login
-// @Description Authenticate a user with username and password and return a JWT token
+// @Description Authenticate a user with username and password and return a JWT token. After repeated failed attempts for a username or from a client IP, logins are refused with 429 for a growing delay and then a lockout; Retry-After gives the seconds to wait
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -32,6 +39,8 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 // @Success 200 {object} models.LoginResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -62,8 +71,18 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	// Authenticate user
-	loginResponse, err := h.authService.Login(req)
+	loginResponse, err := h.authService.Login(req, c.ClientIP())
 	if err != nil {
+		var throttled *service.LoginThrottledError
+		if errors.As(err, &throttled) {
+			seconds := int(math.Ceil(throttled.RetryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.JSON(http.StatusTooManyRequests, ErrorResponse{
+				Error:   "Too many login attempts",
+				Message: fmt.Sprintf(constants.MsgLoginThrottled, seconds),
+			})
+			return
+		}
 		if err.Error() == "invalid username or password" {
 			c.JSON(http.StatusUnauthorized, ErrorResponse{
 				Error:   "Authentication failed",
@@ -71,6 +90,13 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			})
 			return
 		}
+		if err.Error() == "account is disabled" {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   "Authentication failed",
+				Message: constants.MsgAccountDisabled,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "Login failed",
 			Message: err.Error(),
@@ -81,9 +107,283 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, loginResponse)
 }
 
+// Register creates a student account
+// @Summary Student registration
+// @Description Create a student account with an email and password and return a JWT token. The email is the username to log in with. An email that already belongs to a student can only be registered with the invite_token an admin issued for that student, which links the account to it
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param registration body models.RegisterRequest true "Account details"
+// @Success 201 {object} models.LoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/register [post]
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req models.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	registerResponse, err := h.authService.Register(req)
+	if err != nil {
+		if writePasswordPolicyError(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "email is required":
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: "Email is required",
+			})
+		case "invalid email format":
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: "Invalid email format",
+			})
+		case "account already exists":
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   constants.HTTPConflict,
+				Message: "An account already exists for this email",
+			})
+		case "student already exists":
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   constants.HTTPConflict,
+				Message: "A student with this email already exists; register with the invite_token an administrator issues for it",
+			})
+		case "invalid invite token":
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid token",
+				Message: "Invite token is invalid, expired, already used or for another email",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Registration failed",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, registerResponse)
+}
+
+// Refresh renews an access token
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access token and refresh token. Each refresh token works once; using one again ends its session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param refresh body models.RefreshRequest true "Refresh token"
+// @Success 200 {object} models.LoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+	if req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Refresh token is required",
+		})
+		return
+	}
+
+	refreshResponse, err := h.authService.Refresh(req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid refresh token":
+			c.JSON(http.StatusUnauthorized, ErrorResponse{
+				Error:   "Invalid token",
+				Message: "Refresh token is invalid, expired or already used",
+			})
+		case "account is disabled":
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   "Authentication failed",
+				Message: constants.MsgAccountDisabled,
+			})
+		default:
+			log.Printf("Failed to refresh token: %v", err)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   constants.HTTPInternalServerError,
+				Message: "Failed to refresh token",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, refreshResponse)
+}
+
+// Logout ends the current session
+// @Summary Logout
+// @Description Revoke the session of the access token: it and every refresh token issued with it stop working. API keys have no session; delete them instead
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	sessionID := c.GetString("session_id")
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Only access tokens can be logged out",
+		})
+		return
+	}
+
+	if err := h.authService.Logout(sessionID); err != nil {
+		log.Printf("Failed to log out: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: "Failed to log out",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ChangePassword changes the password of the signed-in user
+// @Summary Change password
+// @Description Replace the password of the signed-in user, who must give the current one. The new password must meet the password policy. Clears must_change_password; users with it set can use no other route except their profile and logout. The current session is ended and new tokens are returned; refresh tokens of other sessions stop working
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param password body models.ChangePasswordRequest true "Current and new password"
+// @Security BearerAuth
+// @Success 200 {object} models.LoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/change-password [post]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Authentication required",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	response, err := h.authService.ChangePassword(userID, c.GetString("session_id"), req)
+	if err != nil {
+		if writePasswordPolicyError(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "current password is incorrect":
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: "Current password is incorrect",
+			})
+		case "new password must differ":
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: "New password must differ from the current one",
+			})
+		case "user not found":
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   constants.HTTPNotFound,
+				Message: "User not found",
+			})
+		default:
+			log.Printf("Failed to change password: %v", err)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   constants.HTTPInternalServerError,
+				Message: "Failed to change password",
+			})
+		}
+		return
+	}
+
+	log.Printf("User %s changed their password", response.User.Username)
+	c.JSON(http.StatusOK, response)
+}
+
+// ResetPassword sets a new password with a reset token
+// @Summary Reset password
+// @Description Set a new password with a reset token an admin issued, and log in. Each token works once, until it expires. The new password must meet the password policy; refresh tokens issued before stop working
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param reset body models.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} models.LoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.authService.ResetPassword(req)
+	if err != nil {
+		if writePasswordPolicyError(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "invalid reset token":
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid token",
+				Message: "Reset token is invalid, expired or already used",
+			})
+		case "account is disabled":
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   "Authentication failed",
+				Message: constants.MsgAccountDisabled,
+			})
+		default:
+			log.Printf("Failed to reset password: %v", err)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   constants.HTTPInternalServerError,
+				Message: "Failed to reset password",
+			})
+		}
+		return
+	}
+
+	log.Printf("User %s reset their password", response.User.Username)
+	c.JSON(http.StatusOK, response)
+}
+
 // GetProfile returns the current user's profile
 // @Summary Get user profile
-// @Description Get the profile of the currently authenticated user
+// @Description Get the profile of the currently authenticated user, admin or student
 // @Tags auth
 // @Produce json
 // @Security BearerAuth
@@ -114,10 +414,42 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
+	profile := models.UserResponse{
+		ID:                 userUUID,
+		Username:           username.(string),
+		Role:               role.(string),
+		MustChangePassword: c.GetBool("must_change_password"),
+	}
+	if studentID, err := uuid.Parse(c.GetString("student_id")); err == nil {
+		profile.StudentID = &studentID
+	}
+
 	// Return user profile
-	c.JSON(http.StatusOK, models.UserResponse{
-		ID:       userUUID,
-		Username: username.(string),
-		Role:     role.(string),
+	c.JSON(http.StatusOK, profile)
+}
+
+// GetJWKS returns the public keys that verify tokens
+// @Summary Get token signing keys
+// @Description Get the public keys that sign access and refresh tokens as a JSON Web Key Set, so other services can verify tokens by their kid header. Includes keys scheduled to sign later; empty while tokens are signed with the shared secret
+// @Tags auth
+// @Produce json
+// @Success 200 {object} auth.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, auth.PublicKeys())
+}
+
+// writePasswordPolicyError writes a 400 response when err is a password the
+// password policy rejects, and reports whether it did
+func writePasswordPolicyError(c *gin.Context, err error) bool {
+	var weak *service.PasswordPolicyError
+	if !errors.As(err, &weak) {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error:   "Validation failed",
+		Message: weak.Message,
 	})
+	return true
 }
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CategoryHandler handles course category HTTP requests
+type CategoryHandler struct {
+	categoryService service.CategoryService
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(categoryService service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+	}
+}
+
+// GetCategories retrieves the category tree
+// @Summary Get categories
+// @Description Get every course category as a tree, each level sorted by name
+// @Tags categories
+// @Produce json
+// @Success 200 {object} models.CategoryListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /categories [get]
+func (h *CategoryHandler) GetCategories(c *gin.Context) {
+	categories, err := h.categoryService.GetCategories()
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve categories")
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+// GetCategory retrieves a category by ID
+// @Summary Get category by ID
+// @Description Retrieve a category with its subcategories
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} models.CategoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /categories/{id} [get]
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	id, ok := parseCategoryID(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.categoryService.GetCategory(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve category")
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// CreateCategory adds a course category
+// @Summary Create category
+// @Description Add a category at the top level or under a parent. The slug is derived from the name when omitted (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param category body models.CategoryRequest true "Category data"
+// @Success 201 {object} models.CategoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /categories [post]
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req models.CategoryRequest
+	if !bindCategoryRequest(c, &req) {
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create category")
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// UpdateCategory updates a category
+// @Summary Update category
+// @Description Rename a category or move it under another parent; its subcategories move along (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param category body models.CategoryRequest true "Category data"
+// @Success 200 {object} models.CategoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	id, ok := parseCategoryID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CategoryRequest
+	if !bindCategoryRequest(c, &req) {
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(id, req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update category")
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// DeleteCategory deletes a category
+// @Summary Delete category
+// @Description Delete a category without subcategories; courses in it lose the category (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, ok := parseCategoryID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(id); err != nil {
+		h.handleError(c, err, "Failed to delete category")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleError maps category errors to HTTP responses
+func (h *CategoryHandler) handleError(c *gin.Context, err error, failure string) {
+	switch err.Error() {
+	case "category not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Category not found",
+		})
+	case "category name is required":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Name is required",
+		})
+	case "category name is too long":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Name must be at most 100 characters",
+		})
+	case "invalid category slug":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Slug must contain letters or digits and be at most 100 characters",
+		})
+	case "parent category not found":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Parent category not found",
+		})
+	case "category cannot be moved under itself":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "A category cannot be moved under itself or one of its subcategories",
+		})
+	case "category slug already exists":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "A category with this slug already exists",
+		})
+	case "category has subcategories":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "Category cannot be deleted while it has subcategories",
+		})
+	default:
+		log.Printf("%s: %v", failure, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: failure,
+		})
+	}
+}
+
+// parseCategoryID parses the category ID path parameter. It writes a 400
+// response and returns false if it is invalid.
+func parseCategoryID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid category ID format",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bindCategoryRequest binds a category request body, writing a 400 response if it is malformed
+func bindCategoryRequest(c *gin.Context, req *models.CategoryRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"net/url"
@@ -9,6 +11,7 @@ import (
 	"sonic-labs/course-enrollment-service/internal/service"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -18,13 +21,15 @@ import (
 // CourseHandler handles course-related HTTP requests
 type CourseHandler struct {
 	courseService service.CourseService
+	moduleService service.ModuleService
 	s3Service     *service.S3Service
 }
 
 // NewCourseHandler creates a new course handler
-func NewCourseHandler(courseService service.CourseService, s3Service *service.S3Service) *CourseHandler {
+func NewCourseHandler(courseService service.CourseService, moduleService service.ModuleService, s3Service *service.S3Service) *CourseHandler {
 	return &CourseHandler{
 		courseService: courseService,
+		moduleService: moduleService,
 		s3Service:     s3Service,
 	}
 }
@@ -37,7 +42,12 @@ func NewCourseHandler(courseService service.CourseService, s3Service *service.S3
 // @Produce json
 // @Param title formData string true "Course title"
 // @Param description formData string true "Course description"
-// @Param difficulty formData string true "Course difficulty (Beginner, Intermediate, Advanced)"
+// @Param difficulty formData string true "Course difficulty, the name of a difficulty level"
+// @Param capacity formData int false "Maximum number of enrolled students (omit for unlimited)"
+// @Param enrollment_opens_at formData string false "Start of the enrollment window (RFC 3339)"
+// @Param enrollment_closes_at formData string false "End of the enrollment window (RFC 3339)"
+// @Param category_ids formData string false "Comma-separated IDs of the categories the course is in"
+// @Param tags formData string false "Comma-separated course tags; unknown tags are created"
 // @Param image formData file false "Course image file (JPG, PNG, GIF, WebP, max 5MB)"
 // @Success 201 {object} models.CourseResponse
 // @Failure 400 {object} ErrorResponse
@@ -67,20 +77,53 @@ func (h *CourseHandler) CreateCourseWithImage(c *gin.Context) {
 		return
 	}
 
-	// Validate difficulty
-	validDifficulties := map[string]bool{
-		"Beginner":     true,
-		"Intermediate": true,
-		"Advanced":     true,
+	// Parse capacity (optional)
+	var capacity *int
+	if capacityStr := c.PostForm("capacity"); capacityStr != "" {
+		value, err := strconv.Atoi(capacityStr)
+		if err != nil || value < 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: "Capacity must be a non-negative integer",
+			})
+			return
+		}
+		capacity = &value
+	}
+
+	// Parse enrollment window (optional)
+	opensAt, opensErr := parseFormTime(c.PostForm("enrollment_opens_at"))
+	closesAt, closesErr := parseFormTime(c.PostForm("enrollment_closes_at"))
+	if opensErr != nil || closesErr != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Enrollment window dates must be RFC 3339 timestamps",
+		})
+		return
 	}
-	if !validDifficulties[difficulty] {
+	if !isValidEnrollmentWindow(opensAt, closesAt) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "Validation failed",
-			Message: "Difficulty must be one of: Beginner, Intermediate, Advanced",
+			Message: constants.MsgEnrollmentWindowInvalid,
 		})
 		return
 	}
 
+	// Parse categories and tags (optional)
+	var categoryIDs []uuid.UUID
+	for _, value := range splitFormList(c.PostForm("category_ids")) {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: "Category IDs must be valid UUIDs",
+			})
+			return
+		}
+		categoryIDs = append(categoryIDs, id)
+	}
+	tags := splitFormList(c.PostForm("tags"))
+
 	// Handle image upload (optional)
 	var imageURL *string
 	file, err := c.FormFile("image")
@@ -103,6 +146,12 @@ func (h *CourseHandler) CreateCourseWithImage(c *gin.Context) {
 		Description: description,
 		Difficulty:  difficulty,
 		ImageURL:    imageURL,
+		Capacity:    capacity,
+
+		EnrollmentOpensAt:  opensAt,
+		EnrollmentClosesAt: closesAt,
+		CategoryIDs:        categoryIDs,
+		Tags:               tags,
 	}
 
 	course, err := h.courseService.CreateCourse(req)
@@ -111,6 +160,13 @@ func (h *CourseHandler) CreateCourseWithImage(c *gin.Context) {
 		if imageURL != nil {
 			h.s3Service.DeleteCourseImage(*imageURL)
 		}
+		if message, ok := courseValidationErrorMessage(err); ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: message,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "Failed to create course",
 			Message: err.Error(),
@@ -158,19 +214,6 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 		})
 		return
 	}
-	validDifficulties := map[string]bool{
-		"Beginner":     true,
-		"Intermediate": true,
-		"Advanced":     true,
-	}
-	if !validDifficulties[req.Difficulty] {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Validation failed",
-			Message: "Difficulty must be one of: Beginner, Intermediate, Advanced",
-		})
-		return
-	}
-
 	// Validate image URL if provided
 	if req.ImageURL != nil && *req.ImageURL != "" {
 		if !isValidURL(*req.ImageURL) {
@@ -182,8 +225,31 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 		}
 	}
 
+	if req.Capacity != nil && *req.Capacity < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Capacity must be a non-negative integer",
+		})
+		return
+	}
+
+	if !isValidEnrollmentWindow(req.EnrollmentOpensAt, req.EnrollmentClosesAt) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: constants.MsgEnrollmentWindowInvalid,
+		})
+		return
+	}
+
 	course, err := h.courseService.CreateCourse(req)
 	if err != nil {
+		if message, ok := courseValidationErrorMessage(err); ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: message,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "Failed to create course",
 			Message: err.Error(),
@@ -203,6 +269,12 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 // @Param limit query int false "Items per page (default: 10, max: 100)" example(10)
 // @Param search query string false "Search in title and description" example("golang")
 // @Param difficulty query []string false "Filter by difficulty levels" example("Beginner,Intermediate")
+// @Param sort query string false "Sort by created_at, title or difficulty (in the configured level order); prefix with - to sort descending (default: -created_at)" example("difficulty")
+// @Param open_for_enrollment query bool false "Only courses whose enrollment window is open now" example(true)
+// @Param term_id query string false "Only courses offered in this term" example("123e4567-e89b-12d3-a456-426614174000")
+// @Param category query []string false "Filter by category slugs, including their subcategories" example("programming")
+// @Param tag query []string false "Filter by tags; courses with any of them match" example("backend,concurrency")
+// @Param instructor_id query string false "Only courses this instructor teaches" example("123e4567-e89b-12d3-a456-426614174000")
 // @Success 200 {object} models.CourseListResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -228,21 +300,59 @@ func (h *CourseHandler) GetAllCourses(c *gin.Context) {
 	// Parse search
 	params.Search = strings.TrimSpace(c.Query("search"))
 
-	// Parse difficulty filter
-	if difficultyStr := c.Query("difficulty"); difficultyStr != "" {
-		difficulties := strings.Split(difficultyStr, ",")
-		validDifficulties := []string{}
-		for _, d := range difficulties {
-			d = strings.TrimSpace(d)
-			if d == "Beginner" || d == "Intermediate" || d == "Advanced" {
-				validDifficulties = append(validDifficulties, d)
-			}
+	// Parse difficulty filter; levels are matched to the stored ones by the service
+	params.Difficulty = splitFormList(c.Query("difficulty"))
+
+	// Parse sort
+	params.Sort = strings.TrimSpace(c.Query("sort"))
+	switch strings.TrimPrefix(params.Sort, "-") {
+	case "", constants.CourseSortCreatedAt, constants.CourseSortTitle, constants.CourseSortDifficulty:
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Sort must be one of: created_at, title, difficulty, optionally prefixed with -",
+		})
+		return
+	}
+
+	// Parse enrollment window filter
+	if openStr := c.Query("open_for_enrollment"); openStr != "" {
+		if open, err := strconv.ParseBool(openStr); err == nil {
+			params.OpenForEnrollment = open
 		}
-		params.Difficulty = validDifficulties
+	}
+
+	// Parse term filter
+	termID, ok := parseTermFilter(c)
+	if !ok {
+		return
+	}
+	params.TermID = termID
+
+	// Parse category and tag filters
+	for _, slug := range splitFormList(c.Query("category")) {
+		params.Category = append(params.Category, strings.ToLower(slug))
+	}
+	for _, tag := range splitFormList(c.Query("tag")) {
+		params.Tag = append(params.Tag, models.NormalizeTag(tag))
+	}
+
+	// Parse instructor filter
+	if instructorStr := c.Query("instructor_id"); instructorStr != "" {
+		instructorID, err := uuid.Parse(instructorStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   constants.HTTPBadRequest,
+				Message: "Invalid instructor ID format",
+			})
+			return
+		}
+		params.InstructorID = &instructorID
 	}
 
 	// Check if any pagination/search parameters are provided
-	hasPaginationParams := params.Page > 0 || params.Limit > 0 || params.Search != "" || len(params.Difficulty) > 0
+	hasPaginationParams := params.Page > 0 || params.Limit > 0 || params.Search != "" || len(params.Difficulty) > 0 || params.OpenForEnrollment || params.TermID != nil ||
+		len(params.Category) > 0 || len(params.Tag) > 0 || params.InstructorID != nil || params.Sort != ""
 
 	if hasPaginationParams {
 		// Use new pagination endpoint
@@ -271,10 +381,11 @@ func (h *CourseHandler) GetAllCourses(c *gin.Context) {
 
 // GetCourseByID retrieves a course by ID
 // @Summary Get course by ID
-// @Description Retrieve a specific course by its ID
+// @Description Retrieve a specific course by its ID. Pass include=outline to also get its modules and lessons.
 // @Tags courses
 // @Produce json
 // @Param id path string true "Course ID"
+// @Param include query string false "Comma-separated related data to include (outline)"
 // @Success 200 {object} models.CourseResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
@@ -307,12 +418,39 @@ func (h *CourseHandler) GetCourseByID(c *gin.Context) {
 		return
 	}
 
+	if includes(c, "outline") {
+		outline, err := h.moduleService.GetOutline(id)
+		if err != nil {
+			log.Printf("Failed to retrieve outline for course %s: %v", id, err)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Failed to retrieve course",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		// Copy the course so the outline never ends up in the cached response
+		withOutline := *course
+		withOutline.Outline = outline
+		course = &withOutline
+	}
+
 	c.JSON(http.StatusOK, course)
 }
 
+// includes reports whether the comma-separated include query parameter lists name
+func includes(c *gin.Context, name string) bool {
+	for _, value := range strings.Split(c.Query("include"), ",") {
+		if strings.TrimSpace(value) == name {
+			return true
+		}
+	}
+	return false
+}
+
 // UpdateCourse updates an existing course
 // @Summary Update a course
-// @Description Update an existing course by ID (Admin only)
+// @Description Update an existing course by ID (Admin or course instructor)
 // @Tags courses
 // @Accept json
 // @Produce json
@@ -352,9 +490,35 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 		return
 	}
 
+	if req.Capacity != nil && *req.Capacity < 0 {
+		log.Printf("API Response: PUT %s -> 400", c.Request.URL.Path)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Capacity must be a non-negative integer",
+		})
+		return
+	}
+
+	if !isValidEnrollmentWindow(req.EnrollmentOpensAt, req.EnrollmentClosesAt) {
+		log.Printf("API Response: PUT %s -> 400", c.Request.URL.Path)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: constants.MsgEnrollmentWindowInvalid,
+		})
+		return
+	}
+
 	// Update course
 	response, err := h.courseService.UpdateCourse(courseID, req)
 	if err != nil {
+		if message, ok := courseValidationErrorMessage(err); ok {
+			log.Printf("API Response: PUT %s -> 400", c.Request.URL.Path)
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   constants.HTTPBadRequest,
+				Message: message,
+			})
+			return
+		}
 		if err == gorm.ErrRecordNotFound {
 			log.Printf("API Response: PUT %s -> 404", c.Request.URL.Path)
 			c.JSON(http.StatusNotFound, ErrorResponse{
@@ -431,10 +595,11 @@ func (h *CourseHandler) DeleteCourse(c *gin.Context) {
 
 // GetCourseStudents retrieves all students enrolled in a specific course
 // @Summary Get course students
-// @Description Get all student emails enrolled in a specific course (Admin only)
+// @Description Get all student emails enrolled in a specific course (Admin or course instructor)
 // @Tags admin
 // @Produce json
 // @Param id path string true "Course ID"
+// @Param status query []string false "Filter by enrollment status (default: pending,active)" example("completed")
 // @Success 200 {object} map[string]interface{} "{"students": ["email1", "email2"], "total": 2}"
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
@@ -459,8 +624,16 @@ func (h *CourseHandler) GetCourseStudents(c *gin.Context) {
 	}
 
 	// Get course students
-	students, err := h.courseService.GetCourseStudents(courseID)
+	students, err := h.courseService.GetCourseStudents(courseID, parseStatusFilter(c))
 	if err != nil {
+		if err.Error() == "invalid enrollment status" {
+			log.Printf("API Response: GET %s -> 400", c.Request.URL.Path)
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   constants.HTTPBadRequest,
+				Message: constants.MsgInvalidEnrollmentStatus,
+			})
+			return
+		}
 		if err.Error() == "course not found" {
 			log.Printf("API Response: GET %s -> 404", c.Request.URL.Path)
 			c.JSON(http.StatusNotFound, ErrorResponse{
@@ -487,7 +660,7 @@ func (h *CourseHandler) GetCourseStudents(c *gin.Context) {
 
 // RemoveStudentFromCourse removes a student from a specific course
 // @Summary Remove student from course
-// @Description Remove a student from a specific course (Admin only)
+// @Description Withdraw a student from a specific course; the enrollment is kept with status "withdrawn" (Admin or course instructor)
 // @Tags admin
 // @Produce json
 // @Param id path string true "Course ID"
@@ -527,7 +700,7 @@ func (h *CourseHandler) RemoveStudentFromCourse(c *gin.Context) {
 	}
 
 	// Remove student from course
-	err = h.courseService.RemoveStudentFromCourse(courseID, studentEmail)
+	err = h.courseService.RemoveStudentFromCourse(courseID, studentEmail, currentActor(c))
 	if err != nil {
 		if err.Error() == "course not found" {
 			log.Printf("API Response: DELETE %s -> 404", c.Request.URL.Path)
@@ -558,8 +731,130 @@ func (h *CourseHandler) RemoveStudentFromCourse(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// SetCourseInstructors replaces the instructors of a course
+// @Summary Set course instructors
+// @Description Replace the instructors teaching a course; they can then edit it and manage its enrollments. An empty list removes every instructor (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param instructors body models.CourseInstructorsRequest true "Instructor IDs"
+// @Success 200 {object} models.CourseResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/instructors [put]
+func (h *CourseHandler) SetCourseInstructors(c *gin.Context) {
+	log.Printf("API Request: PUT %s from %s", c.Request.URL.Path, c.ClientIP())
+
+	// Parse course ID
+	courseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Printf("API Response: PUT %s -> 400", c.Request.URL.Path)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid course ID format",
+		})
+		return
+	}
+
+	// Parse request body
+	var req models.CourseInstructorsRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.InstructorIDs == nil {
+		message := "Instructor IDs are required"
+		if err != nil {
+			message = "Invalid request body: " + err.Error()
+		}
+		log.Printf("API Response: PUT %s -> 400", c.Request.URL.Path)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: message,
+		})
+		return
+	}
+
+	// Set course instructors
+	response, err := h.courseService.SetCourseInstructors(courseID, req)
+	if err != nil {
+		if err.Error() == "course not found" {
+			log.Printf("API Response: PUT %s -> 404", c.Request.URL.Path)
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   constants.HTTPNotFound,
+				Message: "Course not found",
+			})
+			return
+		}
+		if err.Error() == "instructor not found" {
+			log.Printf("API Response: PUT %s -> 400", c.Request.URL.Path)
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: "Instructor IDs must belong to existing instructors",
+			})
+			return
+		}
+
+		log.Printf("API Response: PUT %s -> 500", c.Request.URL.Path)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: "Failed to set course instructors",
+		})
+		return
+	}
+
+	log.Printf("API Response: PUT %s -> 200", c.Request.URL.Path)
+	c.JSON(http.StatusOK, response)
+}
+
+// courseValidationErrorMessage returns the response message for a course error
+// caused by its difficulty, categories or tags, and false for any other error
+func courseValidationErrorMessage(err error) (string, bool) {
+	var difficultyErr *service.InvalidDifficultyError
+	if errors.As(err, &difficultyErr) {
+		return fmt.Sprintf(constants.MsgDifficultyInvalid, strings.Join(difficultyErr.Levels, ", ")), true
+	}
+
+	switch err.Error() {
+	case "category not found":
+		return "Category IDs must belong to existing categories", true
+	case "invalid tag":
+		return "Tags must not be empty and must be at most 50 characters", true
+	}
+	return "", false
+}
+
+// splitFormList splits a comma-separated form or query value, dropping empty items
+func splitFormList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // isValidURL checks if a string is a valid URL
 func isValidURL(str string) bool {
 	u, err := url.Parse(str)
 	return err == nil && u.Scheme != "" && u.Host != ""
 }
+
+// isValidEnrollmentWindow reports whether an enrollment window closes after it opens
+func isValidEnrollmentWindow(opensAt, closesAt *time.Time) bool {
+	return opensAt == nil || closesAt == nil || closesAt.After(*opensAt)
+}
+
+// parseFormTime parses an optional RFC 3339 form value
+func parseFormTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
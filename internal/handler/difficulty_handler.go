@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DifficultyHandler handles difficulty level HTTP requests
+type DifficultyHandler struct {
+	difficultyService service.DifficultyService
+}
+
+// NewDifficultyHandler creates a new difficulty level handler
+func NewDifficultyHandler(difficultyService service.DifficultyService) *DifficultyHandler {
+	return &DifficultyHandler{
+		difficultyService: difficultyService,
+	}
+}
+
+// GetLevels retrieves every difficulty level
+// @Summary Get difficulty levels
+// @Description Get the difficulty levels courses can have, easiest first, with the number of courses at each
+// @Tags difficulty-levels
+// @Produce json
+// @Success 200 {object} models.DifficultyLevelListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /difficulty-levels [get]
+func (h *DifficultyHandler) GetLevels(c *gin.Context) {
+	levels, err := h.difficultyService.GetLevels()
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve difficulty levels")
+		return
+	}
+
+	c.JSON(http.StatusOK, levels)
+}
+
+// CreateLevel adds a difficulty level
+// @Summary Create difficulty level
+// @Description Add a difficulty level with a unique name; without a sort order it goes after the hardest level (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param level body models.DifficultyLevelRequest true "Difficulty level data"
+// @Success 201 {object} models.DifficultyLevelResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /difficulty-levels [post]
+func (h *DifficultyHandler) CreateLevel(c *gin.Context) {
+	var req models.DifficultyLevelRequest
+	if !bindDifficultyLevelRequest(c, &req) {
+		return
+	}
+
+	level, err := h.difficultyService.CreateLevel(req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create difficulty level")
+		return
+	}
+
+	c.JSON(http.StatusCreated, level)
+}
+
+// UpdateLevel updates a difficulty level
+// @Summary Update difficulty level
+// @Description Rename, reorder or describe a difficulty level; a new name is copied to its courses (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Difficulty level ID"
+// @Param level body models.DifficultyLevelRequest true "Difficulty level data"
+// @Success 200 {object} models.DifficultyLevelResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /difficulty-levels/{id} [put]
+func (h *DifficultyHandler) UpdateLevel(c *gin.Context) {
+	id, ok := parseDifficultyLevelID(c)
+	if !ok {
+		return
+	}
+
+	var req models.DifficultyLevelRequest
+	if !bindDifficultyLevelRequest(c, &req) {
+		return
+	}
+
+	level, err := h.difficultyService.UpdateLevel(id, req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update difficulty level")
+		return
+	}
+
+	c.JSON(http.StatusOK, level)
+}
+
+// DeleteLevel deletes a difficulty level
+// @Summary Delete difficulty level
+// @Description Delete a difficulty level that no course has (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Difficulty level ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /difficulty-levels/{id} [delete]
+func (h *DifficultyHandler) DeleteLevel(c *gin.Context) {
+	id, ok := parseDifficultyLevelID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.difficultyService.DeleteLevel(id); err != nil {
+		h.handleError(c, err, "Failed to delete difficulty level")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleError maps difficulty level errors to HTTP responses
+func (h *DifficultyHandler) handleError(c *gin.Context, err error, failure string) {
+	switch err.Error() {
+	case "difficulty level not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Difficulty level not found",
+		})
+	case "difficulty level name is required":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Name is required",
+		})
+	case "difficulty level name is too long":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Name must be at most 50 characters",
+		})
+	case "difficulty level name already exists":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "A difficulty level with this name already exists",
+		})
+	case "difficulty level is in use":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "Difficulty level cannot be deleted while courses have it",
+		})
+	default:
+		log.Printf("%s: %v", failure, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: failure,
+		})
+	}
+}
+
+// parseDifficultyLevelID parses the difficulty level ID path parameter. It
+// writes a 400 response and returns false if it is invalid.
+func parseDifficultyLevelID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid difficulty level ID format",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bindDifficultyLevelRequest binds a difficulty level request body, writing a
+// 400 response if it is malformed
+func bindDifficultyLevelRequest(c *gin.Context, req *models.DifficultyLevelRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
@@ -1,9 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"io"
+	"log"
 	"net/http"
+	"sonic-labs/course-enrollment-service/internal/constants"
 	"sonic-labs/course-enrollment-service/internal/models"
 	"sonic-labs/course-enrollment-service/internal/service"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -23,14 +29,19 @@ func NewEnrollmentHandler(enrollmentService service.EnrollmentService) *Enrollme
 
 // EnrollStudent enrolls a student in a course
 // @Summary Enroll a student in a course
-// @Description Enroll a student in a specific course using their email and course ID
+// @Description Enroll a student in a specific course using their email and course ID, in the given section, the given offering or else the course's default offering. The student must have completed every prerequisite unless override_prerequisites is set; overrides are recorded. A section that meets at the same time as the student's other sections is rejected unless override_schedule_conflicts is set. Only admins may set the overrides
 // @Tags enrollments
 // @Accept json
 // @Produce json
 // @Param enrollment body models.EnrollmentRequest true "Enrollment data"
 // @Success 201 {object} models.EnrollmentResponse
+// @Success 202 {object} SuccessResponse "Course is full, student added to the waitlist"
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Overrides are for admins only"
 // @Failure 409 {object} ErrorResponse
+// @Failure 409 {object} ScheduleConflictErrorResponse "Section overlaps the student's schedule"
+// @Failure 422 {object} PrerequisitesErrorResponse "Prerequisites not met"
+// @Failure 422 {object} EnrollmentWindowErrorResponse "Course is outside its enrollment window"
 // @Failure 500 {object} ErrorResponse
 // @Router /enrollments [post]
 func (h *EnrollmentHandler) EnrollStudent(c *gin.Context) {
@@ -59,48 +70,226 @@ func (h *EnrollmentHandler) EnrollStudent(c *gin.Context) {
 		return
 	}
 
-	enrollment, err := h.enrollmentService.EnrollStudent(req)
+	enrollment, err := h.enrollmentService.EnrollStudent(req, currentActor(c), c.GetString("role"))
 	if err != nil {
-		if err.Error() == "invalid email format" {
+		writeEnrollmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, enrollment)
+}
+
+// writeEnrollmentError maps the errors of enrolling a student to HTTP responses
+func writeEnrollmentError(c *gin.Context, err error) {
+	var waitlisted *service.WaitlistedError
+	if errors.As(err, &waitlisted) {
+		c.JSON(http.StatusAccepted, SuccessResponse{
+			Message: "Course is full, student added to the waitlist",
+			Data:    waitlisted.Entry,
+		})
+		return
+	}
+	var outsideWindow *service.EnrollmentWindowError
+	if errors.As(err, &outsideWindow) {
+		response := EnrollmentWindowErrorResponse{
+			Error:              "Enrollment closed",
+			Message:            "Enrollment for this course has closed",
+			Code:               outsideWindow.Code,
+			EnrollmentOpensAt:  outsideWindow.OpensAt,
+			EnrollmentClosesAt: outsideWindow.ClosesAt,
+		}
+		if outsideWindow.Code == constants.EnrollmentWindowNotOpen {
+			response.Error = "Enrollment not open"
+			response.Message = "Enrollment for this course has not opened yet"
+		}
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+	var missing *service.MissingPrerequisitesError
+	if errors.As(err, &missing) {
+		c.JSON(http.StatusUnprocessableEntity, PrerequisitesErrorResponse{
+			Error:                "Prerequisites not met",
+			Message:              "Student has not completed the prerequisites for this course",
+			MissingPrerequisites: missing.Missing,
+		})
+		return
+	}
+	var clash *service.ScheduleConflictError
+	if errors.As(err, &clash) {
+		c.JSON(http.StatusConflict, ScheduleConflictErrorResponse{
+			Error:     "Schedule conflict",
+			Message:   "Section meets at the same time as sections the student is already enrolled in",
+			Conflicts: clash.Conflicts,
+		})
+		return
+	}
+	if err.Error() == "invalid email format" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Invalid email format",
+		})
+		return
+	}
+	if err.Error() == "course not found" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Course not found",
+			Message: "The specified course does not exist",
+		})
+		return
+	}
+	if err.Error() == "offering not found" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Offering not found",
+			Message: "The specified offering does not exist for this course",
+		})
+		return
+	}
+	if err.Error() == "section not found" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Section not found",
+			Message: "The specified section does not exist for this course",
+		})
+		return
+	}
+	if err.Error() == "section is not part of this offering" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "The specified section is not part of the specified offering",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrOverrideNotAllowed) {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "Insufficient permissions",
+			Message: "Only admins may set override_prerequisites or override_schedule_conflicts",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrAlreadyEnrolled) {
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Enrollment conflict",
+			Message: "Student is already enrolled in this course",
+		})
+		return
+	}
+	if err.Error() == "student has already completed this course" {
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Enrollment conflict",
+			Message: "Student has already completed this course",
+		})
+		return
+	}
+	if err.Error() == "student is already on the waitlist for this course" {
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Enrollment conflict",
+			Message: "Student is already on the waitlist for this course",
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   "Failed to enroll student",
+		Message: err.Error(),
+	})
+}
+
+// ImportEnrollments enrolls students in bulk from a CSV file
+// @Summary Import enrollments from CSV
+// @Description Enroll students in bulk from a CSV of student_email,course rows, where course is a course ID or title and an optional header row is skipped. Every row is checked like a single enrollment and the report gives the outcome of each one: created, waitlisted, already_enrolled, invalid_email, unknown_course, rejected or malformed. With dry_run=true nothing is written (Admin only)
+// @Tags admin
+// @Accept multipart/form-data
+// @Accept text/csv
+// @Produce json
+// @Param file formData file false "CSV file (or send the CSV as the request body)"
+// @Param dry_run query bool false "Report what would happen without enrolling anyone"
+// @Success 200 {object} models.EnrollmentImportReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/enrollments/import [post]
+func (h *EnrollmentHandler) ImportEnrollments(c *gin.Context) {
+	dryRun := false
+	if dryRunStr := c.Query("dry_run"); dryRunStr != "" {
+		parsed, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error:   "Validation failed",
-				Message: "Invalid email format",
+				Message: "dry_run must be true or false",
 			})
 			return
 		}
-		if err.Error() == "course not found" {
+		dryRun = parsed
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constants.MaxImportFileSize)
+
+	var csvFile io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:   "Course not found",
-				Message: "The specified course does not exist",
+				Error:   "Validation failed",
+				Message: "CSV file is required",
 			})
 			return
 		}
-		if err.Error() == "student is already enrolled in this course" {
-			c.JSON(http.StatusConflict, ErrorResponse{
-				Error:   "Enrollment conflict",
-				Message: "Student is already enrolled in this course",
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: "Unable to read CSV file",
 			})
 			return
 		}
+		defer file.Close()
+		csvFile = file
+	}
+
+	report, err := h.enrollmentService.ImportEnrollments(csvFile, dryRun, currentActor(c))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: "CSV file is too large",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidImportFile) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: err.Error(),
+			})
+			return
+		}
+		log.Printf("Failed to import enrollments: %v", err)
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to enroll student",
-			Message: err.Error(),
+			Error:   constants.HTTPInternalServerError,
+			Message: "Failed to import enrollments",
 		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, enrollment)
+	c.JSON(http.StatusOK, report)
 }
 
 // GetStudentEnrollments retrieves all enrollments for a student
 // @Summary Get student enrollments
-// @Description Retrieve all courses a specific student is enrolled in
+// @Description Retrieve all courses a specific student is enrolled in. Requires an admin token, the student's own token or a share link; other callers get 403 whether or not the student exists
 // @Tags enrollments
 // @Produce json
 // @Param email path string true "Student email"
+// @Param status query []string false "Filter by enrollment status (pending, active, completed, dropped, withdrawn)" example("active,completed")
+// @Param term_id query string false "Only enrollments in offerings of this term" example("123e4567-e89b-12d3-a456-426614174000")
+// @Param expires query int false "Share link expiry"
+// @Param signature query string false "Share link signature"
 // @Success 200 {object} models.StudentEnrollmentsResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /students/{email}/enrollments [get]
 func (h *EnrollmentHandler) GetStudentEnrollments(c *gin.Context) {
 	email := c.Param("email")
@@ -112,7 +301,12 @@ func (h *EnrollmentHandler) GetStudentEnrollments(c *gin.Context) {
 		return
 	}
 
-	enrollments, err := h.enrollmentService.GetStudentEnrollments(email)
+	termID, ok := parseTermFilter(c)
+	if !ok {
+		return
+	}
+
+	enrollments, err := h.enrollmentService.GetStudentEnrollments(email, termID, parseStatusFilter(c))
 	if err != nil {
 		if err.Error() == "invalid email format" {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
@@ -121,6 +315,228 @@ func (h *EnrollmentHandler) GetStudentEnrollments(c *gin.Context) {
 			})
 			return
 		}
+		if err.Error() == "invalid enrollment status" {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: constants.MsgInvalidEnrollmentStatus,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to retrieve enrollments",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollments)
+}
+
+// UpdateEnrollmentStatus moves an enrollment to a new lifecycle status
+// @Summary Change enrollment status
+// @Description Move an enrollment to a new status. Allowed transitions: pending -> active/dropped/withdrawn, active -> completed/dropped/withdrawn, dropped/withdrawn -> active (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Enrollment ID"
+// @Param status body models.EnrollmentStatusRequest true "New status"
+// @Success 200 {object} models.EnrollmentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/enrollments/{id}/status [patch]
+func (h *EnrollmentHandler) UpdateEnrollmentStatus(c *gin.Context) {
+	enrollmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid enrollment ID format",
+		})
+		return
+	}
+
+	var req models.EnrollmentStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	enrollment, err := h.enrollmentService.UpdateEnrollmentStatus(enrollmentID, req, currentActor(c))
+	if err != nil {
+		if err.Error() == "enrollment not found" {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   constants.HTTPNotFound,
+				Message: "Enrollment not found",
+			})
+			return
+		}
+		if err.Error() == "invalid enrollment status" {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: constants.MsgInvalidEnrollmentStatus,
+			})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidStatusTransition) || err.Error() == "course is full" ||
+			err.Error() == "enrollment status was changed concurrently" {
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   constants.HTTPConflict,
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: "Failed to update enrollment status",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// GetEnrollmentHistory retrieves the status history of an enrollment
+// @Summary Get enrollment status history
+// @Description Get every status change of an enrollment with when it happened and who made it (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Enrollment ID"
+// @Success 200 {object} models.EnrollmentHistoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/enrollments/{id}/history [get]
+func (h *EnrollmentHandler) GetEnrollmentHistory(c *gin.Context) {
+	enrollmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid enrollment ID format",
+		})
+		return
+	}
+
+	history, err := h.enrollmentService.GetEnrollmentHistory(enrollmentID)
+	if err != nil {
+		if err.Error() == "enrollment not found" {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   constants.HTTPNotFound,
+				Message: "Enrollment not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: "Failed to retrieve enrollment history",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// EnrollSelf enrolls the signed-in student in a course
+// @Summary Enroll yourself in a course
+// @Description Enroll the signed-in student in a course, in the given section, the given offering or else the course's default offering. The same rules apply as to an admin enrollment, without overrides: prerequisites must be completed and a section may not clash with the student's other sections (Student accounts only)
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param enrollment body models.SelfEnrollmentRequest true "Course to enroll in"
+// @Success 201 {object} models.EnrollmentResponse
+// @Success 202 {object} SuccessResponse "Course is full, student added to the waitlist"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 409 {object} ScheduleConflictErrorResponse "Section overlaps the student's schedule"
+// @Failure 422 {object} PrerequisitesErrorResponse "Prerequisites not met"
+// @Failure 422 {object} EnrollmentWindowErrorResponse "Course is outside its enrollment window"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /me/enrollments [post]
+func (h *EnrollmentHandler) EnrollSelf(c *gin.Context) {
+	studentID, ok := currentStudentID(c)
+	if !ok {
+		return
+	}
+
+	var req models.SelfEnrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if req.CourseID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Course ID is required",
+		})
+		return
+	}
+
+	enrollment, err := h.enrollmentService.EnrollSelf(studentID, req, currentActor(c))
+	if err != nil {
+		writeEnrollmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, enrollment)
+}
+
+// GetOwnEnrollments retrieves the enrollments of the signed-in student
+// @Summary Get your enrollments
+// @Description Retrieve the courses the signed-in student is enrolled in, with lesson progress (Student accounts only)
+// @Tags me
+// @Produce json
+// @Param status query []string false "Filter by enrollment status (pending, active, completed, dropped, withdrawn)" example("active,completed")
+// @Param term_id query string false "Only enrollments in offerings of this term" example("123e4567-e89b-12d3-a456-426614174000")
+// @Success 200 {object} models.StudentEnrollmentsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /me/enrollments [get]
+func (h *EnrollmentHandler) GetOwnEnrollments(c *gin.Context) {
+	studentID, ok := currentStudentID(c)
+	if !ok {
+		return
+	}
+
+	termID, ok := parseTermFilter(c)
+	if !ok {
+		return
+	}
+
+	enrollments, err := h.enrollmentService.GetOwnEnrollments(studentID, termID, parseStatusFilter(c))
+	if err != nil {
+		if err.Error() == "invalid enrollment status" {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: constants.MsgInvalidEnrollmentStatus,
+			})
+			return
+		}
+		if err.Error() == "student not found" {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   constants.HTTPForbidden,
+				Message: "The student of this account no longer exists",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "Failed to retrieve enrollments",
 			Message: err.Error(),
@@ -130,3 +546,57 @@ func (h *EnrollmentHandler) GetStudentEnrollments(c *gin.Context) {
 
 	c.JSON(http.StatusOK, enrollments)
 }
+
+// DropOwnEnrollment drops the signed-in student from a course
+// @Summary Drop a course
+// @Description Drop the signed-in student from a course; the enrollment is kept with status "dropped" and the seat goes to the waitlist (Student accounts only)
+// @Tags me
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /me/enrollments/{course_id} [delete]
+func (h *EnrollmentHandler) DropOwnEnrollment(c *gin.Context) {
+	studentID, ok := currentStudentID(c)
+	if !ok {
+		return
+	}
+
+	courseID, err := uuid.Parse(c.Param("course_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: constants.MsgInvalidCourseIDFormat,
+		})
+		return
+	}
+
+	if err := h.enrollmentService.DropOwnEnrollment(studentID, courseID, currentActor(c)); err != nil {
+		if err.Error() == "enrollment not found" {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   constants.HTTPNotFound,
+				Message: "You are not enrolled in this course",
+			})
+			return
+		}
+		if err.Error() == "student not found" {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   constants.HTTPForbidden,
+				Message: "The student of this account no longer exists",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to drop enrollment",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
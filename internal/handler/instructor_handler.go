@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// InstructorHandler handles instructor HTTP requests
+type InstructorHandler struct {
+	instructorService service.InstructorService
+}
+
+// NewInstructorHandler creates a new instructor handler
+func NewInstructorHandler(instructorService service.InstructorService) *InstructorHandler {
+	return &InstructorHandler{
+		instructorService: instructorService,
+	}
+}
+
+// GetInstructors retrieves every instructor
+// @Summary Get instructors
+// @Description Get every instructor with the IDs of the courses they teach, sorted by name (Admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.InstructorListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/instructors [get]
+func (h *InstructorHandler) GetInstructors(c *gin.Context) {
+	instructors, err := h.instructorService.GetInstructors()
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve instructors")
+		return
+	}
+
+	c.JSON(http.StatusOK, instructors)
+}
+
+// GetInstructor retrieves an instructor by ID
+// @Summary Get instructor
+// @Description Retrieve an instructor with the IDs of the courses they teach (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Instructor ID"
+// @Success 200 {object} models.InstructorResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/instructors/{id} [get]
+func (h *InstructorHandler) GetInstructor(c *gin.Context) {
+	id, ok := parseInstructorID(c)
+	if !ok {
+		return
+	}
+
+	instructor, err := h.instructorService.GetInstructor(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve instructor")
+		return
+	}
+
+	c.JSON(http.StatusOK, instructor)
+}
+
+// CreateInstructor adds an instructor
+// @Summary Create instructor
+// @Description Add an instructor with a unique email. Assign them to courses with PUT /courses/{id}/instructors (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param instructor body models.InstructorRequest true "Instructor data"
+// @Success 201 {object} models.InstructorResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/instructors [post]
+func (h *InstructorHandler) CreateInstructor(c *gin.Context) {
+	var req models.InstructorRequest
+	if !bindInstructorRequest(c, &req) {
+		return
+	}
+
+	instructor, err := h.instructorService.CreateInstructor(req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create instructor")
+		return
+	}
+
+	c.JSON(http.StatusCreated, instructor)
+}
+
+// UpdateInstructor updates an instructor
+// @Summary Update instructor
+// @Description Change the name, email and bio of an instructor; a changed email is also their new username (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Instructor ID"
+// @Param instructor body models.InstructorRequest true "Instructor data"
+// @Success 200 {object} models.InstructorResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/instructors/{id} [put]
+func (h *InstructorHandler) UpdateInstructor(c *gin.Context) {
+	id, ok := parseInstructorID(c)
+	if !ok {
+		return
+	}
+
+	var req models.InstructorRequest
+	if !bindInstructorRequest(c, &req) {
+		return
+	}
+
+	instructor, err := h.instructorService.UpdateInstructor(id, req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update instructor")
+		return
+	}
+
+	c.JSON(http.StatusOK, instructor)
+}
+
+// DeleteInstructor deletes an instructor
+// @Summary Delete instructor
+// @Description Delete an instructor and their account and take them off their courses (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Instructor ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/instructors/{id} [delete]
+func (h *InstructorHandler) DeleteInstructor(c *gin.Context) {
+	id, ok := parseInstructorID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.instructorService.DeleteInstructor(id); err != nil {
+		h.handleError(c, err, "Failed to delete instructor")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CreateAccount creates the account of an instructor
+// @Summary Create instructor account
+// @Description Create the account an instructor signs in with, using their email as username, to manage the courses they teach (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Instructor ID"
+// @Param account body models.InstructorAccountRequest true "Account password"
+// @Success 201 {object} models.UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/instructors/{id}/account [post]
+func (h *InstructorHandler) CreateAccount(c *gin.Context) {
+	id, ok := parseInstructorID(c)
+	if !ok {
+		return
+	}
+
+	var req models.InstructorAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	user, err := h.instructorService.CreateAccount(id, req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create instructor account")
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+// handleError maps instructor errors to HTTP responses
+func (h *InstructorHandler) handleError(c *gin.Context, err error, failure string) {
+	if writePasswordPolicyError(c, err) {
+		return
+	}
+	switch err.Error() {
+	case "instructor not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Instructor not found",
+		})
+	case "instructor name is required":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Name is required",
+		})
+	case "instructor email is required":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Email is required",
+		})
+	case "invalid email format":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Invalid email format",
+		})
+	case "instructor email already exists":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "An instructor with this email already exists",
+		})
+	case "account already exists":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "An account with this email already exists",
+		})
+	default:
+		log.Printf("%s: %v", failure, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: failure,
+		})
+	}
+}
+
+// parseInstructorID parses the instructor ID path parameter. It writes a 400
+// response and returns false if it is invalid.
+func parseInstructorID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid instructor ID format",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bindInstructorRequest binds an instructor request body, writing a 400
+// response if it is malformed
+func bindInstructorRequest(c *gin.Context, req *models.InstructorRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
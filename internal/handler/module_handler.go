@@ -0,0 +1,517 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ModuleHandler handles course module and lesson HTTP requests
+type ModuleHandler struct {
+	moduleService service.ModuleService
+}
+
+// NewModuleHandler creates a new module handler
+func NewModuleHandler(moduleService service.ModuleService) *ModuleHandler {
+	return &ModuleHandler{
+		moduleService: moduleService,
+	}
+}
+
+// GetModules retrieves the outline of a course
+// @Summary Get course modules
+// @Description Get the ordered modules of a course with their ordered lessons, lesson counts and durations
+// @Tags courses
+// @Produce json
+// @Param id path string true "Course ID"
+// @Success 200 {object} models.CourseOutline
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /courses/{id}/modules [get]
+func (h *ModuleHandler) GetModules(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id")
+	if !ok {
+		return
+	}
+
+	outline, err := h.moduleService.GetOutline(ids[0])
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve modules")
+		return
+	}
+
+	c.JSON(http.StatusOK, outline)
+}
+
+// GetModule retrieves a module of a course
+// @Summary Get course module
+// @Description Get a module of a course with its ordered lessons
+// @Tags courses
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Success 200 {object} models.ModuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /courses/{id}/modules/{module_id} [get]
+func (h *ModuleHandler) GetModule(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id", "module_id")
+	if !ok {
+		return
+	}
+
+	module, err := h.moduleService.GetModule(ids[0], ids[1])
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve module")
+		return
+	}
+
+	c.JSON(http.StatusOK, module)
+}
+
+// CreateModule adds a module to a course
+// @Summary Create course module
+// @Description Add a module to the end of a course (Admin or course instructor)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param module body models.ModuleRequest true "Module data"
+// @Success 201 {object} models.ModuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/modules [post]
+func (h *ModuleHandler) CreateModule(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id")
+	if !ok {
+		return
+	}
+
+	req, ok := bindModuleRequest(c)
+	if !ok {
+		return
+	}
+
+	module, err := h.moduleService.CreateModule(ids[0], req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create module")
+		return
+	}
+
+	c.JSON(http.StatusCreated, module)
+}
+
+// UpdateModule updates a module of a course
+// @Summary Update course module
+// @Description Change the title and description of a module (Admin or course instructor)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Param module body models.ModuleRequest true "Module data"
+// @Success 200 {object} models.ModuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/modules/{module_id} [put]
+func (h *ModuleHandler) UpdateModule(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id", "module_id")
+	if !ok {
+		return
+	}
+
+	req, ok := bindModuleRequest(c)
+	if !ok {
+		return
+	}
+
+	module, err := h.moduleService.UpdateModule(ids[0], ids[1], req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update module")
+		return
+	}
+
+	c.JSON(http.StatusOK, module)
+}
+
+// DeleteModule deletes a module of a course
+// @Summary Delete course module
+// @Description Delete a module and all of its lessons; the remaining modules are renumbered (Admin or course instructor)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/modules/{module_id} [delete]
+func (h *ModuleHandler) DeleteModule(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id", "module_id")
+	if !ok {
+		return
+	}
+
+	if err := h.moduleService.DeleteModule(ids[0], ids[1]); err != nil {
+		h.handleError(c, err, "Failed to delete module")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ReorderModules changes the order of the modules of a course
+// @Summary Reorder course modules
+// @Description Replace the order of the modules of a course; every module must be listed exactly once (Admin or course instructor)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param order body models.ModuleReorderRequest true "New module order"
+// @Success 200 {object} models.CourseOutline
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/modules [put]
+func (h *ModuleHandler) ReorderModules(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.ModuleReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	outline, err := h.moduleService.ReorderModules(ids[0], req)
+	if err != nil {
+		h.handleError(c, err, "Failed to reorder modules")
+		return
+	}
+
+	c.JSON(http.StatusOK, outline)
+}
+
+// GetLesson retrieves a lesson of a module
+// @Summary Get lesson
+// @Description Get a lesson of a course module
+// @Tags courses
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Param lesson_id path string true "Lesson ID"
+// @Success 200 {object} models.LessonResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /courses/{id}/modules/{module_id}/lessons/{lesson_id} [get]
+func (h *ModuleHandler) GetLesson(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id", "module_id", "lesson_id")
+	if !ok {
+		return
+	}
+
+	lesson, err := h.moduleService.GetLesson(ids[0], ids[1], ids[2])
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve lesson")
+		return
+	}
+
+	c.JSON(http.StatusOK, lesson)
+}
+
+// CreateLesson adds a lesson to a module
+// @Summary Create lesson
+// @Description Add a lesson to the end of a course module (Admin or course instructor)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Param lesson body models.LessonRequest true "Lesson data"
+// @Success 201 {object} models.LessonResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/modules/{module_id}/lessons [post]
+func (h *ModuleHandler) CreateLesson(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id", "module_id")
+	if !ok {
+		return
+	}
+
+	req, ok := bindLessonRequest(c)
+	if !ok {
+		return
+	}
+
+	lesson, err := h.moduleService.CreateLesson(ids[0], ids[1], req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create lesson")
+		return
+	}
+
+	c.JSON(http.StatusCreated, lesson)
+}
+
+// UpdateLesson updates a lesson of a module
+// @Summary Update lesson
+// @Description Change the title, content and estimated duration of a lesson (Admin or course instructor)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Param lesson_id path string true "Lesson ID"
+// @Param lesson body models.LessonRequest true "Lesson data"
+// @Success 200 {object} models.LessonResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/modules/{module_id}/lessons/{lesson_id} [put]
+func (h *ModuleHandler) UpdateLesson(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id", "module_id", "lesson_id")
+	if !ok {
+		return
+	}
+
+	req, ok := bindLessonRequest(c)
+	if !ok {
+		return
+	}
+
+	lesson, err := h.moduleService.UpdateLesson(ids[0], ids[1], ids[2], req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update lesson")
+		return
+	}
+
+	c.JSON(http.StatusOK, lesson)
+}
+
+// DeleteLesson deletes a lesson of a module
+// @Summary Delete lesson
+// @Description Delete a lesson; the remaining lessons of the module are renumbered (Admin or course instructor)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Param lesson_id path string true "Lesson ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/modules/{module_id}/lessons/{lesson_id} [delete]
+func (h *ModuleHandler) DeleteLesson(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id", "module_id", "lesson_id")
+	if !ok {
+		return
+	}
+
+	if err := h.moduleService.DeleteLesson(ids[0], ids[1], ids[2]); err != nil {
+		h.handleError(c, err, "Failed to delete lesson")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ReorderLessons changes the order of the lessons of a module
+// @Summary Reorder lessons
+// @Description Replace the order of the lessons of a module; every lesson must be listed exactly once (Admin or course instructor)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Param order body models.LessonReorderRequest true "New lesson order"
+// @Success 200 {object} models.ModuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/modules/{module_id}/lessons [put]
+func (h *ModuleHandler) ReorderLessons(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id", "module_id")
+	if !ok {
+		return
+	}
+
+	var req models.LessonReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	module, err := h.moduleService.ReorderLessons(ids[0], ids[1], req)
+	if err != nil {
+		h.handleError(c, err, "Failed to reorder lessons")
+		return
+	}
+
+	c.JSON(http.StatusOK, module)
+}
+
+// handleError maps module and lesson errors to HTTP responses
+func (h *ModuleHandler) handleError(c *gin.Context, err error, failure string) {
+	switch err.Error() {
+	case "course not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Course not found",
+		})
+	case "module not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Module not found",
+		})
+	case "lesson not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Lesson not found",
+		})
+	case "module order must list every module of the course exactly once":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Module order must list every module of the course exactly once",
+		})
+	case "lesson order must list every lesson of the module exactly once":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Lesson order must list every lesson of the module exactly once",
+		})
+	default:
+		log.Printf("%s: %v", failure, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: failure,
+		})
+	}
+}
+
+// outlineIDNames describes the path parameters used by module, lesson, offering and section routes
+var outlineIDNames = map[string]string{
+	"id":          "course",
+	"module_id":   "module",
+	"lesson_id":   "lesson",
+	"offering_id": "offering",
+	"section_id":  "section",
+}
+
+// parseOutlineIDs parses the named UUID path parameters, in order. It writes a
+// 400 response and returns false if any of them is invalid.
+func parseOutlineIDs(c *gin.Context, params ...string) ([]uuid.UUID, bool) {
+	ids := make([]uuid.UUID, len(params))
+	for i, param := range params {
+		id, err := uuid.Parse(c.Param(param))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   constants.HTTPBadRequest,
+				Message: "Invalid " + outlineIDNames[param] + " ID format",
+			})
+			return nil, false
+		}
+		ids[i] = id
+	}
+	return ids, true
+}
+
+// bindModuleRequest binds and validates a module request body
+func bindModuleRequest(c *gin.Context) (models.ModuleRequest, bool) {
+	var req models.ModuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return req, false
+	}
+
+	if req.Title == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: constants.MsgTitleRequired,
+		})
+		return req, false
+	}
+
+	return req, true
+}
+
+// bindLessonRequest binds and validates a lesson request body
+func bindLessonRequest(c *gin.Context) (models.LessonRequest, bool) {
+	var req models.LessonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return req, false
+	}
+
+	if req.Title == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: constants.MsgTitleRequired,
+		})
+		return req, false
+	}
+
+	if req.Content == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Content is required",
+		})
+		return req, false
+	}
+
+	if req.DurationMinutes <= 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Duration must be a positive number of minutes",
+		})
+		return req, false
+	}
+
+	return req, true
+}
@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OfferingHandler handles course offering HTTP requests
+type OfferingHandler struct {
+	offeringService service.OfferingService
+}
+
+// NewOfferingHandler creates a new offering handler
+func NewOfferingHandler(offeringService service.OfferingService) *OfferingHandler {
+	return &OfferingHandler{
+		offeringService: offeringService,
+	}
+}
+
+// GetOfferings retrieves the offerings of a course
+// @Summary Get course offerings
+// @Description Get the default offering of a course followed by its offerings in each term, with enrolled counts
+// @Tags courses
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param term_id query string false "Only the offering in this term" example("123e4567-e89b-12d3-a456-426614174000")
+// @Success 200 {object} models.OfferingListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /courses/{id}/offerings [get]
+func (h *OfferingHandler) GetOfferings(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id")
+	if !ok {
+		return
+	}
+	termID, ok := parseTermFilter(c)
+	if !ok {
+		return
+	}
+
+	offerings, err := h.offeringService.GetOfferings(ids[0], termID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve offerings")
+		return
+	}
+
+	c.JSON(http.StatusOK, offerings)
+}
+
+// GetOffering retrieves an offering of a course
+// @Summary Get course offering
+// @Description Get an offering of a course with its term and enrolled count
+// @Tags courses
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param offering_id path string true "Offering ID"
+// @Success 200 {object} models.OfferingResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /courses/{id}/offerings/{offering_id} [get]
+func (h *OfferingHandler) GetOffering(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id", "offering_id")
+	if !ok {
+		return
+	}
+
+	offering, err := h.offeringService.GetOffering(ids[0], ids[1])
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve offering")
+		return
+	}
+
+	c.JSON(http.StatusOK, offering)
+}
+
+// CreateOffering offers a course in a term
+// @Summary Create course offering
+// @Description Offer a course in a term, optionally with its own capacity and schedule (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param offering body models.OfferingRequest true "Offering data"
+// @Success 201 {object} models.OfferingResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/offerings [post]
+func (h *OfferingHandler) CreateOffering(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.OfferingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	offering, err := h.offeringService.CreateOffering(ids[0], req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create offering")
+		return
+	}
+
+	c.JSON(http.StatusCreated, offering)
+}
+
+// UpdateOffering updates an offering of a course
+// @Summary Update course offering
+// @Description Change the capacity and schedule of an offering; new seats go to the waitlist (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param offering_id path string true "Offering ID"
+// @Param offering body models.OfferingUpdateRequest true "Offering data"
+// @Success 200 {object} models.OfferingResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/offerings/{offering_id} [put]
+func (h *OfferingHandler) UpdateOffering(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id", "offering_id")
+	if !ok {
+		return
+	}
+
+	var req models.OfferingUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	offering, err := h.offeringService.UpdateOffering(ids[0], ids[1], req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update offering")
+		return
+	}
+
+	c.JSON(http.StatusOK, offering)
+}
+
+// DeleteOffering deletes an offering of a course
+// @Summary Delete course offering
+// @Description Delete an offering nobody is enrolled or waitlisted in (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param offering_id path string true "Offering ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/offerings/{offering_id} [delete]
+func (h *OfferingHandler) DeleteOffering(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id", "offering_id")
+	if !ok {
+		return
+	}
+
+	if err := h.offeringService.DeleteOffering(ids[0], ids[1]); err != nil {
+		h.handleError(c, err, "Failed to delete offering")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleError maps offering errors to HTTP responses
+func (h *OfferingHandler) handleError(c *gin.Context, err error, failure string) {
+	switch err.Error() {
+	case "course not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Course not found",
+		})
+	case "offering not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Offering not found",
+		})
+	case "term not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Term not found",
+		})
+	case "term ID is required":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Term ID is required",
+		})
+	case "capacity must not be negative":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Capacity must not be negative",
+		})
+	case "course is already offered in this term":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "Course is already offered in this term",
+		})
+	case "offering has enrollments":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "Offering cannot be deleted while students are enrolled or waitlisted in it",
+		})
+	default:
+		log.Printf("%s: %v", failure, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: failure,
+		})
+	}
+}
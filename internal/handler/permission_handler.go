@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionHandler handles role permission HTTP requests
+type PermissionHandler struct {
+	permissionService service.PermissionService
+}
+
+// NewPermissionHandler creates a new permission handler
+func NewPermissionHandler(permissionService service.PermissionService) *PermissionHandler {
+	return &PermissionHandler{
+		permissionService: permissionService,
+	}
+}
+
+// GetRoles retrieves every role with its permissions
+// @Summary Get roles
+// @Description Get every role with the permissions it holds, and the permissions that can be granted. Super-admins hold every permission and cannot be edited (Super-admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.RoleListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/roles [get]
+func (h *PermissionHandler) GetRoles(c *gin.Context) {
+	roles, err := h.permissionService.GetRoles()
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve roles")
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+// SetRolePermissions replaces the permissions of a role
+// @Summary Set role permissions
+// @Description Replace the permissions every user with the role holds. Takes effect on their next request (Super-admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param role path string true "Role" Enums(admin, instructor, user)
+// @Param permissions body models.RolePermissionsRequest true "Permissions of the role"
+// @Success 200 {object} models.RoleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/roles/{role}/permissions [put]
+func (h *PermissionHandler) SetRolePermissions(c *gin.Context) {
+	var req models.RolePermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	if req.Permissions == nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Permissions are required",
+		})
+		return
+	}
+
+	role, err := h.permissionService.SetRolePermissions(c.Param("role"), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update role permissions")
+		return
+	}
+
+	c.JSON(http.StatusOK, role)
+}
+
+// handleError maps permission errors to HTTP responses
+func (h *PermissionHandler) handleError(c *gin.Context, err error, failure string) {
+	switch err.Error() {
+	case "role not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Role not found",
+		})
+	case "role is not editable":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Super-admins hold every permission and cannot be edited",
+		})
+	case "unknown permission":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Permissions must be among: " + strings.Join(constants.GrantablePermissions, ", "),
+		})
+	default:
+		log.Printf("%s: %v", failure, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: failure,
+		})
+	}
+}
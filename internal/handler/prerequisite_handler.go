@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PrerequisiteHandler handles course prerequisite HTTP requests
+type PrerequisiteHandler struct {
+	prerequisiteService service.PrerequisiteService
+}
+
+// NewPrerequisiteHandler creates a new prerequisite handler
+func NewPrerequisiteHandler(prerequisiteService service.PrerequisiteService) *PrerequisiteHandler {
+	return &PrerequisiteHandler{
+		prerequisiteService: prerequisiteService,
+	}
+}
+
+// GetPrerequisites retrieves the prerequisites of a course
+// @Summary Get course prerequisites
+// @Description Get the courses a student must complete before enrolling in a course
+// @Tags courses
+// @Produce json
+// @Param id path string true "Course ID"
+// @Success 200 {object} models.PrerequisitesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /courses/{id}/prerequisites [get]
+func (h *PrerequisiteHandler) GetPrerequisites(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: constants.MsgInvalidCourseIDFormat,
+		})
+		return
+	}
+
+	prerequisites, err := h.prerequisiteService.GetPrerequisites(courseID)
+	if err != nil {
+		if err.Error() == "course not found" {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   constants.HTTPNotFound,
+				Message: "Course not found",
+			})
+			return
+		}
+		log.Printf("Failed to retrieve prerequisites for course %s: %v", courseID, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: "Failed to retrieve prerequisites",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, prerequisites)
+}
+
+// AddPrerequisite adds a prerequisite to a course
+// @Summary Add course prerequisite
+// @Description Require a course to be completed before enrolling in this course; edges that would create a cycle are rejected (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param prerequisite body models.PrerequisiteRequest true "Prerequisite course"
+// @Success 201 {object} models.PrerequisitesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/prerequisites [post]
+func (h *PrerequisiteHandler) AddPrerequisite(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: constants.MsgInvalidCourseIDFormat,
+		})
+		return
+	}
+
+	var req models.PrerequisiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	if req.PrerequisiteID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Prerequisite ID is required",
+		})
+		return
+	}
+
+	prerequisites, err := h.prerequisiteService.AddPrerequisite(courseID, req)
+	if err != nil {
+		h.handleWriteError(c, courseID, err, "Failed to add prerequisite")
+		return
+	}
+
+	c.JSON(http.StatusCreated, prerequisites)
+}
+
+// ReplacePrerequisites sets the complete list of prerequisites of a course
+// @Summary Replace course prerequisites
+// @Description Replace every prerequisite of a course; an empty list removes them all (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param prerequisites body models.PrerequisitesReplaceRequest true "Prerequisite courses"
+// @Success 200 {object} models.PrerequisitesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/prerequisites [put]
+func (h *PrerequisiteHandler) ReplacePrerequisites(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: constants.MsgInvalidCourseIDFormat,
+		})
+		return
+	}
+
+	var req models.PrerequisitesReplaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	prerequisites, err := h.prerequisiteService.ReplacePrerequisites(courseID, req)
+	if err != nil {
+		h.handleWriteError(c, courseID, err, "Failed to replace prerequisites")
+		return
+	}
+
+	c.JSON(http.StatusOK, prerequisites)
+}
+
+// RemovePrerequisite removes a prerequisite from a course
+// @Summary Remove course prerequisite
+// @Description Stop requiring a course to be completed before enrolling in this course (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param prerequisite_id path string true "Prerequisite course ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/prerequisites/{prerequisite_id} [delete]
+func (h *PrerequisiteHandler) RemovePrerequisite(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: constants.MsgInvalidCourseIDFormat,
+		})
+		return
+	}
+
+	prerequisiteID, err := uuid.Parse(c.Param("prerequisite_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid prerequisite ID format",
+		})
+		return
+	}
+
+	err = h.prerequisiteService.RemovePrerequisite(courseID, prerequisiteID)
+	if err != nil {
+		if err.Error() == "course not found" {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   constants.HTTPNotFound,
+				Message: "Course not found",
+			})
+			return
+		}
+		if err.Error() == "prerequisite not found for this course" {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   constants.HTTPNotFound,
+				Message: "Prerequisite not found for this course",
+			})
+			return
+		}
+		log.Printf("Failed to remove prerequisite %s from course %s: %v", prerequisiteID, courseID, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: "Failed to remove prerequisite",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetOverrides retrieves the prerequisite overrides recorded for a course
+// @Summary Get prerequisite overrides
+// @Description Get every enrollment in a course that skipped the prerequisite check, newest first (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Course ID"
+// @Success 200 {object} models.PrerequisiteOverridesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/prerequisites/overrides [get]
+func (h *PrerequisiteHandler) GetOverrides(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: constants.MsgInvalidCourseIDFormat,
+		})
+		return
+	}
+
+	overrides, err := h.prerequisiteService.GetOverrides(courseID)
+	if err != nil {
+		if err.Error() == "course not found" {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   constants.HTTPNotFound,
+				Message: "Course not found",
+			})
+			return
+		}
+		log.Printf("Failed to retrieve prerequisite overrides for course %s: %v", courseID, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: "Failed to retrieve prerequisite overrides",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, overrides)
+}
+
+// handleWriteError maps errors from adding or replacing prerequisites to HTTP responses
+func (h *PrerequisiteHandler) handleWriteError(c *gin.Context, courseID uuid.UUID, err error, failure string) {
+	switch err.Error() {
+	case "course not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Course not found",
+		})
+	case "prerequisite course not found":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "The specified prerequisite course does not exist",
+		})
+	case "course cannot be its own prerequisite":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "A course cannot be its own prerequisite",
+		})
+	case "prerequisite would create a cycle":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "Prerequisite would create a cycle",
+		})
+	case "prerequisite already exists for this course":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "Prerequisite already exists for this course",
+		})
+	default:
+		log.Printf("%s for course %s: %v", failure, courseID, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: failure,
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ProgressHandler handles lesson progress HTTP requests
+type ProgressHandler struct {
+	progressService service.ProgressService
+}
+
+// NewProgressHandler creates a new progress handler
+func NewProgressHandler(progressService service.ProgressService) *ProgressHandler {
+	return &ProgressHandler{
+		progressService: progressService,
+	}
+}
+
+// GetEnrollmentProgress retrieves the lesson progress of an enrollment
+// @Summary Get enrollment progress
+// @Description Get the status of every lesson of the course for an enrollment, in course order, with the completion percentage (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Enrollment ID"
+// @Success 200 {object} models.EnrollmentProgressResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/enrollments/{id}/progress [get]
+func (h *ProgressHandler) GetEnrollmentProgress(c *gin.Context) {
+	enrollmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid enrollment ID format",
+		})
+		return
+	}
+
+	progress, err := h.progressService.GetEnrollmentProgress(enrollmentID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve enrollment progress")
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// RecordLessonProgress marks a lesson as started or completed for an enrollment
+// @Summary Record lesson progress
+// @Description Mark a lesson as started or completed for an active enrollment. Progress never goes backwards. Completing the last required lesson moves the enrollment to completed (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Enrollment ID"
+// @Param lesson_id path string true "Lesson ID"
+// @Param progress body models.LessonProgressRequest true "Lesson progress"
+// @Success 200 {object} models.EnrollmentProgressResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/enrollments/{id}/progress/{lesson_id} [put]
+func (h *ProgressHandler) RecordLessonProgress(c *gin.Context) {
+	enrollmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid enrollment ID format",
+		})
+		return
+	}
+
+	lessonID, err := uuid.Parse(c.Param("lesson_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid lesson ID format",
+		})
+		return
+	}
+
+	var req models.LessonProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	progress, err := h.progressService.RecordLessonProgress(enrollmentID, lessonID, req, currentActor(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to record lesson progress")
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// handleError maps lesson progress errors to HTTP responses
+func (h *ProgressHandler) handleError(c *gin.Context, err error, failure string) {
+	switch {
+	case err.Error() == "enrollment not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Enrollment not found",
+		})
+	case err.Error() == "lesson not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Lesson not found in the enrolled course",
+		})
+	case err.Error() == "invalid lesson progress status":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Status must be one of: started, completed",
+		})
+	case err.Error() == "progress can only be recorded for active enrollments",
+		errors.Is(err, service.ErrInvalidStatusTransition):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: err.Error(),
+		})
+	default:
+		log.Printf("%s: %v", failure, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: failure,
+		})
+	}
+}
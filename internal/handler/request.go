@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// currentActor returns the username of the authenticated caller, used to attribute changes
+func currentActor(c *gin.Context) string {
+	return c.GetString("username")
+}
+
+// currentStudentID returns the student the signed-in student account acts for.
+// StudentMiddleware guarantees it is set on the routes that need it.
+func currentStudentID(c *gin.Context) (uuid.UUID, bool) {
+	studentID, err := uuid.Parse(c.GetString("student_id"))
+	if err != nil {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   constants.HTTPForbidden,
+			Message: constants.MsgStudentAccountOnly,
+		})
+		return uuid.Nil, false
+	}
+	return studentID, true
+}
+
+// parseStatusFilter reads the comma-separated status query parameter
+func parseStatusFilter(c *gin.Context) []string {
+	statusStr := c.Query("status")
+	if statusStr == "" {
+		return nil
+	}
+
+	var statuses []string
+	for _, status := range strings.Split(statusStr, ",") {
+		status = strings.TrimSpace(status)
+		if status != "" {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// parseTermFilter reads the optional term_id query parameter. It writes a 400
+// response and returns false if the value is not a valid ID.
+func parseTermFilter(c *gin.Context) (*uuid.UUID, bool) {
+	termStr := c.Query("term_id")
+	if termStr == "" {
+		return nil, true
+	}
+
+	termID, err := uuid.Parse(termStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid term ID format",
+		})
+		return nil, false
+	}
+	return &termID, true
+}
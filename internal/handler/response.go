@@ -1,5 +1,11 @@
 package handler
 
+import (
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+)
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
 	Error   string `json:"error" example:"Validation failed"`
@@ -11,3 +17,28 @@ type SuccessResponse struct {
 	Message string      `json:"message" example:"Operation completed successfully"`
 	Data    interface{} `json:"data,omitempty"`
 }
+
+// PrerequisitesErrorResponse represents an enrollment rejected because prerequisites are missing
+type PrerequisitesErrorResponse struct {
+	Error                string                  `json:"error" example:"Prerequisites not met"`
+	Message              string                  `json:"message" example:"Student has not completed the prerequisites for this course"`
+	MissingPrerequisites []models.CourseResponse `json:"missing_prerequisites"`
+}
+
+// ScheduleConflictErrorResponse represents an enrollment rejected because the section
+// meets at the same time as sections the student already holds
+type ScheduleConflictErrorResponse struct {
+	Error     string                   `json:"error" example:"Schedule conflict"`
+	Message   string                   `json:"message" example:"Section meets at the same time as sections the student is already enrolled in"`
+	Conflicts []models.SectionResponse `json:"conflicts"`
+}
+
+// EnrollmentWindowErrorResponse represents an enrollment rejected because the course
+// is outside its enrollment window. Code is "enrollment_not_open" or "enrollment_closed".
+type EnrollmentWindowErrorResponse struct {
+	Error              string     `json:"error" example:"Enrollment closed"`
+	Message            string     `json:"message" example:"Enrollment for this course has closed"`
+	Code               string     `json:"code" example:"enrollment_closed"`
+	EnrollmentOpensAt  *time.Time `json:"enrollment_opens_at,omitempty" example:"2023-01-01T00:00:00Z"`
+	EnrollmentClosesAt *time.Time `json:"enrollment_closes_at,omitempty" example:"2023-02-01T00:00:00Z"`
+}
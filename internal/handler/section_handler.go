@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SectionHandler handles class section and timetable HTTP requests
+type SectionHandler struct {
+	sectionService service.SectionService
+}
+
+// NewSectionHandler creates a new section handler
+func NewSectionHandler(sectionService service.SectionService) *SectionHandler {
+	return &SectionHandler{
+		sectionService: sectionService,
+	}
+}
+
+// GetSections retrieves the sections of a course
+// @Summary Get course sections
+// @Description Get the sections of a course with their weekly meetings, ordered by name
+// @Tags courses
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param offering_id query string false "Only the sections of this offering" example("123e4567-e89b-12d3-a456-426614174000")
+// @Success 200 {object} models.SectionListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /courses/{id}/sections [get]
+func (h *SectionHandler) GetSections(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id")
+	if !ok {
+		return
+	}
+
+	var offeringID *uuid.UUID
+	if offeringStr := c.Query("offering_id"); offeringStr != "" {
+		id, err := uuid.Parse(offeringStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   constants.HTTPBadRequest,
+				Message: "Invalid offering ID format",
+			})
+			return
+		}
+		offeringID = &id
+	}
+
+	sections, err := h.sectionService.GetSections(ids[0], offeringID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve sections")
+		return
+	}
+
+	c.JSON(http.StatusOK, sections)
+}
+
+// GetSection retrieves a section of a course
+// @Summary Get course section
+// @Description Get a section of a course with its term and weekly meetings
+// @Tags courses
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param section_id path string true "Section ID"
+// @Success 200 {object} models.SectionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /courses/{id}/sections/{section_id} [get]
+func (h *SectionHandler) GetSection(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id", "section_id")
+	if !ok {
+		return
+	}
+
+	section, err := h.sectionService.GetSection(ids[0], ids[1])
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve section")
+		return
+	}
+
+	c.JSON(http.StatusOK, section)
+}
+
+// CreateSection adds a section to a course offering
+// @Summary Create course section
+// @Description Add a section with weekly meetings to an offering of a course, or to its default offering when offering_id is omitted. Meeting times are HH:MM in the meeting's IANA time zone (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param section body models.SectionRequest true "Section data"
+// @Success 201 {object} models.SectionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/sections [post]
+func (h *SectionHandler) CreateSection(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.SectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	section, err := h.sectionService.CreateSection(ids[0], req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create section")
+		return
+	}
+
+	c.JSON(http.StatusCreated, section)
+}
+
+// UpdateSection updates a section of a course
+// @Summary Update course section
+// @Description Rename a section and replace its weekly meetings; its offering cannot be changed (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param section_id path string true "Section ID"
+// @Param section body models.SectionRequest true "Section data"
+// @Success 200 {object} models.SectionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/sections/{section_id} [put]
+func (h *SectionHandler) UpdateSection(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id", "section_id")
+	if !ok {
+		return
+	}
+
+	var req models.SectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	section, err := h.sectionService.UpdateSection(ids[0], ids[1], req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update section")
+		return
+	}
+
+	c.JSON(http.StatusOK, section)
+}
+
+// DeleteSection deletes a section of a course
+// @Summary Delete course section
+// @Description Delete a section nobody is enrolled or waitlisted in (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param section_id path string true "Section ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/sections/{section_id} [delete]
+func (h *SectionHandler) DeleteSection(c *gin.Context) {
+	ids, ok := parseOutlineIDs(c, "id", "section_id")
+	if !ok {
+		return
+	}
+
+	if err := h.sectionService.DeleteSection(ids[0], ids[1]); err != nil {
+		h.handleError(c, err, "Failed to delete section")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetStudentTimetable retrieves the weekly timetable of a student
+// @Summary Get student timetable
+// @Description Get the weekly meetings of every section a student holds a seat in, ordered by day and start time. Requires an admin token, the student's own token or a share link
+// @Tags students
+// @Produce json
+// @Param email path string true "Student email"
+// @Param term_id query string false "Only sections of offerings in this term" example("123e4567-e89b-12d3-a456-426614174000")
+// @Param expires query int false "Share link expiry"
+// @Param signature query string false "Share link signature"
+// @Success 200 {object} models.TimetableResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /students/{email}/timetable [get]
+func (h *SectionHandler) GetStudentTimetable(c *gin.Context) {
+	termID, ok := parseTermFilter(c)
+	if !ok {
+		return
+	}
+
+	timetable, err := h.sectionService.GetStudentTimetable(c.Param("email"), termID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve timetable")
+		return
+	}
+
+	c.JSON(http.StatusOK, timetable)
+}
+
+// handleError maps section errors to HTTP responses
+func (h *SectionHandler) handleError(c *gin.Context, err error, failure string) {
+	switch err.Error() {
+	case "course not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Course not found",
+		})
+	case "offering not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Offering not found",
+		})
+	case "section not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Section not found",
+		})
+	case "invalid email format":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Invalid email format",
+		})
+	case "section name is required":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Section name is required",
+		})
+	case "invalid meeting day":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Meeting day must be a day of the week, e.g. monday",
+		})
+	case "invalid meeting time":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Meeting times must be in HH:MM format",
+		})
+	case "meeting must end after it starts":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Meeting must end after it starts",
+		})
+	case "invalid time zone":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Meeting time zone must be an IANA time zone, e.g. Europe/London",
+		})
+	case "section name already exists":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "A section with this name already exists in the offering",
+		})
+	case "section has enrollments":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "Section cannot be deleted while students are enrolled or waitlisted in it",
+		})
+	default:
+		log.Printf("%s: %v", failure, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: failure,
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
 	"sonic-labs/course-enrollment-service/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -51,12 +53,317 @@ func (h *StudentHandler) GetAllStudents(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetStudent retrieves a student by ID
+// @Summary Get student
+// @Description Get a student profile with their enrollment count (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Student ID"
+// @Success 200 {object} models.StudentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/students/{id} [get]
+func (h *StudentHandler) GetStudent(c *gin.Context) {
+	log.Printf("API Request: GET %s from %s", c.Request.URL.Path, c.ClientIP())
+
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	student, err := h.studentService.GetStudent(studentID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve student")
+		return
+	}
+
+	log.Printf("API Response: GET %s -> 200", c.Request.URL.Path)
+	c.JSON(http.StatusOK, student)
+}
+
+// CreateStudent creates a student
+// @Summary Create student
+// @Description Create a student profile with a unique email and optional name and student number. Students are also created on their first enrollment (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param student body models.StudentRequest true "Student data"
+// @Success 201 {object} models.StudentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/students [post]
+func (h *StudentHandler) CreateStudent(c *gin.Context) {
+	log.Printf("API Request: POST %s from %s", c.Request.URL.Path, c.ClientIP())
+
+	var req models.StudentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("API Response: POST %s -> 400", c.Request.URL.Path)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	student, err := h.studentService.CreateStudent(req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create student")
+		return
+	}
+
+	log.Printf("API Response: POST %s -> 201", c.Request.URL.Path)
+	c.JSON(http.StatusCreated, student)
+}
+
+// UpdateStudent updates a student
+// @Summary Update student
+// @Description Replace the profile of a student. A new email is copied to the student's enrollments and waitlist entries (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Student ID"
+// @Param student body models.StudentRequest true "Student data"
+// @Success 200 {object} models.StudentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/students/{id} [put]
+func (h *StudentHandler) UpdateStudent(c *gin.Context) {
+	log.Printf("API Request: PUT %s from %s", c.Request.URL.Path, c.ClientIP())
+
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	var req models.StudentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("API Response: PUT %s -> 400", c.Request.URL.Path)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	student, err := h.studentService.UpdateStudent(studentID, req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update student")
+		return
+	}
+
+	log.Printf("API Response: PUT %s -> 200", c.Request.URL.Path)
+	c.JSON(http.StatusOK, student)
+}
+
+// DeleteStudent deletes a student
+// @Summary Delete student
+// @Description Delete a student that has no enrollments, in any status, and no waitlist entries (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Student ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/students/{id} [delete]
+func (h *StudentHandler) DeleteStudent(c *gin.Context) {
+	log.Printf("API Request: DELETE %s from %s", c.Request.URL.Path, c.ClientIP())
+
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.studentService.DeleteStudent(studentID); err != nil {
+		h.handleError(c, err, "Failed to delete student")
+		return
+	}
+
+	log.Printf("API Response: DELETE %s -> 204", c.Request.URL.Path)
+	c.Status(http.StatusNoContent)
+}
+
+// MergeStudents merges a student into another one
+// @Summary Merge students
+// @Description Merge the source student into the student in the path, for when one person enrolled under two identities. Enrollments and waitlist entries move to the target; when both are enrolled in a course the enrollment furthest along is kept. The source student is deleted and the merge is recorded (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Target student ID"
+// @Param merge body models.StudentMergeRequest true "Student to merge"
+// @Success 200 {object} models.StudentMergeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/students/{id}/merge [post]
+func (h *StudentHandler) MergeStudents(c *gin.Context) {
+	log.Printf("API Request: POST %s from %s", c.Request.URL.Path, c.ClientIP())
+
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	var req models.StudentMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("API Response: POST %s -> 400", c.Request.URL.Path)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	merge, err := h.studentService.MergeStudents(studentID, req, currentActor(c))
+	if err != nil {
+		h.handleError(c, err, "Failed to merge students")
+		return
+	}
+
+	log.Printf("API Response: POST %s -> 200", c.Request.URL.Path)
+	c.JSON(http.StatusOK, merge)
+}
+
+// GetStudentMerges retrieves the merges into a student
+// @Summary Get student merges
+// @Description Get the recorded merges of other students into a student, newest first (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Student ID"
+// @Success 200 {object} models.StudentMergesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/students/{id}/merges [get]
+func (h *StudentHandler) GetStudentMerges(c *gin.Context) {
+	log.Printf("API Request: GET %s from %s", c.Request.URL.Path, c.ClientIP())
+
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	merges, err := h.studentService.GetStudentMerges(studentID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve student merges")
+		return
+	}
+
+	log.Printf("API Response: GET %s -> 200", c.Request.URL.Path)
+	c.JSON(http.StatusOK, merges)
+}
+
+// CreateInvite issues an account invite token for a student
+// @Summary Invite student to register
+// @Description Issue a token with which the student can register an account for their existing record, valid for 7 days and replacing any earlier one. It is only returned here; hand it to the student (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Student ID"
+// @Success 201 {object} models.StudentInviteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/students/{id}/invite [post]
+func (h *StudentHandler) CreateInvite(c *gin.Context) {
+	log.Printf("API Request: POST %s from %s", c.Request.URL.Path, c.ClientIP())
+
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	invite, err := h.studentService.CreateInvite(studentID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create invite")
+		return
+	}
+
+	log.Printf("API Response: POST %s -> 201", c.Request.URL.Path)
+	c.JSON(http.StatusCreated, invite)
+}
+
+// CreateShareLink creates share links to a student's records
+// @Summary Share student records
+// @Description Create signed links to the enrollments and timetable of a student that anyone holding them can open without signing in, until they expire (72 hours by default, at most 720) or the student's email changes (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Student ID"
+// @Param link body models.ShareLinkRequest false "Link expiry"
+// @Success 201 {object} models.ShareLinkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/students/{id}/share-link [post]
+func (h *StudentHandler) CreateShareLink(c *gin.Context) {
+	log.Printf("API Request: POST %s from %s", c.Request.URL.Path, c.ClientIP())
+
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ShareLinkRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Printf("API Response: POST %s -> 400", c.Request.URL.Path)
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   constants.HTTPBadRequest,
+				Message: "Invalid request body: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	link, err := h.studentService.CreateShareLink(studentID, req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create share link")
+		return
+	}
+
+	log.Printf("API Response: POST %s -> 201", c.Request.URL.Path)
+	c.JSON(http.StatusCreated, link)
+}
+
 // GetAllEnrollments retrieves all enrollments with course details
 // @Summary Get all enrollments
 // @Description Get all enrollments with course details (Admin only)
 // @Tags admin
 // @Produce json
+// @Param status query []string false "Filter by enrollment status (pending, active, completed, dropped, withdrawn)" example("active")
+// @Param term_id query string false "Only enrollments in offerings of this term" example("123e4567-e89b-12d3-a456-426614174000")
 // @Success 200 {object} models.AllEnrollmentsResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -65,8 +372,23 @@ func (h *StudentHandler) GetAllStudents(c *gin.Context) {
 func (h *StudentHandler) GetAllEnrollments(c *gin.Context) {
 	log.Printf("API Request: GET %s from %s", c.Request.URL.Path, c.ClientIP())
 
-	response, err := h.studentService.GetAllEnrollments()
+	termID, ok := parseTermFilter(c)
+	if !ok {
+		log.Printf("API Response: GET %s -> 400", c.Request.URL.Path)
+		return
+	}
+
+	response, err := h.studentService.GetAllEnrollments(termID, parseStatusFilter(c))
 	if err != nil {
+		if err.Error() == "invalid enrollment status" {
+			log.Printf("API Response: GET %s -> 400", c.Request.URL.Path)
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   constants.HTTPBadRequest,
+				Message: constants.MsgInvalidEnrollmentStatus,
+			})
+			return
+		}
+
 		log.Printf("API Response: GET %s -> 500", c.Request.URL.Path)
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   constants.HTTPInternalServerError,
@@ -79,9 +401,9 @@ func (h *StudentHandler) GetAllEnrollments(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// DeleteEnrollment deletes an enrollment
-// @Summary Delete an enrollment
-// @Description Delete an enrollment by ID (Admin only)
+// DeleteEnrollment withdraws an enrollment
+// @Summary Withdraw an enrollment
+// @Description Withdraw an enrollment by ID. The record and its history are kept with status "withdrawn" (Admin only)
 // @Tags admin
 // @Produce json
 // @Param id path string true "Enrollment ID"
@@ -90,6 +412,7 @@ func (h *StudentHandler) GetAllEnrollments(c *gin.Context) {
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Security BearerAuth
 // @Router /admin/enrollments/{id} [delete]
@@ -108,8 +431,8 @@ func (h *StudentHandler) DeleteEnrollment(c *gin.Context) {
 		return
 	}
 
-	// Delete enrollment
-	err = h.studentService.DeleteEnrollment(enrollmentID)
+	// Withdraw enrollment
+	err = h.studentService.DeleteEnrollment(enrollmentID, currentActor(c))
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			log.Printf("API Response: DELETE %s -> 404", c.Request.URL.Path)
@@ -119,6 +442,14 @@ func (h *StudentHandler) DeleteEnrollment(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, service.ErrInvalidStatusTransition) {
+			log.Printf("API Response: DELETE %s -> 409", c.Request.URL.Path)
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   constants.HTTPConflict,
+				Message: "Enrollment is no longer active",
+			})
+			return
+		}
 
 		log.Printf("API Response: DELETE %s -> 500", c.Request.URL.Path)
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -131,3 +462,65 @@ func (h *StudentHandler) DeleteEnrollment(c *gin.Context) {
 	log.Printf("API Response: DELETE %s -> 204", c.Request.URL.Path)
 	c.Status(http.StatusNoContent)
 }
+
+// parseStudentID parses the student ID path parameter. It writes a 400 response
+// and returns false if it is invalid.
+func parseStudentID(c *gin.Context) (uuid.UUID, bool) {
+	studentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Printf("API Response: %s %s -> 400", c.Request.Method, c.Request.URL.Path)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid student ID format",
+		})
+		return uuid.Nil, false
+	}
+	return studentID, true
+}
+
+// handleError maps student errors to HTTP responses
+func (h *StudentHandler) handleError(c *gin.Context, err error, failure string) {
+	status := http.StatusInternalServerError
+	response := ErrorResponse{Error: constants.HTTPInternalServerError, Message: failure}
+
+	switch err.Error() {
+	case "student not found":
+		status = http.StatusNotFound
+		response = ErrorResponse{Error: constants.HTTPNotFound, Message: "Student not found"}
+	case "student email is required":
+		status = http.StatusBadRequest
+		response = ErrorResponse{Error: "Validation failed", Message: "Email is required"}
+	case "invalid email format":
+		status = http.StatusBadRequest
+		response = ErrorResponse{Error: "Validation failed", Message: "Invalid email format"}
+	case "student email already exists":
+		status = http.StatusConflict
+		response = ErrorResponse{Error: constants.HTTPConflict, Message: "A student with this email already exists"}
+	case "student number already exists":
+		status = http.StatusConflict
+		response = ErrorResponse{Error: constants.HTTPConflict, Message: "A student with this student number already exists"}
+	case "source student ID is required":
+		status = http.StatusBadRequest
+		response = ErrorResponse{Error: "Validation failed", Message: "Source student ID is required"}
+	case "source student not found":
+		status = http.StatusBadRequest
+		response = ErrorResponse{Error: constants.HTTPBadRequest, Message: "Source student does not exist"}
+	case "cannot merge a student into itself":
+		status = http.StatusBadRequest
+		response = ErrorResponse{Error: constants.HTTPBadRequest, Message: "A student cannot be merged into itself"}
+	case "invalid share link expiry":
+		status = http.StatusBadRequest
+		response = ErrorResponse{Error: "Validation failed", Message: "Share links expire after 1 to 720 hours"}
+	case "student already has an account":
+		status = http.StatusConflict
+		response = ErrorResponse{Error: constants.HTTPConflict, Message: "Student already has an account"}
+	case "student has enrollments":
+		status = http.StatusConflict
+		response = ErrorResponse{Error: constants.HTTPConflict, Message: "Student cannot be deleted while they have enrollments or waitlist entries"}
+	default:
+		log.Printf("%s: %v", failure, err)
+	}
+
+	log.Printf("API Response: %s %s -> %d", c.Request.Method, c.Request.URL.Path, status)
+	c.JSON(status, response)
+}
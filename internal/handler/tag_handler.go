@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TagHandler handles course tag HTTP requests
+type TagHandler struct {
+	tagService service.TagService
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(tagService service.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// GetTags retrieves every tag
+// @Summary Get tags
+// @Description Get every course tag with the number of courses that have it, sorted by name
+// @Tags tags
+// @Produce json
+// @Success 200 {object} models.TagListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tags [get]
+func (h *TagHandler) GetTags(c *gin.Context) {
+	tags, err := h.tagService.GetTags()
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// CreateTag adds a tag
+// @Summary Create tag
+// @Description Add a tag; names are trimmed and lower-cased. Tags are also created when a course is given an unknown tag (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param tag body models.TagRequest true "Tag data"
+// @Success 201 {object} models.TagResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req models.TagRequest
+	if !bindTagRequest(c, &req) {
+		return
+	}
+
+	tag, err := h.tagService.CreateTag(req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create tag")
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// UpdateTag renames a tag
+// @Summary Rename tag
+// @Description Rename a tag on every course that has it (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Param tag body models.TagRequest true "Tag data"
+// @Success 200 {object} models.TagResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tags/{id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id, ok := parseTagID(c)
+	if !ok {
+		return
+	}
+
+	var req models.TagRequest
+	if !bindTagRequest(c, &req) {
+		return
+	}
+
+	tag, err := h.tagService.UpdateTag(id, req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// DeleteTag deletes a tag
+// @Summary Delete tag
+// @Description Delete a tag and remove it from every course (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, ok := parseTagID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.tagService.DeleteTag(id); err != nil {
+		h.handleError(c, err, "Failed to delete tag")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleError maps tag errors to HTTP responses
+func (h *TagHandler) handleError(c *gin.Context, err error, failure string) {
+	switch err.Error() {
+	case "tag not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Tag not found",
+		})
+	case "tag name is required":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Name is required",
+		})
+	case "tag name is too long":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Name must be at most 50 characters",
+		})
+	case "tag name already exists":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "A tag with this name already exists",
+		})
+	default:
+		log.Printf("%s: %v", failure, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: failure,
+		})
+	}
+}
+
+// parseTagID parses the tag ID path parameter. It writes a 400 response and
+// returns false if it is invalid.
+func parseTagID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid tag ID format",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bindTagRequest binds a tag request body, writing a 400 response if it is malformed
+func bindTagRequest(c *gin.Context, req *models.TagRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
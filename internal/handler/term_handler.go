@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TermHandler handles academic term HTTP requests
+type TermHandler struct {
+	termService service.TermService
+}
+
+// NewTermHandler creates a new term handler
+func NewTermHandler(termService service.TermService) *TermHandler {
+	return &TermHandler{
+		termService: termService,
+	}
+}
+
+// GetTerms retrieves the academic calendar
+// @Summary Get terms
+// @Description Get every term of the academic calendar, earliest first
+// @Tags terms
+// @Produce json
+// @Success 200 {object} models.TermListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /terms [get]
+func (h *TermHandler) GetTerms(c *gin.Context) {
+	terms, err := h.termService.GetTerms()
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve terms")
+		return
+	}
+
+	c.JSON(http.StatusOK, terms)
+}
+
+// GetTerm retrieves a term by ID
+// @Summary Get term by ID
+// @Description Retrieve a specific term by its ID
+// @Tags terms
+// @Produce json
+// @Param id path string true "Term ID"
+// @Success 200 {object} models.TermResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /terms/{id} [get]
+func (h *TermHandler) GetTerm(c *gin.Context) {
+	id, ok := parseTermID(c)
+	if !ok {
+		return
+	}
+
+	term, err := h.termService.GetTerm(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve term")
+		return
+	}
+
+	c.JSON(http.StatusOK, term)
+}
+
+// CreateTerm adds a term to the academic calendar
+// @Summary Create term
+// @Description Add a term with a unique name and its first and last day (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param term body models.TermRequest true "Term data"
+// @Success 201 {object} models.TermResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /terms [post]
+func (h *TermHandler) CreateTerm(c *gin.Context) {
+	var req models.TermRequest
+	if !bindTermRequest(c, &req) {
+		return
+	}
+
+	term, err := h.termService.CreateTerm(req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create term")
+		return
+	}
+
+	c.JSON(http.StatusCreated, term)
+}
+
+// UpdateTerm updates a term
+// @Summary Update term
+// @Description Change the name and dates of a term (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Term ID"
+// @Param term body models.TermRequest true "Term data"
+// @Success 200 {object} models.TermResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /terms/{id} [put]
+func (h *TermHandler) UpdateTerm(c *gin.Context) {
+	id, ok := parseTermID(c)
+	if !ok {
+		return
+	}
+
+	var req models.TermRequest
+	if !bindTermRequest(c, &req) {
+		return
+	}
+
+	term, err := h.termService.UpdateTerm(id, req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update term")
+		return
+	}
+
+	c.JSON(http.StatusOK, term)
+}
+
+// DeleteTerm deletes a term
+// @Summary Delete term
+// @Description Delete a term that no course is offered in (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Term ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /terms/{id} [delete]
+func (h *TermHandler) DeleteTerm(c *gin.Context) {
+	id, ok := parseTermID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.termService.DeleteTerm(id); err != nil {
+		h.handleError(c, err, "Failed to delete term")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleError maps term errors to HTTP responses
+func (h *TermHandler) handleError(c *gin.Context, err error, failure string) {
+	switch err.Error() {
+	case "term not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "Term not found",
+		})
+	case "term name is required":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Name is required",
+		})
+	case "term start and end dates are required":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Start date and end date are required",
+		})
+	case "term must end after it starts":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Term must end after it starts",
+		})
+	case "term name already exists":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "A term with this name already exists",
+		})
+	case "term has course offerings":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "Term cannot be deleted while courses are offered in it",
+		})
+	default:
+		log.Printf("%s: %v", failure, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: failure,
+		})
+	}
+}
+
+// parseTermID parses the term ID path parameter. It writes a 400 response and
+// returns false if it is invalid.
+func parseTermID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid term ID format",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bindTermRequest binds a term request body, writing a 400 response if it is malformed
+func bindTermRequest(c *gin.Context, req *models.TermRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UserHandler handles user account HTTP requests
+type UserHandler struct {
+	userService service.UserService
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(userService service.UserService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+// GetUsers retrieves every user account
+// @Summary Get users
+// @Description Get every user account sorted by username, optionally only those with a role (Super-admin only)
+// @Tags admin
+// @Produce json
+// @Param role query string false "Filter by role" Enums(super_admin, admin, instructor, user)
+// @Success 200 {object} models.UserListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users [get]
+func (h *UserHandler) GetUsers(c *gin.Context) {
+	users, err := h.userService.GetUsers(c.Query("role"))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve users")
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// CreateUser creates an admin or super-admin account
+// @Summary Create user
+// @Description Create an admin or super-admin account. With must_change_password the password is a temporary one the user has to change when they first log in. Students register themselves and instructor accounts are created from the instructor (Super-admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param user body models.CreateUserRequest true "Account details"
+// @Success 201 {object} models.UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users [post]
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var req models.CreateUserRequest
+	if !bindUserRequest(c, &req) {
+		return
+	}
+
+	user, err := h.userService.CreateUser(req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create user")
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+// UpdateUserRole changes the role of a user
+// @Summary Update user role
+// @Description Move an account between the admin and super-admin roles. The last enabled super-admin cannot be demoted (Super-admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param role body models.UpdateUserRoleRequest true "New role"
+// @Success 200 {object} models.UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/role [put]
+func (h *UserHandler) UpdateUserRole(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateUserRoleRequest
+	if !bindUserRequest(c, &req) {
+		return
+	}
+
+	user, err := h.userService.UpdateRole(id, req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update user role")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// DisableUser stops a user from logging in
+// @Summary Disable user
+// @Description Stop a user from logging in; their account and records are kept. The last enabled super-admin cannot be disabled (Super-admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} models.UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/disable [post]
+func (h *UserHandler) DisableUser(c *gin.Context) {
+	h.setDisabled(c, true)
+}
+
+// EnableUser lets a disabled user log in again
+// @Summary Enable user
+// @Description Let a disabled user log in again (Super-admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} models.UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/enable [post]
+func (h *UserHandler) EnableUser(c *gin.Context) {
+	h.setDisabled(c, false)
+}
+
+// UnlockUser lifts the login lockout of a user
+// @Summary Unlock user
+// @Description Let a user log in again straight away after failed attempts delayed or locked out their logins, and forget the failures. Lockouts of client IPs expire on their own (Super-admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} models.UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/unlock [post]
+func (h *UserHandler) UnlockUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.Unlock(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to unlock user")
+		return
+	}
+
+	log.Printf("User %s unlocked the login of %s", c.GetString("username"), user.Username)
+	c.JSON(http.StatusOK, user)
+}
+
+// IssuePasswordReset issues a password reset token for a user
+// @Summary Issue password reset
+// @Description Issue a token the user can set a new password with at /auth/reset-password, replacing any earlier one. Hand it to the user; it is only returned here and works once, until expires_at. The current password keeps working until then (Super-admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 201 {object} models.PasswordResetResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/password-reset [post]
+func (h *UserHandler) IssuePasswordReset(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	reset, err := h.userService.IssuePasswordReset(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to issue password reset")
+		return
+	}
+
+	log.Printf("User %s issued a password reset for %s, valid until %s", c.GetString("username"), reset.User.Username, reset.ExpiresAt.Format(time.RFC3339))
+	c.JSON(http.StatusCreated, reset)
+}
+
+// DeleteUser deletes a user account
+// @Summary Delete user
+// @Description Delete a user account. The student or instructor it belongs to is kept. The last enabled super-admin cannot be deleted (Super-admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id} [delete]
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.DeleteUser(id); err != nil {
+		h.handleError(c, err, "Failed to delete user")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// setDisabled disables or re-enables the user in the :id path parameter
+func (h *UserHandler) setDisabled(c *gin.Context, disabled bool) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.SetDisabled(id, disabled)
+	if err != nil {
+		h.handleError(c, err, "Failed to update user")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// handleError maps user errors to HTTP responses
+func (h *UserHandler) handleError(c *gin.Context, err error, failure string) {
+	if writePasswordPolicyError(c, err) {
+		return
+	}
+	switch err.Error() {
+	case "user not found":
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   constants.HTTPNotFound,
+			Message: "User not found",
+		})
+	case "username is required":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Username is required",
+		})
+	case "invalid role":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: fmt.Sprintf("Role must be one of: %s, %s", constants.RoleAdmin, constants.RoleSuperAdmin),
+		})
+	case "role is fixed":
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "Student and instructor accounts keep their role",
+		})
+	case "username already exists":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "An account with this username already exists",
+		})
+	case "last super admin":
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "The last enabled super-admin cannot be deleted, disabled or demoted",
+		})
+	default:
+		log.Printf("%s: %v", failure, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: failure,
+		})
+	}
+}
+
+// parseUserID parses the user ID path parameter. It writes a 400 response and
+// returns false if it is invalid.
+func parseUserID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid user ID format",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bindUserRequest binds a user request body, writing a 400 response if it is malformed
+func bindUserRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WaitlistHandler handles course waitlist HTTP requests
+type WaitlistHandler struct {
+	waitlistService service.WaitlistService
+}
+
+// NewWaitlistHandler creates a new waitlist handler
+func NewWaitlistHandler(waitlistService service.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{
+		waitlistService: waitlistService,
+	}
+}
+
+// GetWaitlist retrieves the waitlist of a course
+// @Summary Get course waitlist
+// @Description Get the ordered waitlist of a course together with its seat usage (Admin or course instructor)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Course ID"
+// @Success 200 {object} models.WaitlistResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/waitlist [get]
+func (h *WaitlistHandler) GetWaitlist(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: constants.MsgInvalidCourseIDFormat,
+		})
+		return
+	}
+
+	waitlist, err := h.waitlistService.GetWaitlist(courseID)
+	if err != nil {
+		if err.Error() == "course not found" {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   constants.HTTPNotFound,
+				Message: "Course not found",
+			})
+			return
+		}
+		log.Printf("Failed to retrieve waitlist for course %s: %v", courseID, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: "Failed to retrieve waitlist",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, waitlist)
+}
+
+// ReorderWaitlist changes the order of a course waitlist
+// @Summary Reorder course waitlist
+// @Description Replace the order of a course waitlist; every waitlisted student must be listed exactly once (Admin or course instructor)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param order body models.WaitlistReorderRequest true "New waitlist order"
+// @Success 200 {object} models.WaitlistResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/waitlist [put]
+func (h *WaitlistHandler) ReorderWaitlist(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: constants.MsgInvalidCourseIDFormat,
+		})
+		return
+	}
+
+	var req models.WaitlistReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	waitlist, err := h.waitlistService.ReorderWaitlist(courseID, req)
+	if err != nil {
+		if err.Error() == "course not found" {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   constants.HTTPNotFound,
+				Message: "Course not found",
+			})
+			return
+		}
+		if err.Error() == "waitlist order must list every waitlisted student exactly once" {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: "Waitlist order must list every waitlisted student exactly once",
+			})
+			return
+		}
+		log.Printf("Failed to reorder waitlist for course %s: %v", courseID, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: "Failed to reorder waitlist",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, waitlist)
+}
+
+// RemoveFromWaitlist removes a student from a course waitlist
+// @Summary Remove student from waitlist
+// @Description Remove a student from the waitlist of a course (Admin or course instructor)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param email path string true "Student Email"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/waitlist/{email} [delete]
+func (h *WaitlistHandler) RemoveFromWaitlist(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: constants.MsgInvalidCourseIDFormat,
+		})
+		return
+	}
+
+	studentEmail := c.Param("email")
+	if studentEmail == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   constants.HTTPBadRequest,
+			Message: "Student email is required",
+		})
+		return
+	}
+
+	err = h.waitlistService.RemoveFromWaitlist(courseID, studentEmail)
+	if err != nil {
+		if err.Error() == "course not found" {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   constants.HTTPNotFound,
+				Message: "Course not found",
+			})
+			return
+		}
+		if err.Error() == "student not on the waitlist for this course" {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   constants.HTTPNotFound,
+				Message: "Student not on the waitlist for this course",
+			})
+			return
+		}
+		log.Printf("Failed to remove %s from waitlist for course %s: %v", studentEmail, courseID, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: "Failed to remove student from waitlist",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
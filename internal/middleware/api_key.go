@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyAuthenticator looks up the API keys clients send in the X-API-Key
+// header, failing with "invalid api key" for keys that cannot be used. It is
+// implemented by service.APIKeyService.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(key string) (*models.APIKey, error)
+}
+
+// authenticateAPIKey returns the owner of an API key, restricted to its scopes
+func authenticateAPIKey(apiKeys APIKeyAuthenticator, secret string) (*principal, error) {
+	key, err := apiKeys.AuthenticateAPIKey(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	caller := &principal{
+		userID:             key.Owner.ID.String(),
+		username:           key.Owner.Username,
+		role:               key.Owner.Role,
+		apiKeyID:           key.ID.String(),
+		scopes:             key.ScopeNames(),
+		mustChangePassword: key.Owner.MustChangePassword,
+	}
+	if key.Owner.StudentID != nil {
+		caller.studentID = key.Owner.StudentID.String()
+	}
+	if key.Owner.InstructorID != nil {
+		caller.instructorID = key.Owner.InstructorID.String()
+	}
+	return caller, nil
+}
+
+// denyAPIKey writes the response for an API key that could not be authenticated
+func denyAPIKey(c *gin.Context, err error) {
+	if err.Error() == "invalid api key" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Invalid API key",
+			Message: constants.MsgAPIKeyInvalid,
+		})
+	} else {
+		log.Printf("Failed to authenticate API key: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   constants.HTTPInternalServerError,
+			Message: "Failed to validate API key",
+		})
+	}
+	c.Abort()
+}
+
+// inScope reports whether an API key with the scopes may use the permission
+func inScope(scopes []string, permission string) bool {
+	for _, scope := range scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strings"
 
@@ -16,9 +19,53 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
-// AuthMiddleware validates JWT tokens and protects routes
-func AuthMiddleware() gin.HandlerFunc {
+// TokenDenylist keeps the IDs of revoked tokens and sessions until the tokens
+// expire. It is implemented by service.RedisService and, when Redis is disabled,
+// by repository.RevokedTokenRepository.
+type TokenDenylist interface {
+	IsTokenRevoked(id string) (bool, error)
+}
+
+var (
+	// errTokenRevoked is returned by validateAccessToken for tokens on the denylist
+	errTokenRevoked = errors.New("token has been revoked")
+	// errDenylistUnavailable wraps failures to read the token denylist
+	errDenylistUnavailable = errors.New("token denylist unavailable")
+)
+
+// AuthMiddleware validates JWT access tokens and API keys and protects routes.
+// Tokens whose ID or session is on the denylist are rejected. A key in the
+// X-API-Key header is used instead of the Authorization header when both are set.
+// Users who must change their password are refused with 403.
+func AuthMiddleware(denylist TokenDenylist, apiKeys APIKeyAuthenticator) gin.HandlerFunc {
+	return authenticate(denylist, apiKeys, false)
+}
+
+// PasswordChangeAuthMiddleware is AuthMiddleware for the routes users who must
+// change their password can still use: changing it, their profile and logging out
+func PasswordChangeAuthMiddleware(denylist TokenDenylist, apiKeys APIKeyAuthenticator) gin.HandlerFunc {
+	return authenticate(denylist, apiKeys, true)
+}
+
+// authenticate validates the access token or API key of the request, letting
+// users who must change their password through only when allowPasswordChange is set
+func authenticate(denylist TokenDenylist, apiKeys APIKeyAuthenticator, allowPasswordChange bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if key := c.GetHeader(constants.HeaderAPIKey); key != "" {
+			caller, err := authenticateAPIKey(apiKeys, key)
+			if err != nil {
+				denyAPIKey(c, err)
+				return
+			}
+			if caller.mustChangePassword && !allowPasswordChange {
+				denyPasswordChange(c)
+				return
+			}
+			caller.set(c)
+			c.Next()
+			return
+		}
+
 		// Get the Authorization header
 		authHeader := c.GetHeader(constants.HeaderAuthorization)
 		if authHeader == "" {
@@ -52,26 +99,54 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		// Validate the token using auth package
-		claims, err := auth.ValidateToken(tokenString)
+		claims, err := validateAccessToken(tokenString, denylist)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, ErrorResponse{
-				Error:   "Invalid token",
-				Message: constants.MsgJWTTokenInvalid,
-			})
+			switch {
+			case errors.Is(err, errTokenRevoked):
+				c.JSON(http.StatusUnauthorized, ErrorResponse{
+					Error:   "Invalid token",
+					Message: constants.MsgJWTTokenRevoked,
+				})
+			case errors.Is(err, errDenylistUnavailable):
+				log.Printf("Failed to check token denylist: %v", err)
+				c.JSON(http.StatusInternalServerError, ErrorResponse{
+					Error:   constants.HTTPInternalServerError,
+					Message: "Failed to validate token",
+				})
+			default:
+				c.JSON(http.StatusUnauthorized, ErrorResponse{
+					Error:   "Invalid token",
+					Message: constants.MsgJWTTokenInvalid,
+				})
+			}
 			c.Abort()
 			return
 		}
 
+		caller := tokenPrincipal(claims)
+		if caller.mustChangePassword && !allowPasswordChange {
+			denyPasswordChange(c)
+			return
+		}
+
 		// Store user information in context for use in handlers
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("role", claims.Role)
+		caller.set(c)
 		c.Next()
 	}
 }
 
-// AdminMiddleware ensures the user has admin role
-func AdminMiddleware() gin.HandlerFunc {
+// denyPasswordChange writes the response for users who must change their
+// password before doing anything else
+func denyPasswordChange(c *gin.Context) {
+	c.JSON(http.StatusForbidden, ErrorResponse{
+		Error:   "Password change required",
+		Message: constants.MsgPasswordChangeRequired,
+	})
+	c.Abort()
+}
+
+// StudentMiddleware ensures the user is signed in to a student account
+func StudentMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
 		if !exists {
@@ -83,10 +158,10 @@ func AdminMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		if role != constants.RoleAdmin {
+		if role != constants.RoleUser || c.GetString("student_id") == "" {
 			c.JSON(http.StatusForbidden, ErrorResponse{
 				Error:   "Insufficient permissions",
-				Message: constants.MsgAdminAccessRequired,
+				Message: constants.MsgStudentAccountOnly,
 			})
 			c.Abort()
 			return
@@ -96,66 +171,67 @@ func AdminMiddleware() gin.HandlerFunc {
 	}
 }
 
-// AdminAuthMiddleware combines authentication and admin authorization
-func AdminAuthMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// First, validate JWT token
-		authHeader := c.GetHeader(constants.HeaderAuthorization)
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, ErrorResponse{
-				Error:   "Authorization required",
-				Message: constants.MsgAuthHeaderRequired,
-			})
-			c.Abort()
-			return
-		}
+// principal is the caller of a request: the user of an access token, or the
+// owner of an API key restricted to the key's scopes
+type principal struct {
+	userID             string
+	username           string
+	role               string
+	studentID          string
+	instructorID       string
+	sessionID          string   // empty for API keys
+	apiKeyID           string   // empty for access tokens
+	scopes             []string // the permissions an API key may use
+	mustChangePassword bool     // set while the user may only change their password
+}
 
-		// Check if the header starts with "Bearer "
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, ErrorResponse{
-				Error:   "Invalid authorization format",
-				Message: constants.MsgInvalidTokenFormat,
-			})
-			c.Abort()
-			return
-		}
+// tokenPrincipal returns the user an access token was issued to
+func tokenPrincipal(claims *auth.Claims) *principal {
+	return &principal{
+		userID:             claims.UserID,
+		username:           claims.Username,
+		role:               claims.Role,
+		studentID:          claims.StudentID,
+		instructorID:       claims.InstructorID,
+		sessionID:          claims.SessionID,
+		mustChangePassword: claims.MustChangePassword,
+	}
+}
 
-		// Extract the token
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == "" {
-			c.JSON(http.StatusUnauthorized, ErrorResponse{
-				Error:   "Token required",
-				Message: "JWT token is required",
-			})
-			c.Abort()
-			return
-		}
+// set stores the caller in the context for later middleware and handlers
+func (p *principal) set(c *gin.Context) {
+	c.Set("user_id", p.userID)
+	c.Set("username", p.username)
+	c.Set("role", p.role)
+	c.Set("student_id", p.studentID)
+	c.Set("instructor_id", p.instructorID)
+	c.Set("session_id", p.sessionID)
+	c.Set("must_change_password", p.mustChangePassword)
+	if p.apiKeyID != "" {
+		c.Set("api_key_id", p.apiKeyID)
+		c.Set("api_key_scopes", p.scopes)
+	}
+}
 
-		// Validate the token using auth package
-		claims, err := auth.ValidateToken(tokenString)
+// validateAccessToken validates an access token and checks that neither it nor
+// its session has been revoked
+func validateAccessToken(tokenString string, denylist TokenDenylist) (*auth.Claims, error) {
+	claims, err := auth.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != constants.TokenTypeAccess {
+		return nil, errors.New("not an access token")
+	}
+
+	for _, id := range []string{claims.ID, claims.SessionID} {
+		revoked, err := denylist.IsTokenRevoked(id)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, ErrorResponse{
-				Error:   "Invalid token",
-				Message: constants.MsgJWTTokenInvalid,
-			})
-			c.Abort()
-			return
+			return nil, fmt.Errorf("%w: %v", errDenylistUnavailable, err)
 		}
-
-		// Check if user is admin
-		if claims.Role != constants.RoleAdmin {
-			c.JSON(http.StatusForbidden, ErrorResponse{
-				Error:   "Insufficient permissions",
-				Message: constants.MsgAdminAccessRequired,
-			})
-			c.Abort()
-			return
+		if revoked {
+			return nil, errTokenRevoked
 		}
-
-		// Store user information in context for use in handlers
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("role", claims.Role)
-		c.Next()
 	}
+	return claims, nil
 }
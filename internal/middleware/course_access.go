@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CourseInstructorLookup reports whether an instructor teaches a course. It is
+// implemented by repository.InstructorRepository.
+type CourseInstructorLookup interface {
+	Teaches(instructorID, courseID uuid.UUID) (bool, error)
+}
+
+// EnrollmentLookup finds enrollments by ID. It is implemented by
+// repository.EnrollmentRepository.
+type EnrollmentLookup interface {
+	GetByID(id uuid.UUID) (*models.Enrollment, error)
+}
+
+// CourseAccessMiddleware restricts instructors to the courses they teach on
+// routes for the course in the :id path parameter, and must run after
+// AuthMiddleware and before RequirePermission. Instructors of the course may use
+// the permissions of their role on it; other instructors get a 403. Everyone
+// else is left to the permission check.
+func CourseAccessMiddleware(instructors CourseInstructorLookup) gin.HandlerFunc {
+	return courseAccess(instructors, func(c *gin.Context) (uuid.UUID, error) {
+		courseID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return uuid.Nil, errNoCourse
+		}
+		return courseID, nil
+	})
+}
+
+// EnrollmentCourseAccessMiddleware is CourseAccessMiddleware for routes for the
+// enrollment in the :id path parameter, which belongs to the course of the
+// enrollment. Instructors get a 403 for enrollments that do not exist.
+func EnrollmentCourseAccessMiddleware(instructors CourseInstructorLookup, enrollments EnrollmentLookup) gin.HandlerFunc {
+	return courseAccess(instructors, func(c *gin.Context) (uuid.UUID, error) {
+		enrollmentID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return uuid.Nil, errNoCourse
+		}
+		enrollment, err := enrollments.GetByID(enrollmentID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return uuid.Nil, errNoCourse
+		}
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return enrollment.CourseID, nil
+	})
+}
+
+// EnrollmentRequestCourseAccessMiddleware is CourseAccessMiddleware for routes
+// that take the course in the course_id field of the JSON request body. The body
+// is left for the handler to read.
+func EnrollmentRequestCourseAccessMiddleware(instructors CourseInstructorLookup) gin.HandlerFunc {
+	return courseAccess(instructors, func(c *gin.Context) (uuid.UUID, error) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return uuid.Nil, errNoCourse
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var req struct {
+			CourseID uuid.UUID `json:"course_id"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil || req.CourseID == uuid.Nil {
+			return uuid.Nil, errNoCourse
+		}
+		return req.CourseID, nil
+	})
+}
+
+// errNoCourse is returned by course resolvers when the request names no course
+// an instructor could teach
+var errNoCourse = errors.New("request names no course")
+
+// courseAccess restricts instructors to the course resolveCourse finds for the
+// request. Instructors get a 403 for errNoCourse and a 500 for other errors.
+func courseAccess(instructors CourseInstructorLookup, resolveCourse func(c *gin.Context) (uuid.UUID, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != constants.RoleInstructor {
+			c.Next()
+			return
+		}
+
+		instructorID, err := uuid.Parse(c.GetString("instructor_id"))
+		if err != nil {
+			denyCourseAccess(c)
+			return
+		}
+		courseID, err := resolveCourse(c)
+		if errors.Is(err, errNoCourse) {
+			denyCourseAccess(c)
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to find the course of %s for instructor %s: %v", c.Request.URL.Path, instructorID, err)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   constants.HTTPInternalServerError,
+				Message: "Failed to check course access",
+			})
+			c.Abort()
+			return
+		}
+
+		teaches, err := instructors.Teaches(instructorID, courseID)
+		if err != nil {
+			log.Printf("Failed to check instructor %s of course %s: %v", instructorID, courseID, err)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   constants.HTTPInternalServerError,
+				Message: "Failed to check course access",
+			})
+			c.Abort()
+			return
+		}
+		if !teaches {
+			denyCourseAccess(c)
+			return
+		}
+
+		c.Set("teaches_course", true)
+		c.Next()
+	}
+}
+
+// denyCourseAccess writes the response for callers that may not manage a course
+func denyCourseAccess(c *gin.Context) {
+	c.JSON(http.StatusForbidden, ErrorResponse{
+		Error:   "Insufficient permissions",
+		Message: constants.MsgCourseAccessRequired,
+	})
+	c.Abort()
+}
@@ -0,0 +1,232 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyStore keeps the requests made with an Idempotency-Key header.
+// It is implemented by service.RedisService and, when Redis is disabled, by
+// repository.IdempotencyRepository.
+type IdempotencyStore interface {
+	// ReserveIdempotencyKey claims record.Key for a new request and returns nil,
+	// or returns the stored record if the key is already taken
+	ReserveIdempotencyKey(record *models.IdempotencyRecord) (*models.IdempotencyRecord, error)
+	// CompleteIdempotencyKey stores the response produced for a reserved key
+	CompleteIdempotencyKey(record *models.IdempotencyRecord) error
+	// ReleaseIdempotencyKey forgets a reserved key so the request can be retried
+	ReleaseIdempotencyKey(key string) error
+}
+
+// IdempotencyMiddleware makes mutating requests that carry an Idempotency-Key
+// header safe to retry. The first request with a key runs normally and its
+// response is stored; a retry with the same key and payload replays that
+// response without running the handler again. Reusing a key for a different
+// payload is rejected with 422, and a retry that arrives while the original is
+// still running gets 409. Server errors are not stored, so they can be retried,
+// and neither are rejections by later middleware, such as a failed permission
+// check, so the request can be retried once the caller may make it. Bodies of
+// requests with a key are limited to constants.MaxIdempotentBodySize. Keys are
+// scoped to the authenticated user, so it must run after authentication.
+func IdempotencyMiddleware(store IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(constants.HeaderIdempotencyKey)
+		if key == "" || !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		if len(key) > constants.MaxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid Idempotency-Key",
+				Message: "Idempotency-Key must be at most 255 characters",
+			})
+			c.Abort()
+			return
+		}
+
+		// The body is read before any handler can limit it, so limit it here
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constants.MaxIdempotentBodySize)
+		body, err := io.ReadAll(c.Request.Body)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+				Error:   "Request body too large",
+				Message: "Requests with an Idempotency-Key must be at most 10MB",
+			})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request body",
+				Message: "Unable to read request body",
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := &models.IdempotencyRecord{
+			Key:         c.GetString("username") + ":" + key,
+			Fingerprint: requestFingerprint(c.Request, body),
+			ExpiresAt:   time.Now().Add(constants.IdempotencyLockTTL),
+		}
+
+		existing, err := store.ReserveIdempotencyKey(record)
+		if err != nil {
+			log.Printf("Failed to reserve idempotency key %s: %v", record.Key, err)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   constants.HTTPInternalServerError,
+				Message: "Failed to process Idempotency-Key",
+			})
+			c.Abort()
+			return
+		}
+		if existing != nil {
+			replayIdempotentResponse(c, record, existing)
+			return
+		}
+
+		// Give the key back if the handler panics, so the request can be retried
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				releaseIdempotencyKey(store, record.Key)
+				panic(recovered)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// Handlers never abort, so an aborted request was rejected by middleware
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError || c.IsAborted() {
+			releaseIdempotencyKey(store, record.Key)
+			return
+		}
+
+		record.StatusCode = status
+		record.ContentType = recorder.Header().Get(constants.HeaderContentType)
+		record.ResponseBody = recorder.body.String()
+		record.ExpiresAt = time.Now().Add(constants.IdempotencyKeyTTL)
+		if err := store.CompleteIdempotencyKey(record); err != nil {
+			log.Printf("Failed to store response for idempotency key %s: %v", record.Key, err)
+			releaseIdempotencyKey(store, record.Key)
+		}
+	}
+}
+
+// replayIdempotentResponse answers a request whose key is already taken
+func replayIdempotentResponse(c *gin.Context, record, existing *models.IdempotencyRecord) {
+	defer c.Abort()
+
+	if existing.Fingerprint != record.Fingerprint {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "Idempotency key reused",
+			Message: "Idempotency-Key was already used for a different request",
+		})
+		return
+	}
+
+	if existing.InProgress() {
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   constants.HTTPConflict,
+			Message: "A request with this Idempotency-Key is still being processed",
+		})
+		return
+	}
+
+	c.Header(constants.HeaderIdempotencyReplayed, "true")
+	c.Data(existing.StatusCode, existing.ContentType, []byte(existing.ResponseBody))
+}
+
+// releaseIdempotencyKey releases a key, logging rather than failing the request
+func releaseIdempotencyKey(store IdempotencyStore, key string) {
+	if err := store.ReleaseIdempotencyKey(key); err != nil {
+		log.Printf("Failed to release idempotency key %s: %v", key, err)
+	}
+}
+
+// requestFingerprint identifies a request by its method, path, query and body.
+// Multipart bodies are identified by their fields instead, because clients pick
+// a new random boundary for every request, including retries.
+func requestFingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	if !writeMultipartFingerprint(hash, req.Header.Get(constants.HeaderContentType), body) {
+		hash.Write(body)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// writeMultipartFingerprint writes the name, file name and content hash of each
+// part of a multipart body to hash. It reports false, having written nothing,
+// if the body is not a well-formed multipart body.
+func writeMultipartFingerprint(hash io.Writer, contentType string, body []byte) bool {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return false
+	}
+
+	var parts bytes.Buffer
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false
+		}
+		content := sha256.New()
+		if _, err := io.Copy(content, part); err != nil {
+			return false
+		}
+		fmt.Fprintf(&parts, "%q %q %x\n", part.FormName(), part.FileName(), content.Sum(nil))
+	}
+
+	hash.Write(parts.Bytes())
+	return true
+}
+
+// isMutatingMethod reports whether requests with the method change server state
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// responseRecorder copies everything written to the response so it can be stored
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionChecker reports whether a role holds a permission. It is
+// implemented by service.PermissionService.
+type PermissionChecker interface {
+	HasPermission(role, permission string) (bool, error)
+}
+
+// RequirePermission allows the request only when the role of the signed-in user
+// holds the permission, and must run after AuthMiddleware. Callers without a
+// role get a 401 and callers without the permission a 403.
+func RequirePermission(checker PermissionChecker, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		if role == "" {
+			c.JSON(http.StatusUnauthorized, ErrorResponse{
+				Error:   "Authentication required",
+				Message: "User role not found in context",
+			})
+			c.Abort()
+			return
+		}
+
+		allowed, err := holdsPermission(c, checker, role, permission)
+		if err != nil {
+			log.Printf("Failed to check permission %s of role %s: %v", permission, role, err)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   constants.HTTPInternalServerError,
+				Message: "Failed to check permissions",
+			})
+			c.Abort()
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   "Insufficient permissions",
+				Message: fmt.Sprintf(constants.MsgPermissionRequired, permission),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// holdsPermission reports whether the role holds the permission for this
+// request. Instructors only use their permissions on the courses they teach,
+// which CourseAccessMiddleware checks, so they hold none anywhere else. API
+// keys only use the permissions in their scopes that their owner still holds.
+func holdsPermission(c *gin.Context, checker PermissionChecker, role, permission string) (bool, error) {
+	if role == constants.RoleInstructor && !c.GetBool("teaches_course") {
+		return false, nil
+	}
+	if scopes, isAPIKey := c.Get("api_key_scopes"); isAPIKey && !inScope(scopes.([]string), permission) {
+		return false, nil
+	}
+	return checker.HasPermission(role, permission)
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimiter counts requests per key within a time window. It is implemented by
+// service.RedisService and, when Redis is disabled, by MemoryRateLimiter.
+type RateLimiter interface {
+	// CheckRateLimit counts a request for key and reports whether it is within
+	// limit requests per window
+	CheckRateLimit(key string, limit int, window time.Duration) (bool, error)
+}
+
+// RateLimitMiddleware rejects clients that make more than limit requests per
+// window with 429. Clients are told apart by IP address, counted separately for
+// every scope. When the limiter fails the request is let through.
+func RateLimitMiddleware(limiter RateLimiter, scope string, limit int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, err := limiter.CheckRateLimit(scope+":"+c.ClientIP(), limit, window)
+		if err != nil {
+			log.Printf("Failed to check rate limit for %s: %v", c.ClientIP(), err)
+			c.Next()
+			return
+		}
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(window.Seconds())))
+			c.JSON(http.StatusTooManyRequests, ErrorResponse{
+				Error:   "Too many requests",
+				Message: "Rate limit exceeded, please try again later",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// MemoryRateLimiter is a RateLimiter that keeps fixed-window counters in memory.
+// Each server instance counts on its own.
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	windows   map[string]*rateWindow
+	nextSweep time.Time
+}
+
+// rateWindow is the request count of a key in the window that ends at resetAt
+type rateWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// NewMemoryRateLimiter creates an in-memory rate limiter
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{windows: make(map[string]*rateWindow)}
+}
+
+// CheckRateLimit counts a request for key and reports whether it is within limit
+// requests per window
+func (l *MemoryRateLimiter) CheckRateLimit(key string, limit int, window time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.After(l.nextSweep) {
+		// Forget the clients whose window has passed
+		for k, w := range l.windows {
+			if !now.Before(w.resetAt) {
+				delete(l.windows, k)
+			}
+		}
+		l.nextSweep = now.Add(window)
+	}
+
+	w, ok := l.windows[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = &rateWindow{resetAt: now.Add(window)}
+		l.windows[key] = w
+	}
+	if w.count >= limit {
+		return false, nil
+	}
+	w.count++
+	return true, nil
+}
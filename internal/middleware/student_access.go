@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/auth"
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StudentLookup finds students by ID. It is implemented by
+// repository.StudentRepository.
+type StudentLookup interface {
+	GetByID(id uuid.UUID) (*models.Student, error)
+}
+
+// StudentAccessMiddleware protects routes that expose the records of the student
+// in the :email path parameter. The caller must hold the student:read
+// permission, be signed in as that student, or present a share link an admin
+// generated for the email, as the expires and signature query parameters.
+// Everyone else gets the same 403, whether or not a student with the email exists.
+func StudentAccessMiddleware(students StudentLookup, checker PermissionChecker, denylist TokenDenylist, apiKeys APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		email := models.NormalizeEmail(c.Param("email"))
+
+		if signature := c.Query("signature"); signature != "" {
+			if auth.VerifyShareLink(email, c.Query("expires"), signature, time.Now()) {
+				c.Next()
+				return
+			}
+			denyStudentAccess(c)
+			return
+		}
+
+		caller := studentRecordsCaller(c, denylist, apiKeys)
+		if caller == nil {
+			denyStudentAccess(c)
+			return
+		}
+		if caller.mustChangePassword {
+			denyPasswordChange(c)
+			return
+		}
+		// Stored first so that the permission check sees the scopes of API keys
+		caller.set(c)
+
+		allowed, err := holdsPermission(c, checker, caller.role, constants.PermissionStudentRead)
+		if err != nil {
+			log.Printf("Failed to check permission %s of role %s: %v", constants.PermissionStudentRead, caller.role, err)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   constants.HTTPInternalServerError,
+				Message: "Failed to check permissions",
+			})
+			c.Abort()
+			return
+		}
+
+		if allowed || ownsEmail(students, caller, email) {
+			c.Next()
+			return
+		}
+		denyStudentAccess(c)
+	}
+}
+
+// studentRecordsCaller returns the caller of the API key or access token of
+// the request, or nil when it has neither or they are not valid
+func studentRecordsCaller(c *gin.Context, denylist TokenDenylist, apiKeys APIKeyAuthenticator) *principal {
+	if key := c.GetHeader(constants.HeaderAPIKey); key != "" {
+		caller, err := authenticateAPIKey(apiKeys, key)
+		if err != nil {
+			return nil
+		}
+		return caller
+	}
+
+	authHeader := c.GetHeader(constants.HeaderAuthorization)
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return nil
+	}
+	claims, err := validateAccessToken(strings.TrimPrefix(authHeader, "Bearer "), denylist)
+	if err != nil {
+		return nil
+	}
+	return tokenPrincipal(claims)
+}
+
+// ownsEmail reports whether the caller is the student account of the student
+// with the email. The student is looked up because an admin may have changed
+// the email since the token was issued.
+func ownsEmail(students StudentLookup, caller *principal, email string) bool {
+	if caller.role != constants.RoleUser {
+		return false
+	}
+	studentID, err := uuid.Parse(caller.studentID)
+	if err != nil {
+		return false
+	}
+	student, err := students.GetByID(studentID)
+	if err != nil {
+		return false
+	}
+	return student.Email == email
+}
+
+// denyStudentAccess writes the response for callers that may not see a student's records
+func denyStudentAccess(c *gin.Context) {
+	c.JSON(http.StatusForbidden, ErrorResponse{
+		Error:   "Access denied",
+		Message: constants.MsgStudentRecordsAccess,
+	})
+	c.Abort()
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKey lets another system call the API for its owner, restricted to its
+// scopes. Only the SHA-256 hash of the key is stored; the key itself is shown
+// once, when it is created.
+type APIKey struct {
+	ID         uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name       string        `json:"name" gorm:"not null;size:255"`
+	Prefix     string        `json:"prefix" gorm:"not null;size:16"` // leading characters of the key
+	KeyHash    string        `json:"-" gorm:"not null;size:64;uniqueIndex"`
+	OwnerID    uuid.UUID     `json:"owner_id" gorm:"type:uuid;not null;index"`
+	Owner      *User         `json:"-" gorm:"foreignKey:OwnerID"`
+	Scopes     []APIKeyScope `json:"-" gorm:"foreignKey:APIKeyID;constraint:OnDelete:CASCADE"`
+	ExpiresAt  *time.Time    `json:"expires_at,omitempty"` // nil for keys that never expire
+	LastUsedAt *time.Time    `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time     `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for APIKey model
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeNames returns the permissions the key may use
+func (k *APIKey) ScopeNames() []string {
+	scopes := make([]string, len(k.Scopes))
+	for i, scope := range k.Scopes {
+		scopes[i] = scope.Permission
+	}
+	return scopes
+}
+
+// ToResponse converts APIKey model to APIKeyResponse
+func (k *APIKey) ToResponse() APIKeyResponse {
+	response := APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		OwnerID:    k.OwnerID,
+		Scopes:     k.ScopeNames(),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+	if k.Owner != nil {
+		response.OwnerUsername = k.Owner.Username
+	}
+	return response
+}
+
+// APIKeyScope is a permission an API key may use
+type APIKeyScope struct {
+	APIKeyID   uuid.UUID `json:"api_key_id" gorm:"type:uuid;primaryKey"`
+	Permission string    `json:"permission" gorm:"primaryKey;size:100"`
+}
+
+// TableName returns the table name for APIKeyScope model
+func (APIKeyScope) TableName() string {
+	return "api_key_scopes"
+}
+
+// CreateAPIKeyRequest represents the request payload for creating an API key.
+// Without an owner the key belongs to the admin creating it.
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=255" example:"Nightly enrollment sync"`
+	OwnerID   *uuid.UUID `json:"owner_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1" example:"enrollment:write"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2027-01-01T00:00:00Z"`
+}
+
+// APIKeyResponse represents an API key without the key itself
+type APIKeyResponse struct {
+	ID            uuid.UUID  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name          string     `json:"name" example:"Nightly enrollment sync"`
+	Prefix        string     `json:"prefix" example:"cek_Q2x5bk9w"`
+	OwnerID       uuid.UUID  `json:"owner_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OwnerUsername string     `json:"owner_username" example:"registrar"`
+	Scopes        []string   `json:"scopes" example:"enrollment:write"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty" example:"2027-01-01T00:00:00Z"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty" example:"2026-10-01T02:00:00Z"`
+	CreatedAt     time.Time  `json:"created_at" example:"2026-09-01T00:00:00Z"`
+}
+
+// CreateAPIKeyResponse represents a new API key, including the key itself,
+// which cannot be retrieved again
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key" example:"cek_Q2x5bk9wZXJhdGlvbnMtc3luYy1rZXktZXhhbXBsZQ"`
+}
+
+// APIKeyListResponse represents a list of API keys
+type APIKeyListResponse struct {
+	APIKeys []APIKeyResponse `json:"api_keys"`
+	Total   int              `json:"total" example:"2"`
+}
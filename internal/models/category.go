@@ -0,0 +1,173 @@
+package models
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Category represents a course category. Categories form a tree through ParentID,
+// e.g. Programming > Go.
+type Category struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name        string     `json:"name" gorm:"not null;size:100" example:"Programming"`
+	Slug        string     `json:"slug" gorm:"not null;size:100;uniqueIndex" example:"programming"` // unique across the tree, used in course filters
+	Description *string    `json:"description,omitempty" gorm:"type:text" example:"Software development courses"`
+	ParentID    *uuid.UUID `json:"parent_id,omitempty" gorm:"type:uuid;index" example:"123e4567-e89b-12d3-a456-426614174000"` // nil for a top-level category
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (c *Category) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for Category model
+func (Category) TableName() string {
+	return "categories"
+}
+
+// Tag represents a free-form course label. Names are stored normalized.
+type Tag struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name      string    `json:"name" gorm:"not null;size:50;uniqueIndex" example:"backend"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	t.Name = NormalizeTag(t.Name)
+	return nil
+}
+
+// TableName returns the table name for Tag model
+func (Tag) TableName() string {
+	return "tags"
+}
+
+var slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slugify turns a category name into a slug: lower case letters and digits
+// separated by single dashes
+func Slugify(name string) string {
+	return strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// NormalizeTag returns the form in which tag names are stored and compared:
+// trimmed, lower case and with inner whitespace collapsed
+func NormalizeTag(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// CategoryRequest represents the request payload for creating or updating a
+// category. The slug is derived from the name when omitted.
+type CategoryRequest struct {
+	Name        string     `json:"name" validate:"required,max=100" example:"Programming"`
+	Slug        string     `json:"slug,omitempty" validate:"omitempty,max=100" example:"programming"`
+	Description *string    `json:"description,omitempty" example:"Software development courses"`
+	ParentID    *uuid.UUID `json:"parent_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// CategoryResponse represents a category with its subcategories
+type CategoryResponse struct {
+	ID          uuid.UUID          `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name        string             `json:"name" example:"Programming"`
+	Slug        string             `json:"slug" example:"programming"`
+	Description *string            `json:"description,omitempty" example:"Software development courses"`
+	ParentID    *uuid.UUID         `json:"parent_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CourseCount int                `json:"course_count" example:"4"` // courses in this category itself, not its subcategories
+	Children    []CategoryResponse `json:"children"`
+	CreatedAt   time.Time          `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// CategoryListResponse represents the category tree, top-level categories first
+type CategoryListResponse struct {
+	Categories []CategoryResponse `json:"categories"`
+	Total      int                `json:"total" example:"12"` // number of categories at every level
+}
+
+// CategorySummary represents a category a course is in
+type CategorySummary struct {
+	ID       uuid.UUID  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name     string     `json:"name" example:"Go"`
+	Slug     string     `json:"slug" example:"go"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ToSummary converts Category model to CategorySummary
+func (c *Category) ToSummary() CategorySummary {
+	return CategorySummary{
+		ID:       c.ID,
+		Name:     c.Name,
+		Slug:     c.Slug,
+		ParentID: c.ParentID,
+	}
+}
+
+// BuildCategoryTree arranges categories under their parents, each level sorted
+// by name. courseCounts holds the number of courses per category.
+func BuildCategoryTree(categories []Category, courseCounts map[uuid.UUID]int) []CategoryResponse {
+	children := make(map[uuid.UUID][]Category)
+	known := make(map[uuid.UUID]bool, len(categories))
+	for _, category := range categories {
+		known[category.ID] = true
+	}
+	var roots []Category
+	for _, category := range categories {
+		if category.ParentID == nil || !known[*category.ParentID] {
+			roots = append(roots, category)
+			continue
+		}
+		children[*category.ParentID] = append(children[*category.ParentID], category)
+	}
+
+	var build func(level []Category) []CategoryResponse
+	build = func(level []Category) []CategoryResponse {
+		sort.Slice(level, func(i, j int) bool { return level[i].Name < level[j].Name })
+		responses := make([]CategoryResponse, len(level))
+		for i, category := range level {
+			responses[i] = CategoryResponse{
+				ID:          category.ID,
+				Name:        category.Name,
+				Slug:        category.Slug,
+				Description: category.Description,
+				ParentID:    category.ParentID,
+				CourseCount: courseCounts[category.ID],
+				Children:    build(children[category.ID]),
+				CreatedAt:   category.CreatedAt,
+			}
+		}
+		return responses
+	}
+	return build(roots)
+}
+
+// TagRequest represents the request payload for creating or renaming a tag
+type TagRequest struct {
+	Name string `json:"name" validate:"required,max=50" example:"backend"`
+}
+
+// TagResponse represents a tag with the number of courses that have it
+type TagResponse struct {
+	ID          uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name        string    `json:"name" example:"backend"`
+	CourseCount int       `json:"course_count" example:"3"`
+	CreatedAt   time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// TagListResponse represents every tag, sorted by name
+type TagListResponse struct {
+	Tags  []TagResponse `json:"tags"`
+	Total int           `json:"total" example:"8"`
+}
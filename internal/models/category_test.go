@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "go-concurrency", Slugify("Go & Concurrency"))
+	assert.Equal(t, "web-design-101", Slugify("  Web Design 101! "))
+	assert.Equal(t, "", Slugify("!!!"))
+}
+
+func TestNormalizeTag(t *testing.T) {
+	assert.Equal(t, "machine learning", NormalizeTag("  Machine   Learning "))
+	assert.Equal(t, "", NormalizeTag("   "))
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	programming := Category{ID: uuid.New(), Name: "Programming"}
+	golang := Category{ID: uuid.New(), Name: "Go", ParentID: &programming.ID}
+	algorithms := Category{ID: uuid.New(), Name: "Algorithms", ParentID: &programming.ID}
+	design := Category{ID: uuid.New(), Name: "Design"}
+
+	tree := BuildCategoryTree([]Category{golang, programming, design, algorithms}, map[uuid.UUID]int{golang.ID: 2})
+
+	require.Len(t, tree, 2)
+	assert.Equal(t, "Design", tree[0].Name)
+	assert.Empty(t, tree[0].Children)
+	assert.Equal(t, "Programming", tree[1].Name)
+	require.Len(t, tree[1].Children, 2)
+	assert.Equal(t, "Algorithms", tree[1].Children[0].Name)
+	assert.Equal(t, "Go", tree[1].Children[1].Name)
+	assert.Equal(t, 2, tree[1].Children[1].CourseCount)
+	assert.Equal(t, 0, tree[1].CourseCount)
+}
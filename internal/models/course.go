@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	"sonic-labs/course-enrollment-service/internal/constants"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -12,13 +14,21 @@ type Course struct {
 	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
 	Title       string    `json:"title" gorm:"not null;size:255" validate:"required,min=1,max=255" example:"Introduction to Go Programming"`
 	Description string    `json:"description" gorm:"not null;type:text" validate:"required,min=1" example:"Learn the fundamentals of Go programming language"`
-	Difficulty  string    `json:"difficulty" gorm:"not null;size:50" validate:"required,oneof=Beginner Intermediate Advanced" example:"Beginner"`
+	Difficulty  string    `json:"difficulty" gorm:"not null;size:50" validate:"required,max=50" example:"Beginner"` // name of a DifficultyLevel
 	ImageURL    *string   `json:"image_url,omitempty" gorm:"size:500" validate:"omitempty,url" example:"https://your-s3-bucket.s3.amazonaws.com/course-images/go-programming.jpg"`
-	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
-	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+	Capacity    *int      `json:"capacity,omitempty" validate:"omitempty,min=0" example:"30"` // nil means unlimited seats
+	// Enrollment window; a nil bound leaves that side of the window open
+	EnrollmentOpensAt  *time.Time `json:"enrollment_opens_at,omitempty" example:"2023-01-01T00:00:00Z"`
+	EnrollmentClosesAt *time.Time `json:"enrollment_closes_at,omitempty" example:"2023-02-01T00:00:00Z"`
+	CreatedAt          time.Time  `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt          time.Time  `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
 
 	// Relationships
-	Enrollments []Enrollment `json:"enrollments,omitempty" gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
+	Enrollments []Enrollment    `json:"enrollments,omitempty" gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
+	Waitlist    []WaitlistEntry `json:"waitlist,omitempty" gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
+	Categories  []Category      `json:"categories,omitempty" gorm:"many2many:course_categories"`
+	Tags        []Tag           `json:"tags,omitempty" gorm:"many2many:course_tags"`
+	Instructors []Instructor    `json:"instructors,omitempty" gorm:"many2many:course_instructors"`
 }
 
 // BeforeCreate will set a UUID rather than numeric ID
@@ -34,12 +44,32 @@ func (Course) TableName() string {
 	return "courses"
 }
 
+// EnrollmentWindowState reports whether enrollment in the course has not opened yet,
+// is open or has closed at the given time
+func (c *Course) EnrollmentWindowState(now time.Time) string {
+	if c.EnrollmentOpensAt != nil && now.Before(*c.EnrollmentOpensAt) {
+		return constants.EnrollmentWindowNotOpen
+	}
+	if c.EnrollmentClosesAt != nil && !now.Before(*c.EnrollmentClosesAt) {
+		return constants.EnrollmentWindowClosed
+	}
+	return constants.EnrollmentWindowOpen
+}
+
 // CourseRequest represents the request payload for creating/updating a course
 type CourseRequest struct {
 	Title       string  `json:"title" validate:"required,min=1,max=255" example:"Introduction to Go Programming"`
 	Description string  `json:"description" validate:"required,min=1" example:"Learn the fundamentals of Go programming language"`
-	Difficulty  string  `json:"difficulty" validate:"required,oneof=Beginner Intermediate Advanced" example:"Beginner"`
+	Difficulty  string  `json:"difficulty" validate:"required,max=50" example:"Beginner"`
 	ImageURL    *string `json:"image_url,omitempty" validate:"omitempty,url" example:"https://your-s3-bucket.s3.amazonaws.com/course-images/go-programming.jpg"`
+	Capacity    *int    `json:"capacity,omitempty" validate:"omitempty,min=0" example:"30"`
+	// Enrollment window; omit a bound to leave that side of the window open
+	EnrollmentOpensAt  *time.Time `json:"enrollment_opens_at,omitempty" example:"2023-01-01T00:00:00Z"`
+	EnrollmentClosesAt *time.Time `json:"enrollment_closes_at,omitempty" validate:"omitempty,gtfield=EnrollmentOpensAt" example:"2023-02-01T00:00:00Z"`
+	// Categories and tags of the course; omit to keep the current ones on update,
+	// or pass an empty list to clear them. Unknown tags are created.
+	CategoryIDs []uuid.UUID `json:"category_ids" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Tags        []string    `json:"tags" validate:"omitempty,dive,max=50" example:"backend,concurrency"`
 }
 
 // CourseResponse represents the response payload for course operations
@@ -49,7 +79,16 @@ type CourseResponse struct {
 	Description string    `json:"description" example:"Learn the fundamentals of Go programming language"`
 	Difficulty  string    `json:"difficulty" example:"Beginner"`
 	ImageURL    *string   `json:"image_url,omitempty" example:"https://your-s3-bucket.s3.amazonaws.com/course-images/go-programming.jpg"`
-	CreatedAt   time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Capacity    *int      `json:"capacity,omitempty" example:"30"`
+	// Enrollment window; a missing bound leaves that side of the window open
+	EnrollmentOpensAt  *time.Time          `json:"enrollment_opens_at,omitempty" example:"2023-01-01T00:00:00Z"`
+	EnrollmentClosesAt *time.Time          `json:"enrollment_closes_at,omitempty" example:"2023-02-01T00:00:00Z"`
+	CreatedAt          time.Time           `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Categories         []CategorySummary   `json:"categories,omitempty"`
+	Tags               []string            `json:"tags,omitempty" example:"backend,concurrency"`
+	Instructors        []InstructorSummary `json:"instructors,omitempty"`
+	// Modules and lessons, only included when requested with ?include=outline
+	Outline *CourseOutline `json:"outline,omitempty"`
 }
 
 // CourseQueryParams represents query parameters for course listing
@@ -58,6 +97,21 @@ type CourseQueryParams struct {
 	Limit      int      `form:"limit" json:"limit" example:"10"`
 	Search     string   `form:"search" json:"search" example:"golang"`
 	Difficulty []string `form:"difficulty" json:"difficulty" example:"Beginner,Intermediate"`
+	// OpenForEnrollment limits results to courses whose enrollment window is open now
+	OpenForEnrollment bool `form:"open_for_enrollment" json:"open_for_enrollment" example:"true"`
+	// TermID limits results to courses offered in the term
+	TermID *uuid.UUID `form:"term_id" json:"term_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Category limits results to courses in any of the categories, given by slug,
+	// or their subcategories
+	Category []string `form:"category" json:"category" example:"programming"`
+	// Tag limits results to courses with any of the tags
+	Tag []string `form:"tag" json:"tag" example:"backend"`
+	// InstructorID limits results to courses the instructor teaches
+	InstructorID *uuid.UUID `form:"instructor_id" json:"instructor_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// Sort orders results by created_at, title or difficulty, descending with a
+	// leading "-". Difficulty follows the configured order of the levels. The
+	// default is -created_at, newest first.
+	Sort string `form:"sort" json:"sort" example:"difficulty"`
 }
 
 // PaginationMeta represents pagination metadata
@@ -78,27 +132,28 @@ type CourseListResponse struct {
 
 // ToResponse converts Course model to CourseResponse
 func (c *Course) ToResponse() CourseResponse {
-	return CourseResponse{
+	response := CourseResponse{
 		ID:          c.ID,
 		Title:       c.Title,
 		Description: c.Description,
 		Difficulty:  c.Difficulty,
 		ImageURL:    c.ImageURL,
-		CreatedAt:   c.CreatedAt,
-	}
-}
+		Capacity:    c.Capacity,
 
-// StudentResponse represents a student with their enrollment count
-type StudentResponse struct {
-	Email           string `json:"email" example:"student@example.com"`
-	EnrollmentCount int    `json:"enrollment_count" example:"3"`
-	LastEnrolledAt  string `json:"last_enrolled_at,omitempty" example:"2023-01-01T00:00:00Z"`
-}
-
-// AllStudentsResponse represents the response for all students
-type AllStudentsResponse struct {
-	Students []StudentResponse `json:"students"`
-	Total    int               `json:"total"`
+		EnrollmentOpensAt:  c.EnrollmentOpensAt,
+		EnrollmentClosesAt: c.EnrollmentClosesAt,
+		CreatedAt:          c.CreatedAt,
+	}
+	for _, category := range c.Categories {
+		response.Categories = append(response.Categories, category.ToSummary())
+	}
+	for _, tag := range c.Tags {
+		response.Tags = append(response.Tags, tag.Name)
+	}
+	for _, instructor := range c.Instructors {
+		response.Instructors = append(response.Instructors, instructor.ToSummary())
+	}
+	return response
 }
 
 // AllEnrollmentsResponse represents the response for all enrollments
@@ -110,7 +165,11 @@ type AllEnrollmentsResponse struct {
 // EnrollmentWithCourse represents an enrollment with course details
 type EnrollmentWithCourse struct {
 	ID           uuid.UUID      `json:"id"`
+	StudentID    uuid.UUID      `json:"student_id"`
 	StudentEmail string         `json:"student_email"`
 	Course       CourseResponse `json:"course"`
+	OfferingID   uuid.UUID      `json:"offering_id"`
+	Term         *TermResponse  `json:"term,omitempty"`
 	EnrolledAt   time.Time      `json:"enrolled_at"`
+	Status       string         `json:"status"`
 }
@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"sonic-labs/course-enrollment-service/internal/constants"
+
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
@@ -47,6 +49,32 @@ func TestCourse_ToResponse(t *testing.T) {
 	assert.Equal(t, createdAt, response.CreatedAt)
 }
 
+func TestCourse_EnrollmentWindowState(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		opensAt  *time.Time
+		closesAt *time.Time
+		expected string
+	}{
+		{"no window", nil, nil, constants.EnrollmentWindowOpen},
+		{"inside window", &past, &future, constants.EnrollmentWindowOpen},
+		{"not open yet", &future, nil, constants.EnrollmentWindowNotOpen},
+		{"closed", nil, &past, constants.EnrollmentWindowClosed},
+		{"closes exactly now", nil, &now, constants.EnrollmentWindowClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			course := Course{EnrollmentOpensAt: tt.opensAt, EnrollmentClosesAt: tt.closesAt}
+			assert.Equal(t, tt.expected, course.EnrollmentWindowState(now))
+		})
+	}
+}
+
 func TestCourseRequest_Validation(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DifficultyLevel represents a difficulty level courses can have, such as
+// Beginner. Levels are ordered from easiest to hardest by SortOrder.
+type DifficultyLevel struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name        string    `json:"name" gorm:"not null;size:50;uniqueIndex" example:"Beginner"`
+	SortOrder   int       `json:"sort_order" gorm:"not null;default:0" example:"1"`
+	Description *string   `json:"description,omitempty" gorm:"type:text" example:"No prior knowledge of the subject needed"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (d *DifficultyLevel) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for DifficultyLevel model
+func (DifficultyLevel) TableName() string {
+	return "difficulty_levels"
+}
+
+// DifficultyLevelRequest represents the request payload for creating or updating
+// a difficulty level. Without a sort order a new level goes after the hardest one
+// and an updated level keeps its place.
+type DifficultyLevelRequest struct {
+	Name        string  `json:"name" validate:"required,max=50" example:"Expert"`
+	SortOrder   *int    `json:"sort_order,omitempty" example:"4"`
+	Description *string `json:"description,omitempty" example:"For students who want to master the subject"`
+}
+
+// DifficultyLevelResponse represents a difficulty level with the number of
+// courses that have it
+type DifficultyLevelResponse struct {
+	ID          uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name        string    `json:"name" example:"Beginner"`
+	SortOrder   int       `json:"sort_order" example:"1"`
+	Description *string   `json:"description,omitempty" example:"No prior knowledge of the subject needed"`
+	CourseCount int       `json:"course_count" example:"5"`
+	CreatedAt   time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// ToResponse converts DifficultyLevel model to DifficultyLevelResponse
+func (d *DifficultyLevel) ToResponse(courseCount int) DifficultyLevelResponse {
+	return DifficultyLevelResponse{
+		ID:          d.ID,
+		Name:        d.Name,
+		SortOrder:   d.SortOrder,
+		Description: d.Description,
+		CourseCount: courseCount,
+		CreatedAt:   d.CreatedAt,
+	}
+}
+
+// DifficultyLevelListResponse represents every difficulty level, easiest first
+type DifficultyLevelListResponse struct {
+	Levels []DifficultyLevelResponse `json:"levels"`
+	Total  int                       `json:"total" example:"3"`
+}
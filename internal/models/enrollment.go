@@ -3,21 +3,33 @@ package models
 import (
 	"time"
 
+	"sonic-labs/course-enrollment-service/internal/constants"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 // Enrollment represents a student enrollment in a course
 type Enrollment struct {
-	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
-	StudentEmail string    `json:"student_email" gorm:"not null;size:255;index:idx_student_course,unique" validate:"required,email" example:"student@example.com"`
-	CourseID     uuid.UUID `json:"course_id" gorm:"type:uuid;not null;index:idx_student_course,unique" example:"123e4567-e89b-12d3-a456-426614174000"`
-	EnrolledAt   time.Time `json:"enrolled_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
-	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
-	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	StudentID       uuid.UUID  `json:"student_id" gorm:"type:uuid;not null;index" example:"123e4567-e89b-12d3-a456-426614174000"`
+	StudentEmail    string     `json:"student_email" gorm:"not null;size:255;index:idx_student_course,unique" validate:"required,email" example:"student@example.com"` // copy of the student's email
+	CourseID        uuid.UUID  `json:"course_id" gorm:"type:uuid;not null;index:idx_student_course,unique" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OfferingID      uuid.UUID  `json:"offering_id" gorm:"type:uuid;not null;index" example:"123e4567-e89b-12d3-a456-426614174000"`
+	SectionID       *uuid.UUID `json:"section_id,omitempty" gorm:"type:uuid;index" example:"123e4567-e89b-12d3-a456-426614174000"` // nil when the student is not in a section
+	EnrolledAt      time.Time  `json:"enrolled_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	Status          string     `json:"status" gorm:"not null;size:20;default:active;index" example:"active"`
+	StatusChangedAt time.Time  `json:"status_changed_at" example:"2023-01-01T00:00:00Z"`
+	StatusChangedBy string     `json:"status_changed_by,omitempty" gorm:"size:255" example:"admin"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
 
 	// Relationships
-	Course Course `json:"course,omitempty" gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
+	Student       *Student                 `json:"student,omitempty" gorm:"foreignKey:StudentID"`
+	Course        Course                   `json:"course,omitempty" gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
+	Offering      *CourseOffering          `json:"offering,omitempty" gorm:"foreignKey:OfferingID"`
+	Section       *Section                 `json:"section,omitempty" gorm:"foreignKey:SectionID"`
+	StatusChanges []EnrollmentStatusChange `json:"status_changes,omitempty" gorm:"foreignKey:EnrollmentID;constraint:OnDelete:CASCADE"`
 }
 
 // BeforeCreate will set a UUID rather than numeric ID
@@ -25,12 +37,24 @@ func (e *Enrollment) BeforeCreate(tx *gorm.DB) error {
 	if e.ID == uuid.Nil {
 		e.ID = uuid.New()
 	}
+	e.StudentEmail = NormalizeEmail(e.StudentEmail)
 	if e.EnrolledAt.IsZero() {
 		e.EnrolledAt = time.Now()
 	}
+	if e.Status == "" {
+		e.Status = constants.EnrollmentStatusActive
+	}
+	if e.StatusChangedAt.IsZero() {
+		e.StatusChangedAt = e.EnrolledAt
+	}
 	return nil
 }
 
+// HoldsSeat reports whether the enrollment currently occupies a seat in its course
+func (e *Enrollment) HoldsSeat() bool {
+	return e.Status == constants.EnrollmentStatusPending || e.Status == constants.EnrollmentStatusActive
+}
+
 // TableName returns the table name for Enrollment model
 func (Enrollment) TableName() string {
 	return "enrollments"
@@ -38,26 +62,51 @@ func (Enrollment) TableName() string {
 
 // EnrollmentRequest represents the request payload for creating an enrollment
 type EnrollmentRequest struct {
-	StudentEmail string    `json:"student_email" validate:"required,email" example:"student@example.com"`
-	CourseID     uuid.UUID `json:"course_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	StudentEmail              string     `json:"student_email" validate:"required,email" example:"student@example.com"`
+	CourseID                  uuid.UUID  `json:"course_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OfferingID                *uuid.UUID `json:"offering_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`              // omit to use the course's default offering
+	SectionID                 *uuid.UUID `json:"section_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`               // enroll in a section; implies its offering
+	OverridePrerequisites     bool       `json:"override_prerequisites,omitempty" example:"false"`                                  // skip the prerequisite check; the override is recorded
+	OverrideReason            *string    `json:"override_reason,omitempty" example:"Equivalent course taken at another university"` // why the prerequisite check was skipped
+	OverrideScheduleConflicts bool       `json:"override_schedule_conflicts,omitempty" example:"false"`                             // enroll even if the section clashes with the student's other sections
+}
+
+// SelfEnrollmentRequest represents the request payload for a signed-in student
+// enrolling themselves. Students cannot override prerequisites or schedule conflicts.
+type SelfEnrollmentRequest struct {
+	CourseID   uuid.UUID  `json:"course_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OfferingID *uuid.UUID `json:"offering_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"` // omit to use the course's default offering
+	SectionID  *uuid.UUID `json:"section_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`  // enroll in a section; implies its offering
 }
 
 // EnrollmentResponse represents the response payload for enrollment operations
 type EnrollmentResponse struct {
-	ID           uuid.UUID      `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	StudentEmail string         `json:"student_email" example:"student@example.com"`
-	CourseID     uuid.UUID      `json:"course_id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	EnrolledAt   time.Time      `json:"enrolled_at" example:"2023-01-01T00:00:00Z"`
-	Course       CourseResponse `json:"course,omitempty"`
+	ID              uuid.UUID           `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	StudentID       uuid.UUID           `json:"student_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	StudentEmail    string              `json:"student_email" example:"student@example.com"`
+	CourseID        uuid.UUID           `json:"course_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OfferingID      uuid.UUID           `json:"offering_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	SectionID       *uuid.UUID          `json:"section_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Term            *TermResponse       `json:"term,omitempty"` // missing for the default offering
+	EnrolledAt      time.Time           `json:"enrolled_at" example:"2023-01-01T00:00:00Z"`
+	Status          string              `json:"status" example:"active"`
+	StatusChangedAt time.Time           `json:"status_changed_at" example:"2023-01-01T00:00:00Z"`
+	Course          CourseResponse      `json:"course,omitempty"`
+	Progress        *EnrollmentProgress `json:"progress,omitempty"` // lesson progress, included when listing a student's enrollments
 }
 
 // ToResponse converts Enrollment model to EnrollmentResponse
 func (e *Enrollment) ToResponse() EnrollmentResponse {
 	response := EnrollmentResponse{
-		ID:           e.ID,
-		StudentEmail: e.StudentEmail,
-		CourseID:     e.CourseID,
-		EnrolledAt:   e.EnrolledAt,
+		ID:              e.ID,
+		StudentID:       e.StudentID,
+		StudentEmail:    e.StudentEmail,
+		CourseID:        e.CourseID,
+		OfferingID:      e.OfferingID,
+		SectionID:       e.SectionID,
+		EnrolledAt:      e.EnrolledAt,
+		Status:          e.Status,
+		StatusChangedAt: e.StatusChangedAt,
 	}
 
 	// Include course information if loaded
@@ -65,6 +114,12 @@ func (e *Enrollment) ToResponse() EnrollmentResponse {
 		response.Course = e.Course.ToResponse()
 	}
 
+	// Include the term if the offering was loaded with it
+	if e.Offering != nil && e.Offering.Term != nil {
+		term := e.Offering.Term.ToResponse()
+		response.Term = &term
+	}
+
 	return response
 }
 
@@ -74,3 +129,40 @@ type StudentEnrollmentsResponse struct {
 	Enrollments  []EnrollmentResponse `json:"enrollments"`
 	Total        int                  `json:"total" example:"3"`
 }
+
+// EnrollmentStatusChange records a single transition in the lifecycle of an enrollment
+type EnrollmentStatusChange struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	EnrollmentID uuid.UUID `json:"enrollment_id" gorm:"type:uuid;not null;index" example:"123e4567-e89b-12d3-a456-426614174000"`
+	FromStatus   *string   `json:"from_status,omitempty" gorm:"size:20" example:"active"` // nil when the enrollment was created
+	ToStatus     string    `json:"to_status" gorm:"not null;size:20" example:"dropped"`
+	ChangedBy    string    `json:"changed_by" gorm:"not null;size:255" example:"admin"`
+	Reason       *string   `json:"reason,omitempty" gorm:"type:text" example:"Schedule conflict"`
+	ChangedAt    time.Time `json:"changed_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (c *EnrollmentStatusChange) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for EnrollmentStatusChange model
+func (EnrollmentStatusChange) TableName() string {
+	return "enrollment_status_changes"
+}
+
+// EnrollmentStatusRequest represents the request payload for changing an enrollment status
+type EnrollmentStatusRequest struct {
+	Status string  `json:"status" validate:"required,oneof=pending active completed dropped withdrawn" example:"completed"`
+	Reason *string `json:"reason,omitempty" example:"Passed the final exam"`
+}
+
+// EnrollmentHistoryResponse represents the status history of an enrollment, oldest first
+type EnrollmentHistoryResponse struct {
+	EnrollmentID uuid.UUID                `json:"enrollment_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Status       string                   `json:"status" example:"completed"`
+	Changes      []EnrollmentStatusChange `json:"changes"`
+}
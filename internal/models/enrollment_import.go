@@ -0,0 +1,25 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// EnrollmentImportRowResult reports what happened to a single row of a bulk enrollment import
+type EnrollmentImportRowResult struct {
+	Row          int        `json:"row" example:"2"` // line number in the uploaded file
+	StudentEmail string     `json:"student_email" example:"student@example.com"`
+	Course       string     `json:"course" example:"Introduction to Go"` // course ID or title as written in the file
+	CourseID     *uuid.UUID `json:"course_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Status       string     `json:"status" example:"created"` // created, waitlisted, already_enrolled, invalid_email, unknown_course, rejected or malformed
+	Message      string     `json:"message,omitempty" example:"Student is already enrolled in this course"`
+	EnrollmentID *uuid.UUID `json:"enrollment_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// EnrollmentImportReport represents the outcome of a bulk enrollment import.
+// In a dry run the statuses describe what a real run would do and nothing is written.
+type EnrollmentImportReport struct {
+	DryRun  bool                        `json:"dry_run" example:"false"`
+	Total   int                         `json:"total" example:"3"`
+	Summary map[string]int              `json:"summary"`
+	Rows    []EnrollmentImportRowResult `json:"rows"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// IdempotencyRecord remembers a request made with an Idempotency-Key header and the
+// response it produced, so a retry with the same key gets the same response.
+// StatusCode is zero while the original request is still being processed.
+type IdempotencyRecord struct {
+	Key          string    `json:"key" gorm:"column:idempotency_key;primaryKey;size:512"` // caller's username and the header value
+	Fingerprint  string    `json:"fingerprint" gorm:"not null;size:64"`                   // SHA-256 of the method, path and body
+	StatusCode   int       `json:"status_code" gorm:"not null;default:0"`
+	ContentType  string    `json:"content_type" gorm:"size:255"`
+	ResponseBody string    `json:"response_body" gorm:"type:text"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+	ExpiresAt    time.Time `json:"expires_at" gorm:"not null;index"`
+}
+
+// TableName returns the table name for IdempotencyRecord model
+func (IdempotencyRecord) TableName() string {
+	return "idempotency_keys"
+}
+
+// InProgress reports whether the original request has not produced a response yet
+func (r *IdempotencyRecord) InProgress() bool {
+	return r.StatusCode == 0
+}
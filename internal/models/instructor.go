@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Instructor represents a person teaching courses. A course can have several
+// instructors; an instructor with an account can manage the courses they teach.
+type Instructor struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name      string    `json:"name" gorm:"not null;size:255" example:"Grace Hopper"`
+	Email     string    `json:"email" gorm:"not null;size:255;uniqueIndex" example:"grace@example.com"` // stored normalized, also the username of their account
+	Bio       *string   `json:"bio,omitempty" gorm:"type:text" example:"Teaches compilers and distributed systems"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (i *Instructor) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	i.Email = NormalizeEmail(i.Email)
+	return nil
+}
+
+// TableName returns the table name for Instructor model
+func (Instructor) TableName() string {
+	return "instructors"
+}
+
+// InstructorRequest represents the request payload for creating or updating an
+// instructor. Changing the email also changes the username of their account.
+type InstructorRequest struct {
+	Name  string  `json:"name" validate:"required,max=255" example:"Grace Hopper"`
+	Email string  `json:"email" validate:"required,email" example:"grace@example.com"`
+	Bio   *string `json:"bio,omitempty" example:"Teaches compilers and distributed systems"`
+}
+
+// InstructorAccountRequest represents the request payload for creating the
+// account an instructor signs in with. The username is the instructor's email.
+type InstructorAccountRequest struct {
+	Password string `json:"password" validate:"required,min=8" example:"correct horse battery"`
+}
+
+// InstructorResponse represents an instructor with the courses they teach
+type InstructorResponse struct {
+	ID         uuid.UUID   `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name       string      `json:"name" example:"Grace Hopper"`
+	Email      string      `json:"email" example:"grace@example.com"`
+	Bio        *string     `json:"bio,omitempty" example:"Teaches compilers and distributed systems"`
+	CourseIDs  []uuid.UUID `json:"course_ids" example:"123e4567-e89b-12d3-a456-426614174000"`
+	HasAccount bool        `json:"has_account" example:"true"`
+	CreatedAt  time.Time   `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// InstructorListResponse represents every instructor, sorted by name
+type InstructorListResponse struct {
+	Instructors []InstructorResponse `json:"instructors"`
+	Total       int                  `json:"total" example:"5"`
+}
+
+// InstructorSummary represents an instructor teaching a course
+type InstructorSummary struct {
+	ID    uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name  string    `json:"name" example:"Grace Hopper"`
+	Email string    `json:"email" example:"grace@example.com"`
+}
+
+// ToSummary converts Instructor model to InstructorSummary
+func (i *Instructor) ToSummary() InstructorSummary {
+	return InstructorSummary{
+		ID:    i.ID,
+		Name:  i.Name,
+		Email: i.Email,
+	}
+}
+
+// CourseInstructorsRequest represents the request payload for setting who
+// teaches a course. An empty list removes every instructor.
+type CourseInstructorsRequest struct {
+	InstructorIDs []uuid.UUID `json:"instructor_ids" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
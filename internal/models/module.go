@@ -0,0 +1,175 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CourseModule represents an ordered section of a course that groups lessons
+type CourseModule struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CourseID    uuid.UUID `json:"course_id" gorm:"type:uuid;not null;index:idx_course_modules_course_position" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Title       string    `json:"title" gorm:"not null;size:255" example:"Getting Started"`
+	Description *string   `json:"description,omitempty" gorm:"type:text" example:"Install Go and write your first program"`
+	Position    int       `json:"position" gorm:"not null;index:idx_course_modules_course_position" example:"1"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+
+	// Relationships
+	Lessons []Lesson `json:"lessons,omitempty" gorm:"foreignKey:ModuleID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (m *CourseModule) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for CourseModule model
+func (CourseModule) TableName() string {
+	return "course_modules"
+}
+
+// Lesson represents an ordered unit of content inside a course module
+type Lesson struct {
+	ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ModuleID        uuid.UUID `json:"module_id" gorm:"type:uuid;not null;index:idx_lessons_module_position" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Title           string    `json:"title" gorm:"not null;size:255" example:"Hello, World"`
+	Content         string    `json:"content" gorm:"type:text;not null" example:"Every Go program starts in package main..."`
+	DurationMinutes int       `json:"duration_minutes" gorm:"not null" example:"15"`
+	Optional        bool      `json:"optional" gorm:"not null;default:false" example:"false"` // optional lessons do not count towards completion
+	Position        int       `json:"position" gorm:"not null;index:idx_lessons_module_position" example:"1"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (l *Lesson) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for Lesson model
+func (Lesson) TableName() string {
+	return "lessons"
+}
+
+// ModuleRequest represents the request payload for creating or updating a module
+type ModuleRequest struct {
+	Title       string  `json:"title" validate:"required" example:"Getting Started"`
+	Description *string `json:"description,omitempty" example:"Install Go and write your first program"`
+}
+
+// LessonRequest represents the request payload for creating or updating a lesson
+type LessonRequest struct {
+	Title           string `json:"title" validate:"required" example:"Hello, World"`
+	Content         string `json:"content" validate:"required" example:"Every Go program starts in package main..."`
+	DurationMinutes int    `json:"duration_minutes" validate:"required,gt=0" example:"15"` // estimated time to complete the lesson
+	Optional        bool   `json:"optional,omitempty" example:"false"`                     // optional lessons do not count towards completion
+}
+
+// ModuleReorderRequest represents the new order of the modules of a course.
+// It must list every module of the course exactly once, first module first.
+type ModuleReorderRequest struct {
+	ModuleIDs []uuid.UUID `json:"module_ids" validate:"required"`
+}
+
+// LessonReorderRequest represents the new order of the lessons of a module.
+// It must list every lesson of the module exactly once, first lesson first.
+type LessonReorderRequest struct {
+	LessonIDs []uuid.UUID `json:"lesson_ids" validate:"required"`
+}
+
+// LessonResponse represents a lesson in API responses
+type LessonResponse struct {
+	ID              uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ModuleID        uuid.UUID `json:"module_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Title           string    `json:"title" example:"Hello, World"`
+	Content         string    `json:"content" example:"Every Go program starts in package main..."`
+	DurationMinutes int       `json:"duration_minutes" example:"15"`
+	Optional        bool      `json:"optional" example:"false"`
+	Position        int       `json:"position" example:"1"`
+	CreatedAt       time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt       time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// ToResponse converts Lesson model to LessonResponse
+func (l *Lesson) ToResponse() LessonResponse {
+	return LessonResponse{
+		ID:              l.ID,
+		ModuleID:        l.ModuleID,
+		Title:           l.Title,
+		Content:         l.Content,
+		DurationMinutes: l.DurationMinutes,
+		Optional:        l.Optional,
+		Position:        l.Position,
+		CreatedAt:       l.CreatedAt,
+		UpdatedAt:       l.UpdatedAt,
+	}
+}
+
+// ModuleResponse represents a module and its lessons in API responses.
+// LessonCount and DurationMinutes are derived from the lessons.
+type ModuleResponse struct {
+	ID              uuid.UUID        `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CourseID        uuid.UUID        `json:"course_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Title           string           `json:"title" example:"Getting Started"`
+	Description     *string          `json:"description,omitempty" example:"Install Go and write your first program"`
+	Position        int              `json:"position" example:"1"`
+	LessonCount     int              `json:"lesson_count" example:"4"`
+	DurationMinutes int              `json:"duration_minutes" example:"60"`
+	Lessons         []LessonResponse `json:"lessons"`
+	CreatedAt       time.Time        `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt       time.Time        `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// ToResponse converts CourseModule model to ModuleResponse
+func (m *CourseModule) ToResponse() ModuleResponse {
+	response := ModuleResponse{
+		ID:          m.ID,
+		CourseID:    m.CourseID,
+		Title:       m.Title,
+		Description: m.Description,
+		Position:    m.Position,
+		LessonCount: len(m.Lessons),
+		Lessons:     make([]LessonResponse, len(m.Lessons)),
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+	for i, lesson := range m.Lessons {
+		response.Lessons[i] = lesson.ToResponse()
+		response.DurationMinutes += lesson.DurationMinutes
+	}
+	return response
+}
+
+// CourseOutline represents the ordered modules and lessons of a course.
+// The counts and total duration are derived from the modules.
+type CourseOutline struct {
+	ModuleCount          int              `json:"module_count" example:"3"`
+	LessonCount          int              `json:"lesson_count" example:"12"`
+	TotalDurationMinutes int              `json:"total_duration_minutes" example:"180"`
+	Modules              []ModuleResponse `json:"modules"`
+}
+
+// NewCourseOutline builds the outline of a course from its modules, which must
+// already be in order with their lessons loaded in order
+func NewCourseOutline(modules []CourseModule) CourseOutline {
+	outline := CourseOutline{
+		ModuleCount: len(modules),
+		Modules:     make([]ModuleResponse, len(modules)),
+	}
+	for i, module := range modules {
+		response := module.ToResponse()
+		outline.Modules[i] = response
+		outline.LessonCount += response.LessonCount
+		outline.TotalDurationMinutes += response.DurationMinutes
+	}
+	return outline
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CourseOffering represents a run of a course in a term, with its own capacity
+// and schedule. Every course also has a default offering without a term, which
+// holds enrollments made without choosing an offering.
+type CourseOffering struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CourseID  uuid.UUID  `json:"course_id" gorm:"type:uuid;not null;index" example:"123e4567-e89b-12d3-a456-426614174000"`
+	TermID    *uuid.UUID `json:"term_id,omitempty" gorm:"type:uuid;index" example:"123e4567-e89b-12d3-a456-426614174000"` // nil for the default offering
+	Capacity  *int       `json:"capacity,omitempty" example:"30"`                                                         // nil means the course capacity applies
+	Schedule  *string    `json:"schedule,omitempty" gorm:"type:text" example:"Mon/Wed 10:00-11:30, Room 101"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+
+	// Relationships
+	Term *Term `json:"term,omitempty" gorm:"foreignKey:TermID"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (o *CourseOffering) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for CourseOffering model
+func (CourseOffering) TableName() string {
+	return "course_offerings"
+}
+
+// IsDefault reports whether this is the course's default offering, which has no term
+func (o *CourseOffering) IsDefault() bool {
+	return o.TermID == nil
+}
+
+// EffectiveCapacity returns the number of seats in the offering: its own
+// capacity if it has one, otherwise the capacity of the course. Nil means unlimited.
+func (o *CourseOffering) EffectiveCapacity(course *Course) *int {
+	if o.Capacity != nil {
+		return o.Capacity
+	}
+	return course.Capacity
+}
+
+// OfferingRequest represents the request payload for offering a course in a term
+type OfferingRequest struct {
+	TermID   uuid.UUID `json:"term_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Capacity *int      `json:"capacity,omitempty" validate:"omitempty,min=0" example:"30"` // omit to use the course capacity
+	Schedule *string   `json:"schedule,omitempty" example:"Mon/Wed 10:00-11:30, Room 101"`
+}
+
+// OfferingUpdateRequest represents the request payload for updating an offering.
+// The term of an offering cannot be changed.
+type OfferingUpdateRequest struct {
+	Capacity *int    `json:"capacity,omitempty" validate:"omitempty,min=0" example:"30"` // omit to use the course capacity
+	Schedule *string `json:"schedule,omitempty" example:"Mon/Wed 10:00-11:30, Room 101"`
+}
+
+// OfferingResponse represents a course offering in API responses
+type OfferingResponse struct {
+	ID            uuid.UUID     `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CourseID      uuid.UUID     `json:"course_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Term          *TermResponse `json:"term,omitempty"` // missing for the default offering
+	Capacity      *int          `json:"capacity,omitempty" example:"30"`
+	Schedule      *string       `json:"schedule,omitempty" example:"Mon/Wed 10:00-11:30, Room 101"`
+	EnrolledCount int           `json:"enrolled_count" example:"12"`
+	CreatedAt     time.Time     `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// ToResponse converts CourseOffering model to OfferingResponse
+func (o *CourseOffering) ToResponse() OfferingResponse {
+	response := OfferingResponse{
+		ID:        o.ID,
+		CourseID:  o.CourseID,
+		Capacity:  o.Capacity,
+		Schedule:  o.Schedule,
+		CreatedAt: o.CreatedAt,
+	}
+	if o.Term != nil {
+		term := o.Term.ToResponse()
+		response.Term = &term
+	}
+	return response
+}
+
+// OfferingListResponse represents the offerings of a course
+type OfferingListResponse struct {
+	CourseID  uuid.UUID          `json:"course_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Offerings []OfferingResponse `json:"offerings"`
+	Total     int                `json:"total" example:"2"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// RolePermission grants a named permission, such as course:write, to every user
+// with a role
+type RolePermission struct {
+	Role       string    `json:"role" gorm:"primaryKey;size:50" example:"instructor"`
+	Permission string    `json:"permission" gorm:"primaryKey;size:100" example:"course:write"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// TableName returns the table name for RolePermission model
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
+
+// RolePermissionsRequest represents the request payload for replacing the
+// permissions of a role. An empty list takes every permission away.
+type RolePermissionsRequest struct {
+	Permissions []string `json:"permissions" example:"course:write,enrollment:read"`
+}
+
+// RoleResponse represents a role with the permissions it holds
+type RoleResponse struct {
+	Role        string   `json:"role" example:"instructor"`
+	Permissions []string `json:"permissions" example:"course:write,enrollment:read"`
+	Editable    bool     `json:"editable" example:"true"`
+}
+
+// RoleListResponse represents every role and the permissions that can be granted
+type RoleListResponse struct {
+	Roles       []RoleResponse `json:"roles"`
+	Permissions []string       `json:"available_permissions" example:"course:create,course:write"`
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CoursePrerequisite links a course to a course that must be completed before enrolling in it
+type CoursePrerequisite struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CourseID       uuid.UUID `json:"course_id" gorm:"type:uuid;not null;index:idx_course_prerequisite,unique" example:"123e4567-e89b-12d3-a456-426614174000"`
+	PrerequisiteID uuid.UUID `json:"prerequisite_id" gorm:"type:uuid;not null;index:idx_course_prerequisite,unique" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+
+	// Relationships
+	Prerequisite Course `json:"prerequisite,omitempty" gorm:"foreignKey:PrerequisiteID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (p *CoursePrerequisite) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for CoursePrerequisite model
+func (CoursePrerequisite) TableName() string {
+	return "course_prerequisites"
+}
+
+// PrerequisiteOverride records an admin enrolling a student without the completed prerequisites
+type PrerequisiteOverride struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CourseID     uuid.UUID `json:"course_id" gorm:"type:uuid;not null;index" example:"123e4567-e89b-12d3-a456-426614174000"`
+	StudentEmail string    `json:"student_email" gorm:"not null;size:255" example:"student@example.com"`
+	MissingIDs   string    `json:"-" gorm:"column:missing_prerequisite_ids;type:text;not null"` // comma-separated course IDs
+	OverriddenBy string    `json:"overridden_by" gorm:"not null;size:255" example:"admin"`
+	Reason       *string   `json:"reason,omitempty" gorm:"type:text" example:"Equivalent course taken at another university"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (o *PrerequisiteOverride) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for PrerequisiteOverride model
+func (PrerequisiteOverride) TableName() string {
+	return "prerequisite_overrides"
+}
+
+// PrerequisiteRequest represents the request payload for adding a prerequisite to a course
+type PrerequisiteRequest struct {
+	PrerequisiteID uuid.UUID `json:"prerequisite_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// PrerequisitesReplaceRequest represents the full list of prerequisites of a course
+type PrerequisitesReplaceRequest struct {
+	PrerequisiteIDs []uuid.UUID `json:"prerequisite_ids" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// PrerequisitesResponse represents the prerequisites of a course
+type PrerequisitesResponse struct {
+	CourseID      uuid.UUID        `json:"course_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Prerequisites []CourseResponse `json:"prerequisites"`
+	Total         int              `json:"total" example:"1"`
+}
+
+// PrerequisiteOverrideResponse represents a recorded prerequisite override
+type PrerequisiteOverrideResponse struct {
+	ID                     uuid.UUID   `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CourseID               uuid.UUID   `json:"course_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	StudentEmail           string      `json:"student_email" example:"student@example.com"`
+	MissingPrerequisiteIDs []uuid.UUID `json:"missing_prerequisite_ids"`
+	OverriddenBy           string      `json:"overridden_by" example:"admin"`
+	Reason                 *string     `json:"reason,omitempty" example:"Equivalent course taken at another university"`
+	CreatedAt              time.Time   `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// ToResponse converts PrerequisiteOverride model to PrerequisiteOverrideResponse
+func (o *PrerequisiteOverride) ToResponse() PrerequisiteOverrideResponse {
+	missing := []uuid.UUID{}
+	for _, id := range strings.Split(o.MissingIDs, ",") {
+		if parsed, err := uuid.Parse(id); err == nil {
+			missing = append(missing, parsed)
+		}
+	}
+
+	return PrerequisiteOverrideResponse{
+		ID:                     o.ID,
+		CourseID:               o.CourseID,
+		StudentEmail:           o.StudentEmail,
+		MissingPrerequisiteIDs: missing,
+		OverriddenBy:           o.OverriddenBy,
+		Reason:                 o.Reason,
+		CreatedAt:              o.CreatedAt,
+	}
+}
+
+// PrerequisiteOverridesResponse represents the prerequisite overrides recorded for a course
+type PrerequisiteOverridesResponse struct {
+	Overrides []PrerequisiteOverrideResponse `json:"overrides"`
+	Total     int                            `json:"total" example:"1"`
+}
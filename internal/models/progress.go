@@ -0,0 +1,143 @@
+package models
+
+import (
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LessonProgress records what a student has done in one lesson of an enrollment
+type LessonProgress struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	EnrollmentID uuid.UUID  `json:"enrollment_id" gorm:"type:uuid;not null;uniqueIndex:unique_lesson_progress_enrollment_lesson" example:"123e4567-e89b-12d3-a456-426614174000"`
+	LessonID     uuid.UUID  `json:"lesson_id" gorm:"type:uuid;not null;uniqueIndex:unique_lesson_progress_enrollment_lesson;index" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Status       string     `json:"status" gorm:"not null;size:20;default:started" example:"completed"`
+	StartedAt    time.Time  `json:"started_at" gorm:"not null" example:"2023-01-01T00:00:00Z"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty" example:"2023-01-02T00:00:00Z"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-02T00:00:00Z"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (p *LessonProgress) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	if p.StartedAt.IsZero() {
+		p.StartedAt = time.Now()
+	}
+	return nil
+}
+
+// TableName returns the table name for LessonProgress model
+func (LessonProgress) TableName() string {
+	return "lesson_progress"
+}
+
+// LessonProgressRequest represents the request payload for recording progress in a lesson
+type LessonProgressRequest struct {
+	Status string `json:"status" validate:"required,oneof=started completed" example:"completed"`
+}
+
+// EnrollmentProgress summarizes how far a student is through the lessons of a course.
+// CompletionPercentage only counts required lessons and is rounded down, so it
+// reaches 100 exactly when every required lesson is completed.
+type EnrollmentProgress struct {
+	RequiredLessons          int `json:"required_lessons" example:"10"`
+	RequiredLessonsCompleted int `json:"required_lessons_completed" example:"4"`
+	LessonsStarted           int `json:"lessons_started" example:"1"` // started but not yet completed
+	LessonsCompleted         int `json:"lessons_completed" example:"5"`
+	CompletionPercentage     int `json:"completion_percentage" example:"40"`
+}
+
+// NewEnrollmentProgress builds a progress summary from lesson counts
+func NewEnrollmentProgress(requiredLessons, requiredLessonsCompleted, lessonsStarted, lessonsCompleted int) EnrollmentProgress {
+	progress := EnrollmentProgress{
+		RequiredLessons:          requiredLessons,
+		RequiredLessonsCompleted: requiredLessonsCompleted,
+		LessonsStarted:           lessonsStarted,
+		LessonsCompleted:         lessonsCompleted,
+	}
+	if requiredLessons > 0 {
+		progress.CompletionPercentage = requiredLessonsCompleted * 100 / requiredLessons
+	}
+	return progress
+}
+
+// IsComplete reports whether every required lesson has been completed. A course
+// without required lessons is never complete through progress alone.
+func (p EnrollmentProgress) IsComplete() bool {
+	return p.RequiredLessons > 0 && p.RequiredLessonsCompleted >= p.RequiredLessons
+}
+
+// LessonProgressResponse represents the progress of a student in one lesson
+type LessonProgressResponse struct {
+	LessonID    uuid.UUID  `json:"lesson_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ModuleID    uuid.UUID  `json:"module_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Title       string     `json:"title" example:"Hello, World"`
+	Optional    bool       `json:"optional" example:"false"`
+	Status      string     `json:"status" example:"completed"` // not_started, started or completed
+	StartedAt   *time.Time `json:"started_at,omitempty" example:"2023-01-01T00:00:00Z"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" example:"2023-01-02T00:00:00Z"`
+}
+
+// EnrollmentProgressResponse represents the progress of an enrollment through
+// every lesson of its course, in course order
+type EnrollmentProgressResponse struct {
+	EnrollmentID uuid.UUID `json:"enrollment_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Status       string    `json:"status" example:"active"`
+	EnrollmentProgress
+	Lessons []LessonProgressResponse `json:"lessons"`
+}
+
+// NewEnrollmentProgressResponse builds the progress of an enrollment from the
+// ordered modules of its course and the progress recorded for the enrollment
+func NewEnrollmentProgressResponse(enrollment *Enrollment, modules []CourseModule, records []LessonProgress) EnrollmentProgressResponse {
+	byLesson := make(map[uuid.UUID]LessonProgress, len(records))
+	for _, record := range records {
+		byLesson[record.LessonID] = record
+	}
+
+	response := EnrollmentProgressResponse{
+		EnrollmentID: enrollment.ID,
+		Status:       enrollment.Status,
+		Lessons:      []LessonProgressResponse{},
+	}
+	var required, requiredCompleted, started, completed int
+	for _, module := range modules {
+		for _, lesson := range module.Lessons {
+			entry := LessonProgressResponse{
+				LessonID: lesson.ID,
+				ModuleID: module.ID,
+				Title:    lesson.Title,
+				Optional: lesson.Optional,
+				Status:   constants.LessonProgressNotStarted,
+			}
+			if record, ok := byLesson[lesson.ID]; ok {
+				startedAt := record.StartedAt
+				entry.Status = record.Status
+				entry.StartedAt = &startedAt
+				entry.CompletedAt = record.CompletedAt
+			}
+
+			isCompleted := entry.Status == constants.LessonProgressCompleted
+			if !lesson.Optional {
+				required++
+				if isCompleted {
+					requiredCompleted++
+				}
+			}
+			if isCompleted {
+				completed++
+			} else if entry.Status == constants.LessonProgressStarted {
+				started++
+			}
+
+			response.Lessons = append(response.Lessons, entry)
+		}
+	}
+	response.EnrollmentProgress = NewEnrollmentProgress(required, requiredCompleted, started, completed)
+	return response
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLessonProgress_TableName(t *testing.T) {
+	progress := LessonProgress{}
+	assert.Equal(t, "lesson_progress", progress.TableName())
+}
+
+func TestNewEnrollmentProgress(t *testing.T) {
+	tests := []struct {
+		name              string
+		required          int
+		requiredCompleted int
+		percentage        int
+		complete          bool
+	}{
+		{"no lessons", 0, 0, 0, false},
+		{"not started", 4, 0, 0, false},
+		{"rounds down", 3, 2, 66, false},
+		{"almost done never shows 100", 200, 199, 99, false},
+		{"all required lessons done", 3, 3, 100, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := NewEnrollmentProgress(tt.required, tt.requiredCompleted, 0, tt.requiredCompleted)
+			assert.Equal(t, tt.percentage, progress.CompletionPercentage)
+			assert.Equal(t, tt.complete, progress.IsComplete())
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// RevokedToken is an entry on the token denylist used when Redis is disabled.
+// ID is the jti of a revoked token or the sid of a revoked session; the entry
+// is only needed until the tokens it covers expire.
+type RevokedToken struct {
+	ID        string    `json:"id" gorm:"primaryKey;size:64"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for RevokedToken model
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
@@ -0,0 +1,326 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+	_ "time/tzdata" // meeting time zones must resolve even where the host has no zoneinfo
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// minutesPerWeek is the length of the weekly cycle meetings repeat in
+const minutesPerWeek = 7 * 24 * 60
+
+// weekdays maps meeting day names to weekdays
+var weekdays = map[string]time.Weekday{
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+	"sunday":    time.Sunday,
+}
+
+// Section represents a class section of a course offering, which meets at the
+// same times every week
+type Section struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CourseID   uuid.UUID `json:"course_id" gorm:"type:uuid;not null;index" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OfferingID uuid.UUID `json:"offering_id" gorm:"type:uuid;not null;index" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name       string    `json:"name" gorm:"not null;size:50" example:"A01"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+
+	// Relationships
+	Meetings []SectionMeeting `json:"meetings,omitempty" gorm:"foreignKey:SectionID;constraint:OnDelete:CASCADE"`
+	Offering *CourseOffering  `json:"offering,omitempty" gorm:"foreignKey:OfferingID"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (s *Section) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for Section model
+func (Section) TableName() string {
+	return "course_sections"
+}
+
+// SectionMeeting represents a weekly meeting slot of a section. Times are wall
+// clock times in the meeting's time zone.
+type SectionMeeting struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	SectionID uuid.UUID `json:"section_id" gorm:"type:uuid;not null;index" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Day       string    `json:"day" gorm:"not null;size:9" example:"monday"`
+	StartTime string    `json:"start_time" gorm:"not null;size:5" example:"10:00"`
+	EndTime   string    `json:"end_time" gorm:"not null;size:5" example:"11:30"`
+	Location  *string   `json:"location,omitempty" gorm:"size:255" example:"Room 101"`
+	TimeZone  string    `json:"time_zone" gorm:"not null;size:64" example:"Europe/Amsterdam"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (m *SectionMeeting) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for SectionMeeting model
+func (SectionMeeting) TableName() string {
+	return "section_meetings"
+}
+
+// ParseWeekday parses a meeting day name such as "monday"
+func ParseWeekday(day string) (time.Weekday, bool) {
+	weekday, ok := weekdays[strings.ToLower(day)]
+	return weekday, ok
+}
+
+// ParseClock parses a 24-hour "HH:MM" time into minutes after midnight
+func ParseClock(clock string) (int, bool) {
+	if len(clock) != 5 || clock[2] != ':' {
+		return 0, false
+	}
+	digits := [4]int{}
+	for i, c := range []byte(clock[:2] + clock[3:]) {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		digits[i] = int(c - '0')
+	}
+	hours, minutes := digits[0]*10+digits[1], digits[2]*10+digits[3]
+	if hours > 23 || minutes > 59 {
+		return 0, false
+	}
+	return hours*60 + minutes, true
+}
+
+// weeklyInterval returns the meeting as minutes after Monday 00:00 UTC of the
+// week containing ref. The result may fall outside 0..minutesPerWeek when the
+// time zone shifts the meeting into the previous or next week.
+func (m *SectionMeeting) weeklyInterval(ref time.Time) (int, int) {
+	location, err := time.LoadLocation(m.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	weekday, _ := ParseWeekday(m.Day)
+	start, _ := ParseClock(m.StartTime)
+	end, _ := ParseClock(m.EndTime)
+
+	ref = ref.UTC()
+	monday := time.Date(ref.Year(), ref.Month(), ref.Day()-(int(ref.Weekday())+6)%7, 0, 0, 0, 0, time.UTC)
+	day := monday.AddDate(0, 0, (int(weekday)+6)%7)
+	local := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, location)
+
+	offset := int(local.Add(time.Duration(start)*time.Minute).Sub(monday) / time.Minute)
+	return offset, offset + end - start
+}
+
+// Overlaps reports whether two weekly meetings are held at the same time in the
+// week containing ref. Meetings that only touch, one ending as the other
+// starts, do not overlap.
+func (m *SectionMeeting) Overlaps(other *SectionMeeting, ref time.Time) bool {
+	start, end := m.weeklyInterval(ref)
+	otherStart, otherEnd := other.weeklyInterval(ref)
+	for _, shift := range []int{-minutesPerWeek, 0, minutesPerWeek} {
+		if start < otherEnd+shift && otherStart+shift < end {
+			return true
+		}
+	}
+	return false
+}
+
+// ConflictsWith reports whether the two sections have meetings at the same time
+// while both of their terms are running. Sections of a default offering have no
+// term and run indefinitely. Both sections need their offering, with its term,
+// and their meetings loaded.
+func (s *Section) ConflictsWith(other *Section, now time.Time) bool {
+	ref, overlap := termOverlap(s.term(), other.term(), now)
+	if !overlap {
+		return false
+	}
+	for i := range s.Meetings {
+		for j := range other.Meetings {
+			if s.Meetings[i].Overlaps(&other.Meetings[j], ref) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// term returns the term of the section's offering, if it has one
+func (s *Section) term() *Term {
+	if s.Offering == nil {
+		return nil
+	}
+	return s.Offering.Term
+}
+
+// termOverlap reports whether two terms run at the same time, where a nil term
+// runs indefinitely, and returns the first day both are running as the week to
+// compare meetings in, or now if neither has a term
+func termOverlap(a, b *Term, now time.Time) (time.Time, bool) {
+	switch {
+	case a == nil && b == nil:
+		return now, true
+	case a == nil:
+		return b.StartDate, true
+	case b == nil:
+		return a.StartDate, true
+	}
+	if a.EndDate.Before(b.StartDate) || b.EndDate.Before(a.StartDate) {
+		return time.Time{}, false
+	}
+	if a.StartDate.After(b.StartDate) {
+		return a.StartDate, true
+	}
+	return b.StartDate, true
+}
+
+// SectionMeetingRequest represents a weekly meeting slot in a section request
+type SectionMeetingRequest struct {
+	Day       string  `json:"day" validate:"required,oneof=monday tuesday wednesday thursday friday saturday sunday" example:"monday"`
+	StartTime string  `json:"start_time" validate:"required" example:"10:00"`
+	EndTime   string  `json:"end_time" validate:"required" example:"11:30"`
+	Location  *string `json:"location,omitempty" example:"Room 101"`
+	TimeZone  string  `json:"time_zone" validate:"required" example:"Europe/Amsterdam"`
+}
+
+// SectionRequest represents the request payload for creating or updating a
+// section. Updating replaces every meeting of the section.
+type SectionRequest struct {
+	OfferingID *uuid.UUID              `json:"offering_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"` // omit to use the course's default offering; ignored on update
+	Name       string                  `json:"name" validate:"required,max=50" example:"A01"`
+	Meetings   []SectionMeetingRequest `json:"meetings"`
+}
+
+// SectionMeetingResponse represents a weekly meeting slot in API responses
+type SectionMeetingResponse struct {
+	Day       string  `json:"day" example:"monday"`
+	StartTime string  `json:"start_time" example:"10:00"`
+	EndTime   string  `json:"end_time" example:"11:30"`
+	Location  *string `json:"location,omitempty" example:"Room 101"`
+	TimeZone  string  `json:"time_zone" example:"Europe/Amsterdam"`
+}
+
+// SectionResponse represents a section in API responses
+type SectionResponse struct {
+	ID         uuid.UUID                `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CourseID   uuid.UUID                `json:"course_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OfferingID uuid.UUID                `json:"offering_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Term       *TermResponse            `json:"term,omitempty"` // missing for sections of the default offering
+	Name       string                   `json:"name" example:"A01"`
+	Meetings   []SectionMeetingResponse `json:"meetings"`
+	CreatedAt  time.Time                `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// ToResponse converts Section model to SectionResponse
+func (s *Section) ToResponse() SectionResponse {
+	response := SectionResponse{
+		ID:         s.ID,
+		CourseID:   s.CourseID,
+		OfferingID: s.OfferingID,
+		Name:       s.Name,
+		Meetings:   make([]SectionMeetingResponse, len(s.Meetings)),
+		CreatedAt:  s.CreatedAt,
+	}
+	for i, meeting := range s.Meetings {
+		response.Meetings[i] = SectionMeetingResponse{
+			Day:       meeting.Day,
+			StartTime: meeting.StartTime,
+			EndTime:   meeting.EndTime,
+			Location:  meeting.Location,
+			TimeZone:  meeting.TimeZone,
+		}
+	}
+	if term := s.term(); term != nil {
+		termResponse := term.ToResponse()
+		response.Term = &termResponse
+	}
+	return response
+}
+
+// SectionListResponse represents the sections of a course
+type SectionListResponse struct {
+	CourseID uuid.UUID         `json:"course_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Sections []SectionResponse `json:"sections"`
+	Total    int               `json:"total" example:"2"`
+}
+
+// TimetableEntry represents one weekly meeting in a student's timetable
+type TimetableEntry struct {
+	Day         string        `json:"day" example:"monday"`
+	StartTime   string        `json:"start_time" example:"10:00"`
+	EndTime     string        `json:"end_time" example:"11:30"`
+	TimeZone    string        `json:"time_zone" example:"Europe/Amsterdam"`
+	Location    *string       `json:"location,omitempty" example:"Room 101"`
+	CourseID    uuid.UUID     `json:"course_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CourseTitle string        `json:"course_title" example:"Introduction to Go"`
+	SectionID   uuid.UUID     `json:"section_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	SectionName string        `json:"section_name" example:"A01"`
+	Term        *TermResponse `json:"term,omitempty"`
+}
+
+// TimetableResponse represents the weekly timetable of a student, ordered by
+// day of the week and start time
+type TimetableResponse struct {
+	StudentEmail string           `json:"student_email" example:"student@example.com"`
+	Entries      []TimetableEntry `json:"entries"`
+	Total        int              `json:"total" example:"4"`
+}
+
+// NewTimetable builds a student's weekly timetable from their enrollments that
+// hold a seat in a section. Enrollments without a loaded section are skipped.
+func NewTimetable(email string, enrollments []Enrollment) TimetableResponse {
+	entries := []TimetableEntry{}
+	for _, enrollment := range enrollments {
+		section := enrollment.Section
+		if section == nil {
+			continue
+		}
+		var term *TermResponse
+		if t := section.term(); t != nil {
+			response := t.ToResponse()
+			term = &response
+		}
+		for _, meeting := range section.Meetings {
+			entries = append(entries, TimetableEntry{
+				Day:         meeting.Day,
+				StartTime:   meeting.StartTime,
+				EndTime:     meeting.EndTime,
+				TimeZone:    meeting.TimeZone,
+				Location:    meeting.Location,
+				CourseID:    enrollment.CourseID,
+				CourseTitle: enrollment.Course.Title,
+				SectionID:   section.ID,
+				SectionName: section.Name,
+				Term:        term,
+			})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Day != b.Day {
+			dayA, _ := ParseWeekday(a.Day)
+			dayB, _ := ParseWeekday(b.Day)
+			return (dayA+6)%7 < (dayB+6)%7
+		}
+		return a.StartTime < b.StartTime
+	})
+
+	return TimetableResponse{
+		StudentEmail: email,
+		Entries:      entries,
+		Total:        len(entries),
+	}
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseClock(t *testing.T) {
+	minutes, ok := ParseClock("09:30")
+	assert.True(t, ok)
+	assert.Equal(t, 570, minutes)
+
+	minutes, ok = ParseClock("23:59")
+	assert.True(t, ok)
+	assert.Equal(t, 1439, minutes)
+
+	for _, invalid := range []string{"", "9:30", "24:00", "12:60", "12-30", "ab:cd", "12:300"} {
+		_, ok := ParseClock(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
+
+func TestSectionMeeting_Overlaps(t *testing.T) {
+	// A January week, when Amsterdam is one hour ahead of UTC
+	ref := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	amsterdam := &SectionMeeting{Day: "monday", StartTime: "10:00", EndTime: "11:00", TimeZone: "Europe/Amsterdam"}
+
+	assert.True(t, amsterdam.Overlaps(&SectionMeeting{Day: "monday", StartTime: "09:30", EndTime: "10:30", TimeZone: "UTC"}, ref))
+	// Meetings that only touch do not overlap
+	assert.False(t, amsterdam.Overlaps(&SectionMeeting{Day: "monday", StartTime: "08:00", EndTime: "09:00", TimeZone: "UTC"}, ref))
+	assert.False(t, amsterdam.Overlaps(&SectionMeeting{Day: "tuesday", StartTime: "10:00", EndTime: "11:00", TimeZone: "Europe/Amsterdam"}, ref))
+}
+
+func TestSectionMeeting_OverlapsAcrossWeekBoundary(t *testing.T) {
+	ref := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	// Monday 08:00 in Tokyo is Sunday 23:00 UTC
+	tokyo := &SectionMeeting{Day: "monday", StartTime: "08:00", EndTime: "09:00", TimeZone: "Asia/Tokyo"}
+	sunday := &SectionMeeting{Day: "sunday", StartTime: "22:30", EndTime: "23:30", TimeZone: "UTC"}
+
+	assert.True(t, tokyo.Overlaps(sunday, ref))
+	assert.True(t, sunday.Overlaps(tokyo, ref))
+}
+
+func TestTermOverlap(t *testing.T) {
+	now := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	fall := &Term{StartDate: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)}
+	spring := &Term{StartDate: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)}
+	autumn := &Term{StartDate: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)}
+
+	_, overlap := termOverlap(fall, spring, now)
+	assert.False(t, overlap)
+
+	ref, overlap := termOverlap(fall, autumn, now)
+	assert.True(t, overlap)
+	assert.Equal(t, autumn.StartDate, ref)
+
+	ref, overlap = termOverlap(nil, spring, now)
+	assert.True(t, overlap)
+	assert.Equal(t, spring.StartDate, ref)
+
+	ref, overlap = termOverlap(nil, nil, now)
+	assert.True(t, overlap)
+	assert.Equal(t, now, ref)
+}
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Student represents a student profile. Enrollments reference the student and
+// keep a copy of the email so that email-based lookups keep working.
+type Student struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Email           string     `json:"email" gorm:"not null;size:255;uniqueIndex" example:"student@example.com"`
+	FirstName       *string    `json:"first_name,omitempty" gorm:"size:100" example:"Ada"`
+	LastName        *string    `json:"last_name,omitempty" gorm:"size:100" example:"Lovelace"`
+	StudentNumber   *string    `json:"student_number,omitempty" gorm:"size:50;uniqueIndex" example:"S1234567"` // external student number, unique when set
+	InviteHash      *string    `json:"-" gorm:"size:64;uniqueIndex"`                                           // hash of the outstanding account invite token
+	InviteExpiresAt *time.Time `json:"-"`                                                                      // when the invite token stops working
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (s *Student) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	s.Email = NormalizeEmail(s.Email)
+	return nil
+}
+
+// TableName returns the table name for Student model
+func (Student) TableName() string {
+	return "students"
+}
+
+// NormalizeEmail returns the form in which student emails are stored and compared:
+// trimmed and lower case, so that Alice@Example.com and alice@example.com are
+// the same student
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// StudentRequest represents the request payload for creating or updating a
+// student. Changing the email also changes it on the student's enrollments.
+type StudentRequest struct {
+	Email         string  `json:"email" validate:"required,email" example:"student@example.com"`
+	FirstName     *string `json:"first_name,omitempty" validate:"omitempty,max=100" example:"Ada"`
+	LastName      *string `json:"last_name,omitempty" validate:"omitempty,max=100" example:"Lovelace"`
+	StudentNumber *string `json:"student_number,omitempty" validate:"omitempty,max=50" example:"S1234567"`
+}
+
+// StudentResponse represents a student with their enrollment count. Dropped and
+// withdrawn enrollments are not counted.
+type StudentResponse struct {
+	ID              uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Email           string    `json:"email" example:"student@example.com"`
+	FirstName       *string   `json:"first_name,omitempty" example:"Ada"`
+	LastName        *string   `json:"last_name,omitempty" example:"Lovelace"`
+	StudentNumber   *string   `json:"student_number,omitempty" example:"S1234567"`
+	EnrollmentCount int       `json:"enrollment_count" example:"3"`
+	LastEnrolledAt  string    `json:"last_enrolled_at,omitempty" example:"2023-01-01T00:00:00Z"`
+	CreatedAt       time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// AllStudentsResponse represents the response for all students
+type AllStudentsResponse struct {
+	Students []StudentResponse `json:"students"`
+	Total    int               `json:"total"`
+}
+
+// ShareLinkRequest represents the request payload for sharing the records of a
+// student. Without an expiry the link is valid for 72 hours.
+type ShareLinkRequest struct {
+	ExpiresInHours int `json:"expires_in_hours,omitempty" validate:"omitempty,min=1,max=720" example:"72"`
+}
+
+// StudentInviteResponse represents an account invite issued for a student. The
+// token is only ever returned here; it replaces any earlier one.
+type StudentInviteResponse struct {
+	StudentID    uuid.UUID `json:"student_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	StudentEmail string    `json:"student_email" example:"student@example.com"`
+	Token        string    `json:"token" example:"inv_Yk3Nq8vVb0xJ2m9sT6cR1eLw4uHf7aPz5dGiKoQyXn0"`
+	ExpiresAt    time.Time `json:"expires_at" example:"2023-01-08T00:00:00Z"`
+}
+
+// ShareLinkResponse represents signed links to the enrollments and timetable of a
+// student that anyone holding them can open until they expire
+type ShareLinkResponse struct {
+	StudentEmail   string    `json:"student_email" example:"student@example.com"`
+	EnrollmentsURL string    `json:"enrollments_url" example:"/api/v1/students/student@example.com/enrollments?expires=1700000000&signature=..."`
+	TimetableURL   string    `json:"timetable_url" example:"/api/v1/students/student@example.com/timetable?expires=1700000000&signature=..."`
+	ExpiresAt      time.Time `json:"expires_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// StudentMerge records an admin merging one student into another. The source
+// student no longer exists after the merge, so its ID and email are copied.
+type StudentMerge struct {
+	ID                       uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	SourceStudentID          uuid.UUID `json:"source_student_id" gorm:"type:uuid;not null" example:"123e4567-e89b-12d3-a456-426614174000"`
+	SourceEmail              string    `json:"source_email" gorm:"not null;size:255" example:"Ada@Example.com"`
+	TargetStudentID          uuid.UUID `json:"target_student_id" gorm:"type:uuid;not null;index" example:"123e4567-e89b-12d3-a456-426614174000"`
+	TargetEmail              string    `json:"target_email" gorm:"not null;size:255" example:"ada@example.com"`
+	MovedEnrollments         int       `json:"moved_enrollments" gorm:"not null" example:"2"`
+	DiscardedEnrollmentIDs   string    `json:"-" gorm:"type:text;not null"` // comma-separated enrollment IDs
+	MovedWaitlistEntries     int       `json:"moved_waitlist_entries" gorm:"not null" example:"1"`
+	DiscardedWaitlistEntries int       `json:"discarded_waitlist_entries" gorm:"not null" example:"0"`
+	MergedBy                 string    `json:"merged_by" gorm:"not null;size:255" example:"admin"`
+	Reason                   *string   `json:"reason,omitempty" gorm:"type:text" example:"Same person enrolled with two addresses"`
+	CreatedAt                time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (m *StudentMerge) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for StudentMerge model
+func (StudentMerge) TableName() string {
+	return "student_merges"
+}
+
+// StudentMergeRequest represents the request payload for merging a student into
+// another one
+type StudentMergeRequest struct {
+	SourceStudentID uuid.UUID `json:"source_student_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Reason          *string   `json:"reason,omitempty" validate:"omitempty,max=500" example:"Same person enrolled with two addresses"`
+}
+
+// StudentMergeResponse represents a recorded student merge. When both students
+// were enrolled in the same course, the enrollment furthest along is kept and
+// the other one is listed as discarded.
+type StudentMergeResponse struct {
+	ID                       uuid.UUID   `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	SourceStudentID          uuid.UUID   `json:"source_student_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	SourceEmail              string      `json:"source_email" example:"Ada@Example.com"`
+	TargetStudentID          uuid.UUID   `json:"target_student_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	TargetEmail              string      `json:"target_email" example:"ada@example.com"`
+	MovedEnrollments         int         `json:"moved_enrollments" example:"2"`
+	DiscardedEnrollmentIDs   []uuid.UUID `json:"discarded_enrollment_ids"`
+	MovedWaitlistEntries     int         `json:"moved_waitlist_entries" example:"1"`
+	DiscardedWaitlistEntries int         `json:"discarded_waitlist_entries" example:"0"`
+	MergedBy                 string      `json:"merged_by" example:"admin"`
+	Reason                   *string     `json:"reason,omitempty" example:"Same person enrolled with two addresses"`
+	CreatedAt                time.Time   `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// StudentMergesResponse represents the merges into a student, newest first
+type StudentMergesResponse struct {
+	Merges []StudentMergeResponse `json:"merges"`
+	Total  int                    `json:"total"`
+}
+
+// ToResponse converts StudentMerge model to StudentMergeResponse
+func (m *StudentMerge) ToResponse() StudentMergeResponse {
+	discarded := []uuid.UUID{}
+	for _, id := range strings.Split(m.DiscardedEnrollmentIDs, ",") {
+		if parsed, err := uuid.Parse(id); err == nil {
+			discarded = append(discarded, parsed)
+		}
+	}
+
+	return StudentMergeResponse{
+		ID:                       m.ID,
+		SourceStudentID:          m.SourceStudentID,
+		SourceEmail:              m.SourceEmail,
+		TargetStudentID:          m.TargetStudentID,
+		TargetEmail:              m.TargetEmail,
+		MovedEnrollments:         m.MovedEnrollments,
+		DiscardedEnrollmentIDs:   discarded,
+		MovedWaitlistEntries:     m.MovedWaitlistEntries,
+		DiscardedWaitlistEntries: m.DiscardedWaitlistEntries,
+		MergedBy:                 m.MergedBy,
+		Reason:                   m.Reason,
+		CreatedAt:                m.CreatedAt,
+	}
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Term represents a period of the academic calendar, such as a semester
+type Term struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name      string    `json:"name" gorm:"not null;size:100;uniqueIndex" example:"Fall 2025"`
+	StartDate time.Time `json:"start_date" gorm:"type:date;not null" example:"2025-09-01T00:00:00Z"`
+	EndDate   time.Time `json:"end_date" gorm:"type:date;not null" example:"2025-12-19T00:00:00Z"` // last day of the term
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (t *Term) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for Term model
+func (Term) TableName() string {
+	return "terms"
+}
+
+// HasEnded reports whether the last day of the term is over at the given time
+func (t *Term) HasEnded(now time.Time) bool {
+	return !now.Before(t.EndDate.AddDate(0, 0, 1))
+}
+
+// TermRequest represents the request payload for creating or updating a term
+type TermRequest struct {
+	Name      string    `json:"name" validate:"required,max=100" example:"Fall 2025"`
+	StartDate time.Time `json:"start_date" validate:"required" example:"2025-09-01T00:00:00Z"`
+	EndDate   time.Time `json:"end_date" validate:"required,gtefield=StartDate" example:"2025-12-19T00:00:00Z"`
+}
+
+// TermResponse represents a term in API responses
+type TermResponse struct {
+	ID        uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name      string    `json:"name" example:"Fall 2025"`
+	StartDate time.Time `json:"start_date" example:"2025-09-01T00:00:00Z"`
+	EndDate   time.Time `json:"end_date" example:"2025-12-19T00:00:00Z"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// ToResponse converts Term model to TermResponse
+func (t *Term) ToResponse() TermResponse {
+	return TermResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		StartDate: t.StartDate,
+		EndDate:   t.EndDate,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+// TermListResponse represents the list of terms, earliest first
+type TermListResponse struct {
+	Terms []TermResponse `json:"terms"`
+	Total int            `json:"total" example:"4"`
+}
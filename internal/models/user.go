@@ -7,14 +7,22 @@ import (
 	"gorm.io/gorm"
 )
 
-// User represents a user in the system (admin users for authentication)
+// User represents a user in the system: admins, instructors managing the
+// courses they teach, and students signing in to their own account
 type User struct {
-	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Username  string    `json:"username" gorm:"not null;size:255;unique" validate:"required,min=1,max=255" example:"admin"`
-	Password  string    `json:"-" gorm:"not null;size:255" validate:"required,min=1"` // Password is never returned in JSON
-	Role      string    `json:"role" gorm:"not null;size:50;default:admin" validate:"required" example:"admin"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+	ID                     uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Username               string     `json:"username" gorm:"not null;size:255;unique" validate:"required,min=1,max=255" example:"admin"`
+	Password               string     `json:"-" gorm:"not null;size:255" validate:"required,min=1"` // Password is never returned in JSON
+	Role                   string     `json:"role" gorm:"not null;size:50;default:admin" validate:"required" example:"admin"`
+	StudentID              *uuid.UUID `json:"student_id,omitempty" gorm:"type:uuid;uniqueIndex" example:"123e4567-e89b-12d3-a456-426614174000"`    // set for student accounts
+	InstructorID           *uuid.UUID `json:"instructor_id,omitempty" gorm:"type:uuid;uniqueIndex" example:"123e4567-e89b-12d3-a456-426614174000"` // set for instructor accounts
+	DisabledAt             *time.Time `json:"disabled_at,omitempty" example:"2023-01-01T00:00:00Z"`                                                // set while the user may not log in
+	MustChangePassword     bool       `json:"must_change_password" gorm:"not null" example:"false"`                                                // set while the user may do nothing but change their password
+	PasswordChangedAt      *time.Time `json:"-"`                                                                                                   // refresh tokens issued before this are refused
+	PasswordResetHash      *string    `json:"-" gorm:"size:64;uniqueIndex"`                                                                        // hash of the outstanding password reset token
+	PasswordResetExpiresAt *time.Time `json:"-"`                                                                                                   // when the reset token stops working
+	CreatedAt              time.Time  `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt              time.Time  `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
 }
 
 // BeforeCreate will set a UUID rather than numeric ID
@@ -36,26 +44,93 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required" example:"admin!dev"`
 }
 
-// LoginResponse represents the response payload for successful login
+// RegisterRequest represents the request payload for a student creating their
+// account. The email is also the username to log in with.
+type RegisterRequest struct {
+	Email       string `json:"email" validate:"required,email" example:"student@example.com"`
+	Password    string `json:"password" validate:"required,min=8" example:"correct horse battery"`
+	InviteToken string `json:"invite_token,omitempty" example:"inv_Yk3Nq8vVb0xJ2m9sT6cR1eLw4uHf7aPz5dGiKoQyXn0"` // required when the email belongs to an existing student
+}
+
+// LoginResponse represents the response payload for successful login. Token
+// is the short-lived access token; RefreshToken is exchanged for a new pair at
+// /auth/refresh and can only be used once.
 type LoginResponse struct {
-	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	User  UserResponse `json:"user"`
+	Token        string       `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string       `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ExpiresIn    int          `json:"expires_in" example:"900"` // seconds until the access token expires
+	User         UserResponse `json:"user"`
+}
+
+// RefreshRequest represents the request payload for renewing an access token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
 }
 
 // UserResponse represents the response payload for user operations (without password)
 type UserResponse struct {
-	ID        uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Username  string    `json:"username" example:"admin"`
-	Role      string    `json:"role" example:"admin"`
-	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	ID                 uuid.UUID  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Username           string     `json:"username" example:"admin"`
+	Role               string     `json:"role" example:"admin"`
+	StudentID          *uuid.UUID `json:"student_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	InstructorID       *uuid.UUID `json:"instructor_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	DisabledAt         *time.Time `json:"disabled_at,omitempty" example:"2023-01-01T00:00:00Z"`
+	MustChangePassword bool       `json:"must_change_password" example:"false"` // set while the user may only change their password, view their profile and log out
+	CreatedAt          time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
 }
 
 // ToResponse converts User model to UserResponse
 func (u *User) ToResponse() UserResponse {
 	return UserResponse{
-		ID:        u.ID,
-		Username:  u.Username,
-		Role:      u.Role,
-		CreatedAt: u.CreatedAt,
+		ID:                 u.ID,
+		Username:           u.Username,
+		Role:               u.Role,
+		StudentID:          u.StudentID,
+		InstructorID:       u.InstructorID,
+		DisabledAt:         u.DisabledAt,
+		MustChangePassword: u.MustChangePassword,
+		CreatedAt:          u.CreatedAt,
 	}
 }
+
+// CreateUserRequest represents the request payload for an admin creating an
+// admin or super-admin account. MustChangePassword makes the password a
+// temporary one the user has to change when they first log in.
+type CreateUserRequest struct {
+	Username           string `json:"username" validate:"required,max=255" example:"registrar"`
+	Password           string `json:"password" validate:"required,min=8" example:"correct horse battery"`
+	Role               string `json:"role" validate:"required" example:"admin"`
+	MustChangePassword bool   `json:"must_change_password" example:"true"`
+}
+
+// ChangePasswordRequest represents the request payload for a user changing their password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required" example:"admin!dev"`
+	NewPassword     string `json:"new_password" validate:"required,min=8" example:"correct horse battery staple"`
+}
+
+// ResetPasswordRequest represents the request payload for setting a new
+// password with a reset token an admin issued
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required" example:"pwr_Yk3Nq8vVb0xJ2m9sT6cR1eLw4uHf7aPz5dGiKoQyXn0"`
+	NewPassword string `json:"new_password" validate:"required,min=8" example:"correct horse battery staple"`
+}
+
+// PasswordResetResponse represents a password reset token issued for a user.
+// The token is only ever returned here; it replaces any earlier one.
+type PasswordResetResponse struct {
+	User      UserResponse `json:"user"`
+	Token     string       `json:"token" example:"pwr_Yk3Nq8vVb0xJ2m9sT6cR1eLw4uHf7aPz5dGiKoQyXn0"`
+	ExpiresAt time.Time    `json:"expires_at" example:"2023-01-02T00:00:00Z"`
+}
+
+// UpdateUserRoleRequest represents the request payload for changing the role of a user
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" validate:"required" example:"super_admin"`
+}
+
+// UserListResponse represents a list of users
+type UserListResponse struct {
+	Users []UserResponse `json:"users"`
+	Total int            `json:"total" example:"3"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WaitlistEntry represents a student waiting for a seat in a full course offering.
+// A course has a single waitlist; entries are promoted in order as seats free up
+// in the offering they are waiting for.
+type WaitlistEntry struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CourseID     uuid.UUID  `json:"course_id" gorm:"type:uuid;not null;index:idx_waitlist_course_student,unique" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OfferingID   uuid.UUID  `json:"offering_id" gorm:"type:uuid;not null;index" example:"123e4567-e89b-12d3-a456-426614174000"` // the offering the student is waiting for
+	SectionID    *uuid.UUID `json:"section_id,omitempty" gorm:"type:uuid" example:"123e4567-e89b-12d3-a456-426614174000"`       // the section to join once promoted
+	StudentEmail string     `json:"student_email" gorm:"not null;size:255;index:idx_waitlist_course_student,unique" example:"student@example.com"`
+	Position     int        `json:"position" gorm:"not null" example:"1"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (w *WaitlistEntry) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	w.StudentEmail = NormalizeEmail(w.StudentEmail)
+	return nil
+}
+
+// TableName returns the table name for WaitlistEntry model
+func (WaitlistEntry) TableName() string {
+	return "waitlist_entries"
+}
+
+// WaitlistEntryResponse represents a single waitlist entry in API responses
+type WaitlistEntryResponse struct {
+	ID           uuid.UUID  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CourseID     uuid.UUID  `json:"course_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OfferingID   uuid.UUID  `json:"offering_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	SectionID    *uuid.UUID `json:"section_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	StudentEmail string     `json:"student_email" example:"student@example.com"`
+	Position     int        `json:"position" example:"1"`
+	CreatedAt    time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// ToResponse converts WaitlistEntry model to WaitlistEntryResponse
+func (w *WaitlistEntry) ToResponse() WaitlistEntryResponse {
+	return WaitlistEntryResponse{
+		ID:           w.ID,
+		CourseID:     w.CourseID,
+		OfferingID:   w.OfferingID,
+		SectionID:    w.SectionID,
+		StudentEmail: w.StudentEmail,
+		Position:     w.Position,
+		CreatedAt:    w.CreatedAt,
+	}
+}
+
+// WaitlistResponse represents the waitlist of a course together with its seat usage
+type WaitlistResponse struct {
+	CourseID      uuid.UUID               `json:"course_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Capacity      *int                    `json:"capacity,omitempty" example:"30"`
+	EnrolledCount int                     `json:"enrolled_count" example:"30"`
+	Entries       []WaitlistEntryResponse `json:"entries"`
+	Total         int                     `json:"total" example:"2"`
+}
+
+// WaitlistReorderRequest represents the new order of a course waitlist.
+// It must list every waitlisted student exactly once, first in line first.
+type WaitlistReorderRequest struct {
+	StudentEmails []string `json:"student_emails" validate:"required" example:"first@example.com,second@example.com"`
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository defines the interface for API key data operations
+type APIKeyRepository interface {
+	Create(key *models.APIKey) error
+	GetAll() ([]models.APIKey, error)
+	GetByID(id uuid.UUID) (*models.APIKey, error)
+	GetByHash(keyHash string) (*models.APIKey, error)
+	TouchLastUsed(id uuid.UUID, usedAt time.Time) error
+	Delete(id uuid.UUID) error
+}
+
+// apiKeyRepository implements APIKeyRepository interface
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// Create creates an API key with its scopes
+func (r *apiKeyRepository) Create(key *models.APIKey) error {
+	return r.db.Omit("Owner").Create(key).Error
+}
+
+// GetAll retrieves every API key with its owner and scopes, newest first
+func (r *apiKeyRepository) GetAll() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.withAssociations().Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// GetByID retrieves an API key with its owner and scopes
+func (r *apiKeyRepository) GetByID(id uuid.UUID) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.withAssociations().First(&key, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetByHash retrieves the API key with the hash, with its owner and scopes
+func (r *apiKeyRepository) GetByHash(keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.withAssociations().First(&key, "key_hash = ?", keyHash).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// TouchLastUsed records when an API key was last used
+func (r *apiKeyRepository) TouchLastUsed(id uuid.UUID, usedAt time.Time) error {
+	return r.db.Model(&models.APIKey{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
+
+// Delete deletes an API key and its scopes, returning gorm.ErrRecordNotFound
+// when there is no such key
+func (r *apiKeyRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.APIKeyScope{}, "api_key_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.APIKey{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// withAssociations preloads the owner and the scopes, sorted, of API keys
+func (r *apiKeyRepository) withAssociations() *gorm.DB {
+	return r.db.Preload("Owner").Preload("Scopes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("permission ASC")
+	})
+}
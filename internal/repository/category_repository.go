@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CategoryRepository defines the interface for course category data operations
+type CategoryRepository interface {
+	Create(category *models.Category) error
+	GetAll() ([]models.Category, error)
+	GetByID(id uuid.UUID) (*models.Category, error)
+	GetByIDs(ids []uuid.UUID) ([]models.Category, error)
+	GetBySlug(slug string) (*models.Category, error)
+	CountCourses() (map[uuid.UUID]int, error)
+	CountChildren(id uuid.UUID) (int64, error)
+	Update(category *models.Category) error
+	Delete(id uuid.UUID) error
+}
+
+// categoryRepository implements CategoryRepository interface
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepository creates a new category repository
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+// Create creates a new category
+func (r *categoryRepository) Create(category *models.Category) error {
+	return r.db.Create(category).Error
+}
+
+// GetAll retrieves every category at every level, sorted by name
+func (r *categoryRepository) GetAll() ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+// GetByID retrieves a category by ID
+func (r *categoryRepository) GetByID(id uuid.UUID) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.Where("id = ?", id).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetByIDs retrieves the categories with the given IDs; unknown IDs are skipped
+func (r *categoryRepository) GetByIDs(ids []uuid.UUID) ([]models.Category, error) {
+	var categories []models.Category
+	if len(ids) == 0 {
+		return categories, nil
+	}
+	err := r.db.Where("id IN ?", ids).Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+// GetBySlug retrieves a category by its slug
+func (r *categoryRepository) GetBySlug(slug string) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.Where("slug = ?", slug).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// CountCourses returns the number of courses directly in each category that has any
+func (r *categoryRepository) CountCourses() (map[uuid.UUID]int, error) {
+	var rows []struct {
+		CategoryID uuid.UUID
+		Count      int
+	}
+	err := r.db.Table("course_categories").
+		Select("category_id, COUNT(*) AS count").
+		Group("category_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+	return counts, nil
+}
+
+// CountChildren counts the direct subcategories of a category
+func (r *categoryRepository) CountChildren(id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// Update saves the name, slug, description and parent of a category
+func (r *categoryRepository) Update(category *models.Category) error {
+	return r.db.Model(category).
+		Select("name", "slug", "description", "parent_id").
+		Updates(category).Error
+}
+
+// Delete deletes a category by ID; courses in it lose the category
+func (r *categoryRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM course_categories WHERE category_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&models.Category{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
@@ -1,10 +1,15 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
 	"sonic-labs/course-enrollment-service/internal/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CourseRepository defines the interface for course data operations
@@ -13,7 +18,9 @@ type CourseRepository interface {
 	GetAll() ([]models.Course, error)
 	GetWithPagination(params models.CourseQueryParams) ([]models.Course, int, error)
 	GetByID(id uuid.UUID) (*models.Course, error)
+	GetByTitle(title string) ([]models.Course, error)
 	Update(course *models.Course) error
+	SetInstructors(course *models.Course, instructors []models.Instructor) error
 	Delete(id uuid.UUID) error
 	ExistsByID(id uuid.UUID) (bool, error)
 }
@@ -36,7 +43,7 @@ func (r *courseRepository) Create(course *models.Course) error {
 // GetAll retrieves all courses (backward compatibility)
 func (r *courseRepository) GetAll() ([]models.Course, error) {
 	var courses []models.Course
-	err := r.db.Order("created_at DESC").Find(&courses).Error
+	err := withDetails(r.db).Order("created_at DESC").Find(&courses).Error
 	return courses, err
 }
 
@@ -59,6 +66,39 @@ func (r *courseRepository) GetWithPagination(params models.CourseQueryParams) ([
 		query = query.Where("difficulty IN ?", params.Difficulty)
 	}
 
+	// Apply enrollment window filter
+	if params.OpenForEnrollment {
+		now := time.Now().UTC()
+		query = query.Where("(enrollment_opens_at IS NULL OR enrollment_opens_at <= ?) AND (enrollment_closes_at IS NULL OR enrollment_closes_at > ?)", now, now)
+	}
+
+	// Apply term filter
+	if params.TermID != nil {
+		query = query.Where("id IN (SELECT course_id FROM course_offerings WHERE term_id = ?)", *params.TermID)
+	}
+
+	// Apply category filter, including subcategories
+	if len(params.Category) > 0 {
+		query = query.Where(`id IN (SELECT course_id FROM course_categories WHERE category_id IN (
+			WITH RECURSIVE subtree(id) AS (
+				SELECT id FROM categories WHERE slug IN ?
+				UNION
+				SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id
+			)
+			SELECT id FROM subtree
+		))`, params.Category)
+	}
+
+	// Apply tag filter
+	if len(params.Tag) > 0 {
+		query = query.Where("id IN (SELECT course_tags.course_id FROM course_tags JOIN tags ON tags.id = course_tags.tag_id WHERE tags.name IN ?)", params.Tag)
+	}
+
+	// Apply instructor filter
+	if params.InstructorID != nil {
+		query = query.Where("id IN (SELECT course_id FROM course_instructors WHERE instructor_id = ?)", *params.InstructorID)
+	}
+
 	// Get total count for pagination
 	if err := query.Count(&totalCount).Error; err != nil {
 		return nil, 0, err
@@ -66,7 +106,7 @@ func (r *courseRepository) GetWithPagination(params models.CourseQueryParams) ([
 
 	// Apply pagination
 	offset := (params.Page - 1) * params.Limit
-	if err := query.Order("created_at DESC").Offset(offset).Limit(params.Limit).Find(&courses).Error; err != nil {
+	if err := withDetails(query).Order(courseOrder(params.Sort)).Offset(offset).Limit(params.Limit).Find(&courses).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -76,16 +116,40 @@ func (r *courseRepository) GetWithPagination(params models.CourseQueryParams) ([
 // GetByID retrieves a course by ID
 func (r *courseRepository) GetByID(id uuid.UUID) (*models.Course, error) {
 	var course models.Course
-	err := r.db.Where("id = ?", id).First(&course).Error
+	err := withDetails(r.db).Where("id = ?", id).First(&course).Error
 	if err != nil {
 		return nil, err
 	}
 	return &course, nil
 }
 
-// Update updates an existing course
+// GetByTitle retrieves every course whose title matches, ignoring case
+func (r *courseRepository) GetByTitle(title string) ([]models.Course, error) {
+	var courses []models.Course
+	err := r.db.Where("LOWER(title) = LOWER(?)", title).Find(&courses).Error
+	return courses, err
+}
+
+// Update updates an existing course and replaces its categories and tags
 func (r *courseRepository) Update(course *models.Course) error {
-	return r.db.Save(course).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(course).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(course).Association("Categories").Replace(course.Categories); err != nil {
+			return err
+		}
+		return tx.Model(course).Association("Tags").Replace(course.Tags)
+	})
+}
+
+// SetInstructors replaces the instructors teaching a course
+func (r *courseRepository) SetInstructors(course *models.Course, instructors []models.Instructor) error {
+	if err := r.db.Model(course).Association("Instructors").Replace(instructors); err != nil {
+		return err
+	}
+	course.Instructors = instructors
+	return nil
 }
 
 // Delete deletes a course by ID
@@ -109,3 +173,34 @@ func (r *courseRepository) ExistsByID(id uuid.UUID) (bool, error) {
 	}
 	return count > 0, nil
 }
+
+// courseOrder returns the ORDER BY clause for a course list sort: created_at,
+// title or difficulty, descending with a leading "-". Difficulty follows the
+// sort order of the levels. Ties and unknown sorts fall back to newest first.
+func courseOrder(sort string) string {
+	direction := "ASC"
+	if strings.HasPrefix(sort, "-") {
+		direction = "DESC"
+		sort = strings.TrimPrefix(sort, "-")
+	}
+
+	switch sort {
+	case constants.CourseSortCreatedAt:
+		return "created_at " + direction
+	case constants.CourseSortTitle:
+		return "LOWER(title) " + direction + ", created_at DESC"
+	case constants.CourseSortDifficulty:
+		return "(SELECT sort_order FROM difficulty_levels WHERE difficulty_levels.name = courses.difficulty) " + direction + ", created_at DESC"
+	default:
+		return "created_at DESC"
+	}
+}
+
+// withDetails loads the categories, tags and instructors of the queried courses,
+// sorted by name
+func withDetails(query *gorm.DB) *gorm.DB {
+	return query.
+		Preload("Categories", func(db *gorm.DB) *gorm.DB { return db.Order("name ASC") }).
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("name ASC") }).
+		Preload("Instructors", func(db *gorm.DB) *gorm.DB { return db.Order("name ASC") })
+}
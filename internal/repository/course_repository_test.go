@@ -36,12 +36,76 @@ func (suite *CourseRepositoryTestSuite) SetupSuite() {
 			description TEXT NOT NULL,
 			difficulty TEXT NOT NULL,
 			image_url TEXT,
+			capacity INTEGER,
+			enrollment_opens_at DATETIME,
+			enrollment_closes_at DATETIME,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)
 	`).Error
 	suite.Require().NoError(err)
 
+	err = suite.db.Exec(`
+		CREATE TABLE categories (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			slug TEXT NOT NULL UNIQUE,
+			description TEXT,
+			parent_id TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`).Error
+	suite.Require().NoError(err)
+
+	err = suite.db.Exec(`
+		CREATE TABLE tags (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`).Error
+	suite.Require().NoError(err)
+
+	err = suite.db.Exec(`
+		CREATE TABLE course_categories (
+			course_id TEXT NOT NULL,
+			category_id TEXT NOT NULL,
+			PRIMARY KEY (course_id, category_id)
+		)
+	`).Error
+	suite.Require().NoError(err)
+
+	err = suite.db.Exec(`
+		CREATE TABLE course_tags (
+			course_id TEXT NOT NULL,
+			tag_id TEXT NOT NULL,
+			PRIMARY KEY (course_id, tag_id)
+		)
+	`).Error
+	suite.Require().NoError(err)
+
+	err = suite.db.Exec(`
+		CREATE TABLE instructors (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			email TEXT NOT NULL UNIQUE,
+			bio TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`).Error
+	suite.Require().NoError(err)
+
+	err = suite.db.Exec(`
+		CREATE TABLE course_instructors (
+			course_id TEXT NOT NULL,
+			instructor_id TEXT NOT NULL,
+			PRIMARY KEY (course_id, instructor_id)
+		)
+	`).Error
+	suite.Require().NoError(err)
+
 	// Initialize repository
 	suite.repo = NewCourseRepository(suite.db)
 }
@@ -59,6 +123,12 @@ func (suite *CourseRepositoryTestSuite) TearDownSuite() {
 // SetupTest runs before each test
 func (suite *CourseRepositoryTestSuite) SetupTest() {
 	// Clean up test data before each test
+	suite.db.Exec("DELETE FROM course_categories")
+	suite.db.Exec("DELETE FROM course_tags")
+	suite.db.Exec("DELETE FROM categories")
+	suite.db.Exec("DELETE FROM tags")
+	suite.db.Exec("DELETE FROM course_instructors")
+	suite.db.Exec("DELETE FROM instructors")
 	suite.db.Exec("DELETE FROM courses")
 }
 
@@ -198,6 +268,115 @@ func (suite *CourseRepositoryTestSuite) TestCourseRepository_Update() {
 	suite.Equal("Advanced", retrievedCourse.Difficulty)
 }
 
+// TestCourseRepository_Update_Taxonomy tests that updating a course replaces
+// its categories and tags
+func (suite *CourseRepositoryTestSuite) TestCourseRepository_Update_Taxonomy() {
+	programming := models.Category{Name: "Programming", Slug: "programming"}
+	design := models.Category{Name: "Design", Slug: "design"}
+	suite.Require().NoError(suite.db.Create(&programming).Error)
+	suite.Require().NoError(suite.db.Create(&design).Error)
+	tags, err := NewTagRepository(suite.db).FindOrCreate([]string{"Backend", "backend ", "web"})
+	suite.Require().NoError(err)
+	suite.Len(tags, 2)
+
+	course := &models.Course{
+		Title:       "Tagged Course",
+		Description: "Test Description",
+		Difficulty:  "Beginner",
+		Categories:  []models.Category{programming},
+		Tags:        tags,
+	}
+	suite.Require().NoError(suite.repo.Create(course))
+
+	course.Categories = []models.Category{design}
+	course.Tags = tags[:1]
+	suite.Require().NoError(suite.repo.Update(course))
+
+	retrievedCourse, err := suite.repo.GetByID(course.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(retrievedCourse.Categories, 1)
+	suite.Equal("design", retrievedCourse.Categories[0].Slug)
+	suite.Require().Len(retrievedCourse.Tags, 1)
+	suite.Equal("backend", retrievedCourse.Tags[0].Name)
+}
+
+// TestCourseRepository_GetWithPagination_Taxonomy tests filtering courses by
+// category, including subcategories, and by tag
+func (suite *CourseRepositoryTestSuite) TestCourseRepository_GetWithPagination_Taxonomy() {
+	programming := models.Category{Name: "Programming", Slug: "programming"}
+	suite.Require().NoError(suite.db.Create(&programming).Error)
+	golang := models.Category{Name: "Go", Slug: "go", ParentID: &programming.ID}
+	suite.Require().NoError(suite.db.Create(&golang).Error)
+	design := models.Category{Name: "Design", Slug: "design"}
+	suite.Require().NoError(suite.db.Create(&design).Error)
+	tags, err := NewTagRepository(suite.db).FindOrCreate([]string{"backend"})
+	suite.Require().NoError(err)
+
+	courses := []*models.Course{
+		{Title: "Go Basics", Description: "D", Difficulty: "Beginner", Categories: []models.Category{golang}, Tags: tags},
+		{Title: "Algorithms", Description: "D", Difficulty: "Beginner", Categories: []models.Category{programming}},
+		{Title: "Typography", Description: "D", Difficulty: "Beginner", Categories: []models.Category{design}},
+	}
+	for _, course := range courses {
+		suite.Require().NoError(suite.repo.Create(course))
+	}
+
+	titles := func(params models.CourseQueryParams) []string {
+		params.Page, params.Limit = 1, 10
+		found, total, err := suite.repo.GetWithPagination(params)
+		suite.Require().NoError(err)
+		suite.Equal(len(found), total)
+		var result []string
+		for _, course := range found {
+			result = append(result, course.Title)
+		}
+		return result
+	}
+
+	suite.ElementsMatch([]string{"Go Basics", "Algorithms"}, titles(models.CourseQueryParams{Category: []string{"programming"}}))
+	suite.ElementsMatch([]string{"Go Basics"}, titles(models.CourseQueryParams{Category: []string{"go"}}))
+	suite.ElementsMatch([]string{"Go Basics", "Typography"}, titles(models.CourseQueryParams{Category: []string{"go", "design"}}))
+	suite.ElementsMatch([]string{"Go Basics"}, titles(models.CourseQueryParams{Tag: []string{"backend"}}))
+	suite.Empty(titles(models.CourseQueryParams{Category: []string{"design"}, Tag: []string{"backend"}}))
+	suite.Empty(titles(models.CourseQueryParams{Category: []string{"unknown"}}))
+}
+
+// TestCourseRepository_SetInstructors tests assigning instructors to courses,
+// keeping them on update and filtering courses by instructor
+func (suite *CourseRepositoryTestSuite) TestCourseRepository_SetInstructors() {
+	ada := models.Instructor{Name: "Ada Lovelace", Email: "Ada@Example.com"}
+	grace := models.Instructor{Name: "Grace Hopper", Email: "grace@example.com"}
+	suite.Require().NoError(suite.db.Create(&ada).Error)
+	suite.Require().NoError(suite.db.Create(&grace).Error)
+	suite.Equal("ada@example.com", ada.Email)
+
+	course := &models.Course{Title: "Compilers", Description: "D", Difficulty: "Advanced"}
+	other := &models.Course{Title: "Typography", Description: "D", Difficulty: "Beginner"}
+	suite.Require().NoError(suite.repo.Create(course))
+	suite.Require().NoError(suite.repo.Create(other))
+	suite.Require().NoError(suite.repo.SetInstructors(course, []models.Instructor{grace, ada}))
+
+	course.Title = "Compilers and Interpreters"
+	suite.Require().NoError(suite.repo.Update(course))
+
+	retrievedCourse, err := suite.repo.GetByID(course.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(retrievedCourse.Instructors, 2)
+	suite.Equal("Ada Lovelace", retrievedCourse.Instructors[0].Name)
+	suite.Equal("Grace Hopper", retrievedCourse.Instructors[1].Name)
+
+	found, total, err := suite.repo.GetWithPagination(models.CourseQueryParams{Page: 1, Limit: 10, InstructorID: &ada.ID})
+	suite.Require().NoError(err)
+	suite.Equal(1, total)
+	suite.Equal(course.ID, found[0].ID)
+
+	suite.Require().NoError(suite.repo.SetInstructors(course, []models.Instructor{grace}))
+	found, total, err = suite.repo.GetWithPagination(models.CourseQueryParams{Page: 1, Limit: 10, InstructorID: &ada.ID})
+	suite.Require().NoError(err)
+	suite.Equal(0, total)
+	suite.Empty(found)
+}
+
 // TestCourseRepository_Delete tests deleting a course
 func (suite *CourseRepositoryTestSuite) TestCourseRepository_Delete() {
 	// Create test course
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DifficultyRepository defines the interface for difficulty level data operations
+type DifficultyRepository interface {
+	Create(level *models.DifficultyLevel) error
+	GetAll() ([]models.DifficultyLevel, error)
+	GetByID(id uuid.UUID) (*models.DifficultyLevel, error)
+	GetByName(name string) (*models.DifficultyLevel, error)
+	GetMaxSortOrder() (int, error)
+	CountCourses() (map[string]int, error)
+	Update(level *models.DifficultyLevel, oldName string) error
+	Delete(id uuid.UUID) error
+}
+
+// difficultyRepository implements DifficultyRepository interface
+type difficultyRepository struct {
+	db *gorm.DB
+}
+
+// NewDifficultyRepository creates a new difficulty level repository
+func NewDifficultyRepository(db *gorm.DB) DifficultyRepository {
+	return &difficultyRepository{db: db}
+}
+
+// Create creates a new difficulty level
+func (r *difficultyRepository) Create(level *models.DifficultyLevel) error {
+	return r.db.Create(level).Error
+}
+
+// GetAll retrieves every difficulty level, easiest first
+func (r *difficultyRepository) GetAll() ([]models.DifficultyLevel, error) {
+	var levels []models.DifficultyLevel
+	err := r.db.Order("sort_order ASC, name ASC").Find(&levels).Error
+	return levels, err
+}
+
+// GetByID retrieves a difficulty level by ID
+func (r *difficultyRepository) GetByID(id uuid.UUID) (*models.DifficultyLevel, error) {
+	var level models.DifficultyLevel
+	if err := r.db.Where("id = ?", id).First(&level).Error; err != nil {
+		return nil, err
+	}
+	return &level, nil
+}
+
+// GetByName retrieves a difficulty level by name, ignoring case
+func (r *difficultyRepository) GetByName(name string) (*models.DifficultyLevel, error) {
+	var level models.DifficultyLevel
+	if err := r.db.Where("LOWER(name) = LOWER(?)", name).First(&level).Error; err != nil {
+		return nil, err
+	}
+	return &level, nil
+}
+
+// GetMaxSortOrder returns the sort order of the hardest level, or 0 without levels
+func (r *difficultyRepository) GetMaxSortOrder() (int, error) {
+	var maxSortOrder int
+	err := r.db.Model(&models.DifficultyLevel{}).
+		Select("COALESCE(MAX(sort_order), 0)").
+		Scan(&maxSortOrder).Error
+	return maxSortOrder, err
+}
+
+// CountCourses returns the number of courses with each difficulty level in use
+func (r *difficultyRepository) CountCourses() (map[string]int, error) {
+	var rows []struct {
+		Difficulty string
+		Count      int
+	}
+	err := r.db.Model(&models.Course{}).
+		Select("difficulty, COUNT(*) AS count").
+		Group("difficulty").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Difficulty] = row.Count
+	}
+	return counts, nil
+}
+
+// Update saves the name, sort order and description of a difficulty level. A
+// new name is copied to the courses that had the old one.
+func (r *difficultyRepository) Update(level *models.DifficultyLevel, oldName string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(level).
+			Select("name", "sort_order", "description").
+			Updates(level).Error
+		if err != nil {
+			return err
+		}
+		if level.Name == oldName {
+			return nil
+		}
+		// The foreign key already cascades the rename on PostgreSQL
+		return tx.Model(&models.Course{}).
+			Where("difficulty = ?", oldName).
+			Update("difficulty", level.Name).Error
+	})
+}
+
+// Delete deletes a difficulty level by ID
+func (r *difficultyRepository) Delete(id uuid.UUID) error {
+	result := r.db.Where("id = ?", id).Delete(&models.DifficultyLevel{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -2,26 +2,38 @@ package repository
 
 import (
 	"errors"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
 	"sonic-labs/course-enrollment-service/internal/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // EnrollmentRepository defines the interface for enrollment data operations
 type EnrollmentRepository interface {
 	Create(enrollment *models.Enrollment) error
-	GetByStudentEmail(email string) ([]models.Enrollment, error)
+	GetByStudentEmail(email string, termID *uuid.UUID, statuses ...string) ([]models.Enrollment, error)
 	GetByStudentAndCourse(email string, courseID uuid.UUID) (*models.Enrollment, error)
 	ExistsByStudentAndCourse(email string, courseID uuid.UUID) (bool, error)
-	Delete(id uuid.UUID) error
-	GetAllStudents() ([]models.StudentResponse, error)
-	GetAllEnrollments() ([]models.EnrollmentWithCourse, error)
+	GetAllEnrollments(termID *uuid.UUID, statuses ...string) ([]models.EnrollmentWithCourse, error)
 	GetByID(id uuid.UUID) (*models.Enrollment, error)
-	GetStudentsByCourseID(courseID uuid.UUID) ([]string, error)
-	DeleteByStudentAndCourse(studentEmail string, courseID uuid.UUID) error
+	GetStudentsByCourseID(courseID uuid.UUID, statuses ...string) ([]string, error)
+	EnrollOrWaitlist(enrollment *models.Enrollment, override *models.PrerequisiteOverride) (*models.WaitlistEntry, error)
+	TransitionStatus(enrollment *models.Enrollment, toStatus, changedBy string, reason *string) ([]models.Enrollment, error)
+	GetStatusChanges(enrollmentID uuid.UUID) ([]models.EnrollmentStatusChange, error)
+	CountByCourseID(courseID uuid.UUID) (int, error)
 }
 
+// ErrAlreadyEnrolled is returned when the student already has an enrollment in the
+// course, including when a concurrent request created it first
+var ErrAlreadyEnrolled = errors.New("student is already enrolled in this course")
+
+// seatHoldingStatuses are the enrollment statuses that occupy a seat in a course
+var seatHoldingStatuses = []string{constants.EnrollmentStatusPending, constants.EnrollmentStatusActive}
+
 // enrollmentRepository implements EnrollmentRepository interface
 type enrollmentRepository struct {
 	db *gorm.DB
@@ -32,29 +44,33 @@ func NewEnrollmentRepository(db *gorm.DB) EnrollmentRepository {
 	return &enrollmentRepository{db: db}
 }
 
+// Create inserts a new enrollment, returning ErrAlreadyEnrolled if the student
+// already has one in the course
 func (r *enrollmentRepository) Create(enrollment *models.Enrollment) error {
-	exists, err := r.ExistsByStudentAndCourse(enrollment.StudentEmail, enrollment.CourseID)
-	if err != nil {
-		return err
-	}
-	if exists {
-		return errors.New("student is already enrolled in this course")
-	}
-
-	return r.db.Create(enrollment).Error
+	return insertEnrollment(r.db, enrollment)
 }
 
-// GetByStudentEmail retrieves all enrollments for a student
-func (r *enrollmentRepository) GetByStudentEmail(email string) ([]models.Enrollment, error) {
+// GetByStudentEmail retrieves all enrollments for a student, optionally limited to
+// offerings in a term and to the given statuses
+func (r *enrollmentRepository) GetByStudentEmail(email string, termID *uuid.UUID, statuses ...string) ([]models.Enrollment, error) {
 	var enrollments []models.Enrollment
-	err := r.db.Preload("Course").Where("student_email = ?", email).Order("enrolled_at DESC").Find(&enrollments).Error
+	query := r.db.Preload("Course").Preload("Offering.Term").Where("student_email = ?", models.NormalizeEmail(email))
+	if termID != nil {
+		query = inTerm(query, *termID)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	err := query.Order("enrolled_at DESC").Find(&enrollments).Error
 	return enrollments, err
 }
 
 // GetByStudentAndCourse retrieves a specific enrollment
 func (r *enrollmentRepository) GetByStudentAndCourse(email string, courseID uuid.UUID) (*models.Enrollment, error) {
 	var enrollment models.Enrollment
-	err := r.db.Preload("Course").Where("student_email = ? AND course_id = ?", email, courseID).First(&enrollment).Error
+	err := r.db.Preload("Course").Preload("Offering.Term").
+		Where("student_email = ? AND course_id = ?", models.NormalizeEmail(email), courseID).
+		First(&enrollment).Error
 	if err != nil {
 		return nil, err
 	}
@@ -64,89 +80,313 @@ func (r *enrollmentRepository) GetByStudentAndCourse(email string, courseID uuid
 // ExistsByStudentAndCourse checks if an enrollment exists
 func (r *enrollmentRepository) ExistsByStudentAndCourse(email string, courseID uuid.UUID) (bool, error) {
 	var count int64
-	err := r.db.Model(&models.Enrollment{}).Where("student_email = ? AND course_id = ?", email, courseID).Count(&count).Error
+	err := r.db.Model(&models.Enrollment{}).
+		Where("student_email = ? AND course_id = ?", models.NormalizeEmail(email), courseID).
+		Count(&count).Error
 	return count > 0, err
 }
 
-// Delete deletes an enrollment by ID
-func (r *enrollmentRepository) Delete(id uuid.UUID) error {
-	result := r.db.Delete(&models.Enrollment{}, id)
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
-	}
-	return nil
-}
-
 // GetByID retrieves an enrollment by ID
 func (r *enrollmentRepository) GetByID(id uuid.UUID) (*models.Enrollment, error) {
 	var enrollment models.Enrollment
-	err := r.db.Preload("Course").Where("id = ?", id).First(&enrollment).Error
+	err := r.db.Preload("Course").Preload("Offering.Term").Where("id = ?", id).First(&enrollment).Error
 	if err != nil {
 		return nil, err
 	}
 	return &enrollment, nil
 }
 
-// GetAllStudents retrieves all unique students with their enrollment count
-func (r *enrollmentRepository) GetAllStudents() ([]models.StudentResponse, error) {
-	var students []models.StudentResponse
-
-	query := `
-		SELECT
-			student_email as email,
-			COUNT(*) as enrollment_count,
-			MAX(enrolled_at) as last_enrolled_at
-		FROM enrollments
-		GROUP BY student_email
-		ORDER BY enrollment_count DESC, last_enrolled_at DESC
-	`
-
-	err := r.db.Raw(query).Scan(&students).Error
-	return students, err
-}
-
-// GetAllEnrollments retrieves all enrollments with course details
-func (r *enrollmentRepository) GetAllEnrollments() ([]models.EnrollmentWithCourse, error) {
+// GetAllEnrollments retrieves all enrollments with course details, optionally limited
+// to offerings in a term and to the given statuses
+func (r *enrollmentRepository) GetAllEnrollments(termID *uuid.UUID, statuses ...string) ([]models.EnrollmentWithCourse, error) {
 	var enrollments []models.Enrollment
-	err := r.db.Preload("Course").Order("enrolled_at DESC").Find(&enrollments).Error
+	query := r.db.Preload("Course").Preload("Offering.Term")
+	if termID != nil {
+		query = inTerm(query, *termID)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	err := query.Order("enrolled_at DESC").Find(&enrollments).Error
 	if err != nil {
 		return nil, err
 	}
 
 	var result []models.EnrollmentWithCourse
 	for _, enrollment := range enrollments {
+		response := enrollment.ToResponse()
 		result = append(result, models.EnrollmentWithCourse{
 			ID:           enrollment.ID,
+			StudentID:    enrollment.StudentID,
 			StudentEmail: enrollment.StudentEmail,
 			Course:       enrollment.Course.ToResponse(),
+			OfferingID:   enrollment.OfferingID,
+			Term:         response.Term,
 			EnrolledAt:   enrollment.EnrolledAt,
+			Status:       enrollment.Status,
 		})
 	}
 
 	return result, nil
 }
 
-// GetStudentsByCourseID retrieves all student emails enrolled in a specific course
-func (r *enrollmentRepository) GetStudentsByCourseID(courseID uuid.UUID) ([]string, error) {
+// GetStudentsByCourseID retrieves all student emails enrolled in a specific course,
+// optionally limited to the given statuses
+func (r *enrollmentRepository) GetStudentsByCourseID(courseID uuid.UUID, statuses ...string) ([]string, error) {
 	var emails []string
-	err := r.db.Model(&models.Enrollment{}).
-		Where("course_id = ?", courseID).
-		Pluck("student_email", &emails).Error
+	query := r.db.Model(&models.Enrollment{}).Where("course_id = ?", courseID)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	err := query.Pluck("student_email", &emails).Error
 	return emails, err
 }
 
-// DeleteByStudentAndCourse deletes an enrollment by student email and course ID
-func (r *enrollmentRepository) DeleteByStudentAndCourse(studentEmail string, courseID uuid.UUID) error {
-	result := r.db.Where("student_email = ? AND course_id = ?", studentEmail, courseID).
-		Delete(&models.Enrollment{})
+// EnrollOrWaitlist enrolls a student in an offering if it has a free seat,
+// otherwise it appends the student to the course waitlist and returns the new
+// entry. An enrollment without an OfferingID goes to the course's default offering.
+// A previous dropped or withdrawn enrollment of the same student is reactivated
+// rather than duplicated, moving it to the requested offering. The course row is
+// locked for the duration so seats cannot be oversold. A non-nil override is
+// recorded in the same transaction.
+func (r *enrollmentRepository) EnrollOrWaitlist(enrollment *models.Enrollment, override *models.PrerequisiteOverride) (*models.WaitlistEntry, error) {
+	var entry *models.WaitlistEntry
+	enrollment.StudentEmail = models.NormalizeEmail(enrollment.StudentEmail)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		course, err := lockCourse(tx, enrollment.CourseID)
+		if err != nil {
+			return err
+		}
+
+		var existing models.Enrollment
+		err = tx.Where("student_email = ? AND course_id = ?", enrollment.StudentEmail, enrollment.CourseID).First(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		found := err == nil
+		if found && existing.HoldsSeat() {
+			return ErrAlreadyEnrolled
+		}
+
+		offering, err := loadOffering(tx, course.ID, enrollment.OfferingID)
+		if err != nil {
+			return err
+		}
+		enrollment.OfferingID = offering.ID
+
+		if capacity := offering.EffectiveCapacity(course); capacity != nil {
+			taken, err := countSeatsTaken(tx, offering.ID)
+			if err != nil {
+				return err
+			}
+			if taken >= *capacity {
+				if entry, err = appendToWaitlist(tx, course.ID, offering.ID, enrollment.SectionID, enrollment.StudentEmail); err != nil {
+					return err
+				}
+				return recordOverride(tx, override)
+			}
+		}
+
+		if found {
+			if err := reactivate(tx, &existing, offering.ID, enrollment.SectionID, enrollment.StatusChangedBy); err != nil {
+				return err
+			}
+			*enrollment = existing
+		} else if err := createEnrollment(tx, enrollment); err != nil {
+			return err
+		}
+		return recordOverride(tx, override)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// TransitionStatus moves an enrollment from its current status to toStatus and
+// records the change. The update only applies if the stored status still matches
+// enrollment.Status, so concurrent transitions cannot overwrite each other.
+// When the enrollment gives up its seat, waitlisted students are promoted into
+// it within the same transaction.
+func (r *enrollmentRepository) TransitionStatus(enrollment *models.Enrollment, toStatus, changedBy string, reason *string) ([]models.Enrollment, error) {
+	var promoted []models.Enrollment
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		course, err := lockCourse(tx, enrollment.CourseID)
+		if err != nil {
+			return err
+		}
+
+		wasHoldingSeat := enrollment.HoldsSeat()
+		if !wasHoldingSeat && isSeatHolding(toStatus) {
+			offering, err := loadOffering(tx, course.ID, enrollment.OfferingID)
+			if err != nil {
+				return err
+			}
+			if capacity := offering.EffectiveCapacity(course); capacity != nil {
+				taken, err := countSeatsTaken(tx, offering.ID)
+				if err != nil {
+					return err
+				}
+				if taken >= *capacity {
+					return errors.New("course is full")
+				}
+			}
+		}
+
+		if err := setStatus(tx, enrollment, toStatus, changedBy, reason); err != nil {
+			return err
+		}
+
+		if wasHoldingSeat && !enrollment.HoldsSeat() {
+			promoted, err = fillFreeSeats(tx, course.ID)
+			return err
+		}
+		return nil
+	})
+	return promoted, err
+}
+
+// GetStatusChanges retrieves the status history of an enrollment, oldest first
+func (r *enrollmentRepository) GetStatusChanges(enrollmentID uuid.UUID) ([]models.EnrollmentStatusChange, error) {
+	var changes []models.EnrollmentStatusChange
+	err := r.db.Where("enrollment_id = ?", enrollmentID).Order("changed_at ASC").Find(&changes).Error
+	return changes, err
+}
+
+// CountByCourseID counts the enrollments that occupy a seat in any offering of a course
+func (r *enrollmentRepository) CountByCourseID(courseID uuid.UUID) (int, error) {
+	var count int64
+	err := r.db.Model(&models.Enrollment{}).
+		Where("course_id = ? AND status IN ?", courseID, seatHoldingStatuses).
+		Count(&count).Error
+	return int(count), err
+}
+
+// isSeatHolding reports whether an enrollment in the given status occupies a seat
+func isSeatHolding(status string) bool {
+	for _, s := range seatHoldingStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// countSeatsTaken counts the enrollments that occupy a seat in an offering
+func countSeatsTaken(tx *gorm.DB, offeringID uuid.UUID) (int, error) {
+	var count int64
+	err := tx.Model(&models.Enrollment{}).
+		Where("offering_id = ? AND status IN ?", offeringID, seatHoldingStatuses).
+		Count(&count).Error
+	return int(count), err
+}
+
+// inTerm limits an enrollment query to offerings in a term
+func inTerm(query *gorm.DB, termID uuid.UUID) *gorm.DB {
+	return query.Where("offering_id IN (SELECT id FROM course_offerings WHERE term_id = ?)", termID)
+}
+
+// insertEnrollment inserts a new enrollment. Rather than checking for an existing
+// row first, which two concurrent requests could both pass, it leaves the decision
+// to the unique student/course constraint: the losing insert does nothing and
+// ErrAlreadyEnrolled is returned. Skipping the row instead of failing keeps the
+// surrounding Postgres transaction usable. An enrollment without an offering is
+// placed in the course's default offering, and the student is created on their
+// first enrollment.
+func insertEnrollment(tx *gorm.DB, enrollment *models.Enrollment) error {
+	student, err := ensureStudent(tx, enrollment.StudentEmail)
+	if err != nil {
+		return err
+	}
+	enrollment.StudentID = student.ID
+
+	if enrollment.OfferingID == uuid.Nil {
+		offering, err := defaultOffering(tx, enrollment.CourseID)
+		if err != nil {
+			return err
+		}
+		enrollment.OfferingID = offering.ID
+	}
+
+	result := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student_email"}, {Name: "course_id"}},
+		DoNothing: true,
+	}).Create(enrollment)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+		return ErrAlreadyEnrolled
 	}
 	return nil
 }
+
+// createEnrollment inserts a new enrollment and records its initial status
+func createEnrollment(tx *gorm.DB, enrollment *models.Enrollment) error {
+	if err := insertEnrollment(tx, enrollment); err != nil {
+		return err
+	}
+	return tx.Create(&models.EnrollmentStatusChange{
+		EnrollmentID: enrollment.ID,
+		ToStatus:     enrollment.Status,
+		ChangedBy:    enrollment.StatusChangedBy,
+		ChangedAt:    enrollment.StatusChangedAt,
+	}).Error
+}
+
+// recordOverride stores a prerequisite override, if there is one
+func recordOverride(tx *gorm.DB, override *models.PrerequisiteOverride) error {
+	if override == nil {
+		return nil
+	}
+	return tx.Create(override).Error
+}
+
+// reactivate gives a dropped or withdrawn enrollment a seat in an offering, and
+// optionally one of its sections, again
+func reactivate(tx *gorm.DB, enrollment *models.Enrollment, offeringID uuid.UUID, sectionID *uuid.UUID, changedBy string) error {
+	err := tx.Model(&models.Enrollment{}).Where("id = ?", enrollment.ID).Updates(map[string]interface{}{
+		"offering_id": offeringID,
+		"section_id":  sectionID,
+	}).Error
+	if err != nil {
+		return err
+	}
+	enrollment.OfferingID = offeringID
+	enrollment.SectionID = sectionID
+	return setStatus(tx, enrollment, constants.EnrollmentStatusActive, changedBy, nil)
+}
+
+// setStatus updates the status of an enrollment if it still has the status
+// the caller saw, and appends the change to the enrollment history
+func setStatus(tx *gorm.DB, enrollment *models.Enrollment, toStatus, changedBy string, reason *string) error {
+	fromStatus := enrollment.Status
+	changedAt := time.Now()
+
+	result := tx.Model(&models.Enrollment{}).
+		Where("id = ? AND status = ?", enrollment.ID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":            toStatus,
+			"status_changed_at": changedAt,
+			"status_changed_by": changedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("enrollment status was changed concurrently")
+	}
+
+	enrollment.Status = toStatus
+	enrollment.StatusChangedAt = changedAt
+	enrollment.StatusChangedBy = changedBy
+
+	return tx.Create(&models.EnrollmentStatusChange{
+		EnrollmentID: enrollment.ID,
+		FromStatus:   &fromStatus,
+		ToStatus:     toStatus,
+		ChangedBy:    changedBy,
+		Reason:       reason,
+		ChangedAt:    changedAt,
+	}).Error
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openPostgresTestDB connects to the database named by TEST_POSTGRES_DSN, a
+// keyword/value DSN such as "host=localhost user=postgres dbname=test", and runs
+// the migrations in a throwaway schema. The test is skipped when it is unset.
+func openPostgresTestDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	config := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+	admin, err := gorm.Open(postgres.Open(dsn), config)
+	require.NoError(t, err)
+
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	require.NoError(t, admin.Exec("CREATE SCHEMA "+schema).Error)
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	db, err := gorm.Open(postgres.Open(dsn+" search_path="+schema), config)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	migrations, err := filepath.Glob(filepath.Join("..", "..", "migrations", "*.sql"))
+	require.NoError(t, err)
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		migrationSQL, err := os.ReadFile(migration)
+		require.NoError(t, err)
+		require.NoError(t, db.Exec(string(migrationSQL)).Error, "migration %s", migration)
+	}
+
+	return db
+}
+
+// TestEnrollmentRepository_Concurrent_Postgres tests that racing enrollments of the
+// same student leave one row and report ErrAlreadyEnrolled to every loser on Postgres
+func TestEnrollmentRepository_Concurrent_Postgres(t *testing.T) {
+	db := openPostgresTestDB(t)
+	repo := NewEnrollmentRepository(db)
+
+	tests := []struct {
+		name   string
+		enroll func(courseID uuid.UUID) error
+	}{
+		{
+			name: "Create",
+			enroll: func(courseID uuid.UUID) error {
+				return repo.Create(&models.Enrollment{
+					StudentEmail: "student@example.com",
+					CourseID:     courseID,
+				})
+			},
+		},
+		{
+			name: "EnrollOrWaitlist",
+			enroll: func(courseID uuid.UUID) error {
+				_, err := repo.EnrollOrWaitlist(&models.Enrollment{
+					StudentEmail:    "student@example.com",
+					CourseID:        courseID,
+					StatusChangedBy: "admin",
+				}, nil)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			course := &models.Course{
+				Title:       fmt.Sprintf("%s Race Course", tt.name),
+				Description: "Test Description",
+				Difficulty:  "Beginner",
+			}
+			require.NoError(t, db.Create(course).Error)
+
+			succeeded := 0
+			for _, err := range raceEnrollments(func() error { return tt.enroll(course.ID) }) {
+				if err == nil {
+					succeeded++
+					continue
+				}
+				assert.ErrorIs(t, err, ErrAlreadyEnrolled)
+			}
+			assert.Equal(t, 1, succeeded)
+
+			var count int64
+			db.Model(&models.Enrollment{}).Where("course_id = ?", course.ID).Count(&count)
+			assert.Equal(t, int64(1), count)
+		})
+	}
+}
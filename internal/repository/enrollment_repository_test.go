@@ -1,8 +1,12 @@
 package repository
 
 import (
+	"fmt"
+	"path/filepath"
+	"sync"
 	"testing"
 
+	"sonic-labs/course-enrollment-service/internal/constants"
 	"sonic-labs/course-enrollment-service/internal/models"
 
 	"github.com/google/uuid"
@@ -21,9 +25,13 @@ type EnrollmentRepositoryTestSuite struct {
 
 // SetupSuite runs once before all tests in the suite
 func (suite *EnrollmentRepositoryTestSuite) SetupSuite() {
-	// Initialize in-memory SQLite database for testing
+	// Initialize a file-backed SQLite database so concurrent tests get real
+	// separate connections; write transactions take the lock up front, much
+	// like the row lock taken on Postgres
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate",
+		filepath.Join(suite.T().TempDir(), "enrollments.db"))
 	var err error
-	suite.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+	suite.db, err = gorm.Open(sqlite.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	})
 	suite.Require().NoError(err)
@@ -36,6 +44,57 @@ func (suite *EnrollmentRepositoryTestSuite) SetupSuite() {
 			description TEXT NOT NULL,
 			difficulty TEXT NOT NULL,
 			image_url TEXT,
+			capacity INTEGER,
+			enrollment_opens_at DATETIME,
+			enrollment_closes_at DATETIME,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`).Error
+	suite.Require().NoError(err)
+
+	err = suite.db.Exec(`
+		CREATE TABLE terms (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			start_date DATE NOT NULL,
+			end_date DATE NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`).Error
+	suite.Require().NoError(err)
+
+	err = suite.db.Exec(`
+		CREATE TABLE course_offerings (
+			id TEXT PRIMARY KEY,
+			course_id TEXT NOT NULL,
+			term_id TEXT,
+			capacity INTEGER,
+			schedule TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+			FOREIGN KEY (term_id) REFERENCES terms(id),
+			UNIQUE(course_id, term_id)
+		)
+	`).Error
+	suite.Require().NoError(err)
+
+	err = suite.db.Exec(`
+		CREATE UNIQUE INDEX idx_course_offerings_default ON course_offerings(course_id) WHERE term_id IS NULL
+	`).Error
+	suite.Require().NoError(err)
+
+	err = suite.db.Exec(`
+		CREATE TABLE students (
+			id TEXT PRIMARY KEY,
+			email TEXT NOT NULL UNIQUE,
+			first_name TEXT,
+			last_name TEXT,
+			student_number TEXT UNIQUE,
+			invite_hash TEXT UNIQUE,
+			invite_expires_at DATETIME,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)
@@ -45,9 +104,15 @@ func (suite *EnrollmentRepositoryTestSuite) SetupSuite() {
 	err = suite.db.Exec(`
 		CREATE TABLE enrollments (
 			id TEXT PRIMARY KEY,
+			student_id TEXT,
 			student_email TEXT NOT NULL,
 			course_id TEXT NOT NULL,
+			offering_id TEXT,
+			section_id TEXT,
 			enrolled_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			status TEXT NOT NULL DEFAULT 'active',
+			status_changed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			status_changed_by TEXT,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
@@ -56,6 +121,36 @@ func (suite *EnrollmentRepositoryTestSuite) SetupSuite() {
 	`).Error
 	suite.Require().NoError(err)
 
+	err = suite.db.Exec(`
+		CREATE TABLE enrollment_status_changes (
+			id TEXT PRIMARY KEY,
+			enrollment_id TEXT NOT NULL,
+			from_status TEXT,
+			to_status TEXT NOT NULL,
+			changed_by TEXT NOT NULL,
+			reason TEXT,
+			changed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (enrollment_id) REFERENCES enrollments(id) ON DELETE CASCADE
+		)
+	`).Error
+	suite.Require().NoError(err)
+
+	err = suite.db.Exec(`
+		CREATE TABLE waitlist_entries (
+			id TEXT PRIMARY KEY,
+			course_id TEXT NOT NULL,
+			offering_id TEXT,
+			section_id TEXT,
+			student_email TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+			UNIQUE(course_id, student_email)
+		)
+	`).Error
+	suite.Require().NoError(err)
+
 	// Initialize repository
 	suite.repo = NewEnrollmentRepository(suite.db)
 }
@@ -73,7 +168,12 @@ func (suite *EnrollmentRepositoryTestSuite) TearDownSuite() {
 // SetupTest runs before each test
 func (suite *EnrollmentRepositoryTestSuite) SetupTest() {
 	// Clean up test data before each test
+	suite.db.Exec("DELETE FROM waitlist_entries")
+	suite.db.Exec("DELETE FROM enrollment_status_changes")
 	suite.db.Exec("DELETE FROM enrollments")
+	suite.db.Exec("DELETE FROM students")
+	suite.db.Exec("DELETE FROM course_offerings")
+	suite.db.Exec("DELETE FROM terms")
 	suite.db.Exec("DELETE FROM courses")
 }
 
@@ -115,6 +215,25 @@ func (suite *EnrollmentRepositoryTestSuite) TestEnrollmentRepository_Create() {
 	suite.Equal(enrollment.CourseID, dbEnrollment.CourseID)
 }
 
+// TestEnrollmentRepository_Create_LinksStudent tests that enrollments of the same
+// email share one student, created on the first enrollment
+func (suite *EnrollmentRepositoryTestSuite) TestEnrollmentRepository_Create_LinksStudent() {
+	course1 := suite.createTestCourse("Course 1", "Description 1", "Beginner")
+	course2 := suite.createTestCourse("Course 2", "Description 2", "Beginner")
+
+	first := &models.Enrollment{StudentEmail: "student@example.com", CourseID: course1.ID}
+	second := &models.Enrollment{StudentEmail: "student@example.com", CourseID: course2.ID}
+	suite.Require().NoError(suite.repo.Create(first))
+	suite.Require().NoError(suite.repo.Create(second))
+
+	suite.NotEqual(uuid.Nil, first.StudentID)
+	suite.Equal(first.StudentID, second.StudentID)
+
+	var student models.Student
+	suite.Require().NoError(suite.db.First(&student, "id = ?", first.StudentID.String()).Error)
+	suite.Equal("student@example.com", student.Email)
+}
+
 // TestEnrollmentRepository_GetByStudentEmail tests retrieving enrollments by student email
 func (suite *EnrollmentRepositoryTestSuite) TestEnrollmentRepository_GetByStudentEmail() {
 	course1 := suite.createTestCourse("Course 1", "Description 1", "Beginner")
@@ -140,7 +259,7 @@ func (suite *EnrollmentRepositoryTestSuite) TestEnrollmentRepository_GetByStuden
 	suite.NoError(err)
 
 	// Get enrollments by student email
-	enrollments, err := suite.repo.GetByStudentEmail(studentEmail)
+	enrollments, err := suite.repo.GetByStudentEmail(studentEmail, nil)
 
 	suite.NoError(err)
 	suite.Len(enrollments, 2)
@@ -159,7 +278,7 @@ func (suite *EnrollmentRepositoryTestSuite) TestEnrollmentRepository_GetByStuden
 
 // TestEnrollmentRepository_GetByStudentEmail_Empty tests retrieving enrollments when none exist
 func (suite *EnrollmentRepositoryTestSuite) TestEnrollmentRepository_GetByStudentEmail_Empty() {
-	enrollments, err := suite.repo.GetByStudentEmail("nonexistent@example.com")
+	enrollments, err := suite.repo.GetByStudentEmail("nonexistent@example.com", nil)
 
 	suite.NoError(err)
 	suite.Len(enrollments, 0)
@@ -224,41 +343,172 @@ func (suite *EnrollmentRepositoryTestSuite) TestEnrollmentRepository_ExistsByStu
 	suite.True(exists)
 }
 
-// TestEnrollmentRepository_Delete tests deleting an enrollment
-func (suite *EnrollmentRepositoryTestSuite) TestEnrollmentRepository_Delete() {
+// TestEnrollmentRepository_TransitionStatus tests that a status change keeps the row and records history
+func (suite *EnrollmentRepositoryTestSuite) TestEnrollmentRepository_TransitionStatus() {
 	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
 
 	enrollment := &models.Enrollment{
-		ID:           uuid.New(),
-		StudentEmail: "student@example.com",
-		CourseID:     course.ID,
+		StudentEmail:    "student@example.com",
+		CourseID:        course.ID,
+		StatusChangedBy: "admin",
 	}
+	_, err := suite.repo.EnrollOrWaitlist(enrollment, nil)
+	suite.Require().NoError(err)
+	suite.Equal(constants.EnrollmentStatusActive, enrollment.Status)
 
-	err := suite.repo.Create(enrollment)
+	reason := "Schedule conflict"
+	_, err = suite.repo.TransitionStatus(enrollment, constants.EnrollmentStatusDropped, "admin", &reason)
 	suite.NoError(err)
 
-	// Delete enrollment
-	err = suite.repo.Delete(enrollment.ID)
+	// The row is kept with its new status
+	retrievedEnrollment, err := suite.repo.GetByStudentAndCourse(enrollment.StudentEmail, enrollment.CourseID)
 	suite.NoError(err)
+	suite.Equal(constants.EnrollmentStatusDropped, retrievedEnrollment.Status)
+	suite.Equal("admin", retrievedEnrollment.StatusChangedBy)
 
-	// Verify deletion
-	retrievedEnrollment, err := suite.repo.GetByStudentAndCourse(enrollment.StudentEmail, enrollment.CourseID)
+	// Filtering by status hides it from active listings
+	enrollments, err := suite.repo.GetByStudentEmail(enrollment.StudentEmail, nil, constants.EnrollmentStatusActive)
+	suite.NoError(err)
+	suite.Len(enrollments, 0)
+
+	changes, err := suite.repo.GetStatusChanges(enrollment.ID)
+	suite.NoError(err)
+	suite.Require().Len(changes, 2)
+	suite.Nil(changes[0].FromStatus)
+	suite.Equal(constants.EnrollmentStatusActive, changes[0].ToStatus)
+	suite.Equal(constants.EnrollmentStatusActive, *changes[1].FromStatus)
+	suite.Equal(constants.EnrollmentStatusDropped, changes[1].ToStatus)
+	suite.Equal(reason, *changes[1].Reason)
+}
+
+// TestEnrollmentRepository_TransitionStatus_Stale tests that a transition from an outdated status is rejected
+func (suite *EnrollmentRepositoryTestSuite) TestEnrollmentRepository_TransitionStatus_Stale() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+
+	enrollment := &models.Enrollment{
+		StudentEmail:    "student@example.com",
+		CourseID:        course.ID,
+		StatusChangedBy: "admin",
+	}
+	_, err := suite.repo.EnrollOrWaitlist(enrollment, nil)
+	suite.Require().NoError(err)
+
+	stale := *enrollment
+	_, err = suite.repo.TransitionStatus(enrollment, constants.EnrollmentStatusCompleted, "admin", nil)
+	suite.NoError(err)
+
+	_, err = suite.repo.TransitionStatus(&stale, constants.EnrollmentStatusDropped, "admin", nil)
 	suite.Error(err)
-	suite.Nil(retrievedEnrollment)
-	suite.Equal(gorm.ErrRecordNotFound, err)
+
+	retrievedEnrollment, err := suite.repo.GetByID(enrollment.ID)
+	suite.NoError(err)
+	suite.Equal(constants.EnrollmentStatusCompleted, retrievedEnrollment.Status)
 }
 
-// TestEnrollmentRepository_Delete_NotFound tests deleting a non-existent enrollment
-func (suite *EnrollmentRepositoryTestSuite) TestEnrollmentRepository_Delete_NotFound() {
-	nonExistentID := uuid.New()
+// TestEnrollmentRepository_EnrollOrWaitlist_Reactivates tests that re-enrolling after a drop reuses the record
+func (suite *EnrollmentRepositoryTestSuite) TestEnrollmentRepository_EnrollOrWaitlist_Reactivates() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
 
-	err := suite.repo.Delete(nonExistentID)
+	enrollment := &models.Enrollment{
+		StudentEmail:    "student@example.com",
+		CourseID:        course.ID,
+		StatusChangedBy: "admin",
+	}
+	_, err := suite.repo.EnrollOrWaitlist(enrollment, nil)
+	suite.Require().NoError(err)
+	_, err = suite.repo.TransitionStatus(enrollment, constants.EnrollmentStatusDropped, "admin", nil)
+	suite.Require().NoError(err)
 
-	// Should not return error for non-existent record (idempotent operation)
+	again := &models.Enrollment{
+		StudentEmail:    "student@example.com",
+		CourseID:        course.ID,
+		StatusChangedBy: "admin",
+	}
+	_, err = suite.repo.EnrollOrWaitlist(again, nil)
 	suite.NoError(err)
+	suite.Equal(enrollment.ID, again.ID)
+	suite.Equal(constants.EnrollmentStatusActive, again.Status)
+
+	var count int64
+	suite.db.Model(&models.Enrollment{}).Where("course_id = ?", course.ID).Count(&count)
+	suite.Equal(int64(1), count)
+}
+
+// TestEnrollmentRepository_Create_Concurrent tests that racing inserts of the same enrollment leave one row
+func (suite *EnrollmentRepositoryTestSuite) TestEnrollmentRepository_Create_Concurrent() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+
+	errs := raceEnrollments(func() error {
+		return suite.repo.Create(&models.Enrollment{
+			StudentEmail: "student@example.com",
+			CourseID:     course.ID,
+		})
+	})
+	suite.assertSingleWinner(errs)
+
+	var count int64
+	suite.db.Model(&models.Enrollment{}).Where("course_id = ?", course.ID).Count(&count)
+	suite.Equal(int64(1), count)
+}
+
+// TestEnrollmentRepository_EnrollOrWaitlist_Concurrent tests that racing enrollments of the same student leave one row
+func (suite *EnrollmentRepositoryTestSuite) TestEnrollmentRepository_EnrollOrWaitlist_Concurrent() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+
+	errs := raceEnrollments(func() error {
+		_, err := suite.repo.EnrollOrWaitlist(&models.Enrollment{
+			StudentEmail:    "student@example.com",
+			CourseID:        course.ID,
+			StatusChangedBy: "admin",
+		}, nil)
+		return err
+	})
+	suite.assertSingleWinner(errs)
+
+	var count int64
+	suite.db.Model(&models.Enrollment{}).Where("course_id = ?", course.ID).Count(&count)
+	suite.Equal(int64(1), count)
+}
+
+// assertSingleWinner checks that exactly one attempt succeeded and every other one
+// was told the student is already enrolled
+func (suite *EnrollmentRepositoryTestSuite) assertSingleWinner(errs []error) {
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		suite.ErrorIs(err, ErrAlreadyEnrolled)
+	}
+	suite.Equal(1, succeeded)
 }
 
 // TestEnrollmentRepositoryTestSuite runs the enrollment repository test suite
 func TestEnrollmentRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(EnrollmentRepositoryTestSuite))
 }
+
+// concurrentAttempts is how many goroutines race to enroll the same student
+const concurrentAttempts = 20
+
+// raceEnrollments releases concurrentAttempts goroutines running attempt at the
+// same moment and returns the error each one got
+func raceEnrollments(attempt func() error) []error {
+	errs := make([]error, concurrentAttempts)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = attempt()
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyRepository defines the interface for idempotency key data operations.
+// It backs the Idempotency-Key middleware when Redis is disabled.
+type IdempotencyRepository interface {
+	ReserveIdempotencyKey(record *models.IdempotencyRecord) (*models.IdempotencyRecord, error)
+	CompleteIdempotencyKey(record *models.IdempotencyRecord) error
+	ReleaseIdempotencyKey(key string) error
+}
+
+// idempotencyRepository implements IdempotencyRepository interface
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository creates a new idempotency repository
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// ReserveIdempotencyKey stores record if its key is unused, or has expired, and
+// returns nil. If the key is taken it returns the stored record instead. The
+// insert relies on the primary key, so only one concurrent request can win.
+func (r *idempotencyRepository) ReserveIdempotencyKey(record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	err := r.db.Where("idempotency_key = ? AND expires_at <= ?", record.Key, time.Now()).
+		Delete(&models.IdempotencyRecord{}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var existing models.IdempotencyRecord
+	err = r.db.Where("idempotency_key = ?", record.Key).First(&existing).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("idempotency key was released concurrently")
+		}
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// CompleteIdempotencyKey stores the response of a reserved key
+func (r *idempotencyRepository) CompleteIdempotencyKey(record *models.IdempotencyRecord) error {
+	return r.db.Model(&models.IdempotencyRecord{}).
+		Where("idempotency_key = ?", record.Key).
+		Updates(map[string]interface{}{
+			"status_code":   record.StatusCode,
+			"content_type":  record.ContentType,
+			"response_body": record.ResponseBody,
+			"expires_at":    record.ExpiresAt,
+		}).Error
+}
+
+// ReleaseIdempotencyKey forgets a reserved key so the request can be retried
+func (r *idempotencyRepository) ReleaseIdempotencyKey(key string) error {
+	return r.db.Where("idempotency_key = ?", key).Delete(&models.IdempotencyRecord{}).Error
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InstructorRepository defines the interface for instructor data operations
+type InstructorRepository interface {
+	Create(instructor *models.Instructor) error
+	GetAll() ([]models.Instructor, error)
+	GetByID(id uuid.UUID) (*models.Instructor, error)
+	GetByIDs(ids []uuid.UUID) ([]models.Instructor, error)
+	GetByEmail(email string) (*models.Instructor, error)
+	GetCourseIDs() (map[uuid.UUID][]uuid.UUID, error)
+	GetAccountHolders() (map[uuid.UUID]bool, error)
+	Teaches(instructorID, courseID uuid.UUID) (bool, error)
+	Update(instructor *models.Instructor, previousEmail string) error
+	Delete(id uuid.UUID) error
+}
+
+// instructorRepository implements InstructorRepository interface
+type instructorRepository struct {
+	db *gorm.DB
+}
+
+// NewInstructorRepository creates a new instructor repository
+func NewInstructorRepository(db *gorm.DB) InstructorRepository {
+	return &instructorRepository{db: db}
+}
+
+// Create creates a new instructor
+func (r *instructorRepository) Create(instructor *models.Instructor) error {
+	return r.db.Create(instructor).Error
+}
+
+// GetAll retrieves every instructor, sorted by name
+func (r *instructorRepository) GetAll() ([]models.Instructor, error) {
+	var instructors []models.Instructor
+	err := r.db.Order("name ASC").Find(&instructors).Error
+	return instructors, err
+}
+
+// GetByID retrieves an instructor by ID
+func (r *instructorRepository) GetByID(id uuid.UUID) (*models.Instructor, error) {
+	var instructor models.Instructor
+	if err := r.db.Where("id = ?", id).First(&instructor).Error; err != nil {
+		return nil, err
+	}
+	return &instructor, nil
+}
+
+// GetByIDs retrieves the instructors with the given IDs, sorted by name.
+// IDs without an instructor are skipped.
+func (r *instructorRepository) GetByIDs(ids []uuid.UUID) ([]models.Instructor, error) {
+	instructors := []models.Instructor{}
+	if len(ids) == 0 {
+		return instructors, nil
+	}
+	err := r.db.Where("id IN ?", ids).Order("name ASC").Find(&instructors).Error
+	return instructors, err
+}
+
+// GetByEmail retrieves an instructor by email, ignoring case and surrounding whitespace
+func (r *instructorRepository) GetByEmail(email string) (*models.Instructor, error) {
+	var instructor models.Instructor
+	if err := r.db.Where("email = ?", models.NormalizeEmail(email)).First(&instructor).Error; err != nil {
+		return nil, err
+	}
+	return &instructor, nil
+}
+
+// GetCourseIDs returns the IDs of the courses each instructor teaches
+func (r *instructorRepository) GetCourseIDs() (map[uuid.UUID][]uuid.UUID, error) {
+	var rows []struct {
+		InstructorID uuid.UUID
+		CourseID     uuid.UUID
+	}
+	err := r.db.Table("course_instructors").
+		Select("instructor_id, course_id").
+		Order("course_id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	courseIDs := make(map[uuid.UUID][]uuid.UUID)
+	for _, row := range rows {
+		courseIDs[row.InstructorID] = append(courseIDs[row.InstructorID], row.CourseID)
+	}
+	return courseIDs, nil
+}
+
+// GetAccountHolders returns the IDs of the instructors that have an account
+func (r *instructorRepository) GetAccountHolders() (map[uuid.UUID]bool, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&models.User{}).
+		Where("instructor_id IS NOT NULL").
+		Pluck("instructor_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	holders := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		holders[id] = true
+	}
+	return holders, nil
+}
+
+// Teaches reports whether the instructor is one of the instructors of the course
+func (r *instructorRepository) Teaches(instructorID, courseID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Table("course_instructors").
+		Where("instructor_id = ? AND course_id = ?", instructorID, courseID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Update saves the profile of an instructor. When the email changed, the
+// username of their account is changed with it.
+func (r *instructorRepository) Update(instructor *models.Instructor, previousEmail string) error {
+	instructor.Email = models.NormalizeEmail(instructor.Email)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(instructor).
+			Select("name", "email", "bio").
+			Updates(instructor).Error
+		if err != nil {
+			return err
+		}
+		if instructor.Email == previousEmail {
+			return nil
+		}
+		return tx.Model(&models.User{}).
+			Where("instructor_id = ?", instructor.ID).
+			Update("username", instructor.Email).Error
+	})
+}
+
+// Delete deletes an instructor by ID, taking them off their courses and
+// deleting their account
+func (r *instructorRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM course_instructors WHERE instructor_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("instructor_id = ?", id).Delete(&models.User{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&models.Instructor{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ModuleRepository defines the interface for course module and lesson data operations
+type ModuleRepository interface {
+	GetByCourseID(courseID uuid.UUID) ([]models.CourseModule, error)
+	GetByID(courseID, moduleID uuid.UUID) (*models.CourseModule, error)
+	Create(module *models.CourseModule) error
+	Update(module *models.CourseModule) error
+	Delete(courseID, moduleID uuid.UUID) error
+	Reorder(courseID uuid.UUID, moduleIDs []uuid.UUID) error
+	GetLesson(moduleID, lessonID uuid.UUID) (*models.Lesson, error)
+	CreateLesson(lesson *models.Lesson) error
+	UpdateLesson(lesson *models.Lesson) error
+	DeleteLesson(moduleID, lessonID uuid.UUID) error
+	ReorderLessons(moduleID uuid.UUID, lessonIDs []uuid.UUID) error
+}
+
+// moduleRepository implements ModuleRepository interface
+type moduleRepository struct {
+	db *gorm.DB
+}
+
+// NewModuleRepository creates a new module repository
+func NewModuleRepository(db *gorm.DB) ModuleRepository {
+	return &moduleRepository{db: db}
+}
+
+// GetByCourseID retrieves the modules of a course with their lessons, both in order
+func (r *moduleRepository) GetByCourseID(courseID uuid.UUID) ([]models.CourseModule, error) {
+	var modules []models.CourseModule
+	err := r.db.Preload("Lessons", orderByPosition).
+		Where("course_id = ?", courseID).
+		Order("position ASC").
+		Find(&modules).Error
+	return modules, err
+}
+
+// GetByID retrieves a module of a course with its lessons in order
+func (r *moduleRepository) GetByID(courseID, moduleID uuid.UUID) (*models.CourseModule, error) {
+	var module models.CourseModule
+	err := r.db.Preload("Lessons", orderByPosition).
+		Where("id = ? AND course_id = ?", moduleID, courseID).
+		First(&module).Error
+	if err != nil {
+		return nil, err
+	}
+	return &module, nil
+}
+
+// Create appends a module to the end of its course
+func (r *moduleRepository) Create(module *models.CourseModule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockCourse(tx, module.CourseID); err != nil {
+			return err
+		}
+
+		position, err := nextPosition(tx.Model(&models.CourseModule{}).Where("course_id = ?", module.CourseID))
+		if err != nil {
+			return err
+		}
+		module.Position = position
+		return tx.Create(module).Error
+	})
+}
+
+// Update saves the title and description of a module
+func (r *moduleRepository) Update(module *models.CourseModule) error {
+	return r.db.Model(module).
+		Select("title", "description").
+		Updates(module).Error
+}
+
+// Delete removes a module and its lessons and closes the gap in positions
+func (r *moduleRepository) Delete(courseID, moduleID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockCourse(tx, courseID); err != nil {
+			return err
+		}
+
+		if err := tx.Where("module_id = ?", moduleID).Delete(&models.Lesson{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ? AND course_id = ?", moduleID, courseID).Delete(&models.CourseModule{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var ids []uuid.UUID
+		err := tx.Model(&models.CourseModule{}).Where("course_id = ?", courseID).Order("position ASC").Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+		return setPositions(tx, &models.CourseModule{}, "course_id", courseID, ids)
+	})
+}
+
+// Reorder rewrites the positions of the modules of a course in the given order.
+// The caller is responsible for passing every module of the course exactly once.
+func (r *moduleRepository) Reorder(courseID uuid.UUID, moduleIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockCourse(tx, courseID); err != nil {
+			return err
+		}
+		return setPositions(tx, &models.CourseModule{}, "course_id", courseID, moduleIDs)
+	})
+}
+
+// GetLesson retrieves a lesson of a module
+func (r *moduleRepository) GetLesson(moduleID, lessonID uuid.UUID) (*models.Lesson, error) {
+	var lesson models.Lesson
+	err := r.db.Where("id = ? AND module_id = ?", lessonID, moduleID).First(&lesson).Error
+	if err != nil {
+		return nil, err
+	}
+	return &lesson, nil
+}
+
+// CreateLesson appends a lesson to the end of its module
+func (r *moduleRepository) CreateLesson(lesson *models.Lesson) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockModule(tx, lesson.ModuleID); err != nil {
+			return err
+		}
+
+		position, err := nextPosition(tx.Model(&models.Lesson{}).Where("module_id = ?", lesson.ModuleID))
+		if err != nil {
+			return err
+		}
+		lesson.Position = position
+		return tx.Create(lesson).Error
+	})
+}
+
+// UpdateLesson saves the title, content, duration and optional flag of a lesson
+func (r *moduleRepository) UpdateLesson(lesson *models.Lesson) error {
+	return r.db.Model(lesson).
+		Select("title", "content", "duration_minutes", "optional").
+		Updates(lesson).Error
+}
+
+// DeleteLesson removes a lesson and closes the gap in positions
+func (r *moduleRepository) DeleteLesson(moduleID, lessonID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockModule(tx, moduleID); err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ? AND module_id = ?", lessonID, moduleID).Delete(&models.Lesson{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var ids []uuid.UUID
+		err := tx.Model(&models.Lesson{}).Where("module_id = ?", moduleID).Order("position ASC").Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+		return setPositions(tx, &models.Lesson{}, "module_id", moduleID, ids)
+	})
+}
+
+// ReorderLessons rewrites the positions of the lessons of a module in the given order.
+// The caller is responsible for passing every lesson of the module exactly once.
+func (r *moduleRepository) ReorderLessons(moduleID uuid.UUID, lessonIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockModule(tx, moduleID); err != nil {
+			return err
+		}
+		return setPositions(tx, &models.Lesson{}, "module_id", moduleID, lessonIDs)
+	})
+}
+
+// orderByPosition orders preloaded lessons
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+// lockModule locks a module row, on databases that support it, so that
+// concurrent changes to its lessons are serialized
+func lockModule(tx *gorm.DB, moduleID uuid.UUID) error {
+	query := tx
+	if tx.Dialector.Name() == "postgres" {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var module models.CourseModule
+	return query.Where("id = ?", moduleID).First(&module).Error
+}
+
+// nextPosition returns the position after the last row matched by query
+func nextPosition(query *gorm.DB) (int, error) {
+	var last int
+	err := query.Select("COALESCE(MAX(position), 0)").Scan(&last).Error
+	return last + 1, err
+}
+
+// setPositions numbers the rows with the given IDs 1, 2, 3... in order. Every
+// row must belong to the parent identified by parentColumn and parentID.
+func setPositions(tx *gorm.DB, model interface{}, parentColumn string, parentID uuid.UUID, ids []uuid.UUID) error {
+	for i, id := range ids {
+		result := tx.Model(model).
+			Where("id = ? AND "+parentColumn+" = ?", id, parentID).
+			Update("position", i+1)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"errors"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OfferingRepository defines the interface for course offering data operations
+type OfferingRepository interface {
+	GetByCourseID(courseID uuid.UUID, termID *uuid.UUID) ([]models.CourseOffering, error)
+	GetByID(courseID, offeringID uuid.UUID) (*models.CourseOffering, error)
+	GetDefault(courseID uuid.UUID) (*models.CourseOffering, error)
+	ExistsForTerm(courseID, termID uuid.UUID) (bool, error)
+	CountByTermID(termID uuid.UUID) (int, error)
+	CountSeatsTaken(offeringIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	HasEnrollments(offeringID uuid.UUID) (bool, error)
+	Create(offering *models.CourseOffering) error
+	Update(offering *models.CourseOffering) error
+	Delete(courseID, offeringID uuid.UUID) error
+}
+
+// offeringRepository implements OfferingRepository interface
+type offeringRepository struct {
+	db *gorm.DB
+}
+
+// NewOfferingRepository creates a new offering repository
+func NewOfferingRepository(db *gorm.DB) OfferingRepository {
+	return &offeringRepository{db: db}
+}
+
+// GetByCourseID retrieves the offerings of a course with their terms, the default
+// offering first and the rest by term start date. A non-nil termID limits the
+// result to the offering in that term.
+func (r *offeringRepository) GetByCourseID(courseID uuid.UUID, termID *uuid.UUID) ([]models.CourseOffering, error) {
+	var offerings []models.CourseOffering
+	query := r.db.Preload("Term").
+		Select("course_offerings.*").
+		Joins("LEFT JOIN terms ON terms.id = course_offerings.term_id").
+		Where("course_offerings.course_id = ?", courseID)
+	if termID != nil {
+		query = query.Where("course_offerings.term_id = ?", *termID)
+	}
+	err := query.Order("course_offerings.term_id IS NOT NULL, terms.start_date ASC").Find(&offerings).Error
+	return offerings, err
+}
+
+// GetByID retrieves an offering of a course with its term
+func (r *offeringRepository) GetByID(courseID, offeringID uuid.UUID) (*models.CourseOffering, error) {
+	var offering models.CourseOffering
+	err := r.db.Preload("Term").
+		Where("id = ? AND course_id = ?", offeringID, courseID).
+		First(&offering).Error
+	if err != nil {
+		return nil, err
+	}
+	return &offering, nil
+}
+
+// GetDefault retrieves the default offering of a course, creating it on first use
+func (r *offeringRepository) GetDefault(courseID uuid.UUID) (*models.CourseOffering, error) {
+	return defaultOffering(r.db, courseID)
+}
+
+// ExistsForTerm checks whether a course is already offered in a term
+func (r *offeringRepository) ExistsForTerm(courseID, termID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.CourseOffering{}).
+		Where("course_id = ? AND term_id = ?", courseID, termID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CountByTermID counts the course offerings in a term
+func (r *offeringRepository) CountByTermID(termID uuid.UUID) (int, error) {
+	var count int64
+	err := r.db.Model(&models.CourseOffering{}).Where("term_id = ?", termID).Count(&count).Error
+	return int(count), err
+}
+
+// CountSeatsTaken counts the enrollments that occupy a seat in each of the offerings
+func (r *offeringRepository) CountSeatsTaken(offeringIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int, len(offeringIDs))
+	if len(offeringIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		OfferingID uuid.UUID
+		Taken      int
+	}
+	err := r.db.Model(&models.Enrollment{}).
+		Select("offering_id, COUNT(*) AS taken").
+		Where("offering_id IN ? AND status IN ?", offeringIDs, seatHoldingStatuses).
+		Group("offering_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.OfferingID] = row.Taken
+	}
+	return counts, nil
+}
+
+// HasEnrollments reports whether any enrollment, in any status, or waitlist entry
+// belongs to the offering
+func (r *offeringRepository) HasEnrollments(offeringID uuid.UUID) (bool, error) {
+	var enrollments, waiting int64
+	if err := r.db.Model(&models.Enrollment{}).Where("offering_id = ?", offeringID).Count(&enrollments).Error; err != nil {
+		return false, err
+	}
+	if err := r.db.Model(&models.WaitlistEntry{}).Where("offering_id = ?", offeringID).Count(&waiting).Error; err != nil {
+		return false, err
+	}
+	return enrollments+waiting > 0, nil
+}
+
+// Create creates a new offering
+func (r *offeringRepository) Create(offering *models.CourseOffering) error {
+	return r.db.Create(offering).Error
+}
+
+// Update saves the capacity and schedule of an offering
+func (r *offeringRepository) Update(offering *models.CourseOffering) error {
+	return r.db.Model(offering).
+		Select("capacity", "schedule").
+		Updates(offering).Error
+}
+
+// Delete deletes an offering of a course
+func (r *offeringRepository) Delete(courseID, offeringID uuid.UUID) error {
+	result := r.db.Where("id = ? AND course_id = ?", offeringID, courseID).Delete(&models.CourseOffering{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// defaultOffering returns the default offering of a course, creating it on first
+// use. Concurrent callers cannot create two: the losing insert does nothing
+// because of the unique index on the default offering of each course.
+func defaultOffering(tx *gorm.DB, courseID uuid.UUID) (*models.CourseOffering, error) {
+	var offering models.CourseOffering
+	err := tx.Where("course_id = ? AND term_id IS NULL", courseID).First(&offering).Error
+	if err == nil {
+		return &offering, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	offering = models.CourseOffering{CourseID: courseID}
+	err = tx.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "course_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "term_id IS NULL"}}},
+		DoNothing:   true,
+	}).Create(&offering).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Where("course_id = ? AND term_id IS NULL", courseID).First(&offering).Error; err != nil {
+		return nil, err
+	}
+	return &offering, nil
+}
+
+// loadOffering retrieves an offering of a course, or the default offering when
+// offeringID is nil
+func loadOffering(tx *gorm.DB, courseID, offeringID uuid.UUID) (*models.CourseOffering, error) {
+	if offeringID == uuid.Nil {
+		return defaultOffering(tx, courseID)
+	}
+
+	var offering models.CourseOffering
+	if err := tx.Where("id = ? AND course_id = ?", offeringID, courseID).First(&offering).Error; err != nil {
+		return nil, err
+	}
+	return &offering, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PermissionRepository defines the interface for role permission data operations
+type PermissionRepository interface {
+	GetAll() ([]models.RolePermission, error)
+	HasPermission(role, permission string) (bool, error)
+	ReplaceForRole(role string, permissions []string) error
+}
+
+// permissionRepository implements PermissionRepository interface
+type permissionRepository struct {
+	db *gorm.DB
+}
+
+// NewPermissionRepository creates a new role permission repository
+func NewPermissionRepository(db *gorm.DB) PermissionRepository {
+	return &permissionRepository{db: db}
+}
+
+// GetAll retrieves every granted permission, sorted by role and permission
+func (r *permissionRepository) GetAll() ([]models.RolePermission, error) {
+	var permissions []models.RolePermission
+	err := r.db.Order("role ASC, permission ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+// HasPermission reports whether the role has been granted the permission
+func (r *permissionRepository) HasPermission(role, permission string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.RolePermission{}).
+		Where("role = ? AND permission = ?", role, permission).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ReplaceForRole replaces the permissions of a role in a single transaction
+func (r *permissionRepository) ReplaceForRole(role string, permissions []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role = ?", role).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(permissions) == 0 {
+			return nil
+		}
+
+		rows := make([]models.RolePermission, len(permissions))
+		for i, permission := range permissions {
+			rows[i] = models.RolePermission{Role: role, Permission: permission}
+		}
+		return tx.Create(&rows).Error
+	})
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"errors"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PrerequisiteRepository defines the interface for course prerequisite data operations
+type PrerequisiteRepository interface {
+	GetByCourseID(courseID uuid.UUID) ([]models.Course, error)
+	Add(courseID, prerequisiteID uuid.UUID) error
+	Replace(courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error
+	Remove(courseID, prerequisiteID uuid.UUID) error
+	CreateOverride(override *models.PrerequisiteOverride) error
+	GetOverridesByCourseID(courseID uuid.UUID) ([]models.PrerequisiteOverride, error)
+}
+
+// prerequisiteRepository implements PrerequisiteRepository interface
+type prerequisiteRepository struct {
+	db *gorm.DB
+}
+
+// NewPrerequisiteRepository creates a new prerequisite repository
+func NewPrerequisiteRepository(db *gorm.DB) PrerequisiteRepository {
+	return &prerequisiteRepository{db: db}
+}
+
+// GetByCourseID retrieves the courses that must be completed before enrolling in a course
+func (r *prerequisiteRepository) GetByCourseID(courseID uuid.UUID) ([]models.Course, error) {
+	var prerequisites []models.CoursePrerequisite
+	err := r.db.Preload("Prerequisite").Where("course_id = ?", courseID).Order("created_at ASC").Find(&prerequisites).Error
+	if err != nil {
+		return nil, err
+	}
+
+	courses := make([]models.Course, len(prerequisites))
+	for i, prerequisite := range prerequisites {
+		courses[i] = prerequisite.Prerequisite
+	}
+	return courses, nil
+}
+
+// Add makes prerequisiteID a prerequisite of courseID, rejecting edges that would
+// make a course (indirectly) a prerequisite of itself
+func (r *prerequisiteRepository) Add(courseID, prerequisiteID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		graph, err := lockPrerequisiteGraph(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, existing := range graph[courseID] {
+			if existing == prerequisiteID {
+				return errors.New("prerequisite already exists for this course")
+			}
+		}
+		if reaches(graph, prerequisiteID, courseID) {
+			return errors.New("prerequisite would create a cycle")
+		}
+
+		return tx.Create(&models.CoursePrerequisite{
+			CourseID:       courseID,
+			PrerequisiteID: prerequisiteID,
+		}).Error
+	})
+}
+
+// Replace sets the complete list of prerequisites of a course, rejecting the
+// whole list if any of it would create a cycle
+func (r *prerequisiteRepository) Replace(courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		graph, err := lockPrerequisiteGraph(tx)
+		if err != nil {
+			return err
+		}
+
+		delete(graph, courseID)
+		for _, prerequisiteID := range prerequisiteIDs {
+			if reaches(graph, prerequisiteID, courseID) {
+				return errors.New("prerequisite would create a cycle")
+			}
+		}
+
+		if err := tx.Where("course_id = ?", courseID).Delete(&models.CoursePrerequisite{}).Error; err != nil {
+			return err
+		}
+		for _, prerequisiteID := range prerequisiteIDs {
+			err := tx.Create(&models.CoursePrerequisite{
+				CourseID:       courseID,
+				PrerequisiteID: prerequisiteID,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Remove removes a prerequisite from a course
+func (r *prerequisiteRepository) Remove(courseID, prerequisiteID uuid.UUID) error {
+	result := r.db.Where("course_id = ? AND prerequisite_id = ?", courseID, prerequisiteID).
+		Delete(&models.CoursePrerequisite{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CreateOverride records that the prerequisite check was skipped for an enrollment
+func (r *prerequisiteRepository) CreateOverride(override *models.PrerequisiteOverride) error {
+	return r.db.Create(override).Error
+}
+
+// GetOverridesByCourseID retrieves the prerequisite overrides of a course, newest first
+func (r *prerequisiteRepository) GetOverridesByCourseID(courseID uuid.UUID) ([]models.PrerequisiteOverride, error) {
+	var overrides []models.PrerequisiteOverride
+	err := r.db.Where("course_id = ?", courseID).Order("created_at DESC").Find(&overrides).Error
+	return overrides, err
+}
+
+// lockPrerequisiteGraph loads every prerequisite edge as an adjacency list from
+// course to its prerequisites. On databases that support it the table is locked
+// against concurrent writers, so two edges added at once cannot form a cycle.
+func lockPrerequisiteGraph(tx *gorm.DB) (map[uuid.UUID][]uuid.UUID, error) {
+	if tx.Dialector.Name() == "postgres" {
+		if err := tx.Exec("LOCK TABLE course_prerequisites IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var edges []models.CoursePrerequisite
+	if err := tx.Select("course_id", "prerequisite_id").Find(&edges).Error; err != nil {
+		return nil, err
+	}
+
+	graph := make(map[uuid.UUID][]uuid.UUID)
+	for _, edge := range edges {
+		graph[edge.CourseID] = append(graph[edge.CourseID], edge.PrerequisiteID)
+	}
+	return graph, nil
+}
+
+// reaches reports whether target can be reached from start by following prerequisite edges
+func reaches(graph map[uuid.UUID][]uuid.UUID, start, target uuid.UUID) bool {
+	visited := map[uuid.UUID]bool{start: true}
+	stack := []uuid.UUID{start}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == target {
+			return true
+		}
+		for _, next := range graph[current] {
+			if !visited[next] {
+				visited[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	return false
+}
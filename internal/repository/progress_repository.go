@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProgressRepository defines the interface for lesson progress data operations
+type ProgressRepository interface {
+	GetByEnrollmentID(enrollmentID uuid.UUID) ([]models.LessonProgress, error)
+	Record(progress *models.LessonProgress) error
+	GetSummaries(enrollmentIDs []uuid.UUID) (map[uuid.UUID]models.EnrollmentProgress, error)
+}
+
+// progressRepository implements ProgressRepository interface
+type progressRepository struct {
+	db *gorm.DB
+}
+
+// NewProgressRepository creates a new progress repository
+func NewProgressRepository(db *gorm.DB) ProgressRepository {
+	return &progressRepository{db: db}
+}
+
+// GetByEnrollmentID retrieves the progress recorded for every lesson of an enrollment
+func (r *progressRepository) GetByEnrollmentID(enrollmentID uuid.UUID) ([]models.LessonProgress, error) {
+	var records []models.LessonProgress
+	err := r.db.Where("enrollment_id = ?", enrollmentID).Find(&records).Error
+	return records, err
+}
+
+// Record moves a lesson of an enrollment forward to progress.Status and loads the
+// stored record into progress. Progress never goes backwards: starting a lesson
+// that is already completed leaves it completed. The first record for a lesson
+// is inserted with ON CONFLICT DO NOTHING, so concurrent requests cannot fail on
+// the unique enrollment/lesson constraint.
+func (r *progressRepository) Record(progress *models.LessonProgress) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		record := models.LessonProgress{
+			EnrollmentID: progress.EnrollmentID,
+			LessonID:     progress.LessonID,
+			Status:       progress.Status,
+			StartedAt:    now,
+		}
+		if progress.Status == constants.LessonProgressCompleted {
+			record.CompletedAt = &now
+		}
+
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "enrollment_id"}, {Name: "lesson_id"}},
+			DoNothing: true,
+		}).Create(&record).Error
+		if err != nil {
+			return err
+		}
+
+		if progress.Status == constants.LessonProgressCompleted {
+			err := tx.Model(&models.LessonProgress{}).
+				Where("enrollment_id = ? AND lesson_id = ? AND status = ?", progress.EnrollmentID, progress.LessonID, constants.LessonProgressStarted).
+				Updates(map[string]interface{}{
+					"status":       constants.LessonProgressCompleted,
+					"completed_at": now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("enrollment_id = ? AND lesson_id = ?", progress.EnrollmentID, progress.LessonID).
+			First(progress).Error
+	})
+}
+
+// GetSummaries computes the progress of each of the given enrollments in two
+// grouped queries. Enrollments without lessons or progress get an empty summary.
+func (r *progressRepository) GetSummaries(enrollmentIDs []uuid.UUID) (map[uuid.UUID]models.EnrollmentProgress, error) {
+	summaries := make(map[uuid.UUID]models.EnrollmentProgress, len(enrollmentIDs))
+	if len(enrollmentIDs) == 0 {
+		return summaries, nil
+	}
+
+	var required []struct {
+		EnrollmentID    uuid.UUID
+		RequiredLessons int
+	}
+	err := r.db.Table("enrollments").
+		Select("enrollments.id AS enrollment_id, COUNT(lessons.id) AS required_lessons").
+		Joins("JOIN course_modules ON course_modules.course_id = enrollments.course_id").
+		Joins("JOIN lessons ON lessons.module_id = course_modules.id AND lessons.optional = ?", false).
+		Where("enrollments.id IN ?", enrollmentIDs).
+		Group("enrollments.id").
+		Scan(&required).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var done []struct {
+		EnrollmentID             uuid.UUID
+		LessonsStarted           int
+		LessonsCompleted         int
+		RequiredLessonsCompleted int
+	}
+	err = r.db.Table("lesson_progress").
+		Select(`lesson_progress.enrollment_id,
+			SUM(CASE WHEN lesson_progress.status = ? THEN 1 ELSE 0 END) AS lessons_started,
+			SUM(CASE WHEN lesson_progress.status = ? THEN 1 ELSE 0 END) AS lessons_completed,
+			SUM(CASE WHEN lesson_progress.status = ? AND lessons.optional = ? THEN 1 ELSE 0 END) AS required_lessons_completed`,
+			constants.LessonProgressStarted, constants.LessonProgressCompleted, constants.LessonProgressCompleted, false).
+		Joins("JOIN lessons ON lessons.id = lesson_progress.lesson_id").
+		Where("lesson_progress.enrollment_id IN ?", enrollmentIDs).
+		Group("lesson_progress.enrollment_id").
+		Scan(&done).Error
+	if err != nil {
+		return nil, err
+	}
+
+	requiredByEnrollment := make(map[uuid.UUID]int, len(required))
+	for _, row := range required {
+		requiredByEnrollment[row.EnrollmentID] = row.RequiredLessons
+	}
+	for _, id := range enrollmentIDs {
+		summaries[id] = models.NewEnrollmentProgress(requiredByEnrollment[id], 0, 0, 0)
+	}
+	for _, row := range done {
+		summaries[row.EnrollmentID] = models.NewEnrollmentProgress(
+			requiredByEnrollment[row.EnrollmentID], row.RequiredLessonsCompleted, row.LessonsStarted, row.LessonsCompleted)
+	}
+	return summaries, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RevokedTokenRepository keeps the token denylist in the database when Redis is disabled
+type RevokedTokenRepository interface {
+	RevokeToken(id string, expiresAt time.Time) error
+	MarkTokenUsed(id string, expiresAt time.Time) (bool, error)
+	IsTokenRevoked(id string) (bool, error)
+}
+
+// revokedTokenRepository implements RevokedTokenRepository interface
+type revokedTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRevokedTokenRepository creates a new revoked token repository
+func NewRevokedTokenRepository(db *gorm.DB) RevokedTokenRepository {
+	return &revokedTokenRepository{db: db}
+}
+
+// RevokeToken puts a token or session ID on the denylist until expiresAt. Entries
+// that have expired are cleared on the way.
+func (r *revokedTokenRepository) RevokeToken(id string, expiresAt time.Time) error {
+	now := time.Now()
+	if err := r.db.Where("expires_at <= ?", now).Delete(&models.RevokedToken{}).Error; err != nil {
+		return err
+	}
+	if !expiresAt.After(now) {
+		return nil
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"expires_at"}),
+	}).Create(&models.RevokedToken{ID: id, ExpiresAt: expiresAt}).Error
+}
+
+// MarkTokenUsed puts a token ID on the denylist until expiresAt and reports
+// whether this call put it there. Of concurrent calls for the same ID only one
+// gets true.
+func (r *revokedTokenRepository) MarkTokenUsed(id string, expiresAt time.Time) (bool, error) {
+	now := time.Now()
+	if err := r.db.Where("expires_at <= ?", now).Delete(&models.RevokedToken{}).Error; err != nil {
+		return false, err
+	}
+	if !expiresAt.After(now) {
+		return false, nil
+	}
+
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoNothing: true,
+	}).Create(&models.RevokedToken{ID: id, ExpiresAt: expiresAt})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// IsTokenRevoked reports whether a token or session ID is on the denylist
+func (r *revokedTokenRepository) IsTokenRevoked(id string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.RevokedToken{}).
+		Where("id = ? AND expires_at > ?", id, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
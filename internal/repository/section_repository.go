@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SectionRepository defines the interface for class section data operations
+type SectionRepository interface {
+	GetByCourseID(courseID uuid.UUID, offeringID *uuid.UUID) ([]models.Section, error)
+	GetByID(courseID, sectionID uuid.UUID) (*models.Section, error)
+	ExistsByName(offeringID uuid.UUID, name string, excludeID uuid.UUID) (bool, error)
+	HasEnrollments(sectionID uuid.UUID) (bool, error)
+	GetStudentSchedule(email string, termID *uuid.UUID) ([]models.Enrollment, error)
+	Create(section *models.Section) error
+	Update(section *models.Section) error
+	Delete(courseID, sectionID uuid.UUID) error
+}
+
+// sectionRepository implements SectionRepository interface
+type sectionRepository struct {
+	db *gorm.DB
+}
+
+// NewSectionRepository creates a new section repository
+func NewSectionRepository(db *gorm.DB) SectionRepository {
+	return &sectionRepository{db: db}
+}
+
+// GetByCourseID retrieves the sections of a course with their meetings and terms,
+// optionally only those of one offering
+func (r *sectionRepository) GetByCourseID(courseID uuid.UUID, offeringID *uuid.UUID) ([]models.Section, error) {
+	var sections []models.Section
+	query := preloadSection(r.db).Where("course_id = ?", courseID)
+	if offeringID != nil {
+		query = query.Where("offering_id = ?", *offeringID)
+	}
+	err := query.Order("name ASC").Find(&sections).Error
+	return sections, err
+}
+
+// GetByID retrieves a section of a course with its meetings and term
+func (r *sectionRepository) GetByID(courseID, sectionID uuid.UUID) (*models.Section, error) {
+	var section models.Section
+	err := preloadSection(r.db).
+		Where("id = ? AND course_id = ?", sectionID, courseID).
+		First(&section).Error
+	if err != nil {
+		return nil, err
+	}
+	return &section, nil
+}
+
+// ExistsByName checks whether another section of the offering already has the name
+func (r *sectionRepository) ExistsByName(offeringID uuid.UUID, name string, excludeID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Section{}).
+		Where("offering_id = ? AND name = ? AND id <> ?", offeringID, name, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HasEnrollments reports whether any enrollment, in any status, or waitlist entry
+// names the section
+func (r *sectionRepository) HasEnrollments(sectionID uuid.UUID) (bool, error) {
+	var enrollments, waiting int64
+	if err := r.db.Model(&models.Enrollment{}).Where("section_id = ?", sectionID).Count(&enrollments).Error; err != nil {
+		return false, err
+	}
+	if err := r.db.Model(&models.WaitlistEntry{}).Where("section_id = ?", sectionID).Count(&waiting).Error; err != nil {
+		return false, err
+	}
+	return enrollments+waiting > 0, nil
+}
+
+// GetStudentSchedule retrieves the enrollments of a student that hold a seat in
+// a section, with the course and the section's meetings and term, optionally
+// limited to offerings in a term
+func (r *sectionRepository) GetStudentSchedule(email string, termID *uuid.UUID) ([]models.Enrollment, error) {
+	var enrollments []models.Enrollment
+	query := r.db.Preload("Course").
+		Preload("Section.Meetings", orderMeetings).
+		Preload("Section.Offering.Term").
+		Where("student_email = ? AND section_id IS NOT NULL AND status IN ?", models.NormalizeEmail(email), seatHoldingStatuses)
+	if termID != nil {
+		query = inTerm(query, *termID)
+	}
+	err := query.Find(&enrollments).Error
+	return enrollments, err
+}
+
+// Create creates a new section together with its meetings
+func (r *sectionRepository) Create(section *models.Section) error {
+	return r.db.Create(section).Error
+}
+
+// Update saves the name of a section and replaces its meetings
+func (r *sectionRepository) Update(section *models.Section) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(section).Select("name").Updates(section).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("section_id = ?", section.ID).Delete(&models.SectionMeeting{}).Error; err != nil {
+			return err
+		}
+		for i := range section.Meetings {
+			section.Meetings[i].ID = uuid.Nil
+			section.Meetings[i].SectionID = section.ID
+		}
+		if len(section.Meetings) == 0 {
+			return nil
+		}
+		return tx.Create(&section.Meetings).Error
+	})
+}
+
+// Delete deletes a section of a course and its meetings
+func (r *sectionRepository) Delete(courseID, sectionID uuid.UUID) error {
+	result := r.db.Where("id = ? AND course_id = ?", sectionID, courseID).Delete(&models.Section{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// preloadSection loads the meetings, in weekly order, and the offering term of sections
+func preloadSection(db *gorm.DB) *gorm.DB {
+	return db.Preload("Meetings", orderMeetings).Preload("Offering.Term")
+}
+
+// orderMeetings sorts section meetings by day of the week, then start time
+func orderMeetings(db *gorm.DB) *gorm.DB {
+	return db.Order(`CASE day
+		WHEN 'monday' THEN 1 WHEN 'tuesday' THEN 2 WHEN 'wednesday' THEN 3 WHEN 'thursday' THEN 4
+		WHEN 'friday' THEN 5 WHEN 'saturday' THEN 6 ELSE 7 END`).Order("start_time ASC")
+}
@@ -0,0 +1,434 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StudentRepository defines the interface for student data operations
+type StudentRepository interface {
+	GetAllWithStats() ([]models.StudentResponse, error)
+	GetWithStats(id uuid.UUID) (*models.StudentResponse, error)
+	GetByID(id uuid.UUID) (*models.Student, error)
+	ExistsByEmail(email string, excludeID uuid.UUID) (bool, error)
+	ExistsByStudentNumber(number string, excludeID uuid.UUID) (bool, error)
+	HasEnrollments(id uuid.UUID) (bool, error)
+	Create(student *models.Student) error
+	Update(student *models.Student, previousEmail string) error
+	SetInvite(id uuid.UUID, hash string, expiresAt time.Time) error
+	Delete(id uuid.UUID) error
+	Merge(source, target *models.Student, merge *models.StudentMerge) ([]models.Enrollment, error)
+	GetMerges(targetID uuid.UUID) ([]models.StudentMerge, error)
+}
+
+// studentRepository implements StudentRepository interface
+type studentRepository struct {
+	db *gorm.DB
+}
+
+// NewStudentRepository creates a new student repository
+func NewStudentRepository(db *gorm.DB) StudentRepository {
+	return &studentRepository{db: db}
+}
+
+// studentStats is a student row with the statistics of its enrollments
+type studentStats struct {
+	models.Student
+	EnrollmentCount int
+	LastEnrolledAt  *string
+}
+
+// GetAllWithStats retrieves all students with their enrollment count, most
+// enrollments first
+func (r *studentRepository) GetAllWithStats() ([]models.StudentResponse, error) {
+	var rows []studentStats
+	if err := r.withStats().Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	students := make([]models.StudentResponse, len(rows))
+	for i, row := range rows {
+		students[i] = row.toResponse()
+	}
+	return students, nil
+}
+
+// GetWithStats retrieves a student with their enrollment count
+func (r *studentRepository) GetWithStats(id uuid.UUID) (*models.StudentResponse, error) {
+	var row studentStats
+	if err := r.withStats().Where("students.id = ?", id).First(&row).Error; err != nil {
+		return nil, err
+	}
+
+	response := row.toResponse()
+	return &response, nil
+}
+
+// GetByID retrieves a student by ID
+func (r *studentRepository) GetByID(id uuid.UUID) (*models.Student, error) {
+	var student models.Student
+	if err := r.db.Where("id = ?", id).First(&student).Error; err != nil {
+		return nil, err
+	}
+	return &student, nil
+}
+
+// ExistsByEmail checks whether another student already has the email
+func (r *studentRepository) ExistsByEmail(email string, excludeID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Student{}).
+		Where("email = ? AND id <> ?", models.NormalizeEmail(email), excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ExistsByStudentNumber checks whether another student already has the student number
+func (r *studentRepository) ExistsByStudentNumber(number string, excludeID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Student{}).
+		Where("student_number = ? AND id <> ?", number, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HasEnrollments reports whether the student has any enrollment, in any status,
+// or waitlist entry
+func (r *studentRepository) HasEnrollments(id uuid.UUID) (bool, error) {
+	var enrollments, waiting int64
+	if err := r.db.Model(&models.Enrollment{}).Where("student_id = ?", id).Count(&enrollments).Error; err != nil {
+		return false, err
+	}
+	err := r.db.Model(&models.WaitlistEntry{}).
+		Where("student_email IN (SELECT email FROM students WHERE id = ?)", id).
+		Count(&waiting).Error
+	if err != nil {
+		return false, err
+	}
+	return enrollments+waiting > 0, nil
+}
+
+// Create creates a new student
+func (r *studentRepository) Create(student *models.Student) error {
+	return r.db.Create(student).Error
+}
+
+// Update saves the profile of a student. When the email changed, the copy on the
+// student's enrollments and waitlist entries and the username of their account
+// are changed with it.
+func (r *studentRepository) Update(student *models.Student, previousEmail string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(student).
+			Select("email", "first_name", "last_name", "student_number").
+			Updates(student).Error
+		if err != nil {
+			return err
+		}
+		if student.Email == previousEmail {
+			return nil
+		}
+
+		err = tx.Model(&models.Enrollment{}).
+			Where("student_id = ?", student.ID).
+			Update("student_email", student.Email).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&models.User{}).
+			Where("student_id = ?", student.ID).
+			Update("username", student.Email).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.WaitlistEntry{}).
+			Where("student_email = ?", previousEmail).
+			Update("student_email", student.Email).Error
+	})
+}
+
+// SetInvite stores the hash of an account invite token for the student,
+// replacing any earlier one. It fails with ErrStudentHasAccount if the student
+// already has an account.
+func (r *studentRepository) SetInvite(id uuid.UUID, hash string, expiresAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.User{}).Where("student_id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrStudentHasAccount
+		}
+
+		result := tx.Model(&models.Student{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"invite_hash":       hash,
+			"invite_expires_at": expiresAt,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// Delete deletes a student
+func (r *studentRepository) Delete(id uuid.UUID) error {
+	result := r.db.Where("id = ?", id).Delete(&models.Student{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// mergePrecedence ranks enrollment statuses by how far along the student got, so
+// that a merge keeps the most meaningful of two enrollments in the same course
+var mergePrecedence = map[string]int{
+	constants.EnrollmentStatusCompleted: 0,
+	constants.EnrollmentStatusActive:    1,
+	constants.EnrollmentStatusPending:   2,
+	constants.EnrollmentStatusDropped:   3,
+	constants.EnrollmentStatusWithdrawn: 4,
+}
+
+// Merge moves the enrollments and waitlist entries of source to target, deletes
+// source and records merge, all in one transaction. When both students are
+// enrolled in a course the enrollment furthest along is kept, the target's on a
+// tie. Waitlist entries are dropped for courses the merged student holds a seat
+// in, and otherwise the better position is kept. Seats freed by discarded
+// enrollments go to the waitlist; the promoted enrollments are returned.
+func (r *studentRepository) Merge(source, target *models.Student, merge *models.StudentMerge) ([]models.Enrollment, error) {
+	var promoted []models.Enrollment
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var sourceEnrollments, targetEnrollments []models.Enrollment
+		if err := tx.Where("student_id = ?", source.ID).Find(&sourceEnrollments).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("student_id = ?", target.ID).Find(&targetEnrollments).Error; err != nil {
+			return err
+		}
+		byCourse := make(map[uuid.UUID]models.Enrollment, len(targetEnrollments))
+		for _, enrollment := range targetEnrollments {
+			byCourse[enrollment.CourseID] = enrollment
+		}
+
+		var discarded []string
+		var freedCourses []uuid.UUID
+		for _, enrollment := range sourceEnrollments {
+			if existing, ok := byCourse[enrollment.CourseID]; ok {
+				loser := enrollment
+				if mergePrecedence[enrollment.Status] < mergePrecedence[existing.Status] {
+					loser = existing
+				}
+				if err := tx.Delete(&models.Enrollment{}, "id = ?", loser.ID).Error; err != nil {
+					return err
+				}
+				discarded = append(discarded, loser.ID.String())
+				if loser.HoldsSeat() {
+					freedCourses = append(freedCourses, loser.CourseID)
+				}
+				if loser.ID == enrollment.ID {
+					continue
+				}
+			}
+
+			err := tx.Model(&models.Enrollment{}).Where("id = ?", enrollment.ID).Updates(map[string]interface{}{
+				"student_id":    target.ID,
+				"student_email": target.Email,
+			}).Error
+			if err != nil {
+				return err
+			}
+			merge.MovedEnrollments++
+		}
+		merge.DiscardedEnrollmentIDs = strings.Join(discarded, ",")
+
+		var sourceEntries []models.WaitlistEntry
+		if err := tx.Where("student_email = ?", source.Email).Find(&sourceEntries).Error; err != nil {
+			return err
+		}
+		moved := make(map[uuid.UUID]bool, len(sourceEntries))
+		for _, entry := range sourceEntries {
+			var existing models.WaitlistEntry
+			err := tx.Where("course_id = ? AND student_email = ?", entry.CourseID, target.Email).First(&existing).Error
+			if err == nil {
+				loser := entry
+				if entry.Position < existing.Position {
+					loser = existing
+				}
+				if err := tx.Delete(&models.WaitlistEntry{}, "id = ?", loser.ID).Error; err != nil {
+					return err
+				}
+				merge.DiscardedWaitlistEntries++
+				if err := renumberWaitlist(tx, entry.CourseID); err != nil {
+					return err
+				}
+				if loser.ID == entry.ID {
+					continue
+				}
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			err = tx.Model(&models.WaitlistEntry{}).Where("id = ?", entry.ID).Update("student_email", target.Email).Error
+			if err != nil {
+				return err
+			}
+			moved[entry.ID] = true
+		}
+
+		// A student holding a seat in a course has no business on its waitlist
+		var stale []models.WaitlistEntry
+		held := tx.Model(&models.Enrollment{}).
+			Select("course_id").
+			Where("student_id = ? AND status IN ?", target.ID, seatHoldingStatuses)
+		if err := tx.Where("student_email = ? AND course_id IN (?)", target.Email, held).Find(&stale).Error; err != nil {
+			return err
+		}
+		for _, entry := range stale {
+			if err := tx.Delete(&models.WaitlistEntry{}, "id = ?", entry.ID).Error; err != nil {
+				return err
+			}
+			delete(moved, entry.ID)
+			merge.DiscardedWaitlistEntries++
+			if err := renumberWaitlist(tx, entry.CourseID); err != nil {
+				return err
+			}
+		}
+		merge.MovedWaitlistEntries = len(moved)
+
+		err := tx.Model(&models.PrerequisiteOverride{}).
+			Where("student_email = ?", source.Email).
+			Update("student_email", target.Email).Error
+		if err != nil {
+			return err
+		}
+
+		// The source's account moves along unless the target has one, in which
+		// case it goes with the source student
+		var accounts int64
+		if err := tx.Model(&models.User{}).Where("student_id = ?", target.ID).Count(&accounts).Error; err != nil {
+			return err
+		}
+		if accounts == 0 {
+			err = tx.Model(&models.User{}).Where("student_id = ?", source.ID).Updates(map[string]interface{}{
+				"student_id": target.ID,
+				"username":   target.Email,
+			}).Error
+		} else {
+			err = tx.Where("student_id = ?", source.ID).Delete(&models.User{}).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.Student{}, "id = ?", source.ID).Error; err != nil {
+			return err
+		}
+
+		// Keep what the source knew about the student where the target knows nothing
+		if target.FirstName == nil {
+			target.FirstName = source.FirstName
+		}
+		if target.LastName == nil {
+			target.LastName = source.LastName
+		}
+		if target.StudentNumber == nil {
+			target.StudentNumber = source.StudentNumber
+		}
+		err = tx.Model(target).
+			Select("first_name", "last_name", "student_number").
+			Updates(target).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(merge).Error; err != nil {
+			return err
+		}
+
+		for _, courseID := range freedCourses {
+			filled, err := fillFreeSeats(tx, courseID)
+			if err != nil {
+				return err
+			}
+			promoted = append(promoted, filled...)
+		}
+		return nil
+	})
+	return promoted, err
+}
+
+// GetMerges retrieves the merges into a student, newest first
+func (r *studentRepository) GetMerges(targetID uuid.UUID) ([]models.StudentMerge, error) {
+	var merges []models.StudentMerge
+	err := r.db.Where("target_student_id = ?", targetID).Order("created_at DESC").Find(&merges).Error
+	return merges, err
+}
+
+// withStats selects students joined with the count and latest date of their
+// enrollments that are not dropped or withdrawn
+func (r *studentRepository) withStats() *gorm.DB {
+	return r.db.Model(&models.Student{}).
+		Select("students.*, COUNT(enrollments.id) AS enrollment_count, MAX(enrollments.enrolled_at) AS last_enrolled_at").
+		Joins("LEFT JOIN enrollments ON enrollments.student_id = students.id AND enrollments.status NOT IN (?, ?)",
+			constants.EnrollmentStatusDropped, constants.EnrollmentStatusWithdrawn).
+		Group("students.id").
+		Order("enrollment_count DESC, last_enrolled_at DESC, students.email ASC")
+}
+
+// toResponse converts a student row with statistics to StudentResponse
+func (s *studentStats) toResponse() models.StudentResponse {
+	response := models.StudentResponse{
+		ID:              s.ID,
+		Email:           s.Email,
+		FirstName:       s.FirstName,
+		LastName:        s.LastName,
+		StudentNumber:   s.StudentNumber,
+		EnrollmentCount: s.EnrollmentCount,
+		CreatedAt:       s.CreatedAt,
+	}
+	if s.LastEnrolledAt != nil {
+		response.LastEnrolledAt = *s.LastEnrolledAt
+	}
+	return response
+}
+
+// ensureStudent returns the student with the normalized email, creating it if there is none.
+// A concurrent request creating the same student is not an error.
+func ensureStudent(tx *gorm.DB, email string) (*models.Student, error) {
+	email = models.NormalizeEmail(email)
+	var student models.Student
+	err := tx.Where("email = ?", email).First(&student).Error
+	if err == nil {
+		return &student, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	student = models.Student{Email: email}
+	err = tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "email"}},
+		DoNothing: true,
+	}).Create(&student).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Where("email = ?", email).First(&student).Error; err != nil {
+		return nil, err
+	}
+	return &student, nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepository defines the interface for course tag data operations
+type TagRepository interface {
+	Create(tag *models.Tag) error
+	GetAll() ([]models.Tag, error)
+	GetByID(id uuid.UUID) (*models.Tag, error)
+	GetByName(name string) (*models.Tag, error)
+	FindOrCreate(names []string) ([]models.Tag, error)
+	CountCourses() (map[uuid.UUID]int, error)
+	Update(tag *models.Tag) error
+	Delete(id uuid.UUID) error
+}
+
+// tagRepository implements TagRepository interface
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// Create creates a new tag
+func (r *tagRepository) Create(tag *models.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+// GetAll retrieves every tag, sorted by name
+func (r *tagRepository) GetAll() ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.Order("name ASC").Find(&tags).Error
+	return tags, err
+}
+
+// GetByID retrieves a tag by ID
+func (r *tagRepository) GetByID(id uuid.UUID) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.Where("id = ?", id).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetByName retrieves a tag by name, ignoring case and surrounding whitespace
+func (r *tagRepository) GetByName(name string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.Where("name = ?", models.NormalizeTag(name)).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindOrCreate retrieves the tags with the given names, creating the ones that
+// do not exist yet. Names are normalized first and duplicates are merged.
+func (r *tagRepository) FindOrCreate(names []string) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	seen := make(map[string]bool, len(names))
+	var normalized []string
+	for _, name := range names {
+		name = models.NormalizeTag(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+		tags = append(tags, models.Tag{Name: name})
+	}
+	if len(tags) == 0 {
+		return tags, nil
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Tags created concurrently under the same name are kept, not duplicated
+		err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+			Create(&tags).Error
+		if err != nil {
+			return err
+		}
+		tags = nil
+		return tx.Where("name IN ?", normalized).Order("name ASC").Find(&tags).Error
+	})
+	return tags, err
+}
+
+// CountCourses returns the number of courses that have each tag that is in use
+func (r *tagRepository) CountCourses() (map[uuid.UUID]int, error) {
+	var rows []struct {
+		TagID uuid.UUID
+		Count int
+	}
+	err := r.db.Table("course_tags").
+		Select("tag_id, COUNT(*) AS count").
+		Group("tag_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.TagID] = row.Count
+	}
+	return counts, nil
+}
+
+// Update saves the name of a tag
+func (r *tagRepository) Update(tag *models.Tag) error {
+	tag.Name = models.NormalizeTag(tag.Name)
+	return r.db.Model(tag).Select("name").Updates(tag).Error
+}
+
+// Delete deletes a tag by ID and removes it from every course
+func (r *tagRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM course_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&models.Tag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
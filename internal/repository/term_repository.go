@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TermRepository defines the interface for academic term data operations
+type TermRepository interface {
+	Create(term *models.Term) error
+	GetAll() ([]models.Term, error)
+	GetByID(id uuid.UUID) (*models.Term, error)
+	GetByName(name string) (*models.Term, error)
+	Update(term *models.Term) error
+	Delete(id uuid.UUID) error
+}
+
+// termRepository implements TermRepository interface
+type termRepository struct {
+	db *gorm.DB
+}
+
+// NewTermRepository creates a new term repository
+func NewTermRepository(db *gorm.DB) TermRepository {
+	return &termRepository{db: db}
+}
+
+// Create creates a new term
+func (r *termRepository) Create(term *models.Term) error {
+	return r.db.Create(term).Error
+}
+
+// GetAll retrieves every term, earliest first
+func (r *termRepository) GetAll() ([]models.Term, error) {
+	var terms []models.Term
+	err := r.db.Order("start_date ASC, name ASC").Find(&terms).Error
+	return terms, err
+}
+
+// GetByID retrieves a term by ID
+func (r *termRepository) GetByID(id uuid.UUID) (*models.Term, error) {
+	var term models.Term
+	if err := r.db.Where("id = ?", id).First(&term).Error; err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+// GetByName retrieves a term by its exact name
+func (r *termRepository) GetByName(name string) (*models.Term, error) {
+	var term models.Term
+	if err := r.db.Where("name = ?", name).First(&term).Error; err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+// Update saves the name and dates of a term
+func (r *termRepository) Update(term *models.Term) error {
+	return r.db.Model(term).
+		Select("name", "start_date", "end_date").
+		Updates(term).Error
+}
+
+// Delete deletes a term by ID
+func (r *termRepository) Delete(id uuid.UUID) error {
+	result := r.db.Where("id = ?", id).Delete(&models.Term{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
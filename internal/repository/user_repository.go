@@ -1,21 +1,53 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
 	"sonic-labs/course-enrollment-service/internal/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // UserRepository defines the interface for user data operations
 type UserRepository interface {
 	Create(user *models.User) error
+	CreateStudentAccount(user *models.User, email string) error
+	ClaimStudentAccount(user *models.User, email, inviteHash string) error
+	CreateInstructorAccount(user *models.User, instructorID uuid.UUID) error
 	GetByID(id uuid.UUID) (*models.User, error)
 	GetByUsername(username string) (*models.User, error)
+	GetByPasswordResetHash(hash string) (*models.User, error)
+	GetAll(role string) ([]models.User, error)
 	Update(user *models.User) error
+	UpdateAccess(user *models.User) error
+	UpdatePassword(user *models.User) error
 	Delete(id uuid.UUID) error
+	DeleteAccount(id uuid.UUID) error
 }
 
+// ErrStudentExists is returned when registering with the email of an existing
+// student. Registering does not prove owning the email, so the student is not
+// handed over to the new account.
+var ErrStudentExists = errors.New("a student with this email already exists")
+
+// ErrStudentHasAccount is returned when inviting or claiming a student that
+// already has an account
+var ErrStudentHasAccount = errors.New("the student already has an account")
+
+// ErrInvalidInvite is returned when claiming a student with an invite token
+// that is unknown, expired or was issued for a student with another email
+var ErrInvalidInvite = errors.New("invalid student invite")
+
+// ErrInstructorAccountExists is returned when the instructor already has an account
+var ErrInstructorAccountExists = errors.New("an account already exists for this instructor")
+
+// ErrLastSuperAdmin is returned when a change would leave no enabled super-admin
+var ErrLastSuperAdmin = errors.New("the last enabled super-admin cannot be removed")
+
 // userRepository implements UserRepository interface
 type userRepository struct {
 	db *gorm.DB
@@ -33,6 +65,82 @@ func (r *userRepository) Create(user *models.User) error {
 	return r.db.Create(user).Error
 }
 
+// CreateStudentAccount creates a new student with the email and an account for it.
+// It fails with ErrStudentExists if there already is a student with the email.
+func (r *userRepository) CreateStudentAccount(user *models.User, email string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		student := models.Student{Email: models.NormalizeEmail(email)}
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "email"}},
+			DoNothing: true,
+		}).Create(&student)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStudentExists
+		}
+
+		user.StudentID = &student.ID
+		return tx.Create(user).Error
+	})
+}
+
+// ClaimStudentAccount creates an account for the existing student with the email
+// and the invite token of the hash, and uses up the token. It fails with
+// ErrInvalidInvite unless the token is for that student and has not expired.
+func (r *userRepository) ClaimStudentAccount(user *models.User, email, inviteHash string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var student models.Student
+		err := tx.Where("invite_hash = ? AND email = ? AND invite_expires_at > ?", inviteHash, models.NormalizeEmail(email), time.Now()).
+			First(&student).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidInvite
+		}
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.User{}).Where("student_id = ?", student.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrStudentHasAccount
+		}
+
+		// Only one of several concurrent claims with the token uses it up
+		result := tx.Model(&models.Student{}).
+			Where("id = ? AND invite_hash = ?", student.ID, inviteHash).
+			Updates(map[string]interface{}{"invite_hash": nil, "invite_expires_at": nil})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidInvite
+		}
+
+		user.StudentID = &student.ID
+		return tx.Create(user).Error
+	})
+}
+
+// CreateInstructorAccount creates the account of an instructor
+func (r *userRepository) CreateInstructorAccount(user *models.User, instructorID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.User{}).Where("instructor_id = ?", instructorID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrInstructorAccountExists
+		}
+
+		user.InstructorID = &instructorID
+		return tx.Create(user).Error
+	})
+}
+
 // GetByID retrieves a user by ID
 func (r *userRepository) GetByID(id uuid.UUID) (*models.User, error) {
 	var user models.User
@@ -53,12 +161,97 @@ func (r *userRepository) GetByUsername(username string) (*models.User, error) {
 	return &user, nil
 }
 
+// GetByPasswordResetHash retrieves the user with the password reset token of the hash
+func (r *userRepository) GetByPasswordResetHash(hash string) (*models.User, error) {
+	var user models.User
+	err := r.db.First(&user, "password_reset_hash = ?", hash).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetAll retrieves every user, or the users with the role when it is not
+// empty, sorted by username
+func (r *userRepository) GetAll(role string) ([]models.User, error) {
+	query := r.db.Order("username ASC")
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+
+	var users []models.User
+	err := query.Find(&users).Error
+	return users, err
+}
+
 // Update updates a user
 func (r *userRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
 
+// UpdateAccess saves the role and disabled state of a user. It fails with
+// ErrLastSuperAdmin when the user is the last enabled super-admin and would no
+// longer be one.
+func (r *userRepository) UpdateAccess(user *models.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if user.Role != constants.RoleSuperAdmin || user.DisabledAt != nil {
+			if err := ensureOtherSuperAdmin(tx, user.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(user).Select("role", "disabled_at").Updates(user).Error
+	})
+}
+
+// UpdatePassword saves the password of a user, whether they must change it,
+// when it was changed and their password reset token
+func (r *userRepository) UpdatePassword(user *models.User) error {
+	return r.db.Model(user).
+		Select("password", "must_change_password", "password_changed_at", "password_reset_hash", "password_reset_expires_at").
+		Updates(user).Error
+}
+
 // Delete deletes a user by ID
 func (r *userRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&models.User{}, "id = ?", id).Error
 }
+
+// DeleteAccount deletes a user by ID, failing with ErrLastSuperAdmin when they
+// are the last enabled super-admin and with gorm.ErrRecordNotFound when there
+// is no such user
+func (r *userRepository) DeleteAccount(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureOtherSuperAdmin(tx, id); err != nil {
+			return err
+		}
+		result := tx.Delete(&models.User{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// ensureOtherSuperAdmin returns ErrLastSuperAdmin when the user is the only
+// enabled super-admin. The super-admin rows are locked, on databases that
+// support it, so that concurrent demotions cannot remove them all.
+func ensureOtherSuperAdmin(tx *gorm.DB, id uuid.UUID) error {
+	query := tx.Model(&models.User{})
+	if tx.Dialector.Name() == "postgres" {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var ids []uuid.UUID
+	err := query.Where("role = ? AND disabled_at IS NULL", constants.RoleSuperAdmin).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+	if len(ids) == 1 && ids[0] == id {
+		return ErrLastSuperAdmin
+	}
+	return nil
+}
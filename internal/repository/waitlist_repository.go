@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"errors"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WaitlistRepository defines the interface for course waitlist data operations
+type WaitlistRepository interface {
+	GetByCourseID(courseID uuid.UUID) ([]models.WaitlistEntry, error)
+	GetEntry(courseID uuid.UUID, studentEmail string) (*models.WaitlistEntry, error)
+	Remove(courseID uuid.UUID, studentEmail string) error
+	Reorder(courseID uuid.UUID, studentEmails []string) error
+	FillFreeSeats(courseID uuid.UUID) ([]models.Enrollment, error)
+}
+
+// waitlistRepository implements WaitlistRepository interface
+type waitlistRepository struct {
+	db *gorm.DB
+}
+
+// NewWaitlistRepository creates a new waitlist repository
+func NewWaitlistRepository(db *gorm.DB) WaitlistRepository {
+	return &waitlistRepository{db: db}
+}
+
+// GetByCourseID retrieves the waitlist of a course, first in line first
+func (r *waitlistRepository) GetByCourseID(courseID uuid.UUID) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	err := r.db.Where("course_id = ?", courseID).Order("position ASC").Find(&entries).Error
+	return entries, err
+}
+
+// GetEntry retrieves the waitlist entry of a student for a course
+func (r *waitlistRepository) GetEntry(courseID uuid.UUID, studentEmail string) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := r.db.Where("course_id = ? AND student_email = ?", courseID, models.NormalizeEmail(studentEmail)).First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Remove removes a student from a course waitlist and closes the gap in positions
+func (r *waitlistRepository) Remove(courseID uuid.UUID, studentEmail string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("course_id = ? AND student_email = ?", courseID, models.NormalizeEmail(studentEmail)).
+			Delete(&models.WaitlistEntry{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return renumberWaitlist(tx, courseID)
+	})
+}
+
+// Reorder rewrites the positions of a course waitlist in the given order.
+// The caller is responsible for passing every waitlisted student exactly once.
+func (r *waitlistRepository) Reorder(courseID uuid.UUID, studentEmails []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, email := range studentEmails {
+			result := tx.Model(&models.WaitlistEntry{}).
+				Where("course_id = ? AND student_email = ?", courseID, models.NormalizeEmail(email)).
+				Update("position", i+1)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return nil
+	})
+}
+
+// FillFreeSeats promotes waitlisted students into any free seats of a course,
+// e.g. after its capacity was raised
+func (r *waitlistRepository) FillFreeSeats(courseID uuid.UUID) ([]models.Enrollment, error) {
+	var promoted []models.Enrollment
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		promoted, err = fillFreeSeats(tx, courseID)
+		return err
+	})
+	return promoted, err
+}
+
+// lockCourse loads a course and, on databases that support it, locks its row
+// so that concurrent enrollments in the same course are serialized
+func lockCourse(tx *gorm.DB, courseID uuid.UUID) (*models.Course, error) {
+	query := tx
+	if tx.Dialector.Name() == "postgres" {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var course models.Course
+	if err := query.Where("id = ?", courseID).First(&course).Error; err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+// appendToWaitlist puts a student at the end of a course waitlist, waiting for
+// a seat in the given offering and, if not nil, section. The student is created
+// if this is their first contact with any course.
+func appendToWaitlist(tx *gorm.DB, courseID, offeringID uuid.UUID, sectionID *uuid.UUID, studentEmail string) (*models.WaitlistEntry, error) {
+	studentEmail = models.NormalizeEmail(studentEmail)
+	var count int64
+	err := tx.Model(&models.WaitlistEntry{}).
+		Where("course_id = ? AND student_email = ?", courseID, studentEmail).
+		Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("student is already on the waitlist for this course")
+	}
+	if _, err := ensureStudent(tx, studentEmail); err != nil {
+		return nil, err
+	}
+
+	var lastPosition int
+	err = tx.Model(&models.WaitlistEntry{}).
+		Where("course_id = ?", courseID).
+		Select("COALESCE(MAX(position), 0)").
+		Scan(&lastPosition).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.WaitlistEntry{
+		CourseID:     courseID,
+		OfferingID:   offeringID,
+		SectionID:    sectionID,
+		StudentEmail: studentEmail,
+		Position:     lastPosition + 1,
+	}
+	if err := tx.Create(entry).Error; err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// fillFreeSeats walks a course waitlist in order and moves each student into
+// an enrollment if the offering they wait for has a free seat. Students waiting
+// for a full offering keep their place. It must run inside the transaction that
+// freed the seats.
+func fillFreeSeats(tx *gorm.DB, courseID uuid.UUID) ([]models.Enrollment, error) {
+	course, err := lockCourse(tx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.WaitlistEntry
+	if err := tx.Where("course_id = ?", courseID).Order("position ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	freeSeats := make(map[uuid.UUID]int) // by offering; -1 means unlimited
+	var promoted []models.Enrollment
+	for _, entry := range entries {
+		free, known := freeSeats[entry.OfferingID]
+		if !known {
+			if free, err = countFreeSeats(tx, course, entry.OfferingID); err != nil {
+				return nil, err
+			}
+		}
+		if free == 0 {
+			freeSeats[entry.OfferingID] = 0
+			continue
+		}
+
+		enrollment, err := promoteEntry(tx, entry)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Delete(&models.WaitlistEntry{}, "id = ?", entry.ID).Error; err != nil {
+			return nil, err
+		}
+		promoted = append(promoted, *enrollment)
+
+		if free > 0 {
+			free--
+		}
+		freeSeats[entry.OfferingID] = free
+	}
+
+	if len(promoted) == 0 {
+		return nil, nil
+	}
+	return promoted, renumberWaitlist(tx, courseID)
+}
+
+// countFreeSeats returns the number of free seats in an offering of a course,
+// or -1 if it is unlimited
+func countFreeSeats(tx *gorm.DB, course *models.Course, offeringID uuid.UUID) (int, error) {
+	offering, err := loadOffering(tx, course.ID, offeringID)
+	if err != nil {
+		return 0, err
+	}
+	capacity := offering.EffectiveCapacity(course)
+	if capacity == nil {
+		return -1, nil
+	}
+	taken, err := countSeatsTaken(tx, offering.ID)
+	if err != nil {
+		return 0, err
+	}
+	if taken >= *capacity {
+		return 0, nil
+	}
+	return *capacity - taken, nil
+}
+
+// promoteEntry turns a waitlist entry into an active enrollment in the offering
+// and section it waited for, reactivating the student's earlier enrollment in the course if
+// there is one
+func promoteEntry(tx *gorm.DB, entry models.WaitlistEntry) (*models.Enrollment, error) {
+	offering, err := loadOffering(tx, entry.CourseID, entry.OfferingID)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing models.Enrollment
+	err = tx.Where("student_email = ? AND course_id = ?", entry.StudentEmail, entry.CourseID).First(&existing).Error
+	if err == nil {
+		if err := reactivate(tx, &existing, offering.ID, entry.SectionID, constants.SystemActor); err != nil {
+			return nil, err
+		}
+		return &existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	enrollment := &models.Enrollment{
+		StudentEmail:    entry.StudentEmail,
+		CourseID:        entry.CourseID,
+		OfferingID:      offering.ID,
+		SectionID:       entry.SectionID,
+		StatusChangedBy: constants.SystemActor,
+	}
+	if err := createEnrollment(tx, enrollment); err != nil {
+		return nil, err
+	}
+	return enrollment, nil
+}
+
+// renumberWaitlist closes gaps so positions of a course waitlist run 1..n
+func renumberWaitlist(tx *gorm.DB, courseID uuid.UUID) error {
+	var entries []models.WaitlistEntry
+	if err := tx.Where("course_id = ?", courseID).Order("position ASC").Find(&entries).Error; err != nil {
+		return err
+	}
+	for i, entry := range entries {
+		if entry.Position == i+1 {
+			continue
+		}
+		if err := tx.Model(&models.WaitlistEntry{}).Where("id = ?", entry.ID).Update("position", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package router
 import (
 	"log"
 	"sonic-labs/course-enrollment-service/internal/config"
+	"sonic-labs/course-enrollment-service/internal/constants"
 	"sonic-labs/course-enrollment-service/internal/handler"
 	"sonic-labs/course-enrollment-service/internal/middleware"
 	"sonic-labs/course-enrollment-service/internal/repository"
@@ -35,6 +36,22 @@ func Setup(db *gorm.DB, cfg *config.Config) *gin.Engine {
 	courseRepo := repository.NewCourseRepository(db)
 	enrollmentRepo := repository.NewEnrollmentRepository(db)
 	userRepo := repository.NewUserRepository(db)
+	waitlistRepo := repository.NewWaitlistRepository(db)
+	prerequisiteRepo := repository.NewPrerequisiteRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	moduleRepo := repository.NewModuleRepository(db)
+	progressRepo := repository.NewProgressRepository(db)
+	termRepo := repository.NewTermRepository(db)
+	offeringRepo := repository.NewOfferingRepository(db)
+	sectionRepo := repository.NewSectionRepository(db)
+	studentRepo := repository.NewStudentRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	difficultyRepo := repository.NewDifficultyRepository(db)
+	instructorRepo := repository.NewInstructorRepository(db)
+	permissionRepo := repository.NewPermissionRepository(db)
+	revokedTokenRepo := repository.NewRevokedTokenRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
 
 	// Initialize Redis service
 	redisService := service.NewRedisService(cfg)
@@ -47,20 +64,69 @@ func Setup(db *gorm.DB, cfg *config.Config) *gin.Engine {
 		log.Println("Redis connected successfully")
 	}
 
+	// Idempotency keys, revoked tokens and failed logins live in Redis when it
+	// is available, otherwise in the database or in memory
+	var idempotencyStore middleware.IdempotencyStore = idempotencyRepo
+	var rateLimiter middleware.RateLimiter = middleware.NewMemoryRateLimiter()
+	var tokenDenylist service.TokenDenylist = revokedTokenRepo
+	var loginAttempts service.LoginAttemptStore = service.NewMemoryLoginAttemptStore()
+	if redisService != nil {
+		idempotencyStore = redisService
+		rateLimiter = redisService
+		tokenDenylist = redisService
+		loginAttempts = redisService
+	}
+
+	// New passwords must meet the configured policy
+	passwordPolicy := service.NewPasswordPolicy(cfg.Password)
+
 	// Initialize services
-	courseService := service.NewCourseService(courseRepo, enrollmentRepo, redisService)
-	enrollmentService := service.NewEnrollmentService(enrollmentRepo, courseRepo)
-	authService := service.NewAuthService(userRepo)
-	studentService := service.NewStudentService(enrollmentRepo)
+	courseService := service.NewCourseService(courseRepo, enrollmentRepo, waitlistRepo, categoryRepo, tagRepo, difficultyRepo, instructorRepo, redisService)
+	enrollmentService := service.NewEnrollmentService(enrollmentRepo, courseRepo, prerequisiteRepo, progressRepo, offeringRepo, sectionRepo, studentRepo, waitlistRepo)
+	authService := service.NewAuthService(userRepo, tokenDenylist, loginAttempts, cfg.Login, passwordPolicy)
+	studentService := service.NewStudentService(enrollmentRepo, studentRepo)
+	waitlistService := service.NewWaitlistService(waitlistRepo, enrollmentRepo, courseRepo)
+	prerequisiteService := service.NewPrerequisiteService(prerequisiteRepo, courseRepo)
+	moduleService := service.NewModuleService(moduleRepo, courseRepo)
+	progressService := service.NewProgressService(progressRepo, enrollmentRepo, moduleRepo)
+	termService := service.NewTermService(termRepo, offeringRepo)
+	offeringService := service.NewOfferingService(offeringRepo, courseRepo, termRepo, waitlistRepo)
+	sectionService := service.NewSectionService(sectionRepo, courseRepo, offeringRepo)
+	categoryService := service.NewCategoryService(categoryRepo, redisService)
+	tagService := service.NewTagService(tagRepo, redisService)
+	difficultyService := service.NewDifficultyService(difficultyRepo, redisService)
+	instructorService := service.NewInstructorService(instructorRepo, userRepo, redisService, passwordPolicy)
+	permissionService := service.NewPermissionService(permissionRepo)
+	userService := service.NewUserService(userRepo, loginAttempts, passwordPolicy)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, permissionService)
+
+	// can guards a route with a permission of the signed-in user's role
+	can := func(permission string) gin.HandlerFunc {
+		return middleware.RequirePermission(permissionService, permission)
+	}
 
 	// Initialize S3 service
 	s3Service := service.NewS3Service()
 
 	// Initialize handlers
-	courseHandler := handler.NewCourseHandler(courseService, s3Service)
+	courseHandler := handler.NewCourseHandler(courseService, moduleService, s3Service)
 	enrollmentHandler := handler.NewEnrollmentHandler(enrollmentService)
 	studentHandler := handler.NewStudentHandler(studentService)
 	authHandler := handler.NewAuthHandler(authService)
+	waitlistHandler := handler.NewWaitlistHandler(waitlistService)
+	prerequisiteHandler := handler.NewPrerequisiteHandler(prerequisiteService)
+	moduleHandler := handler.NewModuleHandler(moduleService)
+	progressHandler := handler.NewProgressHandler(progressService)
+	termHandler := handler.NewTermHandler(termService)
+	offeringHandler := handler.NewOfferingHandler(offeringService)
+	sectionHandler := handler.NewSectionHandler(sectionService)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	tagHandler := handler.NewTagHandler(tagService)
+	difficultyHandler := handler.NewDifficultyHandler(difficultyService)
+	instructorHandler := handler.NewInstructorHandler(instructorService)
+	permissionHandler := handler.NewPermissionHandler(permissionService)
+	userHandler := handler.NewUserHandler(userService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
 		health := gin.H{
@@ -115,56 +181,206 @@ func Setup(db *gorm.DB, cfg *config.Config) *gin.Engine {
 		// Authentication routes
 		auth := v1.Group("/auth")
 		{
-			auth.POST("/login", authHandler.Login)                                                                  // Public - login only
-			auth.GET("/profile", middleware.AuthMiddleware(), middleware.AdminMiddleware(), authHandler.GetProfile) // Protected - admin only
+			auth.POST("/login", authHandler.Login)                                                                                           // Public - login
+			auth.POST("/register", authHandler.Register)                                                                                     // Public - create a student account
+			auth.POST("/refresh", authHandler.Refresh)                                                                                       // Public - exchange a refresh token for new tokens
+			auth.POST("/reset-password", authHandler.ResetPassword)                                                                          // Public - set a new password with a reset token
+			auth.POST("/logout", middleware.PasswordChangeAuthMiddleware(tokenDenylist, apiKeyService), authHandler.Logout)                  // Protected - end the current session
+			auth.GET("/profile", middleware.PasswordChangeAuthMiddleware(tokenDenylist, apiKeyService), authHandler.GetProfile)              // Protected - any signed-in user
+			auth.POST("/change-password", middleware.PasswordChangeAuthMiddleware(tokenDenylist, apiKeyService), authHandler.ChangePassword) // Protected - also while the password must be changed
+		}
+
+		// Student self-service routes, scoped to the signed-in student
+		me := v1.Group("/me")
+		me.Use(middleware.AuthMiddleware(tokenDenylist, apiKeyService), middleware.StudentMiddleware())
+		me.Use(middleware.IdempotencyMiddleware(idempotencyStore))
+		{
+			me.GET("/enrollments", enrollmentHandler.GetOwnEnrollments)               // Student only - read own enrollments
+			me.POST("/enrollments", enrollmentHandler.EnrollSelf)                     // Student only - enroll in a course
+			me.DELETE("/enrollments/:course_id", enrollmentHandler.DropOwnEnrollment) // Student only - drop a course
 		}
 
 		// Public course routes (read-only)
 		publicCourses := v1.Group("/courses")
 		{
-			publicCourses.GET("", courseHandler.GetAllCourses)     // Public - read all courses
-			publicCourses.GET("/:id", courseHandler.GetCourseByID) // Public - read specific course
+			publicCourses.GET("", courseHandler.GetAllCourses)                                       // Public - read all courses
+			publicCourses.GET("/:id", courseHandler.GetCourseByID)                                   // Public - read specific course
+			publicCourses.GET("/:id/prerequisites", prerequisiteHandler.GetPrerequisites)            // Public - read course prerequisites
+			publicCourses.GET("/:id/modules", moduleHandler.GetModules)                              // Public - read course outline
+			publicCourses.GET("/:id/modules/:module_id", moduleHandler.GetModule)                    // Public - read course module
+			publicCourses.GET("/:id/modules/:module_id/lessons/:lesson_id", moduleHandler.GetLesson) // Public - read lesson
+			publicCourses.GET("/:id/offerings", offeringHandler.GetOfferings)                        // Public - read course offerings
+			publicCourses.GET("/:id/offerings/:offering_id", offeringHandler.GetOffering)            // Public - read course offering
+			publicCourses.GET("/:id/sections", sectionHandler.GetSections)                           // Public - read course sections
+			publicCourses.GET("/:id/sections/:section_id", sectionHandler.GetSection)                // Public - read course section
 		}
 
-		// Public enrollment routes (read-only)
+		// Public term routes (read-only)
+		publicTerms := v1.Group("/terms")
+		{
+			publicTerms.GET("", termHandler.GetTerms)    // Public - read academic calendar
+			publicTerms.GET("/:id", termHandler.GetTerm) // Public - read specific term
+		}
+
+		// Public taxonomy routes (read-only)
+		publicCategories := v1.Group("/categories")
+		{
+			publicCategories.GET("", categoryHandler.GetCategories)   // Public - read category tree
+			publicCategories.GET("/:id", categoryHandler.GetCategory) // Public - read category with subcategories
+		}
+		publicTags := v1.Group("/tags")
+		{
+			publicTags.GET("", tagHandler.GetTags) // Public - read tags
+		}
+		publicDifficultyLevels := v1.Group("/difficulty-levels")
+		{
+			publicDifficultyLevels.GET("", difficultyHandler.GetLevels) // Public - read difficulty levels
+		}
+
+		// Student record routes, for student:read holders, the student themselves or a share link
 		publicStudents := v1.Group("/students")
+		publicStudents.Use(middleware.RateLimitMiddleware(rateLimiter, "students", constants.RateLimitRequests, constants.RateLimitWindow))
+		publicStudents.Use(middleware.StudentAccessMiddleware(studentRepo, permissionService, tokenDenylist, apiKeyService))
+		{
+			publicStudents.GET("/:email/enrollments", enrollmentHandler.GetStudentEnrollments) // Student, share link or student:read - read student enrollments
+			publicStudents.GET("/:email/timetable", sectionHandler.GetStudentTimetable)        // Student, share link or student:read - read student timetable
+		}
+
+		// Course management routes, each guarded by a permission. Instructors
+		// only use their permissions on the courses they teach.
+		courses := v1.Group("/courses")
+		courses.Use(middleware.AuthMiddleware(tokenDenylist, apiKeyService))
+		courses.Use(middleware.IdempotencyMiddleware(idempotencyStore))
 		{
-			publicStudents.GET("/:email/enrollments", enrollmentHandler.GetStudentEnrollments) // Public - read student enrollments
+			courses.POST("", can(constants.PermissionCourseCreate), courseHandler.CreateCourse)                 // course:create - create course JSON (default)
+			courses.POST("/upload", can(constants.PermissionCourseCreate), courseHandler.CreateCourseWithImage) // course:create - create course with image upload
+		}
+		course := courses.Group("/:id")
+		course.Use(middleware.CourseAccessMiddleware(instructorRepo))
+		{
+			course.PUT("", can(constants.PermissionCourseWrite), courseHandler.UpdateCourse)                                                     // course:write - update course
+			course.DELETE("", can(constants.PermissionCourseDelete), courseHandler.DeleteCourse)                                                 // course:delete - delete course
+			course.PUT("/instructors", can(constants.PermissionCourseAssign), courseHandler.SetCourseInstructors)                                // course:assign - assign course instructors
+			course.GET("/students", can(constants.PermissionEnrollmentRead), courseHandler.GetCourseStudents)                                    // enrollment:read - get course students
+			course.DELETE("/students/:email", can(constants.PermissionEnrollmentDelete), courseHandler.RemoveStudentFromCourse)                  // enrollment:delete - remove student from course
+			course.GET("/waitlist", can(constants.PermissionEnrollmentRead), waitlistHandler.GetWaitlist)                                        // enrollment:read - view course waitlist
+			course.PUT("/waitlist", can(constants.PermissionEnrollmentWrite), waitlistHandler.ReorderWaitlist)                                   // enrollment:write - reorder course waitlist
+			course.DELETE("/waitlist/:email", can(constants.PermissionEnrollmentDelete), waitlistHandler.RemoveFromWaitlist)                     // enrollment:delete - remove student from waitlist
+			course.POST("/modules", can(constants.PermissionCourseWrite), moduleHandler.CreateModule)                                            // course:write - add course module
+			course.PUT("/modules", can(constants.PermissionCourseWrite), moduleHandler.ReorderModules)                                           // course:write - reorder course modules
+			course.PUT("/modules/:module_id", can(constants.PermissionCourseWrite), moduleHandler.UpdateModule)                                  // course:write - update course module
+			course.DELETE("/modules/:module_id", can(constants.PermissionCourseWrite), moduleHandler.DeleteModule)                               // course:write - delete course module
+			course.POST("/modules/:module_id/lessons", can(constants.PermissionCourseWrite), moduleHandler.CreateLesson)                         // course:write - add lesson
+			course.PUT("/modules/:module_id/lessons", can(constants.PermissionCourseWrite), moduleHandler.ReorderLessons)                        // course:write - reorder lessons
+			course.PUT("/modules/:module_id/lessons/:lesson_id", can(constants.PermissionCourseWrite), moduleHandler.UpdateLesson)               // course:write - update lesson
+			course.DELETE("/modules/:module_id/lessons/:lesson_id", can(constants.PermissionCourseWrite), moduleHandler.DeleteLesson)            // course:write - delete lesson
+			course.POST("/prerequisites", can(constants.PermissionPrerequisiteWrite), prerequisiteHandler.AddPrerequisite)                       // prerequisite:write - add course prerequisite
+			course.PUT("/prerequisites", can(constants.PermissionPrerequisiteWrite), prerequisiteHandler.ReplacePrerequisites)                   // prerequisite:write - replace course prerequisites
+			course.DELETE("/prerequisites/:prerequisite_id", can(constants.PermissionPrerequisiteWrite), prerequisiteHandler.RemovePrerequisite) // prerequisite:write - remove course prerequisite
+			course.GET("/prerequisites/overrides", can(constants.PermissionEnrollmentRead), prerequisiteHandler.GetOverrides)                    // enrollment:read - view prerequisite overrides
+			course.POST("/offerings", can(constants.PermissionScheduleWrite), offeringHandler.CreateOffering)                                    // schedule:write - offer course in a term
+			course.PUT("/offerings/:offering_id", can(constants.PermissionScheduleWrite), offeringHandler.UpdateOffering)                        // schedule:write - update course offering
+			course.DELETE("/offerings/:offering_id", can(constants.PermissionScheduleWrite), offeringHandler.DeleteOffering)                     // schedule:write - delete course offering
+			course.POST("/sections", can(constants.PermissionScheduleWrite), sectionHandler.CreateSection)                                       // schedule:write - add course section
+			course.PUT("/sections/:section_id", can(constants.PermissionScheduleWrite), sectionHandler.UpdateSection)                            // schedule:write - update course section
+			course.DELETE("/sections/:section_id", can(constants.PermissionScheduleWrite), sectionHandler.DeleteSection)                         // schedule:write - delete course section
 		}
 
-		// All other routes require admin authentication
-		adminRoutes := v1.Group("")
-		adminRoutes.Use(middleware.AdminAuthMiddleware())
+		// All other management routes require authentication and a permission
+		staffRoutes := v1.Group("")
+		staffRoutes.Use(middleware.AuthMiddleware(tokenDenylist, apiKeyService))
+		staffRoutes.Use(middleware.IdempotencyMiddleware(idempotencyStore))
 		{
-			// Course management routes - admin only (write operations)
-			courses := adminRoutes.Group("/courses")
+			// Term management routes (write operations)
+			terms := staffRoutes.Group("/terms")
+			terms.Use(can(constants.PermissionScheduleWrite))
+			{
+				terms.POST("", termHandler.CreateTerm)       // schedule:write - create term
+				terms.PUT("/:id", termHandler.UpdateTerm)    // schedule:write - update term
+				terms.DELETE("/:id", termHandler.DeleteTerm) // schedule:write - delete term
+			}
+
+			// Category, tag and difficulty level management routes (write operations)
+			categories := staffRoutes.Group("/categories")
+			categories.Use(can(constants.PermissionCatalogWrite))
+			{
+				categories.POST("", categoryHandler.CreateCategory)       // catalog:write - create category
+				categories.PUT("/:id", categoryHandler.UpdateCategory)    // catalog:write - rename or move category
+				categories.DELETE("/:id", categoryHandler.DeleteCategory) // catalog:write - delete category
+			}
+			tags := staffRoutes.Group("/tags")
+			tags.Use(can(constants.PermissionCatalogWrite))
+			{
+				tags.POST("", tagHandler.CreateTag)       // catalog:write - create tag
+				tags.PUT("/:id", tagHandler.UpdateTag)    // catalog:write - rename tag
+				tags.DELETE("/:id", tagHandler.DeleteTag) // catalog:write - delete tag
+			}
+			difficultyLevels := staffRoutes.Group("/difficulty-levels")
+			difficultyLevels.Use(can(constants.PermissionCatalogWrite))
 			{
-				courses.POST("", courseHandler.CreateCourse)                                  // Admin only - create course JSON (default)
-				courses.POST("/upload", courseHandler.CreateCourseWithImage)                  // Admin only - create course with image upload
-				courses.PUT("/:id", courseHandler.UpdateCourse)                               // Admin only - update course
-				courses.DELETE("/:id", courseHandler.DeleteCourse)                            // Admin only - delete course
-				courses.GET("/:id/students", courseHandler.GetCourseStudents)                 // Admin only - get course students
-				courses.DELETE("/:id/students/:email", courseHandler.RemoveStudentFromCourse) // Admin only - remove student from course
+				difficultyLevels.POST("", difficultyHandler.CreateLevel)       // catalog:write - create difficulty level
+				difficultyLevels.PUT("/:id", difficultyHandler.UpdateLevel)    // catalog:write - rename or reorder difficulty level
+				difficultyLevels.DELETE("/:id", difficultyHandler.DeleteLevel) // catalog:write - delete difficulty level
 			}
 
-			// Enrollment routes - admin only
-			enrollments := adminRoutes.Group("/enrollments")
+			// Enrollment routes
+			enrollments := staffRoutes.Group("/enrollments")
 			{
-				enrollments.POST("", enrollmentHandler.EnrollStudent) // Admin only - enroll student
+				enrollments.POST("", middleware.EnrollmentRequestCourseAccessMiddleware(instructorRepo), can(constants.PermissionEnrollmentWrite), enrollmentHandler.EnrollStudent) // enrollment:write - enroll student in a course the caller may manage
 			}
 
-			// Admin routes for student and enrollment management
-			admin := adminRoutes.Group("/admin")
+			// Enrollment management routes. Instructors only use their permissions on
+			// the enrollments in the courses they teach.
+			enrollment := staffRoutes.Group("/admin/enrollments/:id")
+			enrollment.Use(middleware.EnrollmentCourseAccessMiddleware(instructorRepo, enrollmentRepo))
 			{
-				admin.GET("/students", studentHandler.GetAllStudents)             // Admin only - get all students
-				admin.GET("/enrollments", studentHandler.GetAllEnrollments)       // Admin only - get all enrollments
-				admin.DELETE("/enrollments/:id", studentHandler.DeleteEnrollment) // Admin only - delete enrollment
+				enrollment.DELETE("", can(constants.PermissionEnrollmentDelete), studentHandler.DeleteEnrollment)                      // enrollment:delete - withdraw enrollment
+				enrollment.PATCH("/status", can(constants.PermissionEnrollmentWrite), enrollmentHandler.UpdateEnrollmentStatus)        // enrollment:write - change enrollment status
+				enrollment.GET("/history", can(constants.PermissionEnrollmentRead), enrollmentHandler.GetEnrollmentHistory)            // enrollment:read - enrollment status history
+				enrollment.GET("/progress", can(constants.PermissionEnrollmentRead), progressHandler.GetEnrollmentProgress)            // enrollment:read - lesson progress
+				enrollment.PUT("/progress/:lesson_id", can(constants.PermissionEnrollmentWrite), progressHandler.RecordLessonProgress) // enrollment:write - mark lesson started or completed
 			}
 
-			// Student management routes - admin only (write operations only, reads are public)
-			// Note: Student enrollment reading is available publicly above
+			// Admin routes for student, instructor, enrollment and role management
+			admin := staffRoutes.Group("/admin")
+			{
+				admin.GET("/students", can(constants.PermissionStudentRead), studentHandler.GetAllStudents)                       // student:read - get all students
+				admin.POST("/students", can(constants.PermissionStudentWrite), studentHandler.CreateStudent)                      // student:write - create student
+				admin.GET("/students/:id", can(constants.PermissionStudentRead), studentHandler.GetStudent)                       // student:read - get student
+				admin.PUT("/students/:id", can(constants.PermissionStudentWrite), studentHandler.UpdateStudent)                   // student:write - update student
+				admin.DELETE("/students/:id", can(constants.PermissionStudentDelete), studentHandler.DeleteStudent)               // student:delete - delete student
+				admin.POST("/students/:id/merge", can(constants.PermissionStudentWrite), studentHandler.MergeStudents)            // student:write - merge another student into this one
+				admin.GET("/students/:id/merges", can(constants.PermissionStudentRead), studentHandler.GetStudentMerges)          // student:read - get merges into student
+				admin.POST("/students/:id/share-link", can(constants.PermissionStudentRead), studentHandler.CreateShareLink)      // student:read - share student records
+				admin.POST("/students/:id/invite", can(constants.PermissionStudentWrite), studentHandler.CreateInvite)            // student:write - invite student to register an account
+				admin.GET("/instructors", can(constants.PermissionInstructorRead), instructorHandler.GetInstructors)              // instructor:read - get all instructors
+				admin.POST("/instructors", can(constants.PermissionInstructorWrite), instructorHandler.CreateInstructor)          // instructor:write - create instructor
+				admin.GET("/instructors/:id", can(constants.PermissionInstructorRead), instructorHandler.GetInstructor)           // instructor:read - get instructor
+				admin.PUT("/instructors/:id", can(constants.PermissionInstructorWrite), instructorHandler.UpdateInstructor)       // instructor:write - update instructor
+				admin.DELETE("/instructors/:id", can(constants.PermissionInstructorWrite), instructorHandler.DeleteInstructor)    // instructor:write - delete instructor
+				admin.POST("/instructors/:id/account", can(constants.PermissionInstructorWrite), instructorHandler.CreateAccount) // instructor:write - create instructor account
+				admin.GET("/enrollments", can(constants.PermissionEnrollmentRead), studentHandler.GetAllEnrollments)              // enrollment:read - get all enrollments
+				admin.POST("/enrollments/import", can(constants.PermissionEnrollmentWrite), enrollmentHandler.ImportEnrollments)  // enrollment:write - bulk enroll from CSV
+				admin.GET("/users", can(constants.PermissionUserManage), userHandler.GetUsers)                                    // user:manage - get all user accounts
+				admin.POST("/users", can(constants.PermissionUserManage), userHandler.CreateUser)                                 // user:manage - create admin or super-admin account
+				admin.PUT("/users/:id/role", can(constants.PermissionUserManage), userHandler.UpdateUserRole)                     // user:manage - change admin or super-admin role
+				admin.POST("/users/:id/disable", can(constants.PermissionUserManage), userHandler.DisableUser)                    // user:manage - stop user logging in
+				admin.POST("/users/:id/enable", can(constants.PermissionUserManage), userHandler.EnableUser)                      // user:manage - let user log in again
+				admin.POST("/users/:id/unlock", can(constants.PermissionUserManage), userHandler.UnlockUser)                      // user:manage - lift login lockout
+				admin.POST("/users/:id/password-reset", can(constants.PermissionUserManage), userHandler.IssuePasswordReset)      // user:manage - issue a password reset token
+				admin.DELETE("/users/:id", can(constants.PermissionUserManage), userHandler.DeleteUser)                           // user:manage - delete user account
+				admin.GET("/roles", can(constants.PermissionRoleManage), permissionHandler.GetRoles)                              // role:manage - get roles and their permissions
+				admin.PUT("/roles/:role/permissions", can(constants.PermissionRoleManage), permissionHandler.SetRolePermissions)  // role:manage - replace the permissions of a role
+				admin.GET("/api-keys", can(constants.PermissionAPIKeyManage), apiKeyHandler.GetAPIKeys)                           // apikey:manage - get all API keys
+				admin.POST("/api-keys", can(constants.PermissionAPIKeyManage), apiKeyHandler.CreateAPIKey)                        // apikey:manage - create scoped API key
+				admin.GET("/api-keys/:id", can(constants.PermissionAPIKeyManage), apiKeyHandler.GetAPIKey)                        // apikey:manage - get API key
+				admin.DELETE("/api-keys/:id", can(constants.PermissionAPIKeyManage), apiKeyHandler.DeleteAPIKey)                  // apikey:manage - revoke API key
+			}
 		}
 	}
+	// Public keys that verify tokens, for other services
+	r.GET("/.well-known/jwks.json", authHandler.GetJWKS)
+
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	return r
@@ -173,8 +389,8 @@ func Setup(db *gorm.DB, cfg *config.Config) *gin.Engine {
 func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, Idempotency-Key")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKeyService defines the interface for API key business logic
+type APIKeyService interface {
+	GetAPIKeys() (*models.APIKeyListResponse, error)
+	GetAPIKey(id uuid.UUID) (*models.APIKeyResponse, error)
+	CreateAPIKey(creatorID uuid.UUID, req models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error)
+	DeleteAPIKey(id uuid.UUID) error
+	AuthenticateAPIKey(key string) (*models.APIKey, error)
+}
+
+// apiKeyService implements APIKeyService interface
+type apiKeyService struct {
+	apiKeyRepo        repository.APIKeyRepository
+	userRepo          repository.UserRepository
+	permissionService PermissionService
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, userRepo repository.UserRepository, permissionService PermissionService) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo:        apiKeyRepo,
+		userRepo:          userRepo,
+		permissionService: permissionService,
+	}
+}
+
+// GetAPIKeys retrieves every API key, newest first
+func (s *apiKeyService) GetAPIKeys() (*models.APIKeyListResponse, error) {
+	keys, err := s.apiKeyRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.APIKeyResponse, len(keys))
+	for i := range keys {
+		responses[i] = keys[i].ToResponse()
+	}
+
+	return &models.APIKeyListResponse{
+		APIKeys: responses,
+		Total:   len(responses),
+	}, nil
+}
+
+// GetAPIKey retrieves an API key
+func (s *apiKeyService) GetAPIKey(id uuid.UUID) (*models.APIKeyResponse, error) {
+	key, err := s.apiKeyRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("api key not found")
+		}
+		return nil, err
+	}
+
+	response := key.ToResponse()
+	return &response, nil
+}
+
+// CreateAPIKey creates an API key for the owner in the request, or for the
+// creator when there is none. Every scope must be a permission the owner's role
+// holds. The key is returned only here; just its hash is stored.
+func (s *apiKeyService) CreateAPIKey(creatorID uuid.UUID, req models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("api key name is required")
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	ownerID := creatorID
+	if req.OwnerID != nil {
+		ownerID = *req.OwnerID
+	}
+	owner, err := s.userRepo.GetByID(ownerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("owner not found")
+		}
+		return nil, err
+	}
+	if owner.DisabledAt != nil {
+		return nil, errors.New("owner is disabled")
+	}
+
+	scopes, err := s.ownerScopes(owner, req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &models.APIKey{
+		Name:      name,
+		Prefix:    secret[:constants.APIKeyDisplayLength],
+		KeyHash:   hashAPIKey(secret),
+		OwnerID:   owner.ID,
+		ExpiresAt: req.ExpiresAt,
+	}
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, models.APIKeyScope{Permission: scope})
+	}
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		return nil, err
+	}
+
+	key.Owner = owner
+	return &models.CreateAPIKeyResponse{
+		APIKeyResponse: key.ToResponse(),
+		Key:            secret,
+	}, nil
+}
+
+// DeleteAPIKey revokes an API key
+func (s *apiKeyService) DeleteAPIKey(id uuid.UUID) error {
+	if err := s.apiKeyRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("api key not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// AuthenticateAPIKey looks up the key sent by a client. Unknown and expired
+// keys, and keys of disabled or deleted owners, are "invalid api key". The
+// last-used time is written at most once per APIKeyLastUsedInterval.
+func (s *apiKeyService) AuthenticateAPIKey(secret string) (*models.APIKey, error) {
+	if !strings.HasPrefix(secret, constants.APIKeyPrefix) {
+		return nil, errors.New("invalid api key")
+	}
+
+	key, err := s.apiKeyRepo.GetByHash(hashAPIKey(secret))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid api key")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if key.ExpiresAt != nil && !now.Before(*key.ExpiresAt) {
+		return nil, errors.New("invalid api key")
+	}
+	if key.Owner == nil || key.Owner.DisabledAt != nil {
+		return nil, errors.New("invalid api key")
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= constants.APIKeyLastUsedInterval {
+		if err := s.apiKeyRepo.TouchLastUsed(key.ID, now); err != nil {
+			log.Printf("Failed to record use of API key %s: %v", key.ID, err)
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+
+	return key, nil
+}
+
+// ownerScopes trims, deduplicates and sorts the requested scopes, checking
+// that each is a permission the owner's role holds
+func (s *apiKeyService) ownerScopes(owner *models.User, requested []string) ([]string, error) {
+	seen := make(map[string]bool, len(requested))
+	scopes := []string{}
+	for _, scope := range requested {
+		scope = strings.TrimSpace(scope)
+		if seen[scope] {
+			continue
+		}
+		if !isGrantablePermission(scope) && !isReservedPermission(scope) {
+			return nil, errors.New("unknown permission")
+		}
+
+		held, err := s.permissionService.HasPermission(owner.Role, scope)
+		if err != nil {
+			return nil, err
+		}
+		if !held {
+			return nil, errors.New("scope not held by owner")
+		}
+
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("scopes are required")
+	}
+
+	sort.Strings(scopes)
+	return scopes, nil
+}
+
+// isReservedPermission reports whether the permission is held only by super-admins
+func isReservedPermission(permission string) bool {
+	for _, reserved := range constants.ReservedPermissions {
+		if permission == reserved {
+			return true
+		}
+	}
+	return false
+}
+
+// generateAPIKey returns a new random API key
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return constants.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashAPIKey returns the hex SHA-256 hash under which an API key is stored.
+// Keys are random, so unlike passwords they need no salt or slow hash.
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
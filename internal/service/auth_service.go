@@ -2,35 +2,65 @@ package service
 
 import (
 	"errors"
+	"net/mail"
+	"strings"
+	"time"
 
 	"sonic-labs/course-enrollment-service/internal/auth"
+	"sonic-labs/course-enrollment-service/internal/config"
+	"sonic-labs/course-enrollment-service/internal/constants"
 	"sonic-labs/course-enrollment-service/internal/models"
 	"sonic-labs/course-enrollment-service/internal/repository"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 // AuthService defines the interface for authentication business logic
 type AuthService interface {
-	Login(req models.LoginRequest) (*models.LoginResponse, error)
+	Login(req models.LoginRequest, clientIP string) (*models.LoginResponse, error)
+	Register(req models.RegisterRequest) (*models.LoginResponse, error)
+	Refresh(req models.RefreshRequest) (*models.LoginResponse, error)
+	Logout(sessionID string) error
+	ChangePassword(userID uuid.UUID, sessionID string, req models.ChangePasswordRequest) (*models.LoginResponse, error)
+	ResetPassword(req models.ResetPasswordRequest) (*models.LoginResponse, error)
 	ValidateToken(tokenString string) (*auth.Claims, error)
 }
 
+// TokenDenylist keeps the IDs of revoked tokens and sessions until the tokens
+// expire. It is implemented by RedisService and, when Redis is disabled, by
+// repository.RevokedTokenRepository.
+type TokenDenylist interface {
+	RevokeToken(id string, expiresAt time.Time) error
+	MarkTokenUsed(id string, expiresAt time.Time) (bool, error)
+	IsTokenRevoked(id string) (bool, error)
+}
+
 // authService implements AuthService interface
 type authService struct {
-	userRepo repository.UserRepository
+	userRepo  repository.UserRepository
+	denylist  TokenDenylist
+	guard     *loginGuard
+	passwords PasswordPolicy
 }
 
-// NewAuthService creates a new authentication service
-func NewAuthService(userRepo repository.UserRepository) AuthService {
+// NewAuthService creates a new authentication service. Failed logins are
+// counted in loginAttempts and throttled as loginPolicy sets out; new passwords
+// must meet the password policy.
+func NewAuthService(userRepo repository.UserRepository, denylist TokenDenylist, loginAttempts LoginAttemptStore, loginPolicy config.LoginConfig, passwords PasswordPolicy) AuthService {
 	return &authService{
-		userRepo: userRepo,
+		userRepo:  userRepo,
+		denylist:  denylist,
+		guard:     &loginGuard{store: loginAttempts, policy: loginPolicy},
+		passwords: passwords,
 	}
 }
 
-// Login authenticates a user and returns a JWT token
-func (s *authService) Login(req models.LoginRequest) (*models.LoginResponse, error) {
+// Login authenticates a user and returns a JWT token. Logins for a username or
+// from a client IP with recent failed attempts are refused with a
+// LoginThrottledError until their delay or lockout has passed.
+func (s *authService) Login(req models.LoginRequest, clientIP string) (*models.LoginResponse, error) {
 	// Validate input
 	if req.Username == "" {
 		return nil, errors.New("username is required")
@@ -39,10 +69,21 @@ func (s *authService) Login(req models.LoginRequest) (*models.LoginResponse, err
 		return nil, errors.New("password is required")
 	}
 
+	// Student accounts log in with their email, in any case
+	username := req.Username
+	if strings.Contains(username, "@") {
+		username = models.NormalizeEmail(username)
+	}
+
+	if err := s.guard.check(username, clientIP); err != nil {
+		return nil, err
+	}
+
 	// Find user by username
-	user, err := s.userRepo.GetByUsername(req.Username)
+	user, err := s.userRepo.GetByUsername(username)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.guard.fail(username, clientIP)
 			return nil, errors.New("invalid username or password")
 		}
 		return nil, err
@@ -51,19 +92,209 @@ func (s *authService) Login(req models.LoginRequest) (*models.LoginResponse, err
 	// Verify password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
+		s.guard.fail(username, clientIP)
 		return nil, errors.New("invalid username or password")
 	}
+	s.guard.succeed(username)
+	if user.DisabledAt != nil {
+		return nil, errors.New("account is disabled")
+	}
+
+	return issueTokens(user, uuid.NewString())
+}
 
-	// Generate JWT token
-	token, err := auth.GenerateToken(user.ID.String(), user.Username, user.Role)
+// Register creates a student account and logs the student in. The email of a
+// student that already exists, such as one an admin enrolled, can only be
+// registered with the invite token an admin issued for that student; the
+// account is then linked to it.
+func (s *authService) Register(req models.RegisterRequest) (*models.LoginResponse, error) {
+	email := models.NormalizeEmail(req.Email)
+	if email == "" {
+		return nil, errors.New("email is required")
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, errors.New("invalid email format")
+	}
+	if err := s.passwords.Check(req.Password); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.GetByUsername(email); err == nil {
+		return nil, errors.New("account already exists")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	hashedPassword, err := HashPassword(req.Password)
 	if err != nil {
-		return nil, errors.New("failed to generate token")
+		return nil, err
 	}
 
-	return &models.LoginResponse{
-		Token: token,
-		User:  user.ToResponse(),
-	}, nil
+	user := &models.User{
+		Username: email,
+		Password: hashedPassword,
+		Role:     constants.RoleUser,
+	}
+	if req.InviteToken != "" {
+		err = s.userRepo.ClaimStudentAccount(user, email, hashToken(req.InviteToken))
+	} else {
+		err = s.userRepo.CreateStudentAccount(user, email)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrStudentExists):
+			return nil, errors.New("student already exists")
+		case errors.Is(err, repository.ErrInvalidInvite):
+			return nil, errors.New("invalid invite token")
+		case errors.Is(err, repository.ErrStudentHasAccount):
+			return nil, errors.New("account already exists")
+		}
+		return nil, err
+	}
+
+	return issueTokens(user, uuid.NewString())
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token in the
+// same session. Each refresh token can only be used once: presenting one again
+// means it was copied, so the whole session is revoked. The user is looked up
+// again, so a changed role applies and a disabled user is refused, as are
+// refresh tokens issued before the password was last changed.
+func (s *authService) Refresh(req models.RefreshRequest) (*models.LoginResponse, error) {
+	claims, err := auth.ValidateToken(req.RefreshToken)
+	if err != nil || claims.TokenType != constants.TokenTypeRefresh {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	revoked, err := s.denylist.IsTokenRevoked(claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	// Marking the token used in one step makes sure only one of several
+	// concurrent refreshes with it wins; the others count as reuse
+	first, err := s.denylist.MarkTokenUsed(claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, err
+	}
+	if !first {
+		if err := s.Logout(claims.SessionID); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("invalid refresh token")
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, err
+	}
+	if user.DisabledAt != nil {
+		return nil, errors.New("account is disabled")
+	}
+	// Token times are in whole seconds
+	if user.PasswordChangedAt != nil && claims.IssuedAt.Time.Before(user.PasswordChangedAt.Truncate(time.Second)) {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	return issueTokens(user, claims.SessionID)
+}
+
+// Logout revokes a session: its access and refresh tokens stop working. The
+// session stays on the denylist until every token issued in it has expired.
+func (s *authService) Logout(sessionID string) error {
+	return s.denylist.RevokeToken(sessionID, time.Now().Add(constants.RefreshTokenExpiry))
+}
+
+// ChangePassword replaces the password of a user who knows the current one and
+// clears their must_change_password flag. The session the request was made in
+// is revoked and the user is logged in again in a new one; refresh tokens of
+// their other sessions stop working.
+func (s *authService) ChangePassword(userID uuid.UUID, sessionID string, req models.ChangePasswordRequest) (*models.LoginResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+		return nil, errors.New("current password is incorrect")
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return nil, errors.New("new password must differ")
+	}
+	if err := s.setPassword(user, req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	if sessionID != "" {
+		if err := s.Logout(sessionID); err != nil {
+			return nil, err
+		}
+	}
+	return issueTokens(user, uuid.NewString())
+}
+
+// ResetPassword sets a new password with a reset token an admin issued and logs
+// the user in. Each token works once, until it expires. Failed logins counted
+// against the user are forgotten.
+func (s *authService) ResetPassword(req models.ResetPasswordRequest) (*models.LoginResponse, error) {
+	if !strings.HasPrefix(req.Token, constants.PasswordResetTokenPrefix) {
+		return nil, errors.New("invalid reset token")
+	}
+
+	user, err := s.userRepo.GetByPasswordResetHash(hashToken(req.Token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid reset token")
+		}
+		return nil, err
+	}
+	if user.PasswordResetExpiresAt == nil || !time.Now().Before(*user.PasswordResetExpiresAt) {
+		return nil, errors.New("invalid reset token")
+	}
+	if user.DisabledAt != nil {
+		return nil, errors.New("account is disabled")
+	}
+
+	if err := s.setPassword(user, req.NewPassword); err != nil {
+		return nil, err
+	}
+	s.guard.succeed(user.Username)
+
+	return issueTokens(user, uuid.NewString())
+}
+
+// setPassword checks a new password against the policy and saves it, clearing
+// the must_change_password flag and any reset token
+func (s *authService) setPassword(user *models.User, password string) error {
+	if err := s.passwords.Check(password); err != nil {
+		return err
+	}
+
+	hashedPassword, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	user.Password = hashedPassword
+	user.MustChangePassword = false
+	user.PasswordChangedAt = &now
+	user.PasswordResetHash = nil
+	user.PasswordResetExpiresAt = nil
+	return s.userRepo.UpdatePassword(user)
 }
 
 // ValidateToken validates a JWT token and returns the claims
@@ -71,6 +302,39 @@ func (s *authService) ValidateToken(tokenString string) (*auth.Claims, error) {
 	return auth.ValidateToken(tokenString)
 }
 
+// issueTokens generates an access and refresh token for the user in the
+// session and builds the login response
+func issueTokens(user *models.User, sessionID string) (*models.LoginResponse, error) {
+	identity := auth.Identity{
+		UserID:             user.ID.String(),
+		Username:           user.Username,
+		Role:               user.Role,
+		MustChangePassword: user.MustChangePassword,
+	}
+	if user.StudentID != nil {
+		identity.StudentID = user.StudentID.String()
+	}
+	if user.InstructorID != nil {
+		identity.InstructorID = user.InstructorID.String()
+	}
+
+	accessToken, err := auth.GenerateToken(identity, constants.TokenTypeAccess, sessionID)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+	refreshToken, err := auth.GenerateToken(identity, constants.TokenTypeRefresh, sessionID)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	return &models.LoginResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(constants.AccessTokenExpiry.Seconds()),
+		User:         user.ToResponse(),
+	}, nil
+}
+
 // HashPassword hashes a password using bcrypt
 func HashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
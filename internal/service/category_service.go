@@ -0,0 +1,220 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CategoryService defines the interface for course category business logic
+type CategoryService interface {
+	GetCategories() (*models.CategoryListResponse, error)
+	GetCategory(id uuid.UUID) (*models.CategoryResponse, error)
+	CreateCategory(req models.CategoryRequest) (*models.CategoryResponse, error)
+	UpdateCategory(id uuid.UUID, req models.CategoryRequest) (*models.CategoryResponse, error)
+	DeleteCategory(id uuid.UUID) error
+}
+
+// categoryService implements CategoryService interface
+type categoryService struct {
+	categoryRepo repository.CategoryRepository
+	redisService *RedisService
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(categoryRepo repository.CategoryRepository, redisService *RedisService) CategoryService {
+	return &categoryService{
+		categoryRepo: categoryRepo,
+		redisService: redisService,
+	}
+}
+
+// GetCategories retrieves the category tree
+func (s *categoryService) GetCategories() (*models.CategoryListResponse, error) {
+	categories, counts, err := s.loadTree()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CategoryListResponse{
+		Categories: models.BuildCategoryTree(categories, counts),
+		Total:      len(categories),
+	}, nil
+}
+
+// GetCategory retrieves a category with its subcategories
+func (s *categoryService) GetCategory(id uuid.UUID) (*models.CategoryResponse, error) {
+	categories, counts, err := s.loadTree()
+	if err != nil {
+		return nil, err
+	}
+
+	if response := findCategory(models.BuildCategoryTree(categories, counts), id); response != nil {
+		return response, nil
+	}
+	return nil, errors.New("category not found")
+}
+
+// CreateCategory adds a category, at the top level or under a parent
+func (s *categoryService) CreateCategory(req models.CategoryRequest) (*models.CategoryResponse, error) {
+	if err := s.validateCategory(uuid.Nil, &req); err != nil {
+		return nil, err
+	}
+
+	category := models.Category{
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: req.Description,
+		ParentID:    req.ParentID,
+	}
+	if err := s.categoryRepo.Create(&category); err != nil {
+		return nil, err
+	}
+
+	return s.GetCategory(category.ID)
+}
+
+// UpdateCategory renames or moves a category. Its subcategories move along.
+func (s *categoryService) UpdateCategory(id uuid.UUID, req models.CategoryRequest) (*models.CategoryResponse, error) {
+	category, err := s.getCategory(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateCategory(id, &req); err != nil {
+		return nil, err
+	}
+
+	category.Name = req.Name
+	category.Slug = req.Slug
+	category.Description = req.Description
+	category.ParentID = req.ParentID
+	if err := s.categoryRepo.Update(category); err != nil {
+		return nil, err
+	}
+
+	// Courses show the name and slug of their categories
+	if s.redisService != nil {
+		s.redisService.InvalidateAllCourses()
+	}
+
+	return s.GetCategory(id)
+}
+
+// DeleteCategory removes a category without subcategories from the tree and
+// from every course in it
+func (s *categoryService) DeleteCategory(id uuid.UUID) error {
+	if _, err := s.getCategory(id); err != nil {
+		return err
+	}
+
+	children, err := s.categoryRepo.CountChildren(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return errors.New("category has subcategories")
+	}
+
+	if err := s.categoryRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("category not found")
+		}
+		return err
+	}
+
+	if s.redisService != nil {
+		s.redisService.InvalidateAllCourses()
+	}
+
+	return nil
+}
+
+// loadTree loads every category and the number of courses in each
+func (s *categoryService) loadTree() ([]models.Category, map[uuid.UUID]int, error) {
+	categories, err := s.categoryRepo.GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	counts, err := s.categoryRepo.CountCourses()
+	if err != nil {
+		return nil, nil, err
+	}
+	return categories, counts, nil
+}
+
+// getCategory loads a category, mapping a missing row to "category not found"
+func (s *categoryService) getCategory(id uuid.UUID) (*models.Category, error) {
+	category, err := s.categoryRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+// validateCategory trims the name and normalizes the slug, deriving it from the
+// name when empty. It checks that the slug is not used by another category and
+// that the parent exists and is not the category itself or one of its subcategories.
+func (s *categoryService) validateCategory(id uuid.UUID, req *models.CategoryRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return errors.New("category name is required")
+	}
+	if len(req.Name) > constants.MaxCategoryNameLength {
+		return errors.New("category name is too long")
+	}
+
+	if strings.TrimSpace(req.Slug) == "" {
+		req.Slug = req.Name
+	}
+	req.Slug = models.Slugify(req.Slug)
+	if req.Slug == "" || len(req.Slug) > constants.MaxCategoryNameLength {
+		return errors.New("invalid category slug")
+	}
+
+	existing, err := s.categoryRepo.GetBySlug(req.Slug)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return errors.New("category slug already exists")
+	}
+
+	// Walk up from the new parent; meeting the category itself means a cycle
+	for parentID := req.ParentID; parentID != nil; {
+		if *parentID == id {
+			return errors.New("category cannot be moved under itself")
+		}
+		parent, err := s.categoryRepo.GetByID(*parentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("parent category not found")
+			}
+			return err
+		}
+		parentID = parent.ParentID
+	}
+
+	return nil
+}
+
+// findCategory looks a category up anywhere in a category tree
+func findCategory(tree []models.CategoryResponse, id uuid.UUID) *models.CategoryResponse {
+	for i := range tree {
+		if tree[i].ID == id {
+			return &tree[i]
+		}
+		if found := findCategory(tree[i].Children, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"errors"
+	"strings"
+
 	"sonic-labs/course-enrollment-service/internal/constants"
 	"sonic-labs/course-enrollment-service/internal/models"
 	"sonic-labs/course-enrollment-service/internal/repository"
@@ -18,32 +20,55 @@ type CourseService interface {
 	GetCourseByID(id uuid.UUID) (*models.CourseResponse, error)
 	UpdateCourse(id uuid.UUID, req models.CourseRequest) (*models.CourseResponse, error)
 	DeleteCourse(id uuid.UUID) error
-	GetCourseStudents(courseID uuid.UUID) ([]string, error)
-	RemoveStudentFromCourse(courseID uuid.UUID, studentEmail string) error
+	GetCourseStudents(courseID uuid.UUID, statuses []string) ([]string, error)
+	RemoveStudentFromCourse(courseID uuid.UUID, studentEmail string, actor string) error
+	SetCourseInstructors(courseID uuid.UUID, req models.CourseInstructorsRequest) (*models.CourseResponse, error)
 }
 
 // courseService implements CourseService interface
 type courseService struct {
 	courseRepo     repository.CourseRepository
 	enrollmentRepo repository.EnrollmentRepository
+	waitlistRepo   repository.WaitlistRepository
+	categoryRepo   repository.CategoryRepository
+	tagRepo        repository.TagRepository
+	difficultyRepo repository.DifficultyRepository
+	instructorRepo repository.InstructorRepository
 	redisService   *RedisService
 }
 
 // NewCourseService creates a new course service
-func NewCourseService(courseRepo repository.CourseRepository, enrollmentRepo repository.EnrollmentRepository, redisService *RedisService) CourseService {
+func NewCourseService(courseRepo repository.CourseRepository, enrollmentRepo repository.EnrollmentRepository, waitlistRepo repository.WaitlistRepository, categoryRepo repository.CategoryRepository, tagRepo repository.TagRepository, difficultyRepo repository.DifficultyRepository, instructorRepo repository.InstructorRepository, redisService *RedisService) CourseService {
 	return &courseService{
 		courseRepo:     courseRepo,
 		enrollmentRepo: enrollmentRepo,
+		waitlistRepo:   waitlistRepo,
+		categoryRepo:   categoryRepo,
+		tagRepo:        tagRepo,
+		difficultyRepo: difficultyRepo,
+		instructorRepo: instructorRepo,
 		redisService:   redisService,
 	}
 }
 
 func (s *courseService) CreateCourse(req models.CourseRequest) (*models.CourseResponse, error) {
+	difficulty, err := resolveDifficulty(s.difficultyRepo, req.Difficulty)
+	if err != nil {
+		return nil, err
+	}
+
 	course := models.Course{
 		Title:       req.Title,
 		Description: req.Description,
-		Difficulty:  req.Difficulty,
+		Difficulty:  difficulty,
 		ImageURL:    req.ImageURL,
+		Capacity:    req.Capacity,
+
+		EnrollmentOpensAt:  req.EnrollmentOpensAt,
+		EnrollmentClosesAt: req.EnrollmentClosesAt,
+	}
+	if err := s.applyTaxonomy(&course, req); err != nil {
+		return nil, err
 	}
 
 	if err := s.courseRepo.Create(&course); err != nil {
@@ -110,6 +135,27 @@ func (s *courseService) GetCoursesWithPagination(params models.CourseQueryParams
 		params.Limit = constants.MaxPageSize // Max limit to prevent abuse
 	}
 
+	// Match difficulty filters to the stored levels. Unknown ones are kept as
+	// given, so that they match no course rather than lifting the filter.
+	if len(params.Difficulty) > 0 {
+		levels, err := s.difficultyRepo.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		stored := make(map[string]string, len(levels))
+		for _, level := range levels {
+			stored[strings.ToLower(level.Name)] = level.Name
+		}
+		difficulties := []string{}
+		for _, name := range params.Difficulty {
+			if level, ok := stored[strings.ToLower(name)]; ok {
+				name = level
+			}
+			difficulties = append(difficulties, name)
+		}
+		params.Difficulty = difficulties
+	}
+
 	// Get courses from repository
 	courses, totalCount, err := s.courseRepo.GetWithPagination(params)
 	if err != nil {
@@ -180,20 +226,81 @@ func (s *courseService) UpdateCourse(id uuid.UUID, req models.CourseRequest) (*m
 		}
 		return nil, err
 	}
+	difficulty, err := resolveDifficulty(s.difficultyRepo, req.Difficulty)
+	if err != nil {
+		return nil, err
+	}
 
 	course.Title = req.Title
 	course.Description = req.Description
-	course.Difficulty = req.Difficulty
+	course.Difficulty = difficulty
 	course.ImageURL = req.ImageURL
+	course.Capacity = req.Capacity
+	course.EnrollmentOpensAt = req.EnrollmentOpensAt
+	course.EnrollmentClosesAt = req.EnrollmentClosesAt
+	if err := s.applyTaxonomy(course, req); err != nil {
+		return nil, err
+	}
 
 	if err := s.courseRepo.Update(course); err != nil {
 		return nil, err
 	}
 
+	if s.redisService != nil {
+		s.redisService.DeleteCourse(course.ID.String())
+		s.redisService.InvalidateCoursesCache()
+	}
+
+	// A raised or removed capacity may free seats for waitlisted students
+	promoted, err := s.waitlistRepo.FillFreeSeats(course.ID)
+	if err != nil {
+		return nil, err
+	}
+	logPromotions(promoted)
+
 	response := course.ToResponse()
 	return &response, nil
 }
 
+// applyTaxonomy sets the categories and tags of a course from the request. A nil
+// list keeps what the course has; tags that do not exist yet are created.
+func (s *courseService) applyTaxonomy(course *models.Course, req models.CourseRequest) error {
+	if req.CategoryIDs != nil {
+		ids := make([]uuid.UUID, 0, len(req.CategoryIDs))
+		seen := make(map[uuid.UUID]bool, len(req.CategoryIDs))
+		for _, id := range req.CategoryIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		categories, err := s.categoryRepo.GetByIDs(ids)
+		if err != nil {
+			return err
+		}
+		if len(categories) != len(ids) {
+			return errors.New("category not found")
+		}
+		course.Categories = categories
+	}
+
+	if req.Tags != nil {
+		for _, name := range req.Tags {
+			name = models.NormalizeTag(name)
+			if name == "" || len(name) > constants.MaxTagLength {
+				return errors.New("invalid tag")
+			}
+		}
+		tags, err := s.tagRepo.FindOrCreate(req.Tags)
+		if err != nil {
+			return err
+		}
+		course.Tags = tags
+	}
+
+	return nil
+}
+
 // DeleteCourse deletes a course
 func (s *courseService) DeleteCourse(id uuid.UUID) error {
 	_, err := s.courseRepo.GetByID(id)
@@ -207,8 +314,16 @@ func (s *courseService) DeleteCourse(id uuid.UUID) error {
 	return s.courseRepo.Delete(id)
 }
 
-// GetCourseStudents retrieves all student emails enrolled in a course
-func (s *courseService) GetCourseStudents(courseID uuid.UUID) ([]string, error) {
+// GetCourseStudents retrieves the emails of students enrolled in a course.
+// Without a status filter only students currently holding a seat are returned.
+func (s *courseService) GetCourseStudents(courseID uuid.UUID, statuses []string) ([]string, error) {
+	if err := validateStatusFilter(statuses); err != nil {
+		return nil, err
+	}
+	if len(statuses) == 0 {
+		statuses = []string{constants.EnrollmentStatusPending, constants.EnrollmentStatusActive}
+	}
+
 	// Check if course exists
 	_, err := s.courseRepo.GetByID(courseID)
 	if err != nil {
@@ -218,11 +333,11 @@ func (s *courseService) GetCourseStudents(courseID uuid.UUID) ([]string, error)
 		return nil, err
 	}
 
-	return s.enrollmentRepo.GetStudentsByCourseID(courseID)
+	return s.enrollmentRepo.GetStudentsByCourseID(courseID, statuses...)
 }
 
-// RemoveStudentFromCourse removes a student from a specific course
-func (s *courseService) RemoveStudentFromCourse(courseID uuid.UUID, studentEmail string) error {
+// RemoveStudentFromCourse withdraws a student from a specific course
+func (s *courseService) RemoveStudentFromCourse(courseID uuid.UUID, studentEmail string, actor string) error {
 	// Check if course exists
 	_, err := s.courseRepo.GetByID(courseID)
 	if err != nil {
@@ -232,14 +347,62 @@ func (s *courseService) RemoveStudentFromCourse(courseID uuid.UUID, studentEmail
 		return err
 	}
 
-	// Remove enrollment
-	err = s.enrollmentRepo.DeleteByStudentAndCourse(studentEmail, courseID)
+	enrollment, err := s.enrollmentRepo.GetByStudentAndCourse(studentEmail, courseID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("student not enrolled in this course")
 		}
 		return err
 	}
+	if !enrollment.HoldsSeat() {
+		return errors.New("student not enrolled in this course")
+	}
+
+	// Withdraw enrollment and hand the freed seat to the waitlist
+	promoted, err := s.enrollmentRepo.TransitionStatus(enrollment, constants.EnrollmentStatusWithdrawn, actor, nil)
+	if err != nil {
+		return err
+	}
+	logPromotions(promoted)
 
 	return nil
 }
+
+// SetCourseInstructors replaces the instructors teaching a course
+func (s *courseService) SetCourseInstructors(courseID uuid.UUID, req models.CourseInstructorsRequest) (*models.CourseResponse, error) {
+	course, err := s.courseRepo.GetByID(courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(req.InstructorIDs))
+	seen := make(map[uuid.UUID]bool, len(req.InstructorIDs))
+	for _, id := range req.InstructorIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	instructors, err := s.instructorRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(instructors) != len(ids) {
+		return nil, errors.New("instructor not found")
+	}
+
+	if err := s.courseRepo.SetInstructors(course, instructors); err != nil {
+		return nil, err
+	}
+
+	if s.redisService != nil {
+		s.redisService.DeleteCourse(course.ID.String())
+		s.redisService.InvalidateCoursesCache()
+	}
+
+	response := course.ToResponse()
+	return &response, nil
+}
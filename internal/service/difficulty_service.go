@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InvalidDifficultyError is returned when a course is given a difficulty that
+// is not a stored level. Levels lists the valid names, easiest first.
+type InvalidDifficultyError struct {
+	Levels []string
+}
+
+func (e *InvalidDifficultyError) Error() string {
+	return "invalid difficulty"
+}
+
+// DifficultyService defines the interface for difficulty level business logic
+type DifficultyService interface {
+	GetLevels() (*models.DifficultyLevelListResponse, error)
+	CreateLevel(req models.DifficultyLevelRequest) (*models.DifficultyLevelResponse, error)
+	UpdateLevel(id uuid.UUID, req models.DifficultyLevelRequest) (*models.DifficultyLevelResponse, error)
+	DeleteLevel(id uuid.UUID) error
+}
+
+// difficultyService implements DifficultyService interface
+type difficultyService struct {
+	difficultyRepo repository.DifficultyRepository
+	redisService   *RedisService
+}
+
+// NewDifficultyService creates a new difficulty level service
+func NewDifficultyService(difficultyRepo repository.DifficultyRepository, redisService *RedisService) DifficultyService {
+	return &difficultyService{
+		difficultyRepo: difficultyRepo,
+		redisService:   redisService,
+	}
+}
+
+// GetLevels retrieves every difficulty level, easiest first
+func (s *difficultyService) GetLevels() (*models.DifficultyLevelListResponse, error) {
+	levels, err := s.difficultyRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.difficultyRepo.CountCourses()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.DifficultyLevelResponse, len(levels))
+	for i, level := range levels {
+		responses[i] = level.ToResponse(counts[level.Name])
+	}
+
+	return &models.DifficultyLevelListResponse{
+		Levels: responses,
+		Total:  len(responses),
+	}, nil
+}
+
+// CreateLevel adds a difficulty level, after the hardest one unless a sort order is given
+func (s *difficultyService) CreateLevel(req models.DifficultyLevelRequest) (*models.DifficultyLevelResponse, error) {
+	if err := s.validateLevel(uuid.Nil, &req); err != nil {
+		return nil, err
+	}
+
+	level := models.DifficultyLevel{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if req.SortOrder != nil {
+		level.SortOrder = *req.SortOrder
+	} else {
+		maxSortOrder, err := s.difficultyRepo.GetMaxSortOrder()
+		if err != nil {
+			return nil, err
+		}
+		level.SortOrder = maxSortOrder + 1
+	}
+	if err := s.difficultyRepo.Create(&level); err != nil {
+		return nil, err
+	}
+
+	response := level.ToResponse(0)
+	return &response, nil
+}
+
+// UpdateLevel renames, moves or describes a difficulty level. A new name is
+// copied to every course with the level.
+func (s *difficultyService) UpdateLevel(id uuid.UUID, req models.DifficultyLevelRequest) (*models.DifficultyLevelResponse, error) {
+	level, err := s.getLevel(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateLevel(id, &req); err != nil {
+		return nil, err
+	}
+
+	oldName := level.Name
+	level.Name = req.Name
+	level.Description = req.Description
+	if req.SortOrder != nil {
+		level.SortOrder = *req.SortOrder
+	}
+	if err := s.difficultyRepo.Update(level, oldName); err != nil {
+		return nil, err
+	}
+	counts, err := s.difficultyRepo.CountCourses()
+	if err != nil {
+		return nil, err
+	}
+
+	// Courses show the name of their level
+	if level.Name != oldName && s.redisService != nil {
+		s.redisService.InvalidateAllCourses()
+	}
+
+	response := level.ToResponse(counts[level.Name])
+	return &response, nil
+}
+
+// DeleteLevel removes a difficulty level no course has
+func (s *difficultyService) DeleteLevel(id uuid.UUID) error {
+	level, err := s.getLevel(id)
+	if err != nil {
+		return err
+	}
+
+	counts, err := s.difficultyRepo.CountCourses()
+	if err != nil {
+		return err
+	}
+	if counts[level.Name] > 0 {
+		return errors.New("difficulty level is in use")
+	}
+
+	if err := s.difficultyRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("difficulty level not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// getLevel loads a difficulty level, mapping a missing row to "difficulty level not found"
+func (s *difficultyService) getLevel(id uuid.UUID) (*models.DifficultyLevel, error) {
+	level, err := s.difficultyRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("difficulty level not found")
+		}
+		return nil, err
+	}
+	return level, nil
+}
+
+// validateLevel trims the level name and checks that it is set and not used by
+// another level, ignoring case
+func (s *difficultyService) validateLevel(id uuid.UUID, req *models.DifficultyLevelRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return errors.New("difficulty level name is required")
+	}
+	if len(req.Name) > constants.MaxDifficultyNameLength {
+		return errors.New("difficulty level name is too long")
+	}
+
+	existing, err := s.difficultyRepo.GetByName(req.Name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return errors.New("difficulty level name already exists")
+	}
+
+	return nil
+}
+
+// resolveDifficulty returns the stored name of the difficulty level matching
+// name, ignoring case, or an InvalidDifficultyError if there is none
+func resolveDifficulty(difficultyRepo repository.DifficultyRepository, name string) (string, error) {
+	if name = strings.TrimSpace(name); name != "" {
+		level, err := difficultyRepo.GetByName(name)
+		if err == nil {
+			return level.Name, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", err
+		}
+	}
+
+	levels, err := difficultyRepo.GetAll()
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, len(levels))
+	for i, level := range levels {
+		names[i] = level.Name
+	}
+	return "", &InvalidDifficultyError{Levels: names}
+}
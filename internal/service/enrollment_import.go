@@ -0,0 +1,295 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidImportFile is returned when an enrollment import cannot be read as a whole,
+// as opposed to individual rows that are reported as malformed
+var ErrInvalidImportFile = errors.New("invalid import file")
+
+// importRow is one data row of an enrollment import and the line it was read from
+type importRow struct {
+	line   int
+	fields []string
+}
+
+// resolvedCourse caches the outcome of looking up a course column value
+type resolvedCourse struct {
+	id  uuid.UUID
+	err error
+}
+
+// enrollmentImport holds the state shared by the rows of a single import
+type enrollmentImport struct {
+	service *enrollmentService
+	dryRun  bool
+	actor   string
+
+	courses map[string]resolvedCourse // course column value, lower-cased -> course
+	seen    map[string]int            // student email and course ID -> first row
+	seats   map[uuid.UUID]int         // seats taken per course, including rows planned by a dry run
+}
+
+// ImportEnrollments enrolls every student listed in a CSV of student_email,course
+// rows, where course is either a course ID or a course title. Each row goes through
+// the same checks as EnrollStudent and is written on its own, so invalid rows do not
+// stop valid ones. A dry run only reports what would happen and writes nothing.
+func (s *enrollmentService) ImportEnrollments(r io.Reader, dryRun bool, actor string) (*models.EnrollmentImportReport, error) {
+	rows, err := readImportRows(r)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &enrollmentImport{
+		service: s,
+		dryRun:  dryRun,
+		actor:   actor,
+		courses: make(map[string]resolvedCourse),
+		seen:    make(map[string]int),
+		seats:   make(map[uuid.UUID]int),
+	}
+
+	report := &models.EnrollmentImportReport{
+		DryRun:  dryRun,
+		Total:   len(rows),
+		Summary: make(map[string]int),
+		Rows:    make([]models.EnrollmentImportRowResult, 0, len(rows)),
+	}
+	for _, row := range rows {
+		result, err := imp.importRow(row)
+		if err != nil {
+			return nil, err
+		}
+		report.Rows = append(report.Rows, result)
+		report.Summary[result.Status]++
+	}
+
+	return report, nil
+}
+
+// readImportRows parses the CSV and returns its data rows. A first row whose
+// first column is "student_email" is treated as a header and skipped.
+func readImportRows(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []importRow
+	first := true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidImportFile, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		if first {
+			first = false
+			if strings.EqualFold(strings.TrimSpace(record[0]), "student_email") {
+				continue
+			}
+		}
+
+		if len(rows) == constants.MaxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidImportFile, constants.MaxImportRows)
+		}
+		rows = append(rows, importRow{line: line, fields: record})
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows to import", ErrInvalidImportFile)
+	}
+	return rows, nil
+}
+
+// importRow checks and, unless this is a dry run, writes a single row
+func (imp *enrollmentImport) importRow(row importRow) (models.EnrollmentImportRowResult, error) {
+	result := models.EnrollmentImportRowResult{Row: row.line}
+	if len(row.fields) > 0 {
+		result.StudentEmail = models.NormalizeEmail(row.fields[0])
+	}
+	if len(row.fields) != 2 {
+		result.Status = constants.ImportRowMalformed
+		result.Message = "Expected 2 columns: student_email,course"
+		return result, nil
+	}
+	result.Course = strings.TrimSpace(row.fields[1])
+
+	if _, err := mail.ParseAddress(result.StudentEmail); err != nil {
+		result.Status = constants.ImportRowInvalidEmail
+		result.Message = "Invalid email format"
+		return result, nil
+	}
+
+	courseID, err := imp.resolveCourse(result.Course)
+	if err != nil {
+		if err.Error() == "course not found" || err.Error() == "course title matches more than one course" {
+			result.Status = constants.ImportRowUnknownCourse
+			result.Message = importErrorMessage(err)
+			return result, nil
+		}
+		return result, err
+	}
+	result.CourseID = &courseID
+
+	key := result.StudentEmail + "|" + courseID.String()
+	if first, ok := imp.seen[key]; ok {
+		result.Status = constants.ImportRowAlreadyEnrolled
+		result.Message = fmt.Sprintf("Duplicate of row %d", first)
+		return result, nil
+	}
+	imp.seen[key] = row.line
+
+	req := models.EnrollmentRequest{
+		StudentEmail: result.StudentEmail,
+		CourseID:     courseID,
+	}
+	course, _, err := imp.service.checkEnrollment(&req, imp.actor)
+	if err != nil {
+		return imp.reject(result, err)
+	}
+
+	if imp.dryRun {
+		return imp.planSeat(result, course)
+	}
+
+	enrollment := models.Enrollment{
+		StudentEmail:    req.StudentEmail,
+		CourseID:        req.CourseID,
+		StatusChangedBy: imp.actor,
+	}
+	entry, err := imp.service.enrollmentRepo.EnrollOrWaitlist(&enrollment, nil)
+	if err != nil {
+		return imp.reject(result, err)
+	}
+	if entry != nil {
+		result.Status = constants.ImportRowWaitlisted
+		result.Message = fmt.Sprintf("Course is full, added to the waitlist at position %d", entry.Position)
+		return result, nil
+	}
+
+	result.Status = constants.ImportRowCreated
+	result.EnrollmentID = &enrollment.ID
+	return result, nil
+}
+
+// resolveCourse turns a course column value into a course ID. Values that parse
+// as a UUID are taken as IDs; anything else is matched against course titles.
+func (imp *enrollmentImport) resolveCourse(value string) (uuid.UUID, error) {
+	key := strings.ToLower(value)
+	if cached, ok := imp.courses[key]; ok {
+		return cached.id, cached.err
+	}
+
+	var resolved resolvedCourse
+	if id, err := uuid.Parse(value); err == nil {
+		resolved.id = id
+	} else {
+		courses, err := imp.service.courseRepo.GetByTitle(value)
+		switch {
+		case err != nil:
+			return uuid.Nil, err
+		case len(courses) == 0:
+			resolved.err = errors.New("course not found")
+		case len(courses) > 1:
+			resolved.err = errors.New("course title matches more than one course")
+		default:
+			resolved.id = courses[0].ID
+		}
+	}
+
+	imp.courses[key] = resolved
+	return resolved.id, resolved.err
+}
+
+// planSeat predicts whether a dry-run row would take a seat in the course's
+// default offering or join the waitlist, counting the seats taken by earlier rows
+// of the same import. A student already on the waitlist of a full course cannot
+// join it again.
+func (imp *enrollmentImport) planSeat(result models.EnrollmentImportRowResult, course *models.Course) (models.EnrollmentImportRowResult, error) {
+	offering, err := imp.service.offeringRepo.GetDefault(course.ID)
+	if err != nil {
+		return result, err
+	}
+
+	taken, ok := imp.seats[course.ID]
+	if !ok {
+		counts, err := imp.service.offeringRepo.CountSeatsTaken([]uuid.UUID{offering.ID})
+		if err != nil {
+			return result, err
+		}
+		taken = counts[offering.ID]
+	}
+
+	if capacity := offering.EffectiveCapacity(course); capacity != nil && taken >= *capacity {
+		_, err := imp.service.waitlistRepo.GetEntry(course.ID, result.StudentEmail)
+		if err == nil {
+			return imp.reject(result, errors.New("student is already on the waitlist for this course"))
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return result, err
+		}
+
+		result.Status = constants.ImportRowWaitlisted
+		result.Message = "Course is full, student would be added to the waitlist"
+		return result, nil
+	}
+
+	imp.seats[course.ID] = taken + 1
+	result.Status = constants.ImportRowCreated
+	return result, nil
+}
+
+// reject records why a row was not imported. Errors that are not about the row
+// itself, such as database failures, abort the import.
+func (imp *enrollmentImport) reject(result models.EnrollmentImportRowResult, err error) (models.EnrollmentImportRowResult, error) {
+	var outsideWindow *EnrollmentWindowError
+	var missing *MissingPrerequisitesError
+	switch {
+	case err.Error() == "invalid email format":
+		result.Status = constants.ImportRowInvalidEmail
+	case err.Error() == "course not found":
+		result.Status = constants.ImportRowUnknownCourse
+	case errors.Is(err, ErrAlreadyEnrolled),
+		err.Error() == "student is already on the waitlist for this course":
+		result.Status = constants.ImportRowAlreadyEnrolled
+	case errors.As(err, &outsideWindow), errors.As(err, &missing),
+		errors.Is(err, ErrInvalidStatusTransition),
+		err.Error() == "student has already completed this course":
+		result.Status = constants.ImportRowRejected
+	default:
+		return result, err
+	}
+
+	result.Message = importErrorMessage(err)
+	return result, nil
+}
+
+// importErrorMessage turns a service error into a sentence for the import report
+func importErrorMessage(err error) string {
+	var missing *MissingPrerequisitesError
+	if errors.As(err, &missing) {
+		return "Student has not completed the prerequisites for this course"
+	}
+	if err.Error() == "course not found" {
+		return "The specified course does not exist"
+	}
+
+	message := err.Error()
+	return strings.ToUpper(message[:1]) + message[1:]
+}
@@ -2,55 +2,107 @@ package service
 
 import (
 	"errors"
+	"io"
 	"net/mail"
+	"sonic-labs/course-enrollment-service/internal/constants"
 	"sonic-labs/course-enrollment-service/internal/models"
 	"sonic-labs/course-enrollment-service/internal/repository"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// ErrAlreadyEnrolled is returned by EnrollStudent when the student already holds a
+// seat in the course, whether found up front or lost to a concurrent request
+var ErrAlreadyEnrolled = repository.ErrAlreadyEnrolled
+
+// ErrOverrideNotAllowed is returned by EnrollStudent when a caller other than an
+// admin asks to skip the prerequisite or schedule conflict checks
+var ErrOverrideNotAllowed = errors.New("only admins may override enrollment checks")
+
+// EnrollmentWindowError is returned by EnrollStudent when the course is not
+// accepting enrollments at the moment. Code is one of the constants.EnrollmentWindow* values.
+type EnrollmentWindowError struct {
+	Code     string
+	OpensAt  *time.Time
+	ClosesAt *time.Time
+}
+
+func (e *EnrollmentWindowError) Error() string {
+	if e.Code == constants.EnrollmentWindowNotOpen {
+		return "enrollment for this course has not opened yet"
+	}
+	return "enrollment for this course has closed"
+}
+
 // EnrollmentService defines the interface for enrollment business logic
 type EnrollmentService interface {
-	EnrollStudent(req models.EnrollmentRequest) (*models.EnrollmentResponse, error)
-	GetStudentEnrollments(email string) (*models.StudentEnrollmentsResponse, error)
-	UnenrollStudent(email string, courseID uuid.UUID) error
+	EnrollStudent(req models.EnrollmentRequest, actor, role string) (*models.EnrollmentResponse, error)
+	ImportEnrollments(r io.Reader, dryRun bool, actor string) (*models.EnrollmentImportReport, error)
+	GetStudentEnrollments(email string, termID *uuid.UUID, statuses []string) (*models.StudentEnrollmentsResponse, error)
+	UnenrollStudent(email string, courseID uuid.UUID, actor string) error
+	EnrollSelf(studentID uuid.UUID, req models.SelfEnrollmentRequest, actor string) (*models.EnrollmentResponse, error)
+	GetOwnEnrollments(studentID uuid.UUID, termID *uuid.UUID, statuses []string) (*models.StudentEnrollmentsResponse, error)
+	DropOwnEnrollment(studentID uuid.UUID, courseID uuid.UUID, actor string) error
+	UpdateEnrollmentStatus(id uuid.UUID, req models.EnrollmentStatusRequest, actor string) (*models.EnrollmentResponse, error)
+	GetEnrollmentHistory(id uuid.UUID) (*models.EnrollmentHistoryResponse, error)
 }
 
 // enrollmentService implements EnrollmentService interface
 type enrollmentService struct {
-	enrollmentRepo repository.EnrollmentRepository
-	courseRepo     repository.CourseRepository
+	enrollmentRepo   repository.EnrollmentRepository
+	courseRepo       repository.CourseRepository
+	prerequisiteRepo repository.PrerequisiteRepository
+	progressRepo     repository.ProgressRepository
+	offeringRepo     repository.OfferingRepository
+	sectionRepo      repository.SectionRepository
+	studentRepo      repository.StudentRepository
+	waitlistRepo     repository.WaitlistRepository
 }
 
 // NewEnrollmentService creates a new enrollment service
-func NewEnrollmentService(enrollmentRepo repository.EnrollmentRepository, courseRepo repository.CourseRepository) EnrollmentService {
+func NewEnrollmentService(enrollmentRepo repository.EnrollmentRepository, courseRepo repository.CourseRepository, prerequisiteRepo repository.PrerequisiteRepository, progressRepo repository.ProgressRepository, offeringRepo repository.OfferingRepository, sectionRepo repository.SectionRepository, studentRepo repository.StudentRepository, waitlistRepo repository.WaitlistRepository) EnrollmentService {
 	return &enrollmentService{
-		enrollmentRepo: enrollmentRepo,
-		courseRepo:     courseRepo,
+		enrollmentRepo:   enrollmentRepo,
+		courseRepo:       courseRepo,
+		prerequisiteRepo: prerequisiteRepo,
+		progressRepo:     progressRepo,
+		offeringRepo:     offeringRepo,
+		sectionRepo:      sectionRepo,
+		studentRepo:      studentRepo,
+		waitlistRepo:     waitlistRepo,
 	}
 }
 
-func (s *enrollmentService) EnrollStudent(req models.EnrollmentRequest) (*models.EnrollmentResponse, error) {
-	if _, err := mail.ParseAddress(req.StudentEmail); err != nil {
-		return nil, errors.New("invalid email format")
+// EnrollStudent enrolls a student in a course, or waitlists them when it is full.
+// Only callers with an admin role may set the override flags.
+func (s *enrollmentService) EnrollStudent(req models.EnrollmentRequest, actor, role string) (*models.EnrollmentResponse, error) {
+	if (req.OverridePrerequisites || req.OverrideScheduleConflicts) && !isStaffRole(role) {
+		return nil, ErrOverrideNotAllowed
 	}
-	_, err := s.courseRepo.GetByID(req.CourseID)
+	_, override, err := s.checkEnrollment(&req, actor)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("course not found")
-		}
 		return nil, err
 	}
 
 	enrollment := models.Enrollment{
-		StudentEmail: req.StudentEmail,
-		CourseID:     req.CourseID,
+		StudentEmail:    req.StudentEmail,
+		CourseID:        req.CourseID,
+		StatusChangedBy: actor,
 	}
+	if req.OfferingID != nil {
+		enrollment.OfferingID = *req.OfferingID
+	}
+	enrollment.SectionID = req.SectionID
 
-	if err := s.enrollmentRepo.Create(&enrollment); err != nil {
+	entry, err := s.enrollmentRepo.EnrollOrWaitlist(&enrollment, override)
+	if err != nil {
 		return nil, err
 	}
+	if entry != nil {
+		return nil, &WaitlistedError{Entry: entry.ToResponse()}
+	}
 	createdEnrollment, err := s.enrollmentRepo.GetByStudentAndCourse(req.StudentEmail, req.CourseID)
 	if err != nil {
 		return nil, err
@@ -60,12 +112,137 @@ func (s *enrollmentService) EnrollStudent(req models.EnrollmentRequest) (*models
 	return &response, nil
 }
 
-func (s *enrollmentService) GetStudentEnrollments(email string) (*models.StudentEnrollmentsResponse, error) {
+// checkEnrollment applies every rule an enrollment request must pass before it is
+// written: a valid email, which is normalized in place, an existing course inside its enrollment window, an
+// offering of that course whose term has not ended, no enrollment already holding
+// a seat, completed prerequisites and a section that fits the student's schedule.
+// A requested section fills in the offering it belongs to. It returns the course
+// and, when an admin skipped missing prerequisites, the override to record.
+func (s *enrollmentService) checkEnrollment(req *models.EnrollmentRequest, actor string) (*models.Course, *models.PrerequisiteOverride, error) {
+	req.StudentEmail = models.NormalizeEmail(req.StudentEmail)
+	if _, err := mail.ParseAddress(req.StudentEmail); err != nil {
+		return nil, nil, errors.New("invalid email format")
+	}
+	course, err := s.courseRepo.GetByID(req.CourseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("course not found")
+		}
+		return nil, nil, err
+	}
+
+	if state := course.EnrollmentWindowState(time.Now()); state != constants.EnrollmentWindowOpen {
+		return nil, nil, &EnrollmentWindowError{
+			Code:     state,
+			OpensAt:  course.EnrollmentOpensAt,
+			ClosesAt: course.EnrollmentClosesAt,
+		}
+	}
+
+	var section *models.Section
+	if req.SectionID != nil {
+		section, err = s.sectionRepo.GetByID(req.CourseID, *req.SectionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, errors.New("section not found")
+			}
+			return nil, nil, err
+		}
+		if req.OfferingID != nil && *req.OfferingID != section.OfferingID {
+			return nil, nil, errors.New("section is not part of this offering")
+		}
+		req.OfferingID = &section.OfferingID
+	}
+
+	if req.OfferingID != nil {
+		offering, err := s.offeringRepo.GetByID(req.CourseID, *req.OfferingID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, errors.New("offering not found")
+			}
+			return nil, nil, err
+		}
+		if offering.Term != nil && offering.Term.HasEnded(time.Now()) {
+			closedAt := offering.Term.EndDate.AddDate(0, 0, 1)
+			return nil, nil, &EnrollmentWindowError{
+				Code:     constants.EnrollmentWindowClosed,
+				ClosesAt: &closedAt,
+			}
+		}
+	}
+
+	// Re-enrolling after a drop reactivates the existing record
+	existing, err := s.enrollmentRepo.GetByStudentAndCourse(req.StudentEmail, req.CourseID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+	if existing != nil {
+		if existing.HoldsSeat() {
+			return nil, nil, ErrAlreadyEnrolled
+		}
+		if err := validateStatusTransition(existing.Status, constants.EnrollmentStatusActive); err != nil {
+			if existing.Status == constants.EnrollmentStatusCompleted {
+				return nil, nil, errors.New("student has already completed this course")
+			}
+			return nil, nil, err
+		}
+	}
+
+	// Students must have completed every prerequisite unless an admin overrides the check
+	missing, err := missingPrerequisites(s.prerequisiteRepo, s.enrollmentRepo, req.StudentEmail, req.CourseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var override *models.PrerequisiteOverride
+	if len(missing) > 0 {
+		if !req.OverridePrerequisites {
+			responses := make([]models.CourseResponse, len(missing))
+			for i, course := range missing {
+				responses[i] = course.ToResponse()
+			}
+			return nil, nil, &MissingPrerequisitesError{Missing: responses}
+		}
+		override = newPrerequisiteOverride(*req, missing, actor)
+	}
+
+	// Sections may not overlap the student's other sections unless the request
+	// explicitly accepts the clash
+	if section != nil && !req.OverrideScheduleConflicts {
+		conflicts, err := scheduleConflicts(s.sectionRepo, req.StudentEmail, section)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(conflicts) > 0 {
+			responses := make([]models.SectionResponse, len(conflicts))
+			for i, conflict := range conflicts {
+				responses[i] = conflict.ToResponse()
+			}
+			return nil, nil, &ScheduleConflictError{Conflicts: responses}
+		}
+	}
+
+	return course, override, nil
+}
+
+func (s *enrollmentService) GetStudentEnrollments(email string, termID *uuid.UUID, statuses []string) (*models.StudentEnrollmentsResponse, error) {
+	email = models.NormalizeEmail(email)
 	if _, err := mail.ParseAddress(email); err != nil {
 		return nil, errors.New("invalid email format")
 	}
+	if err := validateStatusFilter(statuses); err != nil {
+		return nil, err
+	}
+
+	enrollments, err := s.enrollmentRepo.GetByStudentEmail(email, termID, statuses...)
+	if err != nil {
+		return nil, err
+	}
 
-	enrollments, err := s.enrollmentRepo.GetByStudentEmail(email)
+	ids := make([]uuid.UUID, len(enrollments))
+	for i, enrollment := range enrollments {
+		ids[i] = enrollment.ID
+	}
+	progress, err := s.progressRepo.GetSummaries(ids)
 	if err != nil {
 		return nil, err
 	}
@@ -73,6 +250,8 @@ func (s *enrollmentService) GetStudentEnrollments(email string) (*models.Student
 	responses := make([]models.EnrollmentResponse, len(enrollments))
 	for i, enrollment := range enrollments {
 		responses[i] = enrollment.ToResponse()
+		summary := progress[enrollment.ID]
+		responses[i].Progress = &summary
 	}
 
 	return &models.StudentEnrollmentsResponse{
@@ -82,7 +261,9 @@ func (s *enrollmentService) GetStudentEnrollments(email string) (*models.Student
 	}, nil
 }
 
-func (s *enrollmentService) UnenrollStudent(email string, courseID uuid.UUID) error {
+// UnenrollStudent marks a student's enrollment in a course as dropped
+func (s *enrollmentService) UnenrollStudent(email string, courseID uuid.UUID, actor string) error {
+	email = models.NormalizeEmail(email)
 	if _, err := mail.ParseAddress(email); err != nil {
 		return errors.New("invalid email format")
 	}
@@ -94,6 +275,112 @@ func (s *enrollmentService) UnenrollStudent(email string, courseID uuid.UUID) er
 		}
 		return err
 	}
+	if !enrollment.HoldsSeat() {
+		return errors.New("enrollment not found")
+	}
+
+	if err := validateStatusTransition(enrollment.Status, constants.EnrollmentStatusDropped); err != nil {
+		return err
+	}
+
+	promoted, err := s.enrollmentRepo.TransitionStatus(enrollment, constants.EnrollmentStatusDropped, actor, nil)
+	if err != nil {
+		return err
+	}
+	logPromotions(promoted)
+
+	return nil
+}
+
+// EnrollSelf enrolls the signed-in student in a course. The same rules apply as
+// to an admin enrolling them, without the overrides.
+func (s *enrollmentService) EnrollSelf(studentID uuid.UUID, req models.SelfEnrollmentRequest, actor string) (*models.EnrollmentResponse, error) {
+	email, err := s.studentEmail(studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.EnrollStudent(models.EnrollmentRequest{
+		StudentEmail: email,
+		CourseID:     req.CourseID,
+		OfferingID:   req.OfferingID,
+		SectionID:    req.SectionID,
+	}, actor, constants.RoleUser)
+}
+
+// GetOwnEnrollments retrieves the enrollments of the signed-in student
+func (s *enrollmentService) GetOwnEnrollments(studentID uuid.UUID, termID *uuid.UUID, statuses []string) (*models.StudentEnrollmentsResponse, error) {
+	email, err := s.studentEmail(studentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetStudentEnrollments(email, termID, statuses)
+}
+
+// DropOwnEnrollment drops the signed-in student from a course
+func (s *enrollmentService) DropOwnEnrollment(studentID uuid.UUID, courseID uuid.UUID, actor string) error {
+	email, err := s.studentEmail(studentID)
+	if err != nil {
+		return err
+	}
+	return s.UnenrollStudent(email, courseID, actor)
+}
 
-	return s.enrollmentRepo.Delete(enrollment.ID)
+// studentEmail looks up the current email of a student. Tokens carry the student
+// ID rather than the email, which an admin can change.
+func (s *enrollmentService) studentEmail(studentID uuid.UUID) (string, error) {
+	student, err := s.studentRepo.GetByID(studentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errors.New("student not found")
+		}
+		return "", err
+	}
+	return student.Email, nil
+}
+
+// UpdateEnrollmentStatus moves an enrollment to a new status through the enrollment state machine
+func (s *enrollmentService) UpdateEnrollmentStatus(id uuid.UUID, req models.EnrollmentStatusRequest, actor string) (*models.EnrollmentResponse, error) {
+	enrollment, err := s.enrollmentRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("enrollment not found")
+		}
+		return nil, err
+	}
+
+	if err := validateStatusTransition(enrollment.Status, req.Status); err != nil {
+		return nil, err
+	}
+
+	promoted, err := s.enrollmentRepo.TransitionStatus(enrollment, req.Status, actor, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	logPromotions(promoted)
+
+	response := enrollment.ToResponse()
+	return &response, nil
+}
+
+// GetEnrollmentHistory retrieves every status change of an enrollment, oldest first
+func (s *enrollmentService) GetEnrollmentHistory(id uuid.UUID) (*models.EnrollmentHistoryResponse, error) {
+	enrollment, err := s.enrollmentRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("enrollment not found")
+		}
+		return nil, err
+	}
+
+	changes, err := s.enrollmentRepo.GetStatusChanges(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.EnrollmentHistoryResponse{
+		EnrollmentID: enrollment.ID,
+		Status:       enrollment.Status,
+		Changes:      changes,
+	}, nil
 }
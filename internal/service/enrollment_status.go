@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+)
+
+// ErrInvalidStatusTransition is returned when an enrollment cannot move from its
+// current status to the requested one
+var ErrInvalidStatusTransition = errors.New("invalid enrollment status transition")
+
+// enrollmentTransitions lists, for every enrollment status, the statuses it may move to.
+// Completed enrollments are final; dropped and withdrawn ones can be reactivated
+// when the student enrolls again.
+var enrollmentTransitions = map[string][]string{
+	constants.EnrollmentStatusPending: {
+		constants.EnrollmentStatusActive,
+		constants.EnrollmentStatusDropped,
+		constants.EnrollmentStatusWithdrawn,
+	},
+	constants.EnrollmentStatusActive: {
+		constants.EnrollmentStatusCompleted,
+		constants.EnrollmentStatusDropped,
+		constants.EnrollmentStatusWithdrawn,
+	},
+	constants.EnrollmentStatusCompleted: {},
+	constants.EnrollmentStatusDropped: {
+		constants.EnrollmentStatusActive,
+	},
+	constants.EnrollmentStatusWithdrawn: {
+		constants.EnrollmentStatusActive,
+	},
+}
+
+// isValidEnrollmentStatus reports whether status is a known enrollment status
+func isValidEnrollmentStatus(status string) bool {
+	_, ok := enrollmentTransitions[status]
+	return ok
+}
+
+// validateStatusTransition checks that an enrollment may move from one status to another
+func validateStatusTransition(from, to string) error {
+	if !isValidEnrollmentStatus(to) {
+		return errors.New("invalid enrollment status")
+	}
+	for _, allowed := range enrollmentTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w from %s to %s", ErrInvalidStatusTransition, from, to)
+}
+
+// validateStatusFilter checks that every status in a list filter is known
+func validateStatusFilter(statuses []string) error {
+	for _, status := range statuses {
+		if !isValidEnrollmentStatus(status) {
+			return errors.New("invalid enrollment status")
+		}
+	}
+	return nil
+}
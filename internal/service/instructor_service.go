@@ -0,0 +1,248 @@
+package service
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InstructorService defines the interface for instructor business logic
+type InstructorService interface {
+	GetInstructors() (*models.InstructorListResponse, error)
+	GetInstructor(id uuid.UUID) (*models.InstructorResponse, error)
+	CreateInstructor(req models.InstructorRequest) (*models.InstructorResponse, error)
+	UpdateInstructor(id uuid.UUID, req models.InstructorRequest) (*models.InstructorResponse, error)
+	DeleteInstructor(id uuid.UUID) error
+	CreateAccount(id uuid.UUID, req models.InstructorAccountRequest) (*models.UserResponse, error)
+}
+
+// instructorService implements InstructorService interface
+type instructorService struct {
+	instructorRepo repository.InstructorRepository
+	userRepo       repository.UserRepository
+	redisService   *RedisService
+	passwords      PasswordPolicy
+}
+
+// NewInstructorService creates a new instructor service
+func NewInstructorService(instructorRepo repository.InstructorRepository, userRepo repository.UserRepository, redisService *RedisService, passwords PasswordPolicy) InstructorService {
+	return &instructorService{
+		instructorRepo: instructorRepo,
+		userRepo:       userRepo,
+		redisService:   redisService,
+		passwords:      passwords,
+	}
+}
+
+// GetInstructors retrieves every instructor with the courses they teach
+func (s *instructorService) GetInstructors() (*models.InstructorListResponse, error) {
+	instructors, err := s.instructorRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	courseIDs, err := s.instructorRepo.GetCourseIDs()
+	if err != nil {
+		return nil, err
+	}
+	holders, err := s.instructorRepo.GetAccountHolders()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.InstructorResponse, len(instructors))
+	for i, instructor := range instructors {
+		responses[i] = instructorResponse(instructor, courseIDs[instructor.ID], holders[instructor.ID])
+	}
+
+	return &models.InstructorListResponse{
+		Instructors: responses,
+		Total:       len(responses),
+	}, nil
+}
+
+// GetInstructor retrieves an instructor with the courses they teach
+func (s *instructorService) GetInstructor(id uuid.UUID) (*models.InstructorResponse, error) {
+	instructor, err := s.getInstructor(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(*instructor)
+}
+
+// CreateInstructor adds an instructor that teaches no courses yet
+func (s *instructorService) CreateInstructor(req models.InstructorRequest) (*models.InstructorResponse, error) {
+	if err := s.validateInstructor(uuid.Nil, &req); err != nil {
+		return nil, err
+	}
+
+	instructor := models.Instructor{
+		Name:  req.Name,
+		Email: req.Email,
+		Bio:   trimOptional(req.Bio),
+	}
+	if err := s.instructorRepo.Create(&instructor); err != nil {
+		return nil, err
+	}
+
+	response := instructorResponse(instructor, nil, false)
+	return &response, nil
+}
+
+// UpdateInstructor changes the profile of an instructor
+func (s *instructorService) UpdateInstructor(id uuid.UUID, req models.InstructorRequest) (*models.InstructorResponse, error) {
+	instructor, err := s.getInstructor(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateInstructor(id, &req); err != nil {
+		return nil, err
+	}
+
+	previousEmail := instructor.Email
+	instructor.Name = req.Name
+	instructor.Email = req.Email
+	instructor.Bio = trimOptional(req.Bio)
+	if err := s.instructorRepo.Update(instructor, previousEmail); err != nil {
+		return nil, err
+	}
+
+	// Courses show the names and emails of their instructors
+	if s.redisService != nil {
+		s.redisService.InvalidateAllCourses()
+	}
+
+	return s.toResponse(*instructor)
+}
+
+// DeleteInstructor takes an instructor off their courses and deletes them and
+// their account
+func (s *instructorService) DeleteInstructor(id uuid.UUID) error {
+	if err := s.instructorRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("instructor not found")
+		}
+		return err
+	}
+
+	if s.redisService != nil {
+		s.redisService.InvalidateAllCourses()
+	}
+
+	return nil
+}
+
+// CreateAccount creates the account an instructor signs in with to manage the
+// courses they teach. The username is the instructor's email.
+func (s *instructorService) CreateAccount(id uuid.UUID, req models.InstructorAccountRequest) (*models.UserResponse, error) {
+	instructor, err := s.getInstructor(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.passwords.Check(req.Password); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.GetByUsername(instructor.Email); err == nil {
+		return nil, errors.New("account already exists")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	hashedPassword, err := HashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username: instructor.Email,
+		Password: hashedPassword,
+		Role:     constants.RoleInstructor,
+	}
+	if err := s.userRepo.CreateInstructorAccount(user, instructor.ID); err != nil {
+		if errors.Is(err, repository.ErrInstructorAccountExists) {
+			return nil, errors.New("account already exists")
+		}
+		return nil, err
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// getInstructor loads an instructor, mapping a missing row to "instructor not found"
+func (s *instructorService) getInstructor(id uuid.UUID) (*models.Instructor, error) {
+	instructor, err := s.instructorRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("instructor not found")
+		}
+		return nil, err
+	}
+	return instructor, nil
+}
+
+// validateInstructor trims the request and checks that the name and email are
+// set and the email is not used by another instructor
+func (s *instructorService) validateInstructor(id uuid.UUID, req *models.InstructorRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return errors.New("instructor name is required")
+	}
+	req.Email = models.NormalizeEmail(req.Email)
+	if req.Email == "" {
+		return errors.New("instructor email is required")
+	}
+	if _, err := mail.ParseAddress(req.Email); err != nil {
+		return errors.New("invalid email format")
+	}
+
+	existing, err := s.instructorRepo.GetByEmail(req.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return errors.New("instructor email already exists")
+	}
+
+	return nil
+}
+
+// toResponse looks up the courses and account of an instructor and builds
+// their response
+func (s *instructorService) toResponse(instructor models.Instructor) (*models.InstructorResponse, error) {
+	courseIDs, err := s.instructorRepo.GetCourseIDs()
+	if err != nil {
+		return nil, err
+	}
+	holders, err := s.instructorRepo.GetAccountHolders()
+	if err != nil {
+		return nil, err
+	}
+
+	response := instructorResponse(instructor, courseIDs[instructor.ID], holders[instructor.ID])
+	return &response, nil
+}
+
+// instructorResponse converts an instructor, their course IDs and whether they
+// have an account to InstructorResponse
+func instructorResponse(instructor models.Instructor, courseIDs []uuid.UUID, hasAccount bool) models.InstructorResponse {
+	if courseIDs == nil {
+		courseIDs = []uuid.UUID{}
+	}
+	return models.InstructorResponse{
+		ID:         instructor.ID,
+		Name:       instructor.Name,
+		Email:      instructor.Email,
+		Bio:        instructor.Bio,
+		CourseIDs:  courseIDs,
+		HasAccount: hasAccount,
+		CreatedAt:  instructor.CreatedAt,
+	}
+}
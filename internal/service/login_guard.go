@@ -0,0 +1,217 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/config"
+	"sonic-labs/course-enrollment-service/internal/constants"
+)
+
+// LoginAttemptStore counts failed logins and blocks logins per key, a username
+// or a client IP. It is implemented by RedisService and, when Redis is
+// disabled, by MemoryLoginAttemptStore.
+type LoginAttemptStore interface {
+	// AddLoginFailure counts a failed login for key and returns the failures
+	// counted for it. They are forgotten window after the last one.
+	AddLoginFailure(key string, window time.Duration) (int, error)
+	// BlockLogin refuses logins for key until the time
+	BlockLogin(key string, until time.Time) error
+	// LoginBlockedUntil returns the end of the block on key, or the zero time
+	LoginBlockedUntil(key string) (time.Time, error)
+	// ResetLogin forgets the failures and block of key
+	ResetLogin(key string) error
+}
+
+// LoginThrottledError is returned for logins refused because of earlier failed
+// attempts for the username or client IP
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, retry after %s", e.RetryAfter)
+}
+
+// loginGuard slows down and locks out repeated failed logins for a username
+// and for a client IP. Failures to reach the store are logged and the login
+// goes ahead, as with rate limiting.
+type loginGuard struct {
+	store  LoginAttemptStore
+	policy config.LoginConfig
+}
+
+// usernameLoginKey and ipLoginKey are the keys failed logins are counted under
+func usernameLoginKey(username string) string { return "user:" + username }
+func ipLoginKey(ip string) string             { return "ip:" + ip }
+
+// check returns a LoginThrottledError while logins for the username or the IP are blocked
+func (g *loginGuard) check(username, ip string) error {
+	now := time.Now()
+	var wait time.Duration
+	for _, key := range []string{usernameLoginKey(username), ipLoginKey(ip)} {
+		until, err := g.store.LoginBlockedUntil(key)
+		if err != nil {
+			log.Printf("Failed to check login block of %s: %v", key, err)
+			continue
+		}
+		if remaining := until.Sub(now); remaining > wait {
+			wait = remaining
+		}
+	}
+
+	if wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// fail counts a failed login for the username and the IP, delaying the next
+// attempt for each or locking it out once it reaches its threshold
+func (g *loginGuard) fail(username, ip string) {
+	g.count(usernameLoginKey(username), g.policy.MaxFailures)
+	g.count(ipLoginKey(ip), g.policy.IPMaxFailures)
+}
+
+// succeed forgets the failed logins for the username. Those for the IP are
+// kept, so that one known password does not reset the count of guesses at others.
+func (g *loginGuard) succeed(username string) {
+	if err := g.store.ResetLogin(usernameLoginKey(username)); err != nil {
+		log.Printf("Failed to reset failed logins of %s: %v", username, err)
+	}
+}
+
+// count records a failure for key and blocks it for as long as its failures call for
+func (g *loginGuard) count(key string, threshold int) {
+	failures, err := g.store.AddLoginFailure(key, constants.LoginFailureWindow)
+	if err != nil {
+		log.Printf("Failed to count failed login of %s: %v", key, err)
+		return
+	}
+
+	now := time.Now()
+	if threshold > 0 && failures >= threshold {
+		until := now.Add(g.policy.LockoutDuration)
+		log.Printf("Login locked out for %s after %d failed attempts, until %s", key, failures, until.Format(time.RFC3339))
+		if err := g.store.BlockLogin(key, until); err != nil {
+			log.Printf("Failed to lock out login of %s: %v", key, err)
+		}
+		return
+	}
+
+	if delay := g.delay(failures); delay > 0 {
+		if err := g.store.BlockLogin(key, now.Add(delay)); err != nil {
+			log.Printf("Failed to delay login of %s: %v", key, err)
+		}
+	}
+}
+
+// delay returns how long to refuse logins after the failures: nothing after the
+// first, then BaseDelay doubling with each further failure up to LoginMaxDelay
+func (g *loginGuard) delay(failures int) time.Duration {
+	if failures < 2 || g.policy.BaseDelay <= 0 {
+		return 0
+	}
+	delay := g.policy.BaseDelay
+	for i := 2; i < failures && delay < constants.LoginMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > constants.LoginMaxDelay {
+		delay = constants.LoginMaxDelay
+	}
+	return delay
+}
+
+// MemoryLoginAttemptStore is a LoginAttemptStore that keeps failed logins in
+// memory. Each server instance counts on its own.
+type MemoryLoginAttemptStore struct {
+	mu        sync.Mutex
+	attempts  map[string]*loginAttempts
+	nextSweep time.Time
+}
+
+// loginAttempts are the failed logins of a key, forgotten at expiresAt
+type loginAttempts struct {
+	failures     int
+	blockedUntil time.Time
+	expiresAt    time.Time
+}
+
+// NewMemoryLoginAttemptStore creates an in-memory login attempt store
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{attempts: make(map[string]*loginAttempts)}
+}
+
+// AddLoginFailure counts a failed login for key and returns the failures counted for it
+func (m *MemoryLoginAttemptStore) AddLoginFailure(key string, window time.Duration) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweep(now)
+
+	a := m.attempts[key]
+	if a == nil || !now.Before(a.expiresAt) {
+		a = &loginAttempts{}
+		m.attempts[key] = a
+	}
+	a.failures++
+	a.expiresAt = now.Add(window)
+	if a.blockedUntil.After(a.expiresAt) {
+		a.expiresAt = a.blockedUntil
+	}
+	return a.failures, nil
+}
+
+// BlockLogin refuses logins for key until the time
+func (m *MemoryLoginAttemptStore) BlockLogin(key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a := m.attempts[key]
+	if a == nil || !time.Now().Before(a.expiresAt) {
+		a = &loginAttempts{expiresAt: until}
+		m.attempts[key] = a
+	}
+	a.blockedUntil = until
+	if until.After(a.expiresAt) {
+		a.expiresAt = until
+	}
+	return nil
+}
+
+// LoginBlockedUntil returns the end of the block on key, or the zero time
+func (m *MemoryLoginAttemptStore) LoginBlockedUntil(key string) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a := m.attempts[key]
+	if a == nil || !time.Now().Before(a.expiresAt) {
+		return time.Time{}, nil
+	}
+	return a.blockedUntil, nil
+}
+
+// ResetLogin forgets the failures and block of key
+func (m *MemoryLoginAttemptStore) ResetLogin(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.attempts, key)
+	return nil
+}
+
+// sweep forgets the keys whose failures have expired, at most once a minute
+func (m *MemoryLoginAttemptStore) sweep(now time.Time) {
+	if now.Before(m.nextSweep) {
+		return
+	}
+	for key, a := range m.attempts {
+		if !now.Before(a.expiresAt) {
+			delete(m.attempts, key)
+		}
+	}
+	m.nextSweep = now.Add(time.Minute)
+}
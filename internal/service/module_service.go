@@ -0,0 +1,297 @@
+package service
+
+import (
+	"errors"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ModuleService defines the interface for course module and lesson business logic
+type ModuleService interface {
+	GetOutline(courseID uuid.UUID) (*models.CourseOutline, error)
+	GetModule(courseID, moduleID uuid.UUID) (*models.ModuleResponse, error)
+	CreateModule(courseID uuid.UUID, req models.ModuleRequest) (*models.ModuleResponse, error)
+	UpdateModule(courseID, moduleID uuid.UUID, req models.ModuleRequest) (*models.ModuleResponse, error)
+	DeleteModule(courseID, moduleID uuid.UUID) error
+	ReorderModules(courseID uuid.UUID, req models.ModuleReorderRequest) (*models.CourseOutline, error)
+	GetLesson(courseID, moduleID, lessonID uuid.UUID) (*models.LessonResponse, error)
+	CreateLesson(courseID, moduleID uuid.UUID, req models.LessonRequest) (*models.LessonResponse, error)
+	UpdateLesson(courseID, moduleID, lessonID uuid.UUID, req models.LessonRequest) (*models.LessonResponse, error)
+	DeleteLesson(courseID, moduleID, lessonID uuid.UUID) error
+	ReorderLessons(courseID, moduleID uuid.UUID, req models.LessonReorderRequest) (*models.ModuleResponse, error)
+}
+
+// moduleService implements ModuleService interface
+type moduleService struct {
+	moduleRepo repository.ModuleRepository
+	courseRepo repository.CourseRepository
+}
+
+// NewModuleService creates a new module service
+func NewModuleService(moduleRepo repository.ModuleRepository, courseRepo repository.CourseRepository) ModuleService {
+	return &moduleService{
+		moduleRepo: moduleRepo,
+		courseRepo: courseRepo,
+	}
+}
+
+// GetOutline retrieves the ordered modules and lessons of a course
+func (s *moduleService) GetOutline(courseID uuid.UUID) (*models.CourseOutline, error) {
+	if err := s.ensureCourseExists(courseID); err != nil {
+		return nil, err
+	}
+
+	modules, err := s.moduleRepo.GetByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	outline := models.NewCourseOutline(modules)
+	return &outline, nil
+}
+
+// GetModule retrieves a module of a course with its lessons
+func (s *moduleService) GetModule(courseID, moduleID uuid.UUID) (*models.ModuleResponse, error) {
+	module, err := s.getModule(courseID, moduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := module.ToResponse()
+	return &response, nil
+}
+
+// CreateModule adds a module to the end of a course
+func (s *moduleService) CreateModule(courseID uuid.UUID, req models.ModuleRequest) (*models.ModuleResponse, error) {
+	if err := s.ensureCourseExists(courseID); err != nil {
+		return nil, err
+	}
+
+	module := models.CourseModule{
+		CourseID:    courseID,
+		Title:       req.Title,
+		Description: req.Description,
+	}
+	if err := s.moduleRepo.Create(&module); err != nil {
+		return nil, err
+	}
+
+	response := module.ToResponse()
+	return &response, nil
+}
+
+// UpdateModule changes the title and description of a module
+func (s *moduleService) UpdateModule(courseID, moduleID uuid.UUID, req models.ModuleRequest) (*models.ModuleResponse, error) {
+	module, err := s.getModule(courseID, moduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	module.Title = req.Title
+	module.Description = req.Description
+	if err := s.moduleRepo.Update(module); err != nil {
+		return nil, err
+	}
+
+	response := module.ToResponse()
+	return &response, nil
+}
+
+// DeleteModule removes a module and its lessons from a course
+func (s *moduleService) DeleteModule(courseID, moduleID uuid.UUID) error {
+	if err := s.ensureCourseExists(courseID); err != nil {
+		return err
+	}
+
+	if err := s.moduleRepo.Delete(courseID, moduleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("module not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ReorderModules changes the order of the modules of a course
+func (s *moduleService) ReorderModules(courseID uuid.UUID, req models.ModuleReorderRequest) (*models.CourseOutline, error) {
+	if err := s.ensureCourseExists(courseID); err != nil {
+		return nil, err
+	}
+
+	modules, err := s.moduleRepo.GetByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new order must be a permutation of the current modules
+	current := make([]uuid.UUID, len(modules))
+	for i, module := range modules {
+		current[i] = module.ID
+	}
+	if !isPermutation(current, req.ModuleIDs) {
+		return nil, errors.New("module order must list every module of the course exactly once")
+	}
+
+	if err := s.moduleRepo.Reorder(courseID, req.ModuleIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetOutline(courseID)
+}
+
+// GetLesson retrieves a lesson of a module
+func (s *moduleService) GetLesson(courseID, moduleID, lessonID uuid.UUID) (*models.LessonResponse, error) {
+	lesson, err := s.getLesson(courseID, moduleID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := lesson.ToResponse()
+	return &response, nil
+}
+
+// CreateLesson adds a lesson to the end of a module
+func (s *moduleService) CreateLesson(courseID, moduleID uuid.UUID, req models.LessonRequest) (*models.LessonResponse, error) {
+	if _, err := s.getModule(courseID, moduleID); err != nil {
+		return nil, err
+	}
+
+	lesson := models.Lesson{
+		ModuleID:        moduleID,
+		Title:           req.Title,
+		Content:         req.Content,
+		DurationMinutes: req.DurationMinutes,
+		Optional:        req.Optional,
+	}
+	if err := s.moduleRepo.CreateLesson(&lesson); err != nil {
+		return nil, err
+	}
+
+	response := lesson.ToResponse()
+	return &response, nil
+}
+
+// UpdateLesson changes the title, content, duration and optional flag of a lesson
+func (s *moduleService) UpdateLesson(courseID, moduleID, lessonID uuid.UUID, req models.LessonRequest) (*models.LessonResponse, error) {
+	lesson, err := s.getLesson(courseID, moduleID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	lesson.Title = req.Title
+	lesson.Content = req.Content
+	lesson.DurationMinutes = req.DurationMinutes
+	lesson.Optional = req.Optional
+	if err := s.moduleRepo.UpdateLesson(lesson); err != nil {
+		return nil, err
+	}
+
+	response := lesson.ToResponse()
+	return &response, nil
+}
+
+// DeleteLesson removes a lesson from a module
+func (s *moduleService) DeleteLesson(courseID, moduleID, lessonID uuid.UUID) error {
+	if _, err := s.getModule(courseID, moduleID); err != nil {
+		return err
+	}
+
+	if err := s.moduleRepo.DeleteLesson(moduleID, lessonID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("lesson not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ReorderLessons changes the order of the lessons of a module
+func (s *moduleService) ReorderLessons(courseID, moduleID uuid.UUID, req models.LessonReorderRequest) (*models.ModuleResponse, error) {
+	module, err := s.getModule(courseID, moduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new order must be a permutation of the current lessons
+	current := make([]uuid.UUID, len(module.Lessons))
+	for i, lesson := range module.Lessons {
+		current[i] = lesson.ID
+	}
+	if !isPermutation(current, req.LessonIDs) {
+		return nil, errors.New("lesson order must list every lesson of the module exactly once")
+	}
+
+	if err := s.moduleRepo.ReorderLessons(moduleID, req.LessonIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetModule(courseID, moduleID)
+}
+
+// ensureCourseExists returns "course not found" if there is no course with the ID
+func (s *moduleService) ensureCourseExists(courseID uuid.UUID) error {
+	exists, err := s.courseRepo.ExistsByID(courseID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.New("course not found")
+	}
+	return nil
+}
+
+// getModule loads a module, distinguishing a missing course from a missing module
+func (s *moduleService) getModule(courseID, moduleID uuid.UUID) (*models.CourseModule, error) {
+	if err := s.ensureCourseExists(courseID); err != nil {
+		return nil, err
+	}
+
+	module, err := s.moduleRepo.GetByID(courseID, moduleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("module not found")
+		}
+		return nil, err
+	}
+	return module, nil
+}
+
+// getLesson loads a lesson of a module of a course
+func (s *moduleService) getLesson(courseID, moduleID, lessonID uuid.UUID) (*models.Lesson, error) {
+	if _, err := s.getModule(courseID, moduleID); err != nil {
+		return nil, err
+	}
+
+	lesson, err := s.moduleRepo.GetLesson(moduleID, lessonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("lesson not found")
+		}
+		return nil, err
+	}
+	return lesson, nil
+}
+
+// isPermutation reports whether ordered lists every ID of current exactly once
+func isPermutation(current, ordered []uuid.UUID) bool {
+	if len(ordered) != len(current) {
+		return false
+	}
+	remaining := make(map[uuid.UUID]bool, len(current))
+	for _, id := range current {
+		remaining[id] = true
+	}
+	for _, id := range ordered {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
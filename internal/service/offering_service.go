@@ -0,0 +1,221 @@
+package service
+
+import (
+	"errors"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OfferingService defines the interface for course offering business logic
+type OfferingService interface {
+	GetOfferings(courseID uuid.UUID, termID *uuid.UUID) (*models.OfferingListResponse, error)
+	GetOffering(courseID, offeringID uuid.UUID) (*models.OfferingResponse, error)
+	CreateOffering(courseID uuid.UUID, req models.OfferingRequest) (*models.OfferingResponse, error)
+	UpdateOffering(courseID, offeringID uuid.UUID, req models.OfferingUpdateRequest) (*models.OfferingResponse, error)
+	DeleteOffering(courseID, offeringID uuid.UUID) error
+}
+
+// offeringService implements OfferingService interface
+type offeringService struct {
+	offeringRepo repository.OfferingRepository
+	courseRepo   repository.CourseRepository
+	termRepo     repository.TermRepository
+	waitlistRepo repository.WaitlistRepository
+}
+
+// NewOfferingService creates a new offering service
+func NewOfferingService(offeringRepo repository.OfferingRepository, courseRepo repository.CourseRepository, termRepo repository.TermRepository, waitlistRepo repository.WaitlistRepository) OfferingService {
+	return &offeringService{
+		offeringRepo: offeringRepo,
+		courseRepo:   courseRepo,
+		termRepo:     termRepo,
+		waitlistRepo: waitlistRepo,
+	}
+}
+
+// GetOfferings retrieves the offerings of a course, optionally only the one in a term
+func (s *offeringService) GetOfferings(courseID uuid.UUID, termID *uuid.UUID) (*models.OfferingListResponse, error) {
+	if err := s.ensureCourseExists(courseID); err != nil {
+		return nil, err
+	}
+
+	// Make sure the default offering shows up even before anyone enrolled
+	if _, err := s.offeringRepo.GetDefault(courseID); err != nil {
+		return nil, err
+	}
+
+	offerings, err := s.offeringRepo.GetByCourseID(courseID, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.toResponses(offerings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OfferingListResponse{
+		CourseID:  courseID,
+		Offerings: responses,
+		Total:     len(responses),
+	}, nil
+}
+
+// GetOffering retrieves an offering of a course
+func (s *offeringService) GetOffering(courseID, offeringID uuid.UUID) (*models.OfferingResponse, error) {
+	offering, err := s.getOffering(courseID, offeringID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.toResponses([]models.CourseOffering{*offering})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// CreateOffering offers a course in a term
+func (s *offeringService) CreateOffering(courseID uuid.UUID, req models.OfferingRequest) (*models.OfferingResponse, error) {
+	if err := s.ensureCourseExists(courseID); err != nil {
+		return nil, err
+	}
+	if req.TermID == uuid.Nil {
+		return nil, errors.New("term ID is required")
+	}
+	if req.Capacity != nil && *req.Capacity < 0 {
+		return nil, errors.New("capacity must not be negative")
+	}
+
+	term, err := s.termRepo.GetByID(req.TermID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("term not found")
+		}
+		return nil, err
+	}
+
+	exists, err := s.offeringRepo.ExistsForTerm(courseID, term.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("course is already offered in this term")
+	}
+
+	offering := models.CourseOffering{
+		CourseID: courseID,
+		TermID:   &term.ID,
+		Capacity: req.Capacity,
+		Schedule: req.Schedule,
+		Term:     term,
+	}
+	if err := s.offeringRepo.Create(&offering); err != nil {
+		return nil, err
+	}
+
+	response := offering.ToResponse()
+	return &response, nil
+}
+
+// UpdateOffering changes the capacity and schedule of an offering. Raising the
+// capacity hands the new seats to the waitlist.
+func (s *offeringService) UpdateOffering(courseID, offeringID uuid.UUID, req models.OfferingUpdateRequest) (*models.OfferingResponse, error) {
+	offering, err := s.getOffering(courseID, offeringID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Capacity != nil && *req.Capacity < 0 {
+		return nil, errors.New("capacity must not be negative")
+	}
+
+	offering.Capacity = req.Capacity
+	offering.Schedule = req.Schedule
+	if err := s.offeringRepo.Update(offering); err != nil {
+		return nil, err
+	}
+
+	promoted, err := s.waitlistRepo.FillFreeSeats(courseID)
+	if err != nil {
+		return nil, err
+	}
+	logPromotions(promoted)
+
+	return s.GetOffering(courseID, offeringID)
+}
+
+// DeleteOffering removes an offering that has no enrollments or waitlisted students
+func (s *offeringService) DeleteOffering(courseID, offeringID uuid.UUID) error {
+	offering, err := s.getOffering(courseID, offeringID)
+	if err != nil {
+		return err
+	}
+
+	used, err := s.offeringRepo.HasEnrollments(offering.ID)
+	if err != nil {
+		return err
+	}
+	if used {
+		return errors.New("offering has enrollments")
+	}
+
+	if err := s.offeringRepo.Delete(courseID, offeringID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("offering not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// getOffering loads an offering of a course, mapping missing rows to errors
+func (s *offeringService) getOffering(courseID, offeringID uuid.UUID) (*models.CourseOffering, error) {
+	if err := s.ensureCourseExists(courseID); err != nil {
+		return nil, err
+	}
+
+	offering, err := s.offeringRepo.GetByID(courseID, offeringID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("offering not found")
+		}
+		return nil, err
+	}
+	return offering, nil
+}
+
+// toResponses converts offerings to responses with their enrolled counts
+func (s *offeringService) toResponses(offerings []models.CourseOffering) ([]models.OfferingResponse, error) {
+	ids := make([]uuid.UUID, len(offerings))
+	for i, offering := range offerings {
+		ids[i] = offering.ID
+	}
+	taken, err := s.offeringRepo.CountSeatsTaken(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.OfferingResponse, len(offerings))
+	for i, offering := range offerings {
+		responses[i] = offering.ToResponse()
+		responses[i].EnrolledCount = taken[offering.ID]
+	}
+	return responses, nil
+}
+
+// ensureCourseExists returns "course not found" if the course does not exist
+func (s *offeringService) ensureCourseExists(courseID uuid.UUID) error {
+	exists, err := s.courseRepo.ExistsByID(courseID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.New("course not found")
+	}
+	return nil
+}
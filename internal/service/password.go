@@ -0,0 +1,102 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"sonic-labs/course-enrollment-service/internal/config"
+	"sonic-labs/course-enrollment-service/internal/constants"
+)
+
+// PasswordPolicyError is returned for a new password the password policy
+// rejects. Message says what the password is missing.
+type PasswordPolicyError struct {
+	Message string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return e.Message
+}
+
+// PasswordPolicy checks new passwords against the configured strength
+// requirements and sets how long password reset tokens work
+type PasswordPolicy struct {
+	config config.PasswordConfig
+}
+
+// NewPasswordPolicy creates a password policy from the configuration
+func NewPasswordPolicy(cfg config.PasswordConfig) PasswordPolicy {
+	return PasswordPolicy{config: cfg}
+}
+
+// MinLength returns the fewest characters a password may have
+func (p PasswordPolicy) MinLength() int {
+	if p.config.MinLength > constants.MinPasswordLength {
+		return p.config.MinLength
+	}
+	return constants.MinPasswordLength
+}
+
+// Check returns a PasswordPolicyError for the first requirement the password does not meet
+func (p PasswordPolicy) Check(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength() {
+		return &PasswordPolicyError{Message: fmt.Sprintf("Password must be at least %d characters", p.MinLength())}
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	switch {
+	case p.config.RequireUpper && !upper:
+		return &PasswordPolicyError{Message: "Password must contain an uppercase letter"}
+	case p.config.RequireLower && !lower:
+		return &PasswordPolicyError{Message: "Password must contain a lowercase letter"}
+	case p.config.RequireDigit && !digit:
+		return &PasswordPolicyError{Message: "Password must contain a digit"}
+	case p.config.RequireSymbol && !symbol:
+		return &PasswordPolicyError{Message: "Password must contain a symbol"}
+	}
+	return nil
+}
+
+// ResetTokenTTL returns how long a password reset token works
+func (p PasswordPolicy) ResetTokenTTL() time.Duration {
+	if p.config.ResetTokenTTL > 0 {
+		return p.config.ResetTokenTTL
+	}
+	return constants.PasswordResetTokenExpiry
+}
+
+// generateToken returns a new random single-use token that starts with prefix,
+// such as a password reset or student invite token
+func generateToken(prefix string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return prefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken returns the hex SHA-256 hash under which a token from generateToken
+// is stored
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
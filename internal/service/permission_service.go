@@ -0,0 +1,135 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/repository"
+)
+
+// PermissionService defines the interface for role permission business logic
+type PermissionService interface {
+	HasPermission(role, permission string) (bool, error)
+	GetRoles() (*models.RoleListResponse, error)
+	SetRolePermissions(role string, req models.RolePermissionsRequest) (*models.RoleResponse, error)
+}
+
+// permissionService implements PermissionService interface
+type permissionService struct {
+	permissionRepo repository.PermissionRepository
+}
+
+// NewPermissionService creates a new permission service
+func NewPermissionService(permissionRepo repository.PermissionRepository) PermissionService {
+	return &permissionService{
+		permissionRepo: permissionRepo,
+	}
+}
+
+// HasPermission reports whether users with the role hold the permission.
+// Super-admins hold every permission.
+func (s *permissionService) HasPermission(role, permission string) (bool, error) {
+	if role == constants.RoleSuperAdmin {
+		return true, nil
+	}
+	if role == "" {
+		return false, nil
+	}
+	return s.permissionRepo.HasPermission(role, permission)
+}
+
+// GetRoles lists every role with its permissions, super-admins first
+func (s *permissionService) GetRoles() (*models.RoleListResponse, error) {
+	granted, err := s.permissionRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	byRole := make(map[string][]string)
+	for _, rp := range granted {
+		byRole[rp.Role] = append(byRole[rp.Role], rp.Permission)
+	}
+
+	superAdmin := append(append([]string{}, constants.ReservedPermissions...), constants.GrantablePermissions...)
+	sort.Strings(superAdmin)
+	roles := []models.RoleResponse{{
+		Role:        constants.RoleSuperAdmin,
+		Permissions: superAdmin,
+		Editable:    false,
+	}}
+	for _, role := range constants.ManagedRoles {
+		permissions := byRole[role]
+		if permissions == nil {
+			permissions = []string{}
+		}
+		roles = append(roles, models.RoleResponse{
+			Role:        role,
+			Permissions: permissions,
+			Editable:    true,
+		})
+	}
+
+	return &models.RoleListResponse{
+		Roles:       roles,
+		Permissions: constants.GrantablePermissions,
+	}, nil
+}
+
+// SetRolePermissions replaces the permissions of a role. Super-admins always
+// hold every permission, so their role cannot be changed.
+func (s *permissionService) SetRolePermissions(role string, req models.RolePermissionsRequest) (*models.RoleResponse, error) {
+	if role == constants.RoleSuperAdmin {
+		return nil, errors.New("role is not editable")
+	}
+	if !isManagedRole(role) {
+		return nil, errors.New("role not found")
+	}
+
+	seen := make(map[string]bool, len(req.Permissions))
+	permissions := []string{}
+	for _, permission := range req.Permissions {
+		permission = strings.TrimSpace(permission)
+		if !isGrantablePermission(permission) {
+			return nil, errors.New("unknown permission")
+		}
+		if seen[permission] {
+			continue
+		}
+		seen[permission] = true
+		permissions = append(permissions, permission)
+	}
+	sort.Strings(permissions)
+
+	if err := s.permissionRepo.ReplaceForRole(role, permissions); err != nil {
+		return nil, err
+	}
+
+	return &models.RoleResponse{
+		Role:        role,
+		Permissions: permissions,
+		Editable:    true,
+	}, nil
+}
+
+// isManagedRole reports whether the role's permissions are stored and editable
+func isManagedRole(role string) bool {
+	for _, managed := range constants.ManagedRoles {
+		if role == managed {
+			return true
+		}
+	}
+	return false
+}
+
+// isGrantablePermission reports whether the permission can be granted to a role
+func isGrantablePermission(permission string) bool {
+	for _, grantable := range constants.GrantablePermissions {
+		if permission == grantable {
+			return true
+		}
+	}
+	return false
+}
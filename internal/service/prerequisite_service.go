@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MissingPrerequisitesError is returned by EnrollStudent when the student has not
+// completed every prerequisite of the course and no override was requested
+type MissingPrerequisitesError struct {
+	Missing []models.CourseResponse
+}
+
+func (e *MissingPrerequisitesError) Error() string {
+	return "student has not completed the prerequisites for this course"
+}
+
+// PrerequisiteService defines the interface for course prerequisite business logic
+type PrerequisiteService interface {
+	GetPrerequisites(courseID uuid.UUID) (*models.PrerequisitesResponse, error)
+	AddPrerequisite(courseID uuid.UUID, req models.PrerequisiteRequest) (*models.PrerequisitesResponse, error)
+	ReplacePrerequisites(courseID uuid.UUID, req models.PrerequisitesReplaceRequest) (*models.PrerequisitesResponse, error)
+	RemovePrerequisite(courseID, prerequisiteID uuid.UUID) error
+	GetOverrides(courseID uuid.UUID) (*models.PrerequisiteOverridesResponse, error)
+}
+
+// prerequisiteService implements PrerequisiteService interface
+type prerequisiteService struct {
+	prerequisiteRepo repository.PrerequisiteRepository
+	courseRepo       repository.CourseRepository
+}
+
+// NewPrerequisiteService creates a new prerequisite service
+func NewPrerequisiteService(prerequisiteRepo repository.PrerequisiteRepository, courseRepo repository.CourseRepository) PrerequisiteService {
+	return &prerequisiteService{
+		prerequisiteRepo: prerequisiteRepo,
+		courseRepo:       courseRepo,
+	}
+}
+
+// GetPrerequisites retrieves the courses that must be completed before enrolling in a course
+func (s *prerequisiteService) GetPrerequisites(courseID uuid.UUID) (*models.PrerequisitesResponse, error) {
+	if _, err := s.courseRepo.GetByID(courseID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+
+	prerequisites, err := s.prerequisiteRepo.GetByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.CourseResponse, len(prerequisites))
+	for i, prerequisite := range prerequisites {
+		responses[i] = prerequisite.ToResponse()
+	}
+
+	return &models.PrerequisitesResponse{
+		CourseID:      courseID,
+		Prerequisites: responses,
+		Total:         len(responses),
+	}, nil
+}
+
+// AddPrerequisite adds a single prerequisite to a course
+func (s *prerequisiteService) AddPrerequisite(courseID uuid.UUID, req models.PrerequisiteRequest) (*models.PrerequisitesResponse, error) {
+	if err := s.validatePrerequisites(courseID, []uuid.UUID{req.PrerequisiteID}); err != nil {
+		return nil, err
+	}
+
+	if err := s.prerequisiteRepo.Add(courseID, req.PrerequisiteID); err != nil {
+		return nil, err
+	}
+
+	return s.GetPrerequisites(courseID)
+}
+
+// ReplacePrerequisites sets the complete list of prerequisites of a course
+func (s *prerequisiteService) ReplacePrerequisites(courseID uuid.UUID, req models.PrerequisitesReplaceRequest) (*models.PrerequisitesResponse, error) {
+	// Drop duplicates while keeping the requested order
+	seen := make(map[uuid.UUID]bool, len(req.PrerequisiteIDs))
+	prerequisiteIDs := make([]uuid.UUID, 0, len(req.PrerequisiteIDs))
+	for _, id := range req.PrerequisiteIDs {
+		if !seen[id] {
+			seen[id] = true
+			prerequisiteIDs = append(prerequisiteIDs, id)
+		}
+	}
+
+	if err := s.validatePrerequisites(courseID, prerequisiteIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.prerequisiteRepo.Replace(courseID, prerequisiteIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetPrerequisites(courseID)
+}
+
+// RemovePrerequisite removes a prerequisite from a course
+func (s *prerequisiteService) RemovePrerequisite(courseID, prerequisiteID uuid.UUID) error {
+	if _, err := s.courseRepo.GetByID(courseID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("course not found")
+		}
+		return err
+	}
+
+	if err := s.prerequisiteRepo.Remove(courseID, prerequisiteID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("prerequisite not found for this course")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetOverrides retrieves the recorded prerequisite overrides of a course
+func (s *prerequisiteService) GetOverrides(courseID uuid.UUID) (*models.PrerequisiteOverridesResponse, error) {
+	if _, err := s.courseRepo.GetByID(courseID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+
+	overrides, err := s.prerequisiteRepo.GetOverridesByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.PrerequisiteOverrideResponse, len(overrides))
+	for i, override := range overrides {
+		responses[i] = override.ToResponse()
+	}
+
+	return &models.PrerequisiteOverridesResponse{
+		Overrides: responses,
+		Total:     len(responses),
+	}, nil
+}
+
+// validatePrerequisites checks that a course and all of its proposed prerequisites exist
+func (s *prerequisiteService) validatePrerequisites(courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	if _, err := s.courseRepo.GetByID(courseID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("course not found")
+		}
+		return err
+	}
+
+	for _, prerequisiteID := range prerequisiteIDs {
+		if prerequisiteID == courseID {
+			return errors.New("course cannot be its own prerequisite")
+		}
+		exists, err := s.courseRepo.ExistsByID(prerequisiteID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("prerequisite course not found")
+		}
+	}
+
+	return nil
+}
+
+// missingPrerequisites returns the prerequisites of a course the student has not completed
+func missingPrerequisites(prerequisiteRepo repository.PrerequisiteRepository, enrollmentRepo repository.EnrollmentRepository, email string, courseID uuid.UUID) ([]models.Course, error) {
+	prerequisites, err := prerequisiteRepo.GetByCourseID(courseID)
+	if err != nil || len(prerequisites) == 0 {
+		return nil, err
+	}
+
+	completed, err := enrollmentRepo.GetByStudentEmail(email, nil, constants.EnrollmentStatusCompleted)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[uuid.UUID]bool, len(completed))
+	for _, enrollment := range completed {
+		done[enrollment.CourseID] = true
+	}
+
+	var missing []models.Course
+	for _, prerequisite := range prerequisites {
+		if !done[prerequisite.ID] {
+			missing = append(missing, prerequisite)
+		}
+	}
+	return missing, nil
+}
+
+// newPrerequisiteOverride builds the audit record for an enrollment that skipped missing prerequisites
+func newPrerequisiteOverride(req models.EnrollmentRequest, missing []models.Course, actor string) *models.PrerequisiteOverride {
+	ids := make([]string, len(missing))
+	for i, course := range missing {
+		ids[i] = course.ID.String()
+	}
+
+	return &models.PrerequisiteOverride{
+		CourseID:     req.CourseID,
+		StudentEmail: req.StudentEmail,
+		MissingIDs:   strings.Join(ids, ","),
+		OverriddenBy: actor,
+		Reason:       req.OverrideReason,
+	}
+}
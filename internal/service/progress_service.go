@@ -0,0 +1,164 @@
+package service
+
+import (
+	"errors"
+	"log"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// completionReason is recorded in the enrollment history when finishing the last
+// required lesson completes an enrollment
+const completionReason = "All required lessons completed"
+
+// ProgressService defines the interface for lesson progress business logic
+type ProgressService interface {
+	GetEnrollmentProgress(enrollmentID uuid.UUID) (*models.EnrollmentProgressResponse, error)
+	RecordLessonProgress(enrollmentID, lessonID uuid.UUID, req models.LessonProgressRequest, actor string) (*models.EnrollmentProgressResponse, error)
+}
+
+// progressService implements ProgressService interface
+type progressService struct {
+	progressRepo   repository.ProgressRepository
+	enrollmentRepo repository.EnrollmentRepository
+	moduleRepo     repository.ModuleRepository
+}
+
+// NewProgressService creates a new progress service
+func NewProgressService(progressRepo repository.ProgressRepository, enrollmentRepo repository.EnrollmentRepository, moduleRepo repository.ModuleRepository) ProgressService {
+	return &progressService{
+		progressRepo:   progressRepo,
+		enrollmentRepo: enrollmentRepo,
+		moduleRepo:     moduleRepo,
+	}
+}
+
+// GetEnrollmentProgress retrieves the progress of an enrollment through every lesson of its course
+func (s *progressService) GetEnrollmentProgress(enrollmentID uuid.UUID) (*models.EnrollmentProgressResponse, error) {
+	enrollment, err := s.getEnrollment(enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	modules, err := s.moduleRepo.GetByCourseID(enrollment.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildProgress(enrollment, modules)
+}
+
+// RecordLessonProgress marks a lesson of an enrollment as started or completed.
+// Completing the last required lesson of the course completes the enrollment.
+func (s *progressService) RecordLessonProgress(enrollmentID, lessonID uuid.UUID, req models.LessonProgressRequest, actor string) (*models.EnrollmentProgressResponse, error) {
+	if req.Status != constants.LessonProgressStarted && req.Status != constants.LessonProgressCompleted {
+		return nil, errors.New("invalid lesson progress status")
+	}
+
+	enrollment, err := s.getEnrollment(enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	modules, err := s.moduleRepo.GetByCourseID(enrollment.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if !courseHasLesson(modules, lessonID) {
+		return nil, errors.New("lesson not found")
+	}
+
+	if enrollment.Status != constants.EnrollmentStatusActive {
+		return nil, errors.New("progress can only be recorded for active enrollments")
+	}
+
+	progress := models.LessonProgress{
+		EnrollmentID: enrollment.ID,
+		LessonID:     lessonID,
+		Status:       req.Status,
+	}
+	if err := s.progressRepo.Record(&progress); err != nil {
+		return nil, err
+	}
+
+	response, err := s.buildProgress(enrollment, modules)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.IsComplete() {
+		if err := s.completeEnrollment(enrollment, actor); err != nil {
+			return nil, err
+		}
+		response.Status = enrollment.Status
+	}
+
+	return response, nil
+}
+
+// completeEnrollment moves an active enrollment whose required lessons are all
+// done to completed. Losing the race to a concurrent request that completed it
+// first is not an error.
+func (s *progressService) completeEnrollment(enrollment *models.Enrollment, actor string) error {
+	if err := validateStatusTransition(enrollment.Status, constants.EnrollmentStatusCompleted); err != nil {
+		return err
+	}
+
+	reason := completionReason
+	promoted, err := s.enrollmentRepo.TransitionStatus(enrollment, constants.EnrollmentStatusCompleted, actor, &reason)
+	if err != nil {
+		if err.Error() != "enrollment status was changed concurrently" {
+			return err
+		}
+		current, err := s.enrollmentRepo.GetByID(enrollment.ID)
+		if err != nil {
+			return err
+		}
+		enrollment.Status = current.Status
+		return nil
+	}
+	logPromotions(promoted)
+	log.Printf("Enrollment %s completed: all required lessons done", enrollment.ID)
+
+	return nil
+}
+
+// buildProgress combines the ordered lessons of a course with the progress recorded for an enrollment
+func (s *progressService) buildProgress(enrollment *models.Enrollment, modules []models.CourseModule) (*models.EnrollmentProgressResponse, error) {
+	records, err := s.progressRepo.GetByEnrollmentID(enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := models.NewEnrollmentProgressResponse(enrollment, modules, records)
+	return &response, nil
+}
+
+// getEnrollment loads an enrollment, mapping a missing record to "enrollment not found"
+func (s *progressService) getEnrollment(enrollmentID uuid.UUID) (*models.Enrollment, error) {
+	enrollment, err := s.enrollmentRepo.GetByID(enrollmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("enrollment not found")
+		}
+		return nil, err
+	}
+	return enrollment, nil
+}
+
+// courseHasLesson reports whether lessonID is a lesson of one of the modules
+func courseHasLesson(modules []models.CourseModule, lessonID uuid.UUID) bool {
+	for _, module := range modules {
+		for _, lesson := range module.Lessons {
+			if lesson.ID == lessonID {
+				return true
+			}
+		}
+	}
+	return false
+}
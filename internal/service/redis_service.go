@@ -122,6 +122,21 @@ func (r *RedisService) InvalidateCoursesCache() error {
 	return r.client.Del(r.ctx, "courses:all").Err()
 }
 
+// InvalidateAllCourses removes the courses list and every cached course, e.g.
+// after a category or tag that courses show was renamed
+func (r *RedisService) InvalidateAllCourses() error {
+	iter := r.client.Scan(r.ctx, 0, "course:*", 100).Iterator()
+	for iter.Next(r.ctx) {
+		if err := r.client.Del(r.ctx, iter.Val()).Err(); err != nil {
+			return err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	return r.InvalidateCoursesCache()
+}
+
 // Session management methods
 
 // SetSession stores a user session
@@ -149,6 +164,86 @@ func (r *RedisService) DeleteSession(sessionID string) error {
 	return r.client.Del(r.ctx, key).Err()
 }
 
+// RevokeToken puts a token or session ID on the denylist until expiresAt, after
+// which the token is rejected as expired anyway
+func (r *RedisService) RevokeToken(id string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return r.SetSession(revokedSessionID(id), "revoked", ttl)
+}
+
+// MarkTokenUsed puts a token ID on the denylist until expiresAt and reports
+// whether this call put it there. Of concurrent calls for the same ID only one
+// gets true.
+func (r *RedisService) MarkTokenUsed(id string, expiresAt time.Time) (bool, error) {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return false, nil
+	}
+	key := fmt.Sprintf("session:%s", revokedSessionID(id))
+	return r.client.SetNX(r.ctx, key, "revoked", ttl).Result()
+}
+
+// IsTokenRevoked reports whether a token or session ID is on the denylist
+func (r *RedisService) IsTokenRevoked(id string) (bool, error) {
+	value, err := r.GetSession(revokedSessionID(id))
+	if err != nil {
+		return false, err
+	}
+	return value != "", nil
+}
+
+// revokedSessionID is the session ID the denylist entry for id is stored under
+func revokedSessionID(id string) string {
+	return "revoked:" + id
+}
+
+// Login attempt methods
+
+// AddLoginFailure counts a failed login for key and returns the failures
+// counted for it. They are forgotten window after the last one.
+func (r *RedisService) AddLoginFailure(key string, window time.Duration) (int, error) {
+	failuresKey := fmt.Sprintf("login_failures:%s", key)
+
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(r.ctx, failuresKey)
+	pipe.Expire(r.ctx, failuresKey, window)
+	if _, err := pipe.Exec(r.ctx); err != nil {
+		return 0, err
+	}
+	return int(incr.Val()), nil
+}
+
+// BlockLogin refuses logins for key until the time
+func (r *RedisService) BlockLogin(key string, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+	blockedKey := fmt.Sprintf("login_blocked:%s", key)
+	return r.client.Set(r.ctx, blockedKey, until.UnixMilli(), ttl).Err()
+}
+
+// LoginBlockedUntil returns the end of the block on key, or the zero time
+func (r *RedisService) LoginBlockedUntil(key string) (time.Time, error) {
+	blockedKey := fmt.Sprintf("login_blocked:%s", key)
+	until, err := r.client.Get(r.ctx, blockedKey).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	return time.UnixMilli(until), nil
+}
+
+// ResetLogin forgets the failures and block of key
+func (r *RedisService) ResetLogin(key string) error {
+	return r.client.Del(r.ctx, fmt.Sprintf("login_failures:%s", key), fmt.Sprintf("login_blocked:%s", key)).Err()
+}
+
 // Rate limiting methods
 
 // CheckRateLimit checks if a user has exceeded rate limit
@@ -174,6 +269,61 @@ func (r *RedisService) CheckRateLimit(userID string, limit int, window time.Dura
 	return err == nil, err
 }
 
+// Idempotency key methods
+
+// ReserveIdempotencyKey stores record if its key is unused and returns nil. If the
+// key is taken it returns the stored record instead. The reservation expires after
+// constants.IdempotencyLockTTL unless the request completes first.
+func (r *RedisService) ReserveIdempotencyKey(record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	key := fmt.Sprintf("idempotency:%s", record.Key)
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stored record can expire between SETNX and GET, so try twice
+	for attempt := 0; attempt < 2; attempt++ {
+		reserved, err := r.client.SetNX(r.ctx, key, data, constants.IdempotencyLockTTL).Result()
+		if err != nil {
+			return nil, err
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		stored, err := r.client.Get(r.ctx, key).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var existing models.IdempotencyRecord
+		if err := json.Unmarshal([]byte(stored), &existing); err != nil {
+			return nil, err
+		}
+		return &existing, nil
+	}
+
+	return nil, fmt.Errorf("idempotency key %s changed concurrently", record.Key)
+}
+
+// CompleteIdempotencyKey stores the response of a reserved key until record.ExpiresAt
+func (r *RedisService) CompleteIdempotencyKey(record *models.IdempotencyRecord) error {
+	key := fmt.Sprintf("idempotency:%s", record.Key)
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(r.ctx, key, data, time.Until(record.ExpiresAt)).Err()
+}
+
+// ReleaseIdempotencyKey forgets a reserved key so the request can be retried
+func (r *RedisService) ReleaseIdempotencyKey(key string) error {
+	return r.client.Del(r.ctx, fmt.Sprintf("idempotency:%s", key)).Err()
+}
+
 // General cache methods
 
 // Set stores a key-value pair with TTL
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ScheduleConflictError is returned by EnrollStudent when the requested section
+// meets at the same time as sections the student already holds and no override
+// was requested
+type ScheduleConflictError struct {
+	Conflicts []models.SectionResponse
+}
+
+func (e *ScheduleConflictError) Error() string {
+	return "section conflicts with the student's schedule"
+}
+
+// SectionService defines the interface for class section business logic
+type SectionService interface {
+	GetSections(courseID uuid.UUID, offeringID *uuid.UUID) (*models.SectionListResponse, error)
+	GetSection(courseID, sectionID uuid.UUID) (*models.SectionResponse, error)
+	CreateSection(courseID uuid.UUID, req models.SectionRequest) (*models.SectionResponse, error)
+	UpdateSection(courseID, sectionID uuid.UUID, req models.SectionRequest) (*models.SectionResponse, error)
+	DeleteSection(courseID, sectionID uuid.UUID) error
+	GetStudentTimetable(email string, termID *uuid.UUID) (*models.TimetableResponse, error)
+}
+
+// sectionService implements SectionService interface
+type sectionService struct {
+	sectionRepo  repository.SectionRepository
+	courseRepo   repository.CourseRepository
+	offeringRepo repository.OfferingRepository
+}
+
+// NewSectionService creates a new section service
+func NewSectionService(sectionRepo repository.SectionRepository, courseRepo repository.CourseRepository, offeringRepo repository.OfferingRepository) SectionService {
+	return &sectionService{
+		sectionRepo:  sectionRepo,
+		courseRepo:   courseRepo,
+		offeringRepo: offeringRepo,
+	}
+}
+
+// GetSections retrieves the sections of a course, optionally only those of one offering
+func (s *sectionService) GetSections(courseID uuid.UUID, offeringID *uuid.UUID) (*models.SectionListResponse, error) {
+	if err := s.ensureCourseExists(courseID); err != nil {
+		return nil, err
+	}
+
+	sections, err := s.sectionRepo.GetByCourseID(courseID, offeringID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.SectionResponse, len(sections))
+	for i, section := range sections {
+		responses[i] = section.ToResponse()
+	}
+
+	return &models.SectionListResponse{
+		CourseID: courseID,
+		Sections: responses,
+		Total:    len(responses),
+	}, nil
+}
+
+// GetSection retrieves a section of a course with its meetings
+func (s *sectionService) GetSection(courseID, sectionID uuid.UUID) (*models.SectionResponse, error) {
+	section, err := s.getSection(courseID, sectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := section.ToResponse()
+	return &response, nil
+}
+
+// CreateSection adds a section to an offering of a course, or to its default
+// offering when none is given
+func (s *sectionService) CreateSection(courseID uuid.UUID, req models.SectionRequest) (*models.SectionResponse, error) {
+	if err := s.ensureCourseExists(courseID); err != nil {
+		return nil, err
+	}
+	meetings, err := validateSection(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	var offering *models.CourseOffering
+	if req.OfferingID != nil {
+		offering, err = s.offeringRepo.GetByID(courseID, *req.OfferingID)
+	} else {
+		offering, err = s.offeringRepo.GetDefault(courseID)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("offering not found")
+		}
+		return nil, err
+	}
+
+	exists, err := s.sectionRepo.ExistsByName(offering.ID, req.Name, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("section name already exists")
+	}
+
+	section := models.Section{
+		CourseID:   courseID,
+		OfferingID: offering.ID,
+		Name:       req.Name,
+		Meetings:   meetings,
+	}
+	if err := s.sectionRepo.Create(&section); err != nil {
+		return nil, err
+	}
+
+	return s.GetSection(courseID, section.ID)
+}
+
+// UpdateSection renames a section and replaces its meetings. The offering of a
+// section cannot be changed.
+func (s *sectionService) UpdateSection(courseID, sectionID uuid.UUID, req models.SectionRequest) (*models.SectionResponse, error) {
+	section, err := s.getSection(courseID, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	meetings, err := validateSection(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.sectionRepo.ExistsByName(section.OfferingID, req.Name, section.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("section name already exists")
+	}
+
+	section.Name = req.Name
+	section.Meetings = meetings
+	if err := s.sectionRepo.Update(section); err != nil {
+		return nil, err
+	}
+
+	return s.GetSection(courseID, sectionID)
+}
+
+// DeleteSection removes a section nobody is enrolled or waitlisted in
+func (s *sectionService) DeleteSection(courseID, sectionID uuid.UUID) error {
+	section, err := s.getSection(courseID, sectionID)
+	if err != nil {
+		return err
+	}
+
+	used, err := s.sectionRepo.HasEnrollments(section.ID)
+	if err != nil {
+		return err
+	}
+	if used {
+		return errors.New("section has enrollments")
+	}
+
+	if err := s.sectionRepo.Delete(courseID, sectionID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("section not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetStudentTimetable retrieves the weekly meetings of every section a student
+// holds a seat in, optionally limited to one term
+func (s *sectionService) GetStudentTimetable(email string, termID *uuid.UUID) (*models.TimetableResponse, error) {
+	email = models.NormalizeEmail(email)
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, errors.New("invalid email format")
+	}
+
+	enrollments, err := s.sectionRepo.GetStudentSchedule(email, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	timetable := models.NewTimetable(email, enrollments)
+	return &timetable, nil
+}
+
+// getSection loads a section of a course, mapping missing rows to errors
+func (s *sectionService) getSection(courseID, sectionID uuid.UUID) (*models.Section, error) {
+	if err := s.ensureCourseExists(courseID); err != nil {
+		return nil, err
+	}
+
+	section, err := s.sectionRepo.GetByID(courseID, sectionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("section not found")
+		}
+		return nil, err
+	}
+	return section, nil
+}
+
+// ensureCourseExists returns "course not found" if the course does not exist
+func (s *sectionService) ensureCourseExists(courseID uuid.UUID) error {
+	exists, err := s.courseRepo.ExistsByID(courseID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.New("course not found")
+	}
+	return nil
+}
+
+// validateSection trims the section name and checks every meeting, returning
+// the meetings to store
+func validateSection(req *models.SectionRequest) ([]models.SectionMeeting, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, errors.New("section name is required")
+	}
+
+	meetings := make([]models.SectionMeeting, len(req.Meetings))
+	for i, meeting := range req.Meetings {
+		if _, ok := models.ParseWeekday(meeting.Day); !ok {
+			return nil, errors.New("invalid meeting day")
+		}
+		start, startOK := models.ParseClock(meeting.StartTime)
+		end, endOK := models.ParseClock(meeting.EndTime)
+		if !startOK || !endOK {
+			return nil, errors.New("invalid meeting time")
+		}
+		if end <= start {
+			return nil, errors.New("meeting must end after it starts")
+		}
+		if meeting.TimeZone == "" {
+			return nil, errors.New("invalid time zone")
+		}
+		if _, err := time.LoadLocation(meeting.TimeZone); err != nil {
+			return nil, errors.New("invalid time zone")
+		}
+
+		meetings[i] = models.SectionMeeting{
+			Day:       strings.ToLower(meeting.Day),
+			StartTime: meeting.StartTime,
+			EndTime:   meeting.EndTime,
+			Location:  meeting.Location,
+			TimeZone:  meeting.TimeZone,
+		}
+	}
+	return meetings, nil
+}
+
+// scheduleConflicts returns the sections the student holds in other courses that
+// meet at the same time as the given section
+func scheduleConflicts(sectionRepo repository.SectionRepository, email string, section *models.Section) ([]models.Section, error) {
+	held, err := sectionRepo.GetStudentSchedule(email, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var conflicts []models.Section
+	for _, enrollment := range held {
+		if enrollment.CourseID == section.CourseID || enrollment.Section == nil {
+			continue
+		}
+		if section.ConflictsWith(enrollment.Section, now) {
+			conflicts = append(conflicts, *enrollment.Section)
+		}
+	}
+	return conflicts, nil
+}
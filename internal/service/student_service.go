@@ -1,34 +1,54 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/auth"
+	"sonic-labs/course-enrollment-service/internal/constants"
 	"sonic-labs/course-enrollment-service/internal/models"
 	"sonic-labs/course-enrollment-service/internal/repository"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // StudentService defines the interface for student business logic
 type StudentService interface {
 	GetAllStudents() (*models.AllStudentsResponse, error)
-	GetAllEnrollments() (*models.AllEnrollmentsResponse, error)
-	DeleteEnrollment(id uuid.UUID) error
+	GetStudent(id uuid.UUID) (*models.StudentResponse, error)
+	CreateStudent(req models.StudentRequest) (*models.StudentResponse, error)
+	UpdateStudent(id uuid.UUID, req models.StudentRequest) (*models.StudentResponse, error)
+	DeleteStudent(id uuid.UUID) error
+	MergeStudents(targetID uuid.UUID, req models.StudentMergeRequest, actor string) (*models.StudentMergeResponse, error)
+	GetStudentMerges(id uuid.UUID) (*models.StudentMergesResponse, error)
+	CreateShareLink(id uuid.UUID, req models.ShareLinkRequest) (*models.ShareLinkResponse, error)
+	CreateInvite(id uuid.UUID) (*models.StudentInviteResponse, error)
+	GetAllEnrollments(termID *uuid.UUID, statuses []string) (*models.AllEnrollmentsResponse, error)
+	DeleteEnrollment(id uuid.UUID, actor string) error
 }
 
 // studentService implements StudentService interface
 type studentService struct {
 	enrollmentRepo repository.EnrollmentRepository
+	studentRepo    repository.StudentRepository
 }
 
 // NewStudentService creates a new student service
-func NewStudentService(enrollmentRepo repository.EnrollmentRepository) StudentService {
+func NewStudentService(enrollmentRepo repository.EnrollmentRepository, studentRepo repository.StudentRepository) StudentService {
 	return &studentService{
 		enrollmentRepo: enrollmentRepo,
+		studentRepo:    studentRepo,
 	}
 }
 
 // GetAllStudents retrieves all students with their enrollment statistics
 func (s *studentService) GetAllStudents() (*models.AllStudentsResponse, error) {
-	students, err := s.enrollmentRepo.GetAllStudents()
+	students, err := s.studentRepo.GetAllWithStats()
 	if err != nil {
 		return nil, err
 	}
@@ -39,9 +59,268 @@ func (s *studentService) GetAllStudents() (*models.AllStudentsResponse, error) {
 	}, nil
 }
 
-// GetAllEnrollments retrieves all enrollments with course details
-func (s *studentService) GetAllEnrollments() (*models.AllEnrollmentsResponse, error) {
-	enrollments, err := s.enrollmentRepo.GetAllEnrollments()
+// GetStudent retrieves a student with their enrollment statistics
+func (s *studentService) GetStudent(id uuid.UUID) (*models.StudentResponse, error) {
+	student, err := s.studentRepo.GetWithStats(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student not found")
+		}
+		return nil, err
+	}
+	return student, nil
+}
+
+// CreateStudent creates a student profile ahead of their first enrollment
+func (s *studentService) CreateStudent(req models.StudentRequest) (*models.StudentResponse, error) {
+	student := models.Student{}
+	if err := s.applyStudentRequest(&student, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.studentRepo.Create(&student); err != nil {
+		return nil, err
+	}
+
+	return s.GetStudent(student.ID)
+}
+
+// UpdateStudent replaces the profile of a student
+func (s *studentService) UpdateStudent(id uuid.UUID, req models.StudentRequest) (*models.StudentResponse, error) {
+	student, err := s.studentRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student not found")
+		}
+		return nil, err
+	}
+
+	previousEmail := student.Email
+	if err := s.applyStudentRequest(student, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.studentRepo.Update(student, previousEmail); err != nil {
+		return nil, err
+	}
+
+	return s.GetStudent(id)
+}
+
+// DeleteStudent removes a student that has never enrolled or joined a waitlist
+func (s *studentService) DeleteStudent(id uuid.UUID) error {
+	used, err := s.studentRepo.HasEnrollments(id)
+	if err != nil {
+		return err
+	}
+	if used {
+		return errors.New("student has enrollments")
+	}
+
+	if err := s.studentRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("student not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// MergeStudents merges the source student of the request into the target student,
+// for when one person enrolled under two identities. The source student is
+// deleted and the merge is recorded.
+func (s *studentService) MergeStudents(targetID uuid.UUID, req models.StudentMergeRequest, actor string) (*models.StudentMergeResponse, error) {
+	if req.SourceStudentID == uuid.Nil {
+		return nil, errors.New("source student ID is required")
+	}
+	if req.SourceStudentID == targetID {
+		return nil, errors.New("cannot merge a student into itself")
+	}
+
+	target, err := s.studentRepo.GetByID(targetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student not found")
+		}
+		return nil, err
+	}
+	source, err := s.studentRepo.GetByID(req.SourceStudentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("source student not found")
+		}
+		return nil, err
+	}
+
+	merge := &models.StudentMerge{
+		SourceStudentID: source.ID,
+		SourceEmail:     source.Email,
+		TargetStudentID: target.ID,
+		TargetEmail:     target.Email,
+		MergedBy:        actor,
+		Reason:          trimOptional(req.Reason),
+	}
+	promoted, err := s.studentRepo.Merge(source, target, merge)
+	if err != nil {
+		return nil, err
+	}
+	logPromotions(promoted)
+
+	response := merge.ToResponse()
+	return &response, nil
+}
+
+// GetStudentMerges retrieves the merges into a student, newest first
+func (s *studentService) GetStudentMerges(id uuid.UUID) (*models.StudentMergesResponse, error) {
+	if _, err := s.studentRepo.GetByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student not found")
+		}
+		return nil, err
+	}
+
+	merges, err := s.studentRepo.GetMerges(id)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.StudentMergeResponse, len(merges))
+	for i, merge := range merges {
+		responses[i] = merge.ToResponse()
+	}
+
+	return &models.StudentMergesResponse{
+		Merges: responses,
+		Total:  len(responses),
+	}, nil
+}
+
+// CreateShareLink signs links to the enrollments and timetable of a student, for
+// sharing them with someone who cannot sign in as the student. The links stop
+// working when they expire or the student's email changes.
+func (s *studentService) CreateShareLink(id uuid.UUID, req models.ShareLinkRequest) (*models.ShareLinkResponse, error) {
+	ttl := constants.DefaultShareLinkTTL
+	if req.ExpiresInHours != 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	if ttl <= 0 || ttl > constants.MaxShareLinkTTL {
+		return nil, errors.New("invalid share link expiry")
+	}
+
+	student, err := s.studentRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student not found")
+		}
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+	query := url.Values{}
+	query.Set("expires", fmt.Sprint(expiresAt.Unix()))
+	query.Set("signature", auth.SignShareLink(student.Email, expiresAt))
+	base := "/api/v1/students/" + url.PathEscape(student.Email)
+
+	return &models.ShareLinkResponse{
+		StudentEmail:   student.Email,
+		EnrollmentsURL: base + "/enrollments?" + query.Encode(),
+		TimetableURL:   base + "/timetable?" + query.Encode(),
+		ExpiresAt:      expiresAt,
+	}, nil
+}
+
+// CreateInvite issues a token the student can register an account for their
+// existing record with, replacing any earlier one. Students with an account
+// cannot be invited.
+func (s *studentService) CreateInvite(id uuid.UUID) (*models.StudentInviteResponse, error) {
+	student, err := s.studentRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("student not found")
+		}
+		return nil, err
+	}
+
+	token, err := generateToken(constants.StudentInviteTokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(constants.StudentInviteTokenExpiry)
+	if err := s.studentRepo.SetInvite(student.ID, hashToken(token), expiresAt); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrStudentHasAccount):
+			return nil, errors.New("student already has an account")
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, errors.New("student not found")
+		}
+		return nil, err
+	}
+
+	return &models.StudentInviteResponse{
+		StudentID:    student.ID,
+		StudentEmail: student.Email,
+		Token:        token,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+// applyStudentRequest validates a student request and copies it onto the
+// student. Blank names and student numbers are stored as missing.
+func (s *studentService) applyStudentRequest(student *models.Student, req models.StudentRequest) error {
+	email := models.NormalizeEmail(req.Email)
+	if email == "" {
+		return errors.New("student email is required")
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		return errors.New("invalid email format")
+	}
+
+	exists, err := s.studentRepo.ExistsByEmail(email, student.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("student email already exists")
+	}
+
+	number := trimOptional(req.StudentNumber)
+	if number != nil {
+		exists, err := s.studentRepo.ExistsByStudentNumber(*number, student.ID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.New("student number already exists")
+		}
+	}
+
+	student.Email = email
+	student.FirstName = trimOptional(req.FirstName)
+	student.LastName = trimOptional(req.LastName)
+	student.StudentNumber = number
+	return nil
+}
+
+// trimOptional trims an optional string, returning nil if it is blank
+func trimOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// GetAllEnrollments retrieves all enrollments with course details, optionally filtered by term and status
+func (s *studentService) GetAllEnrollments(termID *uuid.UUID, statuses []string) (*models.AllEnrollmentsResponse, error) {
+	if err := validateStatusFilter(statuses); err != nil {
+		return nil, err
+	}
+
+	enrollments, err := s.enrollmentRepo.GetAllEnrollments(termID, statuses...)
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +331,23 @@ func (s *studentService) GetAllEnrollments() (*models.AllEnrollmentsResponse, er
 	}, nil
 }
 
-// DeleteEnrollment deletes an enrollment by ID
-func (s *studentService) DeleteEnrollment(id uuid.UUID) error {
-	return s.enrollmentRepo.Delete(id)
+// DeleteEnrollment withdraws an enrollment by ID, keeping its history, and hands the
+// freed seat to the waitlist
+func (s *studentService) DeleteEnrollment(id uuid.UUID, actor string) error {
+	enrollment, err := s.enrollmentRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := validateStatusTransition(enrollment.Status, constants.EnrollmentStatusWithdrawn); err != nil {
+		return err
+	}
+
+	promoted, err := s.enrollmentRepo.TransitionStatus(enrollment, constants.EnrollmentStatusWithdrawn, actor, nil)
+	if err != nil {
+		return err
+	}
+	logPromotions(promoted)
+
+	return nil
 }
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TagService defines the interface for course tag business logic
+type TagService interface {
+	GetTags() (*models.TagListResponse, error)
+	CreateTag(req models.TagRequest) (*models.TagResponse, error)
+	UpdateTag(id uuid.UUID, req models.TagRequest) (*models.TagResponse, error)
+	DeleteTag(id uuid.UUID) error
+}
+
+// tagService implements TagService interface
+type tagService struct {
+	tagRepo      repository.TagRepository
+	redisService *RedisService
+}
+
+// NewTagService creates a new tag service
+func NewTagService(tagRepo repository.TagRepository, redisService *RedisService) TagService {
+	return &tagService{
+		tagRepo:      tagRepo,
+		redisService: redisService,
+	}
+}
+
+// GetTags retrieves every tag with the number of courses that have it
+func (s *tagService) GetTags() (*models.TagListResponse, error) {
+	tags, err := s.tagRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.tagRepo.CountCourses()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.TagResponse, len(tags))
+	for i, tag := range tags {
+		responses[i] = tagResponse(tag, counts[tag.ID])
+	}
+
+	return &models.TagListResponse{
+		Tags:  responses,
+		Total: len(responses),
+	}, nil
+}
+
+// CreateTag adds a tag that no course has yet
+func (s *tagService) CreateTag(req models.TagRequest) (*models.TagResponse, error) {
+	if err := s.validateTag(uuid.Nil, &req); err != nil {
+		return nil, err
+	}
+
+	tag := models.Tag{Name: req.Name}
+	if err := s.tagRepo.Create(&tag); err != nil {
+		return nil, err
+	}
+
+	response := tagResponse(tag, 0)
+	return &response, nil
+}
+
+// UpdateTag renames a tag on every course that has it
+func (s *tagService) UpdateTag(id uuid.UUID, req models.TagRequest) (*models.TagResponse, error) {
+	tag, err := s.getTag(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateTag(id, &req); err != nil {
+		return nil, err
+	}
+
+	tag.Name = req.Name
+	if err := s.tagRepo.Update(tag); err != nil {
+		return nil, err
+	}
+	counts, err := s.tagRepo.CountCourses()
+	if err != nil {
+		return nil, err
+	}
+
+	// Courses show the names of their tags
+	if s.redisService != nil {
+		s.redisService.InvalidateAllCourses()
+	}
+
+	response := tagResponse(*tag, counts[tag.ID])
+	return &response, nil
+}
+
+// DeleteTag removes a tag from every course and deletes it
+func (s *tagService) DeleteTag(id uuid.UUID) error {
+	if err := s.tagRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("tag not found")
+		}
+		return err
+	}
+
+	if s.redisService != nil {
+		s.redisService.InvalidateAllCourses()
+	}
+
+	return nil
+}
+
+// getTag loads a tag, mapping a missing row to "tag not found"
+func (s *tagService) getTag(id uuid.UUID) (*models.Tag, error) {
+	tag, err := s.tagRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+	return tag, nil
+}
+
+// validateTag normalizes the tag name and checks that it is set and not used by
+// another tag
+func (s *tagService) validateTag(id uuid.UUID, req *models.TagRequest) error {
+	req.Name = models.NormalizeTag(req.Name)
+	if req.Name == "" {
+		return errors.New("tag name is required")
+	}
+	if len(req.Name) > constants.MaxTagLength {
+		return errors.New("tag name is too long")
+	}
+
+	existing, err := s.tagRepo.GetByName(req.Name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return errors.New("tag name already exists")
+	}
+
+	return nil
+}
+
+// tagResponse converts a tag and its course count to TagResponse
+func tagResponse(tag models.Tag, courseCount int) models.TagResponse {
+	return models.TagResponse{
+		ID:          tag.ID,
+		Name:        tag.Name,
+		CourseCount: courseCount,
+		CreatedAt:   tag.CreatedAt,
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TermService defines the interface for academic term business logic
+type TermService interface {
+	GetTerms() (*models.TermListResponse, error)
+	GetTerm(id uuid.UUID) (*models.TermResponse, error)
+	CreateTerm(req models.TermRequest) (*models.TermResponse, error)
+	UpdateTerm(id uuid.UUID, req models.TermRequest) (*models.TermResponse, error)
+	DeleteTerm(id uuid.UUID) error
+}
+
+// termService implements TermService interface
+type termService struct {
+	termRepo     repository.TermRepository
+	offeringRepo repository.OfferingRepository
+}
+
+// NewTermService creates a new term service
+func NewTermService(termRepo repository.TermRepository, offeringRepo repository.OfferingRepository) TermService {
+	return &termService{
+		termRepo:     termRepo,
+		offeringRepo: offeringRepo,
+	}
+}
+
+// GetTerms retrieves every term, earliest first
+func (s *termService) GetTerms() (*models.TermListResponse, error) {
+	terms, err := s.termRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.TermResponse, len(terms))
+	for i, term := range terms {
+		responses[i] = term.ToResponse()
+	}
+
+	return &models.TermListResponse{
+		Terms: responses,
+		Total: len(responses),
+	}, nil
+}
+
+// GetTerm retrieves a term by ID
+func (s *termService) GetTerm(id uuid.UUID) (*models.TermResponse, error) {
+	term, err := s.getTerm(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := term.ToResponse()
+	return &response, nil
+}
+
+// CreateTerm adds a term to the academic calendar
+func (s *termService) CreateTerm(req models.TermRequest) (*models.TermResponse, error) {
+	if err := s.validateTerm(uuid.Nil, &req); err != nil {
+		return nil, err
+	}
+
+	term := models.Term{
+		Name:      req.Name,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	}
+	if err := s.termRepo.Create(&term); err != nil {
+		return nil, err
+	}
+
+	response := term.ToResponse()
+	return &response, nil
+}
+
+// UpdateTerm changes the name and dates of a term
+func (s *termService) UpdateTerm(id uuid.UUID, req models.TermRequest) (*models.TermResponse, error) {
+	term, err := s.getTerm(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateTerm(id, &req); err != nil {
+		return nil, err
+	}
+
+	term.Name = req.Name
+	term.StartDate = req.StartDate
+	term.EndDate = req.EndDate
+	if err := s.termRepo.Update(term); err != nil {
+		return nil, err
+	}
+
+	response := term.ToResponse()
+	return &response, nil
+}
+
+// DeleteTerm removes a term that no course is offered in
+func (s *termService) DeleteTerm(id uuid.UUID) error {
+	if _, err := s.getTerm(id); err != nil {
+		return err
+	}
+
+	offerings, err := s.offeringRepo.CountByTermID(id)
+	if err != nil {
+		return err
+	}
+	if offerings > 0 {
+		return errors.New("term has course offerings")
+	}
+
+	if err := s.termRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("term not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// getTerm loads a term, mapping a missing row to "term not found"
+func (s *termService) getTerm(id uuid.UUID) (*models.Term, error) {
+	term, err := s.termRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("term not found")
+		}
+		return nil, err
+	}
+	return term, nil
+}
+
+// validateTerm trims the term name and checks that it is set and not used by
+// another term, and that the term does not end before it starts
+func (s *termService) validateTerm(id uuid.UUID, req *models.TermRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return errors.New("term name is required")
+	}
+	if req.StartDate.IsZero() || req.EndDate.IsZero() {
+		return errors.New("term start and end dates are required")
+	}
+	if req.EndDate.Before(req.StartDate) {
+		return errors.New("term must end after it starts")
+	}
+
+	existing, err := s.termRepo.GetByName(req.Name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return errors.New("term name already exists")
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserService defines the interface for user account management
+type UserService interface {
+	GetUsers(role string) (*models.UserListResponse, error)
+	CreateUser(req models.CreateUserRequest) (*models.UserResponse, error)
+	UpdateRole(id uuid.UUID, req models.UpdateUserRoleRequest) (*models.UserResponse, error)
+	SetDisabled(id uuid.UUID, disabled bool) (*models.UserResponse, error)
+	Unlock(id uuid.UUID) (*models.UserResponse, error)
+	IssuePasswordReset(id uuid.UUID) (*models.PasswordResetResponse, error)
+	DeleteUser(id uuid.UUID) error
+}
+
+// userService implements UserService interface
+type userService struct {
+	userRepo      repository.UserRepository
+	loginAttempts LoginAttemptStore
+	passwords     PasswordPolicy
+}
+
+// NewUserService creates a new user service
+func NewUserService(userRepo repository.UserRepository, loginAttempts LoginAttemptStore, passwords PasswordPolicy) UserService {
+	return &userService{
+		userRepo:      userRepo,
+		loginAttempts: loginAttempts,
+		passwords:     passwords,
+	}
+}
+
+// GetUsers retrieves every user, or the users with the role when it is not empty
+func (s *userService) GetUsers(role string) (*models.UserListResponse, error) {
+	users, err := s.userRepo.GetAll(strings.TrimSpace(role))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.UserResponse, len(users))
+	for i := range users {
+		responses[i] = users[i].ToResponse()
+	}
+
+	return &models.UserListResponse{
+		Users: responses,
+		Total: len(responses),
+	}, nil
+}
+
+// CreateUser creates an admin or super-admin account. Student and instructor
+// accounts are created through registration and the instructor endpoints, which
+// link them to their student or instructor.
+func (s *userService) CreateUser(req models.CreateUserRequest) (*models.UserResponse, error) {
+	username := strings.TrimSpace(req.Username)
+	if strings.Contains(username, "@") {
+		// Usernames with an @ are looked up in lower case when logging in
+		username = models.NormalizeEmail(username)
+	}
+	if username == "" {
+		return nil, errors.New("username is required")
+	}
+	if err := s.passwords.Check(req.Password); err != nil {
+		return nil, err
+	}
+	if !isStaffRole(req.Role) {
+		return nil, errors.New("invalid role")
+	}
+
+	if _, err := s.userRepo.GetByUsername(username); err == nil {
+		return nil, errors.New("username already exists")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	hashedPassword, err := HashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username:           username,
+		Password:           hashedPassword,
+		Role:               req.Role,
+		MustChangePassword: req.MustChangePassword,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// UpdateRole moves an admin or super-admin account to the other of the two
+// roles. The last enabled super-admin cannot be demoted.
+func (s *userService) UpdateRole(id uuid.UUID, req models.UpdateUserRoleRequest) (*models.UserResponse, error) {
+	user, err := s.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if !isStaffRole(req.Role) {
+		return nil, errors.New("invalid role")
+	}
+	if !isStaffRole(user.Role) {
+		return nil, errors.New("role is fixed")
+	}
+
+	user.Role = req.Role
+	if err := s.userRepo.UpdateAccess(user); err != nil {
+		return nil, mapLastSuperAdmin(err)
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// SetDisabled disables or re-enables a user. Disabled users cannot log in, and
+// the last enabled super-admin cannot be disabled.
+func (s *userService) SetDisabled(id uuid.UUID, disabled bool) (*models.UserResponse, error) {
+	user, err := s.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case disabled && user.DisabledAt == nil:
+		now := time.Now()
+		user.DisabledAt = &now
+	case !disabled:
+		user.DisabledAt = nil
+	}
+
+	if err := s.userRepo.UpdateAccess(user); err != nil {
+		return nil, mapLastSuperAdmin(err)
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// Unlock lifts the delay or lockout on logging in as a user after failed
+// attempts, and forgets the failures. Lockouts of client IPs are left to expire.
+func (s *userService) Unlock(id uuid.UUID) (*models.UserResponse, error) {
+	user, err := s.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loginAttempts.ResetLogin(usernameLoginKey(user.Username)); err != nil {
+		return nil, err
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// IssuePasswordReset issues a token the user can set a new password with at
+// /auth/reset-password, replacing any earlier one. Only the hash of the token
+// is stored. The current password keeps working until it is reset.
+func (s *userService) IssuePasswordReset(id uuid.UUID) (*models.PasswordResetResponse, error) {
+	user, err := s.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := generateToken(constants.PasswordResetTokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := hashToken(token)
+	expiresAt := time.Now().Add(s.passwords.ResetTokenTTL())
+	user.PasswordResetHash = &hash
+	user.PasswordResetExpiresAt = &expiresAt
+	if err := s.userRepo.UpdatePassword(user); err != nil {
+		return nil, err
+	}
+
+	return &models.PasswordResetResponse{
+		User:      user.ToResponse(),
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// DeleteUser deletes a user account. Students and instructors are kept; only
+// their login goes. The last enabled super-admin cannot be deleted.
+func (s *userService) DeleteUser(id uuid.UUID) error {
+	if err := s.userRepo.DeleteAccount(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return mapLastSuperAdmin(err)
+	}
+	return nil
+}
+
+// getUser loads a user, mapping a missing row to "user not found"
+func (s *userService) getUser(id uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// isStaffRole reports whether the role is one that accounts created through
+// user management can have
+func isStaffRole(role string) bool {
+	return role == constants.RoleAdmin || role == constants.RoleSuperAdmin
+}
+
+// mapLastSuperAdmin maps repository.ErrLastSuperAdmin to "last super admin"
+func mapLastSuperAdmin(err error) error {
+	if errors.Is(err, repository.ErrLastSuperAdmin) {
+		return errors.New("last super admin")
+	}
+	return err
+}
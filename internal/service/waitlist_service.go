@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"log"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WaitlistedError is returned by EnrollStudent when the course is full and
+// the student was placed on its waitlist instead of being enrolled
+type WaitlistedError struct {
+	Entry models.WaitlistEntryResponse
+}
+
+func (e *WaitlistedError) Error() string {
+	return "course is full, student added to the waitlist"
+}
+
+// WaitlistService defines the interface for course waitlist business logic
+type WaitlistService interface {
+	GetWaitlist(courseID uuid.UUID) (*models.WaitlistResponse, error)
+	ReorderWaitlist(courseID uuid.UUID, req models.WaitlistReorderRequest) (*models.WaitlistResponse, error)
+	RemoveFromWaitlist(courseID uuid.UUID, studentEmail string) error
+}
+
+// waitlistService implements WaitlistService interface
+type waitlistService struct {
+	waitlistRepo   repository.WaitlistRepository
+	enrollmentRepo repository.EnrollmentRepository
+	courseRepo     repository.CourseRepository
+}
+
+// NewWaitlistService creates a new waitlist service
+func NewWaitlistService(waitlistRepo repository.WaitlistRepository, enrollmentRepo repository.EnrollmentRepository, courseRepo repository.CourseRepository) WaitlistService {
+	return &waitlistService{
+		waitlistRepo:   waitlistRepo,
+		enrollmentRepo: enrollmentRepo,
+		courseRepo:     courseRepo,
+	}
+}
+
+// GetWaitlist retrieves the ordered waitlist of a course
+func (s *waitlistService) GetWaitlist(courseID uuid.UUID) (*models.WaitlistResponse, error) {
+	course, err := s.courseRepo.GetByID(courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+
+	entries, err := s.waitlistRepo.GetByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	enrolled, err := s.enrollmentRepo.CountByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.WaitlistEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = entry.ToResponse()
+	}
+
+	return &models.WaitlistResponse{
+		CourseID:      course.ID,
+		Capacity:      course.Capacity,
+		EnrolledCount: enrolled,
+		Entries:       responses,
+		Total:         len(responses),
+	}, nil
+}
+
+// ReorderWaitlist changes the order in which waitlisted students are promoted
+func (s *waitlistService) ReorderWaitlist(courseID uuid.UUID, req models.WaitlistReorderRequest) (*models.WaitlistResponse, error) {
+	if _, err := s.courseRepo.GetByID(courseID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+
+	entries, err := s.waitlistRepo.GetByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new order must be a permutation of the current waitlist
+	if len(req.StudentEmails) != len(entries) {
+		return nil, errors.New("waitlist order must list every waitlisted student exactly once")
+	}
+	order := make([]string, len(req.StudentEmails))
+	for i, email := range req.StudentEmails {
+		order[i] = models.NormalizeEmail(email)
+	}
+	waiting := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		waiting[entry.StudentEmail] = true
+	}
+	for _, email := range order {
+		if !waiting[email] {
+			return nil, errors.New("waitlist order must list every waitlisted student exactly once")
+		}
+		delete(waiting, email)
+	}
+
+	if err := s.waitlistRepo.Reorder(courseID, order); err != nil {
+		return nil, err
+	}
+
+	return s.GetWaitlist(courseID)
+}
+
+// RemoveFromWaitlist removes a student from a course waitlist
+func (s *waitlistService) RemoveFromWaitlist(courseID uuid.UUID, studentEmail string) error {
+	if _, err := s.courseRepo.GetByID(courseID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("course not found")
+		}
+		return err
+	}
+
+	if err := s.waitlistRepo.Remove(courseID, studentEmail); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("student not on the waitlist for this course")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// logPromotions records students that were moved from a waitlist into a course
+func logPromotions(promoted []models.Enrollment) {
+	for _, enrollment := range promoted {
+		log.Printf("Promoted %s from waitlist into course %s", enrollment.StudentEmail, enrollment.CourseID)
+	}
+}
@@ -20,8 +20,5 @@ CREATE TRIGGER update_users_updated_at
     FOR EACH ROW 
     EXECUTE FUNCTION update_updated_at_column();
 
--- Insert admin user if it doesn't exist
--- Password is hashed using bcrypt for 'admin!dev'
-INSERT INTO users (username, password, role) 
-VALUES ('admin', '$2a$10$V6C81VGFyKg/sRc1JOw8cOs7dV/3StzYs5NUZaYvDFcEEKW0Tlika', 'admin')
-ON CONFLICT (username) DO NOTHING;
+-- The first admin is created on startup by database.SeedAdminUser with the
+-- configured ADMIN_USERNAME and ADMIN_PASSWORD
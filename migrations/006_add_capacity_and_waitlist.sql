@@ -0,0 +1,32 @@
+-- Add optional seat limit to courses (NULL means unlimited)
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS capacity INTEGER CHECK (capacity IS NULL OR capacity >= 0);
+
+-- Create waitlist table for students waiting on a full course
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL,
+    student_email VARCHAR(255) NOT NULL,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign key constraint
+    CONSTRAINT fk_waitlist_entries_course_id
+        FOREIGN KEY (course_id)
+        REFERENCES courses(id)
+        ON DELETE CASCADE,
+
+    -- A student can only wait once per course
+    CONSTRAINT unique_waitlist_course_student
+        UNIQUE (course_id, student_email)
+);
+
+-- Create index on course_id and position for ordered lookups
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_course_position ON waitlist_entries(course_id, position);
+
+-- Add trigger to update updated_at column
+DROP TRIGGER IF EXISTS update_waitlist_entries_updated_at ON waitlist_entries;
+CREATE TRIGGER update_waitlist_entries_updated_at
+    BEFORE UPDATE ON waitlist_entries
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
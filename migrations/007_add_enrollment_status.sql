@@ -0,0 +1,31 @@
+-- Track the lifecycle of enrollments instead of deleting them
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('pending', 'active', 'completed', 'dropped', 'withdrawn'));
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS status_changed_by VARCHAR(255);
+
+-- Existing enrollments became active when they were created
+UPDATE enrollments SET status_changed_at = enrolled_at, status_changed_by = 'system' WHERE status_changed_by IS NULL;
+
+-- Create index on status for filtering
+CREATE INDEX IF NOT EXISTS idx_enrollments_status ON enrollments(status);
+
+-- Create status history table
+CREATE TABLE IF NOT EXISTS enrollment_status_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    enrollment_id UUID NOT NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    changed_by VARCHAR(255) NOT NULL,
+    reason TEXT,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign key constraint
+    CONSTRAINT fk_enrollment_status_changes_enrollment_id
+        FOREIGN KEY (enrollment_id)
+        REFERENCES enrollments(id)
+        ON DELETE CASCADE
+);
+
+-- Create index on enrollment_id and changed_at for history lookups
+CREATE INDEX IF NOT EXISTS idx_enrollment_status_changes_enrollment ON enrollment_status_changes(enrollment_id, changed_at);
@@ -0,0 +1,47 @@
+-- Create prerequisites join table: course_id requires prerequisite_id to be completed first
+CREATE TABLE IF NOT EXISTS course_prerequisites (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL,
+    prerequisite_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign key constraints
+    CONSTRAINT fk_course_prerequisites_course_id
+        FOREIGN KEY (course_id)
+        REFERENCES courses(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_course_prerequisites_prerequisite_id
+        FOREIGN KEY (prerequisite_id)
+        REFERENCES courses(id)
+        ON DELETE CASCADE,
+
+    -- Each edge is stored once and a course cannot require itself;
+    -- longer cycles are rejected by the application
+    CONSTRAINT unique_course_prerequisite
+        UNIQUE (course_id, prerequisite_id),
+    CONSTRAINT check_course_prerequisite_not_self
+        CHECK (course_id <> prerequisite_id)
+);
+
+-- Create index on prerequisite_id for reverse lookups
+CREATE INDEX IF NOT EXISTS idx_course_prerequisites_prerequisite_id ON course_prerequisites(prerequisite_id);
+
+-- Create audit table for enrollments that skipped the prerequisite check
+CREATE TABLE IF NOT EXISTS prerequisite_overrides (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL,
+    student_email VARCHAR(255) NOT NULL,
+    missing_prerequisite_ids TEXT NOT NULL,
+    overridden_by VARCHAR(255) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign key constraint
+    CONSTRAINT fk_prerequisite_overrides_course_id
+        FOREIGN KEY (course_id)
+        REFERENCES courses(id)
+        ON DELETE CASCADE
+);
+
+-- Create index on course_id and created_at for audit lookups
+CREATE INDEX IF NOT EXISTS idx_prerequisite_overrides_course ON prerequisite_overrides(course_id, created_at);
@@ -0,0 +1,11 @@
+-- Add optional enrollment window to courses (NULL leaves that side of the window open)
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS enrollment_opens_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS enrollment_closes_at TIMESTAMP WITH TIME ZONE;
+
+-- Enrollment must close after it opens
+ALTER TABLE courses DROP CONSTRAINT IF EXISTS check_courses_enrollment_window;
+ALTER TABLE courses ADD CONSTRAINT check_courses_enrollment_window
+    CHECK (enrollment_opens_at IS NULL OR enrollment_closes_at IS NULL OR enrollment_closes_at > enrollment_opens_at);
+
+-- Create index for the open_for_enrollment filter
+CREATE INDEX IF NOT EXISTS idx_courses_enrollment_window ON courses(enrollment_opens_at, enrollment_closes_at);
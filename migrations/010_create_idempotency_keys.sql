@@ -0,0 +1,13 @@
+-- Create idempotency keys table, used to replay responses when Redis is disabled
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key VARCHAR(512) PRIMARY KEY,
+    fingerprint VARCHAR(64) NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    content_type VARCHAR(255),
+    response_body TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Create index on expires_at for clearing expired keys
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
@@ -0,0 +1,60 @@
+-- Create course modules table: ordered sections of a course
+CREATE TABLE IF NOT EXISTS course_modules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign key constraint
+    CONSTRAINT fk_course_modules_course_id
+        FOREIGN KEY (course_id)
+        REFERENCES courses(id)
+        ON DELETE CASCADE,
+    CONSTRAINT check_course_modules_position
+        CHECK (position > 0)
+);
+
+-- Create index on course_id and position for ordered lookups
+CREATE INDEX IF NOT EXISTS idx_course_modules_course_position ON course_modules(course_id, position);
+
+-- Create lessons table: ordered content inside a module
+CREATE TABLE IF NOT EXISTS lessons (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    module_id UUID NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    duration_minutes INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign key constraint
+    CONSTRAINT fk_lessons_module_id
+        FOREIGN KEY (module_id)
+        REFERENCES course_modules(id)
+        ON DELETE CASCADE,
+    CONSTRAINT check_lessons_duration_minutes
+        CHECK (duration_minutes > 0),
+    CONSTRAINT check_lessons_position
+        CHECK (position > 0)
+);
+
+-- Create index on module_id and position for ordered lookups
+CREATE INDEX IF NOT EXISTS idx_lessons_module_position ON lessons(module_id, position);
+
+-- Create trigger to automatically update updated_at on course_modules
+DROP TRIGGER IF EXISTS update_course_modules_updated_at ON course_modules;
+CREATE TRIGGER update_course_modules_updated_at
+    BEFORE UPDATE ON course_modules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create trigger to automatically update updated_at on lessons
+DROP TRIGGER IF EXISTS update_lessons_updated_at ON lessons;
+CREATE TRIGGER update_lessons_updated_at
+    BEFORE UPDATE ON lessons
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
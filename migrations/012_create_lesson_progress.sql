@@ -0,0 +1,41 @@
+-- Mark lessons that do not count towards completing a course
+ALTER TABLE lessons ADD COLUMN IF NOT EXISTS optional BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Create lesson progress table: what a student has done in each lesson of an enrollment
+CREATE TABLE IF NOT EXISTS lesson_progress (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    enrollment_id UUID NOT NULL,
+    lesson_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'started',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign key constraints
+    CONSTRAINT fk_lesson_progress_enrollment_id
+        FOREIGN KEY (enrollment_id)
+        REFERENCES enrollments(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_lesson_progress_lesson_id
+        FOREIGN KEY (lesson_id)
+        REFERENCES lessons(id)
+        ON DELETE CASCADE,
+
+    -- A lesson is tracked once per enrollment
+    CONSTRAINT unique_lesson_progress_enrollment_lesson
+        UNIQUE (enrollment_id, lesson_id),
+    CONSTRAINT check_lesson_progress_status
+        CHECK (status IN ('started', 'completed')),
+    CONSTRAINT check_lesson_progress_completed_at
+        CHECK ((status = 'completed') = (completed_at IS NOT NULL))
+);
+
+-- Create index on lesson_id for cascading lesson deletes
+CREATE INDEX IF NOT EXISTS idx_lesson_progress_lesson_id ON lesson_progress(lesson_id);
+
+-- Create trigger to automatically update updated_at on lesson_progress
+DROP TRIGGER IF EXISTS update_lesson_progress_updated_at ON lesson_progress;
+CREATE TRIGGER update_lesson_progress_updated_at
+    BEFORE UPDATE ON lesson_progress
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,93 @@
+-- Create terms table: periods of the academic calendar, such as semesters
+CREATE TABLE IF NOT EXISTS terms (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_terms_name
+        UNIQUE (name),
+    CONSTRAINT check_terms_dates
+        CHECK (end_date >= start_date)
+);
+
+-- Create course offerings table: a run of a course in a term with its own capacity
+-- and schedule. The offering without a term is the course's default offering.
+CREATE TABLE IF NOT EXISTS course_offerings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL,
+    term_id UUID,
+    capacity INTEGER CHECK (capacity IS NULL OR capacity >= 0),
+    schedule TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign key constraints
+    CONSTRAINT fk_course_offerings_course_id
+        FOREIGN KEY (course_id)
+        REFERENCES courses(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_course_offerings_term_id
+        FOREIGN KEY (term_id)
+        REFERENCES terms(id),
+
+    -- A course is offered at most once per term
+    CONSTRAINT unique_course_offerings_course_term
+        UNIQUE (course_id, term_id)
+);
+
+-- A course has at most one default offering
+CREATE UNIQUE INDEX IF NOT EXISTS idx_course_offerings_default ON course_offerings(course_id) WHERE term_id IS NULL;
+
+-- Create index on term_id for listing by term
+CREATE INDEX IF NOT EXISTS idx_course_offerings_term_id ON course_offerings(term_id);
+
+-- Give every existing course its default offering
+INSERT INTO course_offerings (course_id)
+SELECT c.id FROM courses c
+WHERE NOT EXISTS (
+    SELECT 1 FROM course_offerings o WHERE o.course_id = c.id AND o.term_id IS NULL
+);
+
+-- Attach enrollments to an offering, moving existing ones onto the default offering
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS offering_id UUID;
+UPDATE enrollments e SET offering_id = o.id
+FROM course_offerings o
+WHERE o.course_id = e.course_id AND o.term_id IS NULL AND e.offering_id IS NULL;
+ALTER TABLE enrollments ALTER COLUMN offering_id SET NOT NULL;
+
+ALTER TABLE enrollments DROP CONSTRAINT IF EXISTS fk_enrollments_offering_id;
+ALTER TABLE enrollments ADD CONSTRAINT fk_enrollments_offering_id
+    FOREIGN KEY (offering_id)
+    REFERENCES course_offerings(id);
+
+CREATE INDEX IF NOT EXISTS idx_enrollments_offering_id ON enrollments(offering_id);
+
+-- Waitlist entries wait for a seat in a specific offering
+ALTER TABLE waitlist_entries ADD COLUMN IF NOT EXISTS offering_id UUID;
+UPDATE waitlist_entries w SET offering_id = o.id
+FROM course_offerings o
+WHERE o.course_id = w.course_id AND o.term_id IS NULL AND w.offering_id IS NULL;
+ALTER TABLE waitlist_entries ALTER COLUMN offering_id SET NOT NULL;
+
+ALTER TABLE waitlist_entries DROP CONSTRAINT IF EXISTS fk_waitlist_entries_offering_id;
+ALTER TABLE waitlist_entries ADD CONSTRAINT fk_waitlist_entries_offering_id
+    FOREIGN KEY (offering_id)
+    REFERENCES course_offerings(id);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_offering_id ON waitlist_entries(offering_id);
+
+-- Create triggers to automatically update updated_at
+DROP TRIGGER IF EXISTS update_terms_updated_at ON terms;
+CREATE TRIGGER update_terms_updated_at
+    BEFORE UPDATE ON terms
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_course_offerings_updated_at ON course_offerings;
+CREATE TRIGGER update_course_offerings_updated_at
+    BEFORE UPDATE ON course_offerings
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,77 @@
+-- Create course sections table: class sections of a course offering
+CREATE TABLE IF NOT EXISTS course_sections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL,
+    offering_id UUID NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign key constraints
+    CONSTRAINT fk_course_sections_course_id
+        FOREIGN KEY (course_id)
+        REFERENCES courses(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_course_sections_offering_id
+        FOREIGN KEY (offering_id)
+        REFERENCES course_offerings(id)
+        ON DELETE CASCADE,
+
+    -- Section names are unique within an offering
+    CONSTRAINT unique_course_sections_offering_name
+        UNIQUE (offering_id, name)
+);
+
+-- Create index on course_id for listing the sections of a course
+CREATE INDEX IF NOT EXISTS idx_course_sections_course_id ON course_sections(course_id);
+
+-- Create section meetings table: weekly meeting slots in the section's time zone
+CREATE TABLE IF NOT EXISTS section_meetings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    section_id UUID NOT NULL,
+    day VARCHAR(9) NOT NULL,
+    start_time VARCHAR(5) NOT NULL,
+    end_time VARCHAR(5) NOT NULL,
+    location VARCHAR(255),
+    time_zone VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign key constraint
+    CONSTRAINT fk_section_meetings_section_id
+        FOREIGN KEY (section_id)
+        REFERENCES course_sections(id)
+        ON DELETE CASCADE,
+    CONSTRAINT check_section_meetings_day
+        CHECK (day IN ('monday', 'tuesday', 'wednesday', 'thursday', 'friday', 'saturday', 'sunday')),
+    CONSTRAINT check_section_meetings_times
+        CHECK (start_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'
+           AND end_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'
+           AND end_time > start_time)
+);
+
+-- Create index on section_id for loading meetings
+CREATE INDEX IF NOT EXISTS idx_section_meetings_section_id ON section_meetings(section_id);
+
+-- Enrollments and waitlist entries may name the section the student attends
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS section_id UUID;
+ALTER TABLE enrollments DROP CONSTRAINT IF EXISTS fk_enrollments_section_id;
+ALTER TABLE enrollments ADD CONSTRAINT fk_enrollments_section_id
+    FOREIGN KEY (section_id)
+    REFERENCES course_sections(id)
+    ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_enrollments_section_id ON enrollments(section_id);
+
+ALTER TABLE waitlist_entries ADD COLUMN IF NOT EXISTS section_id UUID;
+ALTER TABLE waitlist_entries DROP CONSTRAINT IF EXISTS fk_waitlist_entries_section_id;
+ALTER TABLE waitlist_entries ADD CONSTRAINT fk_waitlist_entries_section_id
+    FOREIGN KEY (section_id)
+    REFERENCES course_sections(id)
+    ON DELETE SET NULL;
+
+-- Create trigger to automatically update updated_at on course_sections
+DROP TRIGGER IF EXISTS update_course_sections_updated_at ON course_sections;
+CREATE TRIGGER update_course_sections_updated_at
+    BEFORE UPDATE ON course_sections
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,46 @@
+-- Create students table: the profile behind the student_email of enrollments
+CREATE TABLE IF NOT EXISTS students (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL,
+    first_name VARCHAR(100),
+    last_name VARCHAR(100),
+    student_number VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_students_email
+        UNIQUE (email),
+    CONSTRAINT unique_students_student_number
+        UNIQUE (student_number)
+);
+
+-- Create a student for every email that has enrolled or joined a waitlist
+INSERT INTO students (email, created_at)
+SELECT student_email, MIN(created_at) FROM (
+    SELECT student_email, created_at FROM enrollments
+    UNION ALL
+    SELECT student_email, created_at FROM waitlist_entries
+) known
+GROUP BY student_email
+ON CONFLICT (email) DO NOTHING;
+
+-- Attach enrollments to their student
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS student_id UUID;
+UPDATE enrollments e SET student_id = s.id
+FROM students s
+WHERE s.email = e.student_email AND e.student_id IS NULL;
+ALTER TABLE enrollments ALTER COLUMN student_id SET NOT NULL;
+
+ALTER TABLE enrollments DROP CONSTRAINT IF EXISTS fk_enrollments_student_id;
+ALTER TABLE enrollments ADD CONSTRAINT fk_enrollments_student_id
+    FOREIGN KEY (student_id)
+    REFERENCES students(id);
+
+CREATE INDEX IF NOT EXISTS idx_enrollments_student_id ON enrollments(student_id);
+
+-- Create trigger to automatically update updated_at
+DROP TRIGGER IF EXISTS update_students_updated_at ON students;
+CREATE TRIGGER update_students_updated_at
+    BEFORE UPDATE ON students
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
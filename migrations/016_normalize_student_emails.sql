@@ -0,0 +1,111 @@
+-- Normalize student emails: trimmed and lower case everywhere, so that
+-- Alice@Example.com and alice@example.com are the same student
+
+-- Keep one enrollment per course for emails that only differ in case: the one
+-- furthest along (completed, then active, pending, dropped, withdrawn), earliest first
+DELETE FROM enrollments
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY LOWER(TRIM(student_email)), course_id
+            ORDER BY CASE status
+                    WHEN 'completed' THEN 0
+                    WHEN 'active' THEN 1
+                    WHEN 'pending' THEN 2
+                    WHEN 'dropped' THEN 3
+                    ELSE 4
+                END,
+                enrolled_at ASC,
+                id ASC
+        ) AS rank
+        FROM enrollments
+    ) ranked
+    WHERE rank > 1
+);
+
+-- Keep the best waitlist position of emails that only differ in case
+DELETE FROM waitlist_entries
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY LOWER(TRIM(student_email)), course_id
+            ORDER BY position ASC
+        ) AS rank
+        FROM waitlist_entries
+    ) ranked
+    WHERE rank > 1
+);
+
+-- Close the gaps the removed entries left in the waitlists
+UPDATE waitlist_entries w SET position = ranked.new_position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY course_id ORDER BY position ASC) AS new_position
+    FROM waitlist_entries
+) ranked
+WHERE w.id = ranked.id AND w.position <> ranked.new_position;
+
+UPDATE enrollments SET student_email = LOWER(TRIM(student_email))
+WHERE student_email <> LOWER(TRIM(student_email));
+UPDATE waitlist_entries SET student_email = LOWER(TRIM(student_email))
+WHERE student_email <> LOWER(TRIM(student_email));
+UPDATE prerequisite_overrides SET student_email = LOWER(TRIM(student_email))
+WHERE student_email <> LOWER(TRIM(student_email));
+
+-- Merge students whose emails only differ in case into the earliest created one
+WITH survivors AS (
+    SELECT id, FIRST_VALUE(id) OVER (
+        PARTITION BY LOWER(TRIM(email))
+        ORDER BY created_at ASC, id ASC
+    ) AS survivor_id
+    FROM students
+)
+UPDATE enrollments e SET student_id = s.survivor_id
+FROM survivors s
+WHERE e.student_id = s.id AND s.id <> s.survivor_id;
+
+DELETE FROM students
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY LOWER(TRIM(email))
+            ORDER BY created_at ASC, id ASC
+        ) AS rank
+        FROM students
+    ) ranked
+    WHERE rank > 1
+);
+
+UPDATE students SET email = LOWER(TRIM(email))
+WHERE email <> LOWER(TRIM(email));
+
+ALTER TABLE students DROP CONSTRAINT IF EXISTS check_students_email_normalized;
+ALTER TABLE students ADD CONSTRAINT check_students_email_normalized
+    CHECK (email = LOWER(TRIM(email)));
+
+ALTER TABLE enrollments DROP CONSTRAINT IF EXISTS check_enrollments_student_email_normalized;
+ALTER TABLE enrollments ADD CONSTRAINT check_enrollments_student_email_normalized
+    CHECK (student_email = LOWER(TRIM(student_email)));
+
+-- Create student_merges table: the audit trail of admin student merges
+CREATE TABLE IF NOT EXISTS student_merges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_student_id UUID NOT NULL,
+    source_email VARCHAR(255) NOT NULL,
+    target_student_id UUID NOT NULL,
+    target_email VARCHAR(255) NOT NULL,
+    moved_enrollments INTEGER NOT NULL DEFAULT 0,
+    discarded_enrollment_ids TEXT NOT NULL DEFAULT '',
+    moved_waitlist_entries INTEGER NOT NULL DEFAULT 0,
+    discarded_waitlist_entries INTEGER NOT NULL DEFAULT 0,
+    merged_by VARCHAR(255) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    -- The source student no longer exists, so only the target is a foreign key
+    CONSTRAINT fk_student_merges_target_student_id
+        FOREIGN KEY (target_student_id)
+        REFERENCES students(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_student_merges_target ON student_merges(target_student_id, created_at);
@@ -0,0 +1,19 @@
+-- Link user accounts to students: accounts with the user role are students
+-- signing in to manage their own enrollments
+ALTER TABLE users ADD COLUMN IF NOT EXISTS student_id UUID;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_student_id;
+ALTER TABLE users ADD CONSTRAINT fk_users_student_id
+    FOREIGN KEY (student_id)
+    REFERENCES students(id)
+    ON DELETE CASCADE;
+
+-- A student has at most one account
+ALTER TABLE users DROP CONSTRAINT IF EXISTS unique_users_student_id;
+ALTER TABLE users ADD CONSTRAINT unique_users_student_id
+    UNIQUE (student_id);
+
+-- Student accounts must be linked to a student
+ALTER TABLE users DROP CONSTRAINT IF EXISTS check_users_student_account;
+ALTER TABLE users ADD CONSTRAINT check_users_student_account
+    CHECK (role <> 'user' OR student_id IS NOT NULL);
@@ -0,0 +1,69 @@
+-- Create categories table: a hierarchy of course categories, e.g.
+-- Programming > Go. Slugs are unique across the tree and used in course filters.
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) NOT NULL,
+    description TEXT,
+    parent_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    -- A category with subcategories cannot be deleted
+    CONSTRAINT fk_categories_parent_id
+        FOREIGN KEY (parent_id)
+        REFERENCES categories(id),
+    CONSTRAINT unique_categories_slug
+        UNIQUE (slug),
+    CONSTRAINT check_categories_not_own_parent
+        CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+
+-- Create tags table: free-form course labels, stored trimmed and lower case
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_tags_name
+        UNIQUE (name),
+    CONSTRAINT check_tags_name_normalized
+        CHECK (name = LOWER(TRIM(name)))
+);
+
+-- A course can be in many categories and have many tags
+CREATE TABLE IF NOT EXISTS course_categories (
+    course_id UUID NOT NULL,
+    category_id UUID NOT NULL,
+
+    PRIMARY KEY (course_id, category_id),
+    CONSTRAINT fk_course_categories_course_id
+        FOREIGN KEY (course_id)
+        REFERENCES courses(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_course_categories_category_id
+        FOREIGN KEY (category_id)
+        REFERENCES categories(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_categories_category_id ON course_categories(category_id);
+
+CREATE TABLE IF NOT EXISTS course_tags (
+    course_id UUID NOT NULL,
+    tag_id UUID NOT NULL,
+
+    PRIMARY KEY (course_id, tag_id),
+    CONSTRAINT fk_course_tags_course_id
+        FOREIGN KEY (course_id)
+        REFERENCES courses(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_course_tags_tag_id
+        FOREIGN KEY (tag_id)
+        REFERENCES tags(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_tags_tag_id ON course_tags(tag_id);
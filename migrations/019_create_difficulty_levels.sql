@@ -0,0 +1,35 @@
+-- Create difficulty_levels table: the difficulty levels courses can have, managed
+-- by admins instead of a fixed list. sort_order gives the order from easiest to hardest.
+CREATE TABLE IF NOT EXISTS difficulty_levels (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(50) NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_difficulty_levels_name
+        UNIQUE (name)
+);
+
+-- Names are also unique ignoring case, so Beginner and beginner cannot both exist
+CREATE UNIQUE INDEX IF NOT EXISTS idx_difficulty_levels_name_lower ON difficulty_levels(LOWER(name));
+
+-- Seeded only while the table is empty, so levels admins renamed or deleted
+-- stay that way across restarts
+INSERT INTO difficulty_levels (name, sort_order, description)
+SELECT seed.name, seed.sort_order, seed.description FROM (VALUES
+    ('Beginner', 1, 'No prior knowledge of the subject needed'),
+    ('Intermediate', 2, 'Builds on the basics of the subject'),
+    ('Advanced', 3, 'For students who are comfortable with the subject')
+) AS seed(name, sort_order, description)
+WHERE NOT EXISTS (SELECT 1 FROM difficulty_levels);
+
+-- Courses now reference a stored level instead of the fixed list. Renaming a
+-- level renames it on its courses; a level in use cannot be deleted.
+ALTER TABLE courses DROP CONSTRAINT IF EXISTS courses_difficulty_check;
+ALTER TABLE courses DROP CONSTRAINT IF EXISTS fk_courses_difficulty;
+ALTER TABLE courses ADD CONSTRAINT fk_courses_difficulty
+    FOREIGN KEY (difficulty)
+    REFERENCES difficulty_levels(name)
+    ON UPDATE CASCADE;
@@ -0,0 +1,53 @@
+-- Create instructors table: the people teaching courses. An instructor may have
+-- an account to manage the courses they teach.
+CREATE TABLE IF NOT EXISTS instructors (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    bio TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_instructors_email
+        UNIQUE (email),
+    CONSTRAINT check_instructors_email_normalized
+        CHECK (email = LOWER(TRIM(email)))
+);
+
+-- A course can have several instructors and an instructor several courses
+CREATE TABLE IF NOT EXISTS course_instructors (
+    course_id UUID NOT NULL,
+    instructor_id UUID NOT NULL,
+
+    PRIMARY KEY (course_id, instructor_id),
+    CONSTRAINT fk_course_instructors_course_id
+        FOREIGN KEY (course_id)
+        REFERENCES courses(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_course_instructors_instructor_id
+        FOREIGN KEY (instructor_id)
+        REFERENCES instructors(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_instructors_instructor_id ON course_instructors(instructor_id);
+
+-- Link user accounts to instructors: accounts with the instructor role manage
+-- the courses of their instructor
+ALTER TABLE users ADD COLUMN IF NOT EXISTS instructor_id UUID;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_instructor_id;
+ALTER TABLE users ADD CONSTRAINT fk_users_instructor_id
+    FOREIGN KEY (instructor_id)
+    REFERENCES instructors(id)
+    ON DELETE CASCADE;
+
+-- An instructor has at most one account
+ALTER TABLE users DROP CONSTRAINT IF EXISTS unique_users_instructor_id;
+ALTER TABLE users ADD CONSTRAINT unique_users_instructor_id
+    UNIQUE (instructor_id);
+
+-- Instructor accounts must be linked to an instructor
+ALTER TABLE users DROP CONSTRAINT IF EXISTS check_users_instructor_account;
+ALTER TABLE users ADD CONSTRAINT check_users_instructor_account
+    CHECK (role <> 'instructor' OR instructor_id IS NOT NULL);
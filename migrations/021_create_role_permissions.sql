@@ -0,0 +1,60 @@
+-- Create role_permissions table: the named permissions each role holds, such as
+-- course:write or enrollment:delete. Super-admins hold every permission and have
+-- no rows here; they edit the permissions of the other roles.
+
+-- Defaults that are seeded once, whatever happens to the seeded rows afterwards
+CREATE TABLE IF NOT EXISTS seeded_defaults (
+    name VARCHAR(100) PRIMARY KEY,
+    seeded_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Databases that already have role_permissions were seeded when it was created
+INSERT INTO seeded_defaults (name)
+SELECT 'role_permissions' WHERE to_regclass('role_permissions') IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(50) NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (role, permission)
+);
+
+-- Admins keep everything they could do before; instructors manage the content,
+-- enrollments and waitlists of the courses they teach. Seeded only once, as
+-- recorded in seeded_defaults, so edits made by super-admins survive restarts,
+-- even taking every permission away.
+WITH seeded AS (
+    INSERT INTO seeded_defaults (name) VALUES ('role_permissions')
+    ON CONFLICT DO NOTHING
+    RETURNING name
+)
+INSERT INTO role_permissions (role, permission)
+SELECT seed.role, seed.permission FROM (VALUES
+    ('admin', 'course:create'),
+    ('admin', 'course:write'),
+    ('admin', 'course:delete'),
+    ('admin', 'course:assign'),
+    ('admin', 'prerequisite:write'),
+    ('admin', 'schedule:write'),
+    ('admin', 'catalog:write'),
+    ('admin', 'enrollment:read'),
+    ('admin', 'enrollment:write'),
+    ('admin', 'enrollment:delete'),
+    ('admin', 'student:read'),
+    ('admin', 'student:write'),
+    ('admin', 'student:delete'),
+    ('admin', 'instructor:read'),
+    ('admin', 'instructor:write'),
+    ('instructor', 'course:write'),
+    ('instructor', 'enrollment:read'),
+    ('instructor', 'enrollment:write'),
+    ('instructor', 'enrollment:delete')
+) AS seed(role, permission)
+WHERE EXISTS (SELECT 1 FROM seeded);
+
+-- The default admin becomes the first super-admin, unless there already is one
+UPDATE users SET role = 'super_admin'
+WHERE username = 'admin' AND role = 'admin'
+    AND NOT EXISTS (SELECT 1 FROM users WHERE role = 'super_admin');
@@ -0,0 +1,2 @@
+-- Disabled users keep their account but can no longer log in
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMP WITH TIME ZONE;
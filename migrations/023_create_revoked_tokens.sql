@@ -0,0 +1,10 @@
+-- Create revoked_tokens table: the token denylist used when Redis is disabled.
+-- id is the jti of a revoked token or the sid of a revoked session.
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on expires_at for clearing expired entries
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
@@ -0,0 +1,25 @@
+-- Create api_keys table: keys other systems send in the X-API-Key header to act
+-- for their owner, restricted to their scopes. Only a SHA-256 hash of each key
+-- is stored; prefix keeps its first characters to tell keys apart.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on owner_id for finding the keys of a user
+CREATE INDEX IF NOT EXISTS idx_api_keys_owner_id ON api_keys(owner_id);
+
+-- Create api_key_scopes table: the permissions a key may use. A key never has
+-- more permissions than the role of its owner.
+CREATE TABLE IF NOT EXISTS api_key_scopes (
+    api_key_id UUID NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL,
+
+    PRIMARY KEY (api_key_id, permission)
+);
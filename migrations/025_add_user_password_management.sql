@@ -0,0 +1,14 @@
+-- Users can be made to change their password before using the API, and admins
+-- can issue single-use tokens to reset a password
+ALTER TABLE users ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_reset_hash VARCHAR(64);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_reset_expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_password_reset_hash ON users(password_reset_hash);
+
+-- The admin migration 004 used to create still has the published password
+-- 'admin!dev' until it is changed
+UPDATE users SET must_change_password = TRUE
+WHERE password = '$2a$10$V6C81VGFyKg/sRc1JOw8cOs7dV/3StzYs5NUZaYvDFcEEKW0Tlika'
+    AND NOT must_change_password;
@@ -0,0 +1,6 @@
+-- Admins can invite an existing student to register an account for their
+-- student record with a single-use token
+ALTER TABLE students ADD COLUMN IF NOT EXISTS invite_hash VARCHAR(64);
+ALTER TABLE students ADD COLUMN IF NOT EXISTS invite_expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_students_invite_hash ON students(invite_hash);
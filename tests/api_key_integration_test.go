@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// createTestAPIKey is a helper function to create an API key owned by the user
+func (suite *IntegrationTestSuite) createTestAPIKey(ownerID uuid.UUID, scopes ...string) models.CreateAPIKeyResponse {
+	recorder := suite.makeRequest("POST", "/api/v1/admin/api-keys", models.CreateAPIKeyRequest{
+		Name:    "Nightly enrollment sync",
+		OwnerID: &ownerID,
+		Scopes:  scopes,
+	}, suite.getSuperAdminHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	var key models.CreateAPIKeyResponse
+	suite.parseResponse(recorder, &key)
+	return key
+}
+
+// TestAPIKeyLifecycle tests creating an API key, calling the API with it within
+// its scopes and revoking it
+func (suite *IntegrationTestSuite) TestAPIKeyLifecycle() {
+	headers := suite.getSuperAdminHeaders()
+	batch := suite.createTestUser("batch", constants.RoleAdmin)
+
+	key := suite.createTestAPIKey(batch.ID, " enrollment:write ", constants.PermissionEnrollmentWrite)
+	suite.True(strings.HasPrefix(key.Key, constants.APIKeyPrefix))
+	suite.Equal(key.Key[:constants.APIKeyDisplayLength], key.Prefix)
+	suite.Equal(batch.ID, key.OwnerID)
+	suite.Equal("batch", key.OwnerUsername)
+	suite.Equal([]string{constants.PermissionEnrollmentWrite}, key.Scopes)
+	suite.Nil(key.LastUsedAt)
+
+	var stored models.APIKey
+	suite.Require().NoError(suite.db.First(&stored, "id = ?", key.ID).Error)
+	suite.NotContains(stored.KeyHash, key.Key)
+	suite.Len(stored.KeyHash, 64)
+
+	// The key can enroll students, and nothing else its owner can do
+	keyHeaders := map[string]string{"X-API-Key": key.Key}
+	course := suite.createTestCourse("Go Programming", "Learn Go", "Beginner")
+	recorder := suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     course.ID,
+	}, keyHeaders)
+	suite.Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	recorder = suite.makeRequest("GET", "/api/v1/admin/students", nil, keyHeaders)
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "Permission student:read is required")
+	recorder = suite.makeRequest("GET", "/api/v1/students/student@example.com/enrollments", nil, keyHeaders)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+
+	recorder = suite.makeRequest("GET", "/api/v1/auth/profile", nil, keyHeaders)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var profile models.UserResponse
+	suite.parseResponse(recorder, &profile)
+	suite.Equal("batch", profile.Username)
+
+	recorder = suite.makeRequest("POST", "/api/v1/auth/logout", nil, keyHeaders)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Only access tokens can be logged out")
+
+	// Listing shows when the key was last used, but never the key
+	recorder = suite.makeRequest("GET", "/api/v1/admin/api-keys", nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	suite.NotContains(recorder.Body.String(), key.Key)
+	var list models.APIKeyListResponse
+	suite.parseResponse(recorder, &list)
+	suite.Require().Equal(1, list.Total)
+	suite.Equal(key.ID, list.APIKeys[0].ID)
+	suite.NotNil(list.APIKeys[0].LastUsedAt)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/admin/api-keys/%s", key.ID), nil, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/admin/api-keys/%s", key.ID), nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+	recorder = suite.makeRequest("GET", "/api/v1/auth/profile", nil, keyHeaders)
+	suite.assertErrorResponse(recorder, http.StatusUnauthorized, constants.MsgAPIKeyInvalid)
+
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/admin/api-keys/%s", key.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "API key not found")
+}
+
+// TestAPIKeyValidation tests the checks on new API keys and who may manage them
+func (suite *IntegrationTestSuite) TestAPIKeyValidation() {
+	headers := suite.getSuperAdminHeaders()
+	batch := suite.createTestUser("batch", constants.RoleAdmin)
+	past := time.Now().Add(-time.Hour)
+	missing := uuid.New()
+
+	cases := []struct {
+		req     models.CreateAPIKeyRequest
+		message string
+	}{
+		{models.CreateAPIKeyRequest{Name: " ", OwnerID: &batch.ID, Scopes: []string{"enrollment:write"}}, "Name is required"},
+		{models.CreateAPIKeyRequest{Name: "sync", OwnerID: &batch.ID, Scopes: []string{" "}}, "Unknown permission"},
+		{models.CreateAPIKeyRequest{Name: "sync", OwnerID: &batch.ID}, "At least one scope is required"},
+		{models.CreateAPIKeyRequest{Name: "sync", OwnerID: &batch.ID, Scopes: []string{"enrollment:approve"}}, "Unknown permission"},
+		{models.CreateAPIKeyRequest{Name: "sync", OwnerID: &batch.ID, Scopes: []string{"user:manage"}}, "owner's role holds"},
+		{models.CreateAPIKeyRequest{Name: "sync", OwnerID: &missing, Scopes: []string{"enrollment:write"}}, "Owner not found"},
+		{models.CreateAPIKeyRequest{Name: "sync", OwnerID: &batch.ID, Scopes: []string{"enrollment:write"}, ExpiresAt: &past}, "Expiry must be in the future"},
+	}
+	for _, tc := range cases {
+		recorder := suite.makeRequest("POST", "/api/v1/admin/api-keys", tc.req, headers)
+		suite.assertErrorResponse(recorder, http.StatusBadRequest, tc.message)
+	}
+
+	// Without an owner the key belongs to its creator, who may give it any scope
+	recorder := suite.makeRequest("POST", "/api/v1/admin/api-keys", models.CreateAPIKeyRequest{
+		Name:   "Account audit",
+		Scopes: []string{constants.PermissionUserManage},
+	}, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+	var key models.CreateAPIKeyResponse
+	suite.parseResponse(recorder, &key)
+	suite.Equal(superAdminID, key.OwnerID.String())
+
+	recorder = suite.makeRequest("GET", "/api/v1/admin/users", nil, map[string]string{"X-API-Key": key.Key})
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	// Admins cannot manage keys, and keys that do not exist are rejected
+	recorder = suite.makeRequest("GET", "/api/v1/admin/api-keys", nil, suite.getAuthHeaders())
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "Permission apikey:manage is required")
+
+	for _, secret := range []string{"not-a-key", constants.APIKeyPrefix + "unknown"} {
+		recorder = suite.makeRequest("GET", "/api/v1/auth/profile", nil, map[string]string{"X-API-Key": secret})
+		suite.assertErrorResponse(recorder, http.StatusUnauthorized, constants.MsgAPIKeyInvalid)
+	}
+
+	recorder = suite.makeRequest("GET", "/api/v1/admin/api-keys/not-a-uuid", nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Invalid API key ID format")
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/admin/api-keys/%s", missing), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "API key not found")
+}
+
+// TestAPIKeyFollowsOwner tests that keys stop working when they expire or their
+// owner is disabled, and lose permissions their owner's role loses
+func (suite *IntegrationTestSuite) TestAPIKeyFollowsOwner() {
+	headers := suite.getSuperAdminHeaders()
+	batch := suite.createTestUser("batch", constants.RoleAdmin)
+	key := suite.createTestAPIKey(batch.ID, constants.PermissionStudentRead)
+	keyHeaders := map[string]string{"X-API-Key": key.Key}
+
+	recorder := suite.makeRequest("GET", "/api/v1/admin/students", nil, keyHeaders)
+	suite.Equal(http.StatusOK, recorder.Code)
+	recorder = suite.makeRequest("GET", "/api/v1/students/student@example.com/enrollments", nil, keyHeaders)
+	suite.NotEqual(http.StatusForbidden, recorder.Code)
+
+	permissions := []string{}
+	for _, permission := range constants.GrantablePermissions {
+		if permission != constants.PermissionStudentRead {
+			permissions = append(permissions, permission)
+		}
+	}
+	suite.setRolePermissions(constants.RoleAdmin, permissions...)
+	recorder = suite.makeRequest("GET", "/api/v1/admin/students", nil, keyHeaders)
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "Permission student:read is required")
+	suite.setRolePermissions(constants.RoleAdmin, constants.GrantablePermissions...)
+
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/users/%s/disable", batch.ID), nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	recorder = suite.makeRequest("GET", "/api/v1/admin/students", nil, keyHeaders)
+	suite.assertErrorResponse(recorder, http.StatusUnauthorized, constants.MsgAPIKeyInvalid)
+
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/users/%s/enable", batch.ID), nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	recorder = suite.makeRequest("GET", "/api/v1/admin/students", nil, keyHeaders)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	suite.Require().NoError(suite.db.Model(&models.APIKey{}).Where("id = ?", key.ID).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	recorder = suite.makeRequest("GET", "/api/v1/admin/students", nil, keyHeaders)
+	suite.assertErrorResponse(recorder, http.StatusUnauthorized, constants.MsgAPIKeyInvalid)
+}
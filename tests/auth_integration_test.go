@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"net/http"
+	"sync"
 
 	"sonic-labs/course-enrollment-service/internal/models"
 
@@ -186,3 +187,178 @@ func (suite *IntegrationTestSuite) getAuthToken() string {
 
 	return loginResp.Token
 }
+
+// loginAdmin is a helper function to log in as the test admin
+func (suite *IntegrationTestSuite) loginAdmin() models.LoginResponse {
+	resp := suite.makeRequest("POST", "/api/v1/auth/login", models.LoginRequest{
+		Username: "admin",
+		Password: "admin!dev",
+	}, nil)
+	suite.Require().Equal(http.StatusOK, resp.Code, resp.Body.String())
+
+	var loginResp models.LoginResponse
+	suite.parseResponse(resp, &loginResp)
+	return loginResp
+}
+
+// TestAuthRefresh tests exchanging a refresh token for a new token pair
+func (suite *IntegrationTestSuite) TestAuthRefresh() {
+	login := suite.loginAdmin()
+	suite.NotEmpty(login.RefreshToken)
+	suite.Equal(900, login.ExpiresIn)
+
+	resp := suite.makeRequest("POST", "/api/v1/auth/refresh", models.RefreshRequest{
+		RefreshToken: login.RefreshToken,
+	}, nil)
+	suite.Require().Equal(http.StatusOK, resp.Code, resp.Body.String())
+
+	var refreshed models.LoginResponse
+	suite.parseResponse(resp, &refreshed)
+	suite.NotEqual(login.Token, refreshed.Token)
+	suite.NotEqual(login.RefreshToken, refreshed.RefreshToken)
+	suite.Equal("admin", refreshed.User.Username)
+
+	resp = suite.makeRequest("GET", "/api/v1/auth/profile", nil, map[string]string{
+		"Authorization": "Bearer " + refreshed.Token,
+	})
+	suite.Equal(http.StatusOK, resp.Code)
+
+	// Access tokens cannot be used to refresh, nor refresh tokens to call the API
+	resp = suite.makeRequest("POST", "/api/v1/auth/refresh", models.RefreshRequest{
+		RefreshToken: refreshed.Token,
+	}, nil)
+	suite.assertErrorResponse(resp, http.StatusUnauthorized, "Refresh token is invalid")
+
+	resp = suite.makeRequest("GET", "/api/v1/auth/profile", nil, map[string]string{
+		"Authorization": "Bearer " + refreshed.RefreshToken,
+	})
+	suite.assertErrorResponse(resp, http.StatusUnauthorized, "JWT token is invalid or expired")
+
+	resp = suite.makeRequest("POST", "/api/v1/auth/refresh", models.RefreshRequest{}, nil)
+	suite.assertErrorResponse(resp, http.StatusBadRequest, "Refresh token is required")
+}
+
+// TestAuthRefreshReuse tests that using a refresh token twice ends its session
+func (suite *IntegrationTestSuite) TestAuthRefreshReuse() {
+	login := suite.loginAdmin()
+
+	resp := suite.makeRequest("POST", "/api/v1/auth/refresh", models.RefreshRequest{
+		RefreshToken: login.RefreshToken,
+	}, nil)
+	suite.Require().Equal(http.StatusOK, resp.Code, resp.Body.String())
+	var refreshed models.LoginResponse
+	suite.parseResponse(resp, &refreshed)
+
+	resp = suite.makeRequest("POST", "/api/v1/auth/refresh", models.RefreshRequest{
+		RefreshToken: login.RefreshToken,
+	}, nil)
+	suite.assertErrorResponse(resp, http.StatusUnauthorized, "already used")
+
+	// The tokens issued by the first refresh belong to the revoked session too
+	resp = suite.makeRequest("GET", "/api/v1/auth/profile", nil, map[string]string{
+		"Authorization": "Bearer " + refreshed.Token,
+	})
+	suite.assertErrorResponse(resp, http.StatusUnauthorized, "revoked")
+
+	resp = suite.makeRequest("POST", "/api/v1/auth/refresh", models.RefreshRequest{
+		RefreshToken: refreshed.RefreshToken,
+	}, nil)
+	suite.Equal(http.StatusUnauthorized, resp.Code)
+
+	// Other sessions are unaffected
+	resp = suite.makeRequest("GET", "/api/v1/auth/profile", nil, suite.getAuthHeaders())
+	suite.Equal(http.StatusOK, resp.Code)
+}
+
+// TestAuthRefreshConcurrentReuse tests that of simultaneous refreshes with the
+// same refresh token one wins and the others end its session
+func (suite *IntegrationTestSuite) TestAuthRefreshConcurrentReuse() {
+	login := suite.loginAdmin()
+
+	codes := make([]int, 5)
+	bodies := make([][]byte, len(codes))
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			resp := suite.makeRequest("POST", "/api/v1/auth/refresh", models.RefreshRequest{
+				RefreshToken: login.RefreshToken,
+			}, nil)
+			codes[i], bodies[i] = resp.Code, resp.Body.Bytes()
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	var refreshed []models.LoginResponse
+	for i, code := range codes {
+		if code == http.StatusOK {
+			var tokens models.LoginResponse
+			suite.Require().NoError(json.Unmarshal(bodies[i], &tokens))
+			refreshed = append(refreshed, tokens)
+			continue
+		}
+		suite.Equal(http.StatusUnauthorized, code)
+	}
+	suite.Require().Len(refreshed, 1)
+
+	// The winner's tokens belong to the session the losers revoked
+	resp := suite.makeRequest("GET", "/api/v1/auth/profile", nil, map[string]string{
+		"Authorization": "Bearer " + refreshed[0].Token,
+	})
+	suite.assertErrorResponse(resp, http.StatusUnauthorized, "revoked")
+	resp = suite.makeRequest("POST", "/api/v1/auth/refresh", models.RefreshRequest{
+		RefreshToken: refreshed[0].RefreshToken,
+	}, nil)
+	suite.Equal(http.StatusUnauthorized, resp.Code)
+}
+
+// TestAuthLogout tests that logging out revokes the access and refresh tokens
+func (suite *IntegrationTestSuite) TestAuthLogout() {
+	login := suite.loginAdmin()
+	headers := map[string]string{"Authorization": "Bearer " + login.Token}
+
+	resp := suite.makeRequest("POST", "/api/v1/auth/logout", nil, headers)
+	suite.Require().Equal(http.StatusNoContent, resp.Code, resp.Body.String())
+
+	resp = suite.makeRequest("GET", "/api/v1/auth/profile", nil, headers)
+	suite.assertErrorResponse(resp, http.StatusUnauthorized, "revoked")
+
+	resp = suite.makeRequest("POST", "/api/v1/courses", models.CourseRequest{
+		Title:       "After Logout",
+		Description: "This should fail",
+		Difficulty:  "Beginner",
+	}, headers)
+	suite.Equal(http.StatusUnauthorized, resp.Code)
+
+	resp = suite.makeRequest("POST", "/api/v1/auth/refresh", models.RefreshRequest{
+		RefreshToken: login.RefreshToken,
+	}, nil)
+	suite.Equal(http.StatusUnauthorized, resp.Code)
+
+	resp = suite.makeRequest("POST", "/api/v1/auth/logout", nil, nil)
+	suite.Equal(http.StatusUnauthorized, resp.Code)
+}
+
+// TestAuthRefreshDisabledUser tests that disabled users cannot refresh their tokens
+func (suite *IntegrationTestSuite) TestAuthRefreshDisabledUser() {
+	registrar := suite.createTestUser("registrar", "admin")
+	resp := suite.makeRequest("POST", "/api/v1/auth/login", models.LoginRequest{
+		Username: "registrar",
+		Password: "correct horse battery",
+	}, nil)
+	suite.Require().Equal(http.StatusOK, resp.Code, resp.Body.String())
+	var login models.LoginResponse
+	suite.parseResponse(resp, &login)
+
+	resp = suite.makeRequest("POST", "/api/v1/admin/users/"+registrar.ID.String()+"/disable", nil, suite.getSuperAdminHeaders())
+	suite.Require().Equal(http.StatusOK, resp.Code, resp.Body.String())
+
+	resp = suite.makeRequest("POST", "/api/v1/auth/refresh", models.RefreshRequest{
+		RefreshToken: login.RefreshToken,
+	}, nil)
+	suite.assertErrorResponse(resp, http.StatusForbidden, "disabled")
+}
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+)
+
+// getDifficultyLevels is a helper function to list the difficulty levels
+func (suite *IntegrationTestSuite) getDifficultyLevels() models.DifficultyLevelListResponse {
+	recorder := suite.makeRequest("GET", "/api/v1/difficulty-levels", nil, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	var levels models.DifficultyLevelListResponse
+	suite.parseResponse(recorder, &levels)
+	return levels
+}
+
+// TestDifficultyLevelManagement tests creating, renaming and deleting difficulty levels
+func (suite *IntegrationTestSuite) TestDifficultyLevelManagement() {
+	headers := suite.getAuthHeaders()
+	levels := suite.getDifficultyLevels()
+	suite.Require().Equal(3, levels.Total)
+	suite.Equal("Beginner", levels.Levels[0].Name)
+	suite.Equal("Advanced", levels.Levels[2].Name)
+
+	recorder := suite.makeRequest("POST", "/api/v1/difficulty-levels", models.DifficultyLevelRequest{Name: " Expert "}, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+	var expert models.DifficultyLevelResponse
+	suite.parseResponse(recorder, &expert)
+	suite.Equal("Expert", expert.Name)
+	suite.Equal(4, expert.SortOrder)
+
+	recorder = suite.makeRequest("POST", "/api/v1/difficulty-levels", models.DifficultyLevelRequest{Name: "EXPERT"}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "already exists")
+	recorder = suite.makeRequest("POST", "/api/v1/difficulty-levels", models.DifficultyLevelRequest{Name: "Hacker"}, nil)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+
+	// Courses are validated against the stored levels, ignoring case
+	recorder = suite.makeRequest("POST", "/api/v1/courses", models.CourseRequest{
+		Title:       "Expert Course",
+		Description: "Test Description",
+		Difficulty:  "expert",
+	}, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+	var course models.CourseResponse
+	suite.parseResponse(recorder, &course)
+	suite.Equal("Expert", course.Difficulty)
+
+	recorder = suite.makeRequest("POST", "/api/v1/courses", models.CourseRequest{
+		Title:       "Unknown Level",
+		Description: "Test Description",
+		Difficulty:  "Wizard",
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Beginner, Intermediate, Advanced, Expert")
+
+	// Renaming a level renames it on its courses
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/difficulty-levels/%s", expert.ID), models.DifficultyLevelRequest{Name: "Master"}, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	var renamed models.DifficultyLevelResponse
+	suite.parseResponse(recorder, &renamed)
+	suite.Equal("Master", renamed.Name)
+	suite.Equal(4, renamed.SortOrder)
+	suite.Equal(1, renamed.CourseCount)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s", course.ID), nil, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var fetched models.CourseResponse
+	suite.parseResponse(recorder, &fetched)
+	suite.Equal("Master", fetched.Difficulty)
+
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/difficulty-levels/%s", expert.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "courses have it")
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/courses/%s", course.ID), nil, headers)
+	suite.Require().Equal(http.StatusNoContent, recorder.Code)
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/difficulty-levels/%s", expert.ID), nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+	suite.Equal(3, suite.getDifficultyLevels().Total)
+}
+
+// TestCoursesSortedByDifficulty tests that sorting by difficulty follows the
+// configured order of the levels rather than their names
+func (suite *IntegrationTestSuite) TestCoursesSortedByDifficulty() {
+	suite.createTestCourse("Advanced Course", "Description", "Advanced")
+	suite.createTestCourse("Beginner Course", "Description", "Beginner")
+	suite.createTestCourse("Intermediate Course", "Description", "Intermediate")
+
+	titles := func(query string) []string {
+		recorder := suite.makeRequest("GET", "/api/v1/courses?"+query, nil, nil)
+		suite.Require().Equal(http.StatusOK, recorder.Code)
+		var list models.CourseListResponse
+		suite.parseResponse(recorder, &list)
+		var result []string
+		for _, course := range list.Data {
+			result = append(result, course.Title)
+		}
+		return result
+	}
+
+	suite.Equal([]string{"Beginner Course", "Intermediate Course", "Advanced Course"}, titles("sort=difficulty"))
+	suite.Equal([]string{"Advanced Course", "Intermediate Course", "Beginner Course"}, titles("sort=-difficulty"))
+	suite.Equal([]string{"Beginner Course", "Intermediate Course"}, titles("sort=difficulty&difficulty=intermediate,BEGINNER"))
+	suite.Equal([]string{"Beginner Course"}, titles("sort=difficulty&difficulty=beginner,Nonexistent"))
+	suite.Empty(titles("difficulty=Nonexistent"))
+
+	// Moving Advanced to the front changes the order
+	var advanced models.DifficultyLevelResponse
+	for _, level := range suite.getDifficultyLevels().Levels {
+		if level.Name == "Advanced" {
+			advanced = level
+		}
+	}
+	sortOrder := 0
+	recorder := suite.makeRequest("PUT", fmt.Sprintf("/api/v1/difficulty-levels/%s", advanced.ID), models.DifficultyLevelRequest{
+		Name:      "Advanced",
+		SortOrder: &sortOrder,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	suite.Equal([]string{"Advanced Course", "Beginner Course", "Intermediate Course"}, titles("sort=difficulty"))
+	suite.Equal("Advanced", suite.getDifficultyLevels().Levels[0].Name)
+
+	recorder = suite.makeRequest("GET", "/api/v1/courses?sort=popularity", nil, nil)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Sort must be one of")
+}
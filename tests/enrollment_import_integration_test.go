@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+)
+
+// importCSV is a helper function to post a CSV body to the enrollment import endpoint
+func (suite *IntegrationTestSuite) importCSV(csv string, dryRun bool) *httptest.ResponseRecorder {
+	url := "/api/v1/admin/enrollments/import"
+	if dryRun {
+		url += "?dry_run=true"
+	}
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(csv))
+	suite.Require().NoError(err)
+	req.Header.Set("Content-Type", "text/csv")
+	for key, value := range suite.getAuthHeaders() {
+		req.Header.Set(key, value)
+	}
+
+	return suite.makeHTTPRequest(req)
+}
+
+// TestImportEnrollments tests that a real import enrolls valid rows and reports every row
+func (suite *IntegrationTestSuite) TestImportEnrollments() {
+	goCourse := suite.createTestCourse("Go Programming", "Learn Go", "Beginner")
+	small := suite.createTestCourseWithCapacity("Small Course", 1)
+
+	existing := models.Enrollment{StudentEmail: "existing@example.com", CourseID: goCourse.ID}
+	suite.Require().NoError(suite.db.Create(&existing).Error)
+
+	csv := fmt.Sprintf(`student_email,course
+alice@example.com,%s
+bob@example.com,go programming
+existing@example.com,%s
+not-an-email,%s
+carol@example.com,No Such Course
+dave@example.com,%s
+erin@example.com,%s
+alice@example.com,%s
+frank@example.com
+`, goCourse.ID, goCourse.ID, goCourse.ID, small.ID, small.ID, goCourse.ID)
+
+	recorder := suite.importCSV(csv, false)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var report models.EnrollmentImportReport
+	suite.parseResponse(recorder, &report)
+	suite.False(report.DryRun)
+	suite.Equal(9, report.Total)
+	suite.Require().Len(report.Rows, 9)
+
+	expected := []struct {
+		row    int
+		status string
+	}{
+		{2, "created"},
+		{3, "created"},
+		{4, "already_enrolled"},
+		{5, "invalid_email"},
+		{6, "unknown_course"},
+		{7, "created"},
+		{8, "waitlisted"},
+		{9, "already_enrolled"},
+		{10, "malformed"},
+	}
+	for i, want := range expected {
+		suite.Equal(want.row, report.Rows[i].Row)
+		suite.Equal(want.status, report.Rows[i].Status, "row %d", want.row)
+	}
+	suite.NotNil(report.Rows[0].EnrollmentID)
+	suite.Require().NotNil(report.Rows[1].CourseID)
+	suite.Equal(goCourse.ID, *report.Rows[1].CourseID)
+	suite.Contains(report.Rows[7].Message, "Duplicate of row 2")
+	suite.Equal(3, report.Summary["created"])
+	suite.Equal(2, report.Summary["already_enrolled"])
+
+	var count int64
+	suite.db.Model(&models.Enrollment{}).Where("course_id = ?", goCourse.ID).Count(&count)
+	suite.Equal(int64(3), count)
+	suite.db.Model(&models.WaitlistEntry{}).Where("course_id = ?", small.ID).Count(&count)
+	suite.Equal(int64(1), count)
+}
+
+// TestImportEnrollmentsDryRun tests that a dry run reports outcomes without writing anything
+func (suite *IntegrationTestSuite) TestImportEnrollmentsDryRun() {
+	small := suite.createTestCourseWithCapacity("Small Course", 1)
+
+	csv := fmt.Sprintf("alice@example.com,%s\nbob@example.com,%s\n", small.ID, small.ID)
+
+	recorder := suite.importCSV(csv, true)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var report models.EnrollmentImportReport
+	suite.parseResponse(recorder, &report)
+	suite.True(report.DryRun)
+	suite.Require().Len(report.Rows, 2)
+	suite.Equal(1, report.Rows[0].Row)
+	suite.Equal("created", report.Rows[0].Status)
+	suite.Nil(report.Rows[0].EnrollmentID)
+	suite.Equal("waitlisted", report.Rows[1].Status)
+
+	var count int64
+	suite.db.Model(&models.Enrollment{}).Count(&count)
+	suite.Equal(int64(0), count)
+	suite.db.Model(&models.WaitlistEntry{}).Count(&count)
+	suite.Equal(int64(0), count)
+}
+
+// TestImportEnrollmentsDryRunWaitlisted tests that a dry run reports a student
+// already on the waitlist of a full course the way the import does
+func (suite *IntegrationTestSuite) TestImportEnrollmentsDryRunWaitlisted() {
+	small := suite.createTestCourseWithCapacity("Small Course", 1)
+	suite.enrollTestStudent("alice@example.com", small.ID)
+	recorder := suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "bob@example.com",
+		CourseID:     small.ID,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusAccepted, recorder.Code)
+
+	csv := fmt.Sprintf("bob@example.com,%s\n", small.ID)
+	var reports [2]models.EnrollmentImportReport
+	for i, dryRun := range []bool{true, false} {
+		recorder = suite.importCSV(csv, dryRun)
+		suite.Require().Equal(http.StatusOK, recorder.Code)
+		suite.parseResponse(recorder, &reports[i])
+		suite.Require().Len(reports[i].Rows, 1)
+		suite.Equal("already_enrolled", reports[i].Rows[0].Status)
+	}
+	suite.Equal(reports[1].Rows[0].Message, reports[0].Rows[0].Message)
+}
+
+// TestImportEnrollmentsMultipart tests uploading the CSV as a multipart file
+func (suite *IntegrationTestSuite) TestImportEnrollmentsMultipart() {
+	course := suite.createTestCourse("Go Programming", "Learn Go", "Beginner")
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "enrollments.csv")
+	suite.Require().NoError(err)
+	_, err = fmt.Fprintf(part, "student_email,course_id\nalice@example.com,%s\n", course.ID)
+	suite.Require().NoError(err)
+	suite.Require().NoError(writer.Close())
+
+	req, err := http.NewRequest("POST", "/api/v1/admin/enrollments/import", &body)
+	suite.Require().NoError(err)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	for key, value := range suite.getAuthHeaders() {
+		req.Header.Set(key, value)
+	}
+
+	recorder := suite.makeHTTPRequest(req)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var report models.EnrollmentImportReport
+	suite.parseResponse(recorder, &report)
+	suite.Require().Len(report.Rows, 1)
+	suite.Equal("created", report.Rows[0].Status)
+}
+
+// TestImportEnrollmentsInvalidFile tests that unreadable or empty files are rejected
+func (suite *IntegrationTestSuite) TestImportEnrollmentsInvalidFile() {
+	recorder := suite.importCSV("student_email,course\n", false)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "no rows to import")
+
+	recorder = suite.importCSV("alice@example.com,\"unterminated\n", false)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "invalid import file")
+
+	recorder = suite.importCSV("alice@example.com,Go\n", false)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	req, err := http.NewRequest("POST", "/api/v1/admin/enrollments/import", strings.NewReader("alice@example.com,Go\n"))
+	suite.Require().NoError(err)
+	recorder = suite.makeHTTPRequest(req)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+}
@@ -3,6 +3,7 @@ package tests
 import (
 	"fmt"
 	"net/http"
+	"sync"
 
 	"sonic-labs/course-enrollment-service/internal/models"
 
@@ -64,6 +65,46 @@ func (suite *IntegrationTestSuite) TestEnrollStudentDuplicate() {
 	suite.assertErrorResponse(recorder2, http.StatusConflict, "Student is already enrolled")
 }
 
+// TestEnrollStudentConcurrentDuplicates tests that simultaneous identical enrollments
+// create one enrollment and answer every other request with 409 Conflict
+func (suite *IntegrationTestSuite) TestEnrollStudentConcurrentDuplicates() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+
+	enrollReq := models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     course.ID,
+	}
+	headers := suite.getAuthHeaders()
+
+	codes := make([]int, 10)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			codes[i] = suite.makeRequest("POST", "/api/v1/enrollments", enrollReq, headers).Code
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	created := 0
+	for _, code := range codes {
+		if code == http.StatusCreated {
+			created++
+			continue
+		}
+		suite.Equal(http.StatusConflict, code)
+	}
+	suite.Equal(1, created)
+
+	var count int64
+	suite.db.Model(&models.Enrollment{}).Where("course_id = ?", course.ID).Count(&count)
+	suite.Equal(int64(1), count)
+}
+
 // TestEnrollStudentValidationErrors tests POST /api/v1/enrollments with validation errors
 func (suite *IntegrationTestSuite) TestEnrollStudentValidationErrors() {
 	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
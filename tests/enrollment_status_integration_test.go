@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+)
+
+// TestEnrollmentLifecycle tests status changes, their history and re-enrollment after a drop
+func (suite *IntegrationTestSuite) TestEnrollmentLifecycle() {
+	course := suite.createTestCourse("Go Programming", "Learn Go", "Beginner")
+	headers := suite.getAuthHeaders()
+
+	recorder := suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     course.ID,
+	}, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	var created models.EnrollmentResponse
+	suite.parseResponse(recorder, &created)
+	suite.Equal("active", created.Status)
+
+	statusURL := fmt.Sprintf("/api/v1/admin/enrollments/%s/status", created.ID)
+	recorder = suite.makeRequest("PATCH", statusURL, models.EnrollmentStatusRequest{Status: "dropped"}, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	// Dropped enrollments are kept but hidden by the status filter
+	recorder = suite.makeRequest("GET", "/api/v1/students/student@example.com/enrollments?status=active", nil, suite.getAuthHeaders())
+	suite.Equal(http.StatusOK, recorder.Code)
+	var active models.StudentEnrollmentsResponse
+	suite.parseResponse(recorder, &active)
+	suite.Equal(0, active.Total)
+
+	recorder = suite.makeRequest("GET", "/api/v1/students/student@example.com/enrollments?status=dropped", nil, suite.getAuthHeaders())
+	var dropped models.StudentEnrollmentsResponse
+	suite.parseResponse(recorder, &dropped)
+	suite.Equal(1, dropped.Total)
+
+	// Re-enrolling reactivates the same record
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     course.ID,
+	}, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+	var reactivated models.EnrollmentResponse
+	suite.parseResponse(recorder, &reactivated)
+	suite.Equal(created.ID, reactivated.ID)
+	suite.Equal("active", reactivated.Status)
+
+	recorder = suite.makeRequest("PATCH", statusURL, models.EnrollmentStatusRequest{Status: "completed"}, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	// Completed is final
+	recorder = suite.makeRequest("PATCH", statusURL, models.EnrollmentStatusRequest{Status: "active"}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "invalid enrollment status transition")
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/admin/enrollments/%s/history", created.ID), nil, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+	var history models.EnrollmentHistoryResponse
+	suite.parseResponse(recorder, &history)
+	suite.Equal("completed", history.Status)
+	suite.Require().Len(history.Changes, 4)
+	suite.Equal("admin", history.Changes[1].ChangedBy)
+	suite.Equal("dropped", history.Changes[1].ToStatus)
+}
+
+// TestEnrollmentStatusInvalidFilter tests that unknown status filters are rejected
+func (suite *IntegrationTestSuite) TestEnrollmentStatusInvalidFilter() {
+	recorder := suite.makeRequest("GET", "/api/v1/students/student@example.com/enrollments?status=archived", nil, suite.getAuthHeaders())
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Status must be one of")
+}
+
+// TestDeleteEnrollmentKeepsHistory tests that the admin delete endpoint withdraws instead of deleting
+func (suite *IntegrationTestSuite) TestDeleteEnrollmentKeepsHistory() {
+	course := suite.createTestCourse("Go Programming", "Learn Go", "Beginner")
+	headers := suite.getAuthHeaders()
+
+	recorder := suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     course.ID,
+	}, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+	var created models.EnrollmentResponse
+	suite.parseResponse(recorder, &created)
+
+	url := fmt.Sprintf("/api/v1/admin/enrollments/%s", created.ID)
+	recorder = suite.makeRequest("DELETE", url, nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+
+	var enrollment models.Enrollment
+	suite.Require().NoError(suite.db.First(&enrollment, "id = ?", created.ID).Error)
+	suite.Equal("withdrawn", enrollment.Status)
+
+	recorder = suite.makeRequest("DELETE", url, nil, headers)
+	suite.Equal(http.StatusConflict, recorder.Code)
+}
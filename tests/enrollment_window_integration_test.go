@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+)
+
+// createTestCourseWithWindow is a helper function to create a test course with an enrollment window
+func (suite *IntegrationTestSuite) createTestCourseWithWindow(title string, opensAt, closesAt *time.Time) *models.Course {
+	course := &models.Course{
+		Title:              title,
+		Description:        "Test Description",
+		Difficulty:         "Beginner",
+		EnrollmentOpensAt:  opensAt,
+		EnrollmentClosesAt: closesAt,
+	}
+
+	err := suite.db.Create(course).Error
+	suite.Require().NoError(err)
+
+	return course
+}
+
+// TestEnrollOutsideEnrollmentWindow tests that enrollments are refused before the window opens and after it closes
+func (suite *IntegrationTestSuite) TestEnrollOutsideEnrollmentWindow() {
+	now := time.Now().UTC()
+	past := now.Add(-48 * time.Hour)
+	yesterday := now.Add(-24 * time.Hour)
+	tomorrow := now.Add(24 * time.Hour)
+	headers := suite.getAuthHeaders()
+
+	closed := suite.createTestCourseWithWindow("Closed Course", &past, &yesterday)
+	upcoming := suite.createTestCourseWithWindow("Upcoming Course", &tomorrow, nil)
+	open := suite.createTestCourseWithWindow("Open Course", &yesterday, &tomorrow)
+
+	var response struct {
+		Code               string     `json:"code"`
+		EnrollmentClosesAt *time.Time `json:"enrollment_closes_at"`
+	}
+
+	recorder := suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     closed.ID,
+	}, headers)
+	suite.Equal(http.StatusUnprocessableEntity, recorder.Code)
+	suite.parseResponse(recorder, &response)
+	suite.Equal("enrollment_closed", response.Code)
+	suite.Require().NotNil(response.EnrollmentClosesAt)
+	suite.True(response.EnrollmentClosesAt.Equal(yesterday))
+
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     upcoming.ID,
+	}, headers)
+	suite.Equal(http.StatusUnprocessableEntity, recorder.Code)
+	suite.parseResponse(recorder, &response)
+	suite.Equal("enrollment_not_open", response.Code)
+
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     open.ID,
+	}, headers)
+	suite.Equal(http.StatusCreated, recorder.Code)
+}
+
+// TestCoursesOpenForEnrollmentFilter tests the open_for_enrollment listing filter and the window in responses
+func (suite *IntegrationTestSuite) TestCoursesOpenForEnrollmentFilter() {
+	now := time.Now().UTC()
+	yesterday := now.Add(-24 * time.Hour)
+	tomorrow := now.Add(24 * time.Hour)
+
+	suite.createTestCourseWithWindow("Closed Course", nil, &yesterday)
+	suite.createTestCourseWithWindow("Upcoming Course", &tomorrow, nil)
+	open := suite.createTestCourseWithWindow("Open Course", &yesterday, &tomorrow)
+	suite.createTestCourse("Always Open Course", "No window", "Beginner")
+
+	recorder := suite.makeRequest("GET", "/api/v1/courses?open_for_enrollment=true", nil, nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var response models.CourseListResponse
+	suite.parseResponse(recorder, &response)
+	suite.Equal(2, response.Pagination.TotalCount)
+
+	titles := map[string]models.CourseResponse{}
+	for _, course := range response.Data {
+		titles[course.Title] = course
+	}
+	suite.Contains(titles, "Open Course")
+	suite.Contains(titles, "Always Open Course")
+	suite.Require().NotNil(titles["Open Course"].EnrollmentClosesAt)
+	suite.True(titles["Open Course"].EnrollmentClosesAt.Equal(tomorrow))
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s", open.ID), nil, nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+	var course models.CourseResponse
+	suite.parseResponse(recorder, &course)
+	suite.Require().NotNil(course.EnrollmentOpensAt)
+	suite.True(course.EnrollmentOpensAt.Equal(yesterday))
+}
+
+// TestCreateCourseInvalidEnrollmentWindow tests that a window closing before it opens is rejected
+func (suite *IntegrationTestSuite) TestCreateCourseInvalidEnrollmentWindow() {
+	opensAt := time.Now().UTC().Add(24 * time.Hour)
+	closesAt := opensAt.Add(-time.Hour)
+
+	recorder := suite.makeRequest("POST", "/api/v1/courses", models.CourseRequest{
+		Title:              "Backwards Window",
+		Description:        "Closes before it opens",
+		Difficulty:         "Beginner",
+		EnrollmentOpensAt:  &opensAt,
+		EnrollmentClosesAt: &closesAt,
+	}, suite.getAuthHeaders())
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "close after it opens")
+}
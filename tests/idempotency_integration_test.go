@@ -0,0 +1,196 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+)
+
+// idempotentHeaders is a helper function returning auth headers with an Idempotency-Key
+func (suite *IntegrationTestSuite) idempotentHeaders(key string) map[string]string {
+	headers := suite.getAuthHeaders()
+	headers["Idempotency-Key"] = key
+	return headers
+}
+
+// TestCreateCourseIdempotencyKeyReplay tests that retrying with the same key replays the original response
+func (suite *IntegrationTestSuite) TestCreateCourseIdempotencyKeyReplay() {
+	courseReq := models.CourseRequest{
+		Title:       "Idempotent Course",
+		Description: "Created once",
+		Difficulty:  "Beginner",
+	}
+	headers := suite.idempotentHeaders("create-course-1")
+
+	first := suite.makeRequest("POST", "/api/v1/courses", courseReq, headers)
+	suite.Equal(http.StatusCreated, first.Code)
+	suite.Empty(first.Header().Get("Idempotency-Replayed"))
+
+	retry := suite.makeRequest("POST", "/api/v1/courses", courseReq, headers)
+	suite.Equal(http.StatusCreated, retry.Code)
+	suite.Equal("true", retry.Header().Get("Idempotency-Replayed"))
+	suite.JSONEq(first.Body.String(), retry.Body.String())
+
+	var count int64
+	suite.db.Model(&models.Course{}).Where("title = ?", courseReq.Title).Count(&count)
+	suite.Equal(int64(1), count)
+
+	// Without a key every request is processed
+	suite.makeRequest("POST", "/api/v1/courses", courseReq, suite.getAuthHeaders())
+	suite.db.Model(&models.Course{}).Where("title = ?", courseReq.Title).Count(&count)
+	suite.Equal(int64(2), count)
+}
+
+// TestEnrollIdempotencyKeyReplay tests that a retried enrollment gets the original 201 instead of a conflict
+func (suite *IntegrationTestSuite) TestEnrollIdempotencyKeyReplay() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+	enrollReq := models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     course.ID,
+	}
+	headers := suite.idempotentHeaders("enroll-1")
+
+	first := suite.makeRequest("POST", "/api/v1/enrollments", enrollReq, headers)
+	suite.Equal(http.StatusCreated, first.Code)
+
+	retry := suite.makeRequest("POST", "/api/v1/enrollments", enrollReq, headers)
+	suite.Equal(http.StatusCreated, retry.Code)
+	suite.JSONEq(first.Body.String(), retry.Body.String())
+
+	// A new key is a new request
+	recorder := suite.makeRequest("POST", "/api/v1/enrollments", enrollReq, suite.idempotentHeaders("enroll-2"))
+	suite.Equal(http.StatusConflict, recorder.Code)
+}
+
+// TestIdempotencyKeyNotStoredForRejectedRequest tests that a request refused by
+// a permission check can be retried with the same key once it is allowed
+func (suite *IntegrationTestSuite) TestIdempotencyKeyNotStoredForRejectedRequest() {
+	grace := suite.createTestInstructor("Grace Hopper", "grace@example.com")
+	headers := suite.signInTestInstructor(grace)
+	headers["Idempotency-Key"] = "enroll-by-instructor"
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+	enrollReq := models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     course.ID,
+	}
+
+	first := suite.makeRequest("POST", "/api/v1/enrollments", enrollReq, headers)
+	suite.Equal(http.StatusForbidden, first.Code)
+
+	suite.setCourseInstructors(course.ID, grace.ID)
+	retry := suite.makeRequest("POST", "/api/v1/enrollments", enrollReq, headers)
+	suite.Equal(http.StatusCreated, retry.Code, retry.Body.String())
+	suite.Empty(retry.Header().Get("Idempotency-Replayed"))
+}
+
+// TestIdempotencyKeyReusedWithDifferentPayload tests that a key cannot be reused for another request
+func (suite *IntegrationTestSuite) TestIdempotencyKeyReusedWithDifferentPayload() {
+	headers := suite.idempotentHeaders("create-course-2")
+
+	recorder := suite.makeRequest("POST", "/api/v1/courses", models.CourseRequest{
+		Title:       "First Course",
+		Description: "Test Description",
+		Difficulty:  "Beginner",
+	}, headers)
+	suite.Equal(http.StatusCreated, recorder.Code)
+
+	recorder = suite.makeRequest("POST", "/api/v1/courses", models.CourseRequest{
+		Title:       "Second Course",
+		Description: "Test Description",
+		Difficulty:  "Beginner",
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusUnprocessableEntity, "already used for a different request")
+
+	var count int64
+	suite.db.Model(&models.Course{}).Where("title = ?", "Second Course").Count(&count)
+	suite.Equal(int64(0), count)
+}
+
+// TestIdempotencyKeyInProgress tests that a retry arriving before the original finishes is rejected
+func (suite *IntegrationTestSuite) TestIdempotencyKeyInProgress() {
+	courseReq := models.CourseRequest{
+		Title:       "Slow Course",
+		Description: "Test Description",
+		Difficulty:  "Beginner",
+	}
+	headers := suite.idempotentHeaders("create-course-3")
+
+	// Let the request reserve the key, then mark it as still running
+	recorder := suite.makeRequest("POST", "/api/v1/courses", courseReq, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+	err := suite.db.Model(&models.IdempotencyRecord{}).
+		Where("idempotency_key = ?", "admin:create-course-3").
+		Updates(map[string]interface{}{"status_code": 0, "expires_at": time.Now().Add(time.Minute)}).Error
+	suite.Require().NoError(err)
+
+	recorder = suite.makeRequest("POST", "/api/v1/courses", courseReq, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "still being processed")
+
+	// Once the reservation expires the key can be used again
+	err = suite.db.Model(&models.IdempotencyRecord{}).
+		Where("idempotency_key = ?", "admin:create-course-3").
+		Update("expires_at", time.Now().Add(-time.Minute)).Error
+	suite.Require().NoError(err)
+
+	recorder = suite.makeRequest("POST", "/api/v1/courses", courseReq, headers)
+	suite.Equal(http.StatusCreated, recorder.Code)
+}
+
+// idempotentImportRequest is a helper function building a multipart enrollment
+// import with an Idempotency-Key; every request gets a new random boundary
+func (suite *IntegrationTestSuite) idempotentImportRequest(key, csv string) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	suite.Require().NoError(writer.WriteField("note", "nightly import"))
+	part, err := writer.CreateFormFile("file", "enrollments.csv")
+	suite.Require().NoError(err)
+	_, err = part.Write([]byte(csv))
+	suite.Require().NoError(err)
+	suite.Require().NoError(writer.Close())
+
+	req, err := http.NewRequest("POST", "/api/v1/admin/enrollments/import", &body)
+	suite.Require().NoError(err)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	for name, value := range suite.idempotentHeaders(key) {
+		req.Header.Set(name, value)
+	}
+	return req
+}
+
+// TestIdempotencyKeyMultipartRetry tests that a retried upload is replayed even
+// though its multipart boundary differs, and that a different file is not
+func (suite *IntegrationTestSuite) TestIdempotencyKeyMultipartRetry() {
+	course := suite.createTestCourse("Go Programming", "Learn Go", "Beginner")
+	csv := fmt.Sprintf("student_email,course_id\nalice@example.com,%s\n", course.ID)
+
+	first := suite.makeHTTPRequest(suite.idempotentImportRequest("import-1", csv))
+	suite.Require().Equal(http.StatusOK, first.Code)
+
+	retry := suite.makeHTTPRequest(suite.idempotentImportRequest("import-1", csv))
+	suite.Equal(http.StatusOK, retry.Code)
+	suite.Equal("true", retry.Header().Get("Idempotency-Replayed"))
+	suite.JSONEq(first.Body.String(), retry.Body.String())
+
+	otherCSV := fmt.Sprintf("student_email,course_id\nbob@example.com,%s\n", course.ID)
+	recorder := suite.makeHTTPRequest(suite.idempotentImportRequest("import-1", otherCSV))
+	suite.assertErrorResponse(recorder, http.StatusUnprocessableEntity, "different request")
+}
+
+// TestIdempotencyKeyBodyTooLarge tests that oversized bodies sent with a key are rejected
+func (suite *IntegrationTestSuite) TestIdempotencyKeyBodyTooLarge() {
+	body := strings.Repeat("a", 10<<20+1)
+	req, err := http.NewRequest("POST", "/api/v1/admin/enrollments/import", strings.NewReader(body))
+	suite.Require().NoError(err)
+	req.Header.Set("Content-Type", "text/csv")
+	for name, value := range suite.idempotentHeaders("import-too-large") {
+		req.Header.Set(name, value)
+	}
+
+	recorder := suite.makeHTTPRequest(req)
+	suite.assertErrorResponse(recorder, http.StatusRequestEntityTooLarge, "at most 10MB")
+}
@@ -0,0 +1,335 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// createTestInstructor is a helper function to create an instructor
+func (suite *IntegrationTestSuite) createTestInstructor(name, email string) models.InstructorResponse {
+	recorder := suite.makeRequest("POST", "/api/v1/admin/instructors", models.InstructorRequest{
+		Name:  name,
+		Email: email,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	var instructor models.InstructorResponse
+	suite.parseResponse(recorder, &instructor)
+	return instructor
+}
+
+// signInTestInstructor is a helper function to create the account of an
+// instructor and log in with it
+func (suite *IntegrationTestSuite) signInTestInstructor(instructor models.InstructorResponse) map[string]string {
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/instructors/%s/account", instructor.ID), models.InstructorAccountRequest{
+		Password: "correct horse battery",
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	recorder = suite.makeRequest("POST", "/api/v1/auth/login", models.LoginRequest{
+		Username: instructor.Email,
+		Password: "correct horse battery",
+	}, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var account models.LoginResponse
+	suite.parseResponse(recorder, &account)
+	return map[string]string{"Authorization": "Bearer " + account.Token}
+}
+
+// setCourseInstructors is a helper function to assign instructors to a course
+func (suite *IntegrationTestSuite) setCourseInstructors(courseID uuid.UUID, instructorIDs ...uuid.UUID) models.CourseResponse {
+	recorder := suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s/instructors", courseID), models.CourseInstructorsRequest{
+		InstructorIDs: append([]uuid.UUID{}, instructorIDs...),
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var course models.CourseResponse
+	suite.parseResponse(recorder, &course)
+	return course
+}
+
+// TestInstructorManagement tests creating, updating and deleting instructors
+func (suite *IntegrationTestSuite) TestInstructorManagement() {
+	headers := suite.getAuthHeaders()
+	grace := suite.createTestInstructor("Grace Hopper", " Grace@Example.com ")
+	suite.Equal("grace@example.com", grace.Email)
+	suite.Empty(grace.CourseIDs)
+	suite.False(grace.HasAccount)
+	ada := suite.createTestInstructor("Ada Lovelace", "ada@example.com")
+
+	recorder := suite.makeRequest("POST", "/api/v1/admin/instructors", models.InstructorRequest{
+		Name:  "Someone Else",
+		Email: "GRACE@example.com",
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "already exists")
+	recorder = suite.makeRequest("POST", "/api/v1/admin/instructors", models.InstructorRequest{
+		Name:  "  ",
+		Email: "someone@example.com",
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Name")
+
+	course := suite.createTestCourse("Compilers", "Test Description", "Advanced")
+	suite.setCourseInstructors(course.ID, grace.ID)
+
+	recorder = suite.makeRequest("GET", "/api/v1/admin/instructors", nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var list models.InstructorListResponse
+	suite.parseResponse(recorder, &list)
+	suite.Require().Equal(2, list.Total)
+	suite.Equal(ada.ID, list.Instructors[0].ID)
+	suite.Equal([]uuid.UUID{course.ID}, list.Instructors[1].CourseIDs)
+
+	// A changed email is also the new username of the instructor's account
+	suite.signInTestInstructor(grace)
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/admin/instructors/%s", grace.ID), models.InstructorRequest{
+		Name:  "Rear Admiral Grace Hopper",
+		Email: "hopper@example.com",
+	}, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	var updated models.InstructorResponse
+	suite.parseResponse(recorder, &updated)
+	suite.True(updated.HasAccount)
+	recorder = suite.makeRequest("POST", "/api/v1/auth/login", models.LoginRequest{
+		Username: "hopper@example.com",
+		Password: "correct horse battery",
+	}, nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s", course.ID), nil, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var fetched models.CourseResponse
+	suite.parseResponse(recorder, &fetched)
+	suite.Require().Len(fetched.Instructors, 1)
+	suite.Equal("Rear Admiral Grace Hopper", fetched.Instructors[0].Name)
+
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/instructors/%s/account", grace.ID), models.InstructorAccountRequest{
+		Password: "another password",
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "already exists")
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/instructors/%s/account", ada.ID), models.InstructorAccountRequest{
+		Password: "short",
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "at least")
+
+	// Deleting an instructor takes them off their courses and deletes their account
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/admin/instructors/%s", grace.ID), nil, headers)
+	suite.Require().Equal(http.StatusNoContent, recorder.Code)
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/admin/instructors/%s", grace.ID), nil, headers)
+	suite.Equal(http.StatusNotFound, recorder.Code)
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s", course.ID), nil, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	fetched = models.CourseResponse{}
+	suite.parseResponse(recorder, &fetched)
+	suite.Empty(fetched.Instructors)
+	recorder = suite.makeRequest("POST", "/api/v1/auth/login", models.LoginRequest{
+		Username: "hopper@example.com",
+		Password: "correct horse battery",
+	}, nil)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+
+	recorder = suite.makeRequest("GET", "/api/v1/admin/instructors", nil, nil)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+}
+
+// TestCourseInstructors tests assigning instructors to courses and filtering by them
+func (suite *IntegrationTestSuite) TestCourseInstructors() {
+	grace := suite.createTestInstructor("Grace Hopper", "grace@example.com")
+	ada := suite.createTestInstructor("Ada Lovelace", "ada@example.com")
+	compilers := suite.createTestCourse("Compilers", "Test Description", "Advanced")
+	engines := suite.createTestCourse("Analytical Engines", "Test Description", "Beginner")
+
+	course := suite.setCourseInstructors(compilers.ID, grace.ID, ada.ID, grace.ID)
+	suite.Require().Len(course.Instructors, 2)
+	suite.Equal("Ada Lovelace", course.Instructors[0].Name)
+	suite.Equal("grace@example.com", course.Instructors[1].Email)
+	suite.setCourseInstructors(engines.ID, ada.ID)
+
+	filter := func(instructorID uuid.UUID) []string {
+		recorder := suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses?instructor_id=%s", instructorID), nil, nil)
+		suite.Require().Equal(http.StatusOK, recorder.Code)
+		var list models.CourseListResponse
+		suite.parseResponse(recorder, &list)
+		var titles []string
+		for _, course := range list.Data {
+			titles = append(titles, course.Title)
+		}
+		return titles
+	}
+	suite.ElementsMatch([]string{"Compilers", "Analytical Engines"}, filter(ada.ID))
+	suite.ElementsMatch([]string{"Compilers"}, filter(grace.ID))
+
+	// Updating a course keeps its instructors
+	recorder := suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s", compilers.ID), models.CourseRequest{
+		Title:       "Compilers",
+		Description: "Updated",
+		Difficulty:  "Advanced",
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	var updated models.CourseResponse
+	suite.parseResponse(recorder, &updated)
+	suite.Len(updated.Instructors, 2)
+
+	course = suite.setCourseInstructors(compilers.ID)
+	suite.Empty(course.Instructors)
+	suite.Empty(filter(grace.ID))
+
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s/instructors", compilers.ID), models.CourseInstructorsRequest{
+		InstructorIDs: []uuid.UUID{uuid.New()},
+	}, suite.getAuthHeaders())
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "existing instructors")
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s/instructors", uuid.New()), models.CourseInstructorsRequest{
+		InstructorIDs: []uuid.UUID{grace.ID},
+	}, suite.getAuthHeaders())
+	suite.Equal(http.StatusNotFound, recorder.Code)
+}
+
+// TestInstructorCourseAccess tests that instructors can only manage the courses
+// they teach and the enrollments in them
+func (suite *IntegrationTestSuite) TestInstructorCourseAccess() {
+	grace := suite.createTestInstructor("Grace Hopper", "grace@example.com")
+	headers := suite.signInTestInstructor(grace)
+	own := suite.createTestCourse("Compilers", "Test Description", "Advanced")
+	other := suite.createTestCourse("Typography", "Test Description", "Beginner")
+	suite.setCourseInstructors(own.ID, grace.ID)
+	suite.enrollTestStudent("learner@example.com", own.ID)
+	suite.enrollTestStudent("learner@example.com", other.ID)
+
+	update := models.CourseRequest{Title: "Compilers", Description: "Taught by Grace", Difficulty: "Advanced"}
+	recorder := suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s", own.ID), update, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s", other.ID), update, headers)
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "instructors of this course")
+
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/modules", own.ID), models.ModuleRequest{Title: "Parsing"}, headers)
+	suite.Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/modules", other.ID), models.ModuleRequest{Title: "Kerning"}, headers)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+
+	// Enrollments can be viewed and managed in their own courses only
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/students", own.ID), nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var students struct {
+		Students []string `json:"students"`
+	}
+	suite.parseResponse(recorder, &students)
+	suite.Equal([]string{"learner@example.com"}, students.Students)
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/students", other.ID), nil, headers)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/waitlist", own.ID), nil, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/courses/%s/students/learner@example.com", other.ID), nil, headers)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/courses/%s/students/learner@example.com", own.ID), nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+
+	// Creating, deleting and assigning courses and other admin routes stay admin only
+	recorder = suite.makeRequest("POST", "/api/v1/courses", update, headers)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/courses/%s", own.ID), nil, headers)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s/instructors", other.ID), models.CourseInstructorsRequest{
+		InstructorIDs: []uuid.UUID{grace.ID},
+	}, headers)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	recorder = suite.makeRequest("GET", "/api/v1/admin/enrollments", nil, headers)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+
+	// Students and anonymous callers cannot manage courses
+	_, studentHeaders := suite.registerTestStudent("student@example.com")
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/students", own.ID), nil, studentHeaders)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/students", own.ID), nil, nil)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+
+	// Admins keep access to every course
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/students", other.ID), nil, suite.getAuthHeaders())
+	suite.Equal(http.StatusOK, recorder.Code)
+}
+
+// TestInstructorEnrollmentAccess tests that instructors can enroll students in
+// and manage the enrollments of the courses they teach only
+func (suite *IntegrationTestSuite) TestInstructorEnrollmentAccess() {
+	grace := suite.createTestInstructor("Grace Hopper", "grace@example.com")
+	headers := suite.signInTestInstructor(grace)
+	own := suite.createTestCourse("Compilers", "Test Description", "Advanced")
+	other := suite.createTestCourse("Typography", "Test Description", "Beginner")
+	suite.setCourseInstructors(own.ID, grace.ID)
+	ownLesson := suite.createTestLesson(own.ID, suite.createTestModule(own.ID, "Parsing").ID, "Lexers", 10)
+	otherLesson := suite.createTestLesson(other.ID, suite.createTestModule(other.ID, "Kerning").ID, "Pairs", 10)
+
+	recorder := suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "learner@example.com",
+		CourseID:     own.ID,
+	}, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+	var enrollment models.EnrollmentResponse
+	suite.parseResponse(recorder, &enrollment)
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "learner@example.com",
+		CourseID:     other.ID,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "instructors of this course")
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", map[string]string{"student_email": "learner@example.com"}, headers)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+
+	suite.Equal(http.StatusOK, suite.makeRequest("PUT", fmt.Sprintf("/api/v1/admin/enrollments/%s/progress/%s", enrollment.ID, ownLesson.ID), models.LessonProgressRequest{
+		Status: "started",
+	}, headers).Code)
+	recorder = suite.makeRequest("PATCH", fmt.Sprintf("/api/v1/admin/enrollments/%s/status", enrollment.ID), models.EnrollmentStatusRequest{Status: "completed"}, headers)
+	suite.Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/admin/enrollments/%s/history", enrollment.ID), nil, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	// Enrollments in other courses stay out of reach, as do ones that do not exist
+	foreign := suite.enrollTestStudent("learner@example.com", other.ID)
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/admin/enrollments/%s/progress/%s", foreign.ID, otherLesson.ID), models.LessonProgressRequest{
+		Status: "started",
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "instructors of this course")
+	recorder = suite.makeRequest("PATCH", fmt.Sprintf("/api/v1/admin/enrollments/%s/status", foreign.ID), models.EnrollmentStatusRequest{Status: "completed"}, headers)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	recorder = suite.makeRequest("PATCH", fmt.Sprintf("/api/v1/admin/enrollments/%s/status", uuid.New()), models.EnrollmentStatusRequest{Status: "completed"}, headers)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/admin/enrollments/%s", foreign.ID), nil, headers)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+
+	// Admins keep access to every enrollment
+	recorder = suite.makeRequest("PATCH", fmt.Sprintf("/api/v1/admin/enrollments/%s/status", foreign.ID), models.EnrollmentStatusRequest{Status: "completed"}, suite.getAuthHeaders())
+	suite.Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+}
+
+// TestInstructorEnrollmentOverrides tests that only admins may skip the
+// prerequisite and schedule conflict checks when enrolling a student
+func (suite *IntegrationTestSuite) TestInstructorEnrollmentOverrides() {
+	grace := suite.createTestInstructor("Grace Hopper", "grace@example.com")
+	headers := suite.signInTestInstructor(grace)
+	basics := suite.createTestCourse("Basics", "Test Description", "Beginner")
+	advanced := suite.createTestCourse("Advanced", "Test Description", "Advanced")
+	suite.setCourseInstructors(advanced.ID, grace.ID)
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/prerequisites", advanced.ID), models.PrerequisiteRequest{
+		PrerequisiteID: basics.ID,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	request := models.EnrollmentRequest{StudentEmail: "learner@example.com", CourseID: advanced.ID}
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", request, headers)
+	suite.Equal(http.StatusUnprocessableEntity, recorder.Code)
+
+	overridePrerequisites := request
+	overridePrerequisites.OverridePrerequisites = true
+	overrideConflicts := request
+	overrideConflicts.OverrideScheduleConflicts = true
+	for _, override := range []models.EnrollmentRequest{overridePrerequisites, overrideConflicts} {
+		recorder = suite.makeRequest("POST", "/api/v1/enrollments", override, headers)
+		suite.assertErrorResponse(recorder, http.StatusForbidden, "Only admins")
+	}
+
+	// Admins may override the checks
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", overridePrerequisites, suite.getAuthHeaders())
+	suite.Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+}
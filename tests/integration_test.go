@@ -11,6 +11,7 @@ import (
 
 	"sonic-labs/course-enrollment-service/internal/auth"
 	"sonic-labs/course-enrollment-service/internal/config"
+	"sonic-labs/course-enrollment-service/internal/constants"
 	"sonic-labs/course-enrollment-service/internal/models"
 	"sonic-labs/course-enrollment-service/internal/router"
 
@@ -52,6 +53,14 @@ func (suite *IntegrationTestSuite) SetupSuite() {
 		log.Fatalf("Failed to initialize test database: %v", err)
 	}
 
+	// Every connection to ":memory:" gets its own empty database, so concurrent
+	// requests must share a single connection
+	sqlDB, err := suite.db.DB()
+	if err != nil {
+		log.Fatalf("Failed to get underlying sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
 	// Run migrations with SQLite-compatible schema
 	err = suite.db.Exec(`
 		CREATE TABLE IF NOT EXISTS courses (
@@ -60,6 +69,9 @@ func (suite *IntegrationTestSuite) SetupSuite() {
 			description TEXT NOT NULL,
 			difficulty TEXT NOT NULL,
 			image_url TEXT,
+			capacity INTEGER,
+			enrollment_opens_at DATETIME,
+			enrollment_closes_at DATETIME,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)
@@ -68,12 +80,238 @@ func (suite *IntegrationTestSuite) SetupSuite() {
 		log.Fatalf("Failed to create courses table: %v", err)
 	}
 
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS difficulty_levels (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			sort_order INTEGER NOT NULL DEFAULT 0,
+			description TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create difficulty_levels table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS categories (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			slug TEXT NOT NULL UNIQUE,
+			description TEXT,
+			parent_id TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (parent_id) REFERENCES categories(id)
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create categories table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS tags (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create tags table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS course_categories (
+			course_id TEXT NOT NULL,
+			category_id TEXT NOT NULL,
+			PRIMARY KEY (course_id, category_id),
+			FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+			FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create course_categories table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS course_tags (
+			course_id TEXT NOT NULL,
+			tag_id TEXT NOT NULL,
+			PRIMARY KEY (course_id, tag_id),
+			FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+			FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create course_tags table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS instructors (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			email TEXT NOT NULL UNIQUE,
+			bio TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create instructors table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS course_instructors (
+			course_id TEXT NOT NULL,
+			instructor_id TEXT NOT NULL,
+			PRIMARY KEY (course_id, instructor_id),
+			FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+			FOREIGN KEY (instructor_id) REFERENCES instructors(id) ON DELETE CASCADE
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create course_instructors table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS terms (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			start_date DATE NOT NULL,
+			end_date DATE NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create terms table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS course_offerings (
+			id TEXT PRIMARY KEY,
+			course_id TEXT NOT NULL,
+			term_id TEXT,
+			capacity INTEGER,
+			schedule TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+			FOREIGN KEY (term_id) REFERENCES terms(id),
+			UNIQUE(course_id, term_id)
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create course_offerings table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_course_offerings_default ON course_offerings(course_id) WHERE term_id IS NULL
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create course_offerings default index: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS course_sections (
+			id TEXT PRIMARY KEY,
+			course_id TEXT NOT NULL,
+			offering_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+			FOREIGN KEY (offering_id) REFERENCES course_offerings(id) ON DELETE CASCADE,
+			UNIQUE(offering_id, name)
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create course_sections table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS section_meetings (
+			id TEXT PRIMARY KEY,
+			section_id TEXT NOT NULL,
+			day TEXT NOT NULL,
+			start_time TEXT NOT NULL,
+			end_time TEXT NOT NULL,
+			location TEXT,
+			time_zone TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (section_id) REFERENCES course_sections(id) ON DELETE CASCADE
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create section_meetings table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS idempotency_keys (
+			idempotency_key TEXT PRIMARY KEY,
+			fingerprint TEXT NOT NULL,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			content_type TEXT,
+			response_body TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			expires_at DATETIME NOT NULL
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create idempotency_keys table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS students (
+			id TEXT PRIMARY KEY,
+			email TEXT NOT NULL UNIQUE,
+			first_name TEXT,
+			last_name TEXT,
+			student_number TEXT UNIQUE,
+			invite_hash TEXT UNIQUE,
+			invite_expires_at DATETIME,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create students table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS student_merges (
+			id TEXT PRIMARY KEY,
+			source_student_id TEXT NOT NULL,
+			source_email TEXT NOT NULL,
+			target_student_id TEXT NOT NULL,
+			target_email TEXT NOT NULL,
+			moved_enrollments INTEGER NOT NULL DEFAULT 0,
+			discarded_enrollment_ids TEXT NOT NULL DEFAULT '',
+			moved_waitlist_entries INTEGER NOT NULL DEFAULT 0,
+			discarded_waitlist_entries INTEGER NOT NULL DEFAULT 0,
+			merged_by TEXT NOT NULL,
+			reason TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (target_student_id) REFERENCES students(id) ON DELETE CASCADE
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create student_merges table: %v", err)
+	}
+
 	err = suite.db.Exec(`
 		CREATE TABLE IF NOT EXISTS enrollments (
 			id TEXT PRIMARY KEY,
+			student_id TEXT,
 			student_email TEXT NOT NULL,
 			course_id TEXT NOT NULL,
+			offering_id TEXT,
+			section_id TEXT,
 			enrolled_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			status TEXT NOT NULL DEFAULT 'active',
+			status_changed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			status_changed_by TEXT,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
@@ -84,12 +322,189 @@ func (suite *IntegrationTestSuite) SetupSuite() {
 		log.Fatalf("Failed to create enrollments table: %v", err)
 	}
 
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS enrollment_status_changes (
+			id TEXT PRIMARY KEY,
+			enrollment_id TEXT NOT NULL,
+			from_status TEXT,
+			to_status TEXT NOT NULL,
+			changed_by TEXT NOT NULL,
+			reason TEXT,
+			changed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (enrollment_id) REFERENCES enrollments(id) ON DELETE CASCADE
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create enrollment_status_changes table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS waitlist_entries (
+			id TEXT PRIMARY KEY,
+			course_id TEXT NOT NULL,
+			offering_id TEXT,
+			section_id TEXT,
+			student_email TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+			UNIQUE(course_id, student_email)
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create waitlist_entries table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS course_prerequisites (
+			id TEXT PRIMARY KEY,
+			course_id TEXT NOT NULL,
+			prerequisite_id TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+			FOREIGN KEY (prerequisite_id) REFERENCES courses(id) ON DELETE CASCADE,
+			UNIQUE(course_id, prerequisite_id),
+			CHECK (course_id <> prerequisite_id)
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create course_prerequisites table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS prerequisite_overrides (
+			id TEXT PRIMARY KEY,
+			course_id TEXT NOT NULL,
+			student_email TEXT NOT NULL,
+			missing_prerequisite_ids TEXT NOT NULL,
+			overridden_by TEXT NOT NULL,
+			reason TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create prerequisite_overrides table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS course_modules (
+			id TEXT PRIMARY KEY,
+			course_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			description TEXT,
+			position INTEGER NOT NULL CHECK (position > 0),
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create course_modules table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS lessons (
+			id TEXT PRIMARY KEY,
+			module_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			content TEXT NOT NULL,
+			duration_minutes INTEGER NOT NULL CHECK (duration_minutes > 0),
+			optional BOOLEAN NOT NULL DEFAULT 0,
+			position INTEGER NOT NULL CHECK (position > 0),
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (module_id) REFERENCES course_modules(id) ON DELETE CASCADE
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create lessons table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS lesson_progress (
+			id TEXT PRIMARY KEY,
+			enrollment_id TEXT NOT NULL,
+			lesson_id TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'started' CHECK (status IN ('started', 'completed')),
+			started_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			completed_at DATETIME,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (enrollment_id) REFERENCES enrollments(id) ON DELETE CASCADE,
+			FOREIGN KEY (lesson_id) REFERENCES lessons(id) ON DELETE CASCADE,
+			UNIQUE(enrollment_id, lesson_id)
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create lesson_progress table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS revoked_tokens (
+			id TEXT PRIMARY KEY,
+			expires_at DATETIME NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create revoked_tokens table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS role_permissions (
+			role TEXT NOT NULL,
+			permission TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (role, permission)
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create role_permissions table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS api_keys (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			prefix TEXT NOT NULL,
+			key_hash TEXT NOT NULL UNIQUE,
+			owner_id TEXT NOT NULL,
+			expires_at DATETIME,
+			last_used_at DATETIME,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create api_keys table: %v", err)
+	}
+
+	err = suite.db.Exec(`
+		CREATE TABLE IF NOT EXISTS api_key_scopes (
+			api_key_id TEXT NOT NULL,
+			permission TEXT NOT NULL,
+			PRIMARY KEY (api_key_id, permission),
+			FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE CASCADE
+		)
+	`).Error
+	if err != nil {
+		log.Fatalf("Failed to create api_key_scopes table: %v", err)
+	}
+
 	err = suite.db.Exec(`
 		CREATE TABLE IF NOT EXISTS users (
 			id TEXT PRIMARY KEY,
 			username TEXT NOT NULL UNIQUE,
 			password TEXT NOT NULL,
 			role TEXT NOT NULL DEFAULT 'admin',
+			student_id TEXT UNIQUE,
+			instructor_id TEXT UNIQUE,
+			disabled_at DATETIME,
+			must_change_password BOOLEAN NOT NULL DEFAULT 0,
+			password_changed_at DATETIME,
+			password_reset_hash TEXT UNIQUE,
+			password_reset_expires_at DATETIME,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)
@@ -138,9 +553,58 @@ func (suite *IntegrationTestSuite) TearDownTest() {
 // cleanupTestData removes all test data from the database
 func (suite *IntegrationTestSuite) cleanupTestData() {
 	// Delete in order to respect foreign key constraints
+	suite.db.Exec("DELETE FROM idempotency_keys")
+	suite.db.Exec("DELETE FROM revoked_tokens")
+	suite.db.Exec("DELETE FROM lesson_progress")
+	suite.db.Exec("DELETE FROM waitlist_entries")
+	suite.db.Exec("DELETE FROM prerequisite_overrides")
+	suite.db.Exec("DELETE FROM course_prerequisites")
+	suite.db.Exec("DELETE FROM enrollment_status_changes")
 	suite.db.Exec("DELETE FROM enrollments")
+	suite.db.Exec("DELETE FROM student_merges")
+	suite.db.Exec("DELETE FROM api_key_scopes")
+	suite.db.Exec("DELETE FROM api_keys")
+	suite.db.Exec("DELETE FROM users WHERE username <> 'admin'")
+	suite.db.Exec("DELETE FROM students")
+	suite.db.Exec("DELETE FROM course_instructors")
+	suite.db.Exec("DELETE FROM instructors")
+	suite.db.Exec("DELETE FROM lessons")
+	suite.db.Exec("DELETE FROM course_modules")
+	suite.db.Exec("DELETE FROM section_meetings")
+	suite.db.Exec("DELETE FROM course_sections")
+	suite.db.Exec("DELETE FROM course_offerings")
+	suite.db.Exec("DELETE FROM terms")
+	suite.db.Exec("DELETE FROM course_categories")
+	suite.db.Exec("DELETE FROM course_tags")
+	suite.db.Exec("DELETE FROM categories")
+	suite.db.Exec("DELETE FROM tags")
 	suite.db.Exec("DELETE FROM courses")
-	// Don't delete users as we need admin user for tests
+	suite.db.Exec("DELETE FROM difficulty_levels")
+	suite.db.Exec("DELETE FROM role_permissions")
+	// Don't delete admin users as we need the admin user for tests
+
+	// Restore the difficulty levels the migrations seed
+	for i, name := range []string{"Beginner", "Intermediate", "Advanced"} {
+		suite.db.Create(&models.DifficultyLevel{Name: name, SortOrder: i + 1})
+	}
+
+	// Restore the role permissions the migrations seed
+	for role, permissions := range seededRolePermissions {
+		for _, permission := range permissions {
+			suite.db.Create(&models.RolePermission{Role: role, Permission: permission})
+		}
+	}
+}
+
+// seededRolePermissions are the permissions migration 021 grants to each role
+var seededRolePermissions = map[string][]string{
+	constants.RoleAdmin: constants.GrantablePermissions,
+	constants.RoleInstructor: {
+		constants.PermissionCourseWrite,
+		constants.PermissionEnrollmentRead,
+		constants.PermissionEnrollmentWrite,
+		constants.PermissionEnrollmentDelete,
+	},
 }
 
 // makeRequest is a helper function to make HTTP requests to the test server
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// writeSigningKey writes a PKCS #8 PEM file for the private key and returns its path
+func (suite *IntegrationTestSuite) writeSigningKey(name string, key crypto.PrivateKey) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	suite.Require().NoError(err)
+
+	path := filepath.Join(suite.T().TempDir(), name+".pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	suite.Require().NoError(os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// tokenHeader returns the alg and kid headers of a token without verifying it
+func (suite *IntegrationTestSuite) tokenHeader(tokenString string) (string, string) {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	suite.Require().NoError(err)
+	kid, _ := token.Header["kid"].(string)
+	return token.Method.Alg(), kid
+}
+
+// TestJWKSWithoutSigningKeys tests that the key set is empty while tokens are
+// signed with the shared secret
+func (suite *IntegrationTestSuite) TestJWKSWithoutSigningKeys() {
+	resp := suite.makeRequest("GET", "/.well-known/jwks.json", nil, nil)
+	suite.Require().Equal(http.StatusOK, resp.Code)
+
+	var set auth.JWKSet
+	suite.parseResponse(resp, &set)
+	suite.Empty(set.Keys)
+
+	alg, kid := suite.tokenHeader(suite.loginAdmin().Token)
+	suite.Equal("HS256", alg)
+	suite.Empty(kid)
+}
+
+// TestSigningKeyRotation tests signing with RS256 and EdDSA keys, scheduled
+// rotation between them and the HS256 migration window
+func (suite *IntegrationTestSuite) TestSigningKeyRotation() {
+	defer auth.SetSigningKeys(nil, time.Time{})
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+	rsaPath := suite.writeSigningKey("rsa", rsaKey)
+	edPath := suite.writeSigningKey("ed", edKey)
+
+	legacy := suite.loginAdmin().Token
+	now := time.Now().UTC()
+	window := now.Add(time.Hour).Format(time.RFC3339)
+
+	// The Ed25519 key is published before it starts signing
+	spec := fmt.Sprintf("rsa-1=%s, ed-1=%s@%s", rsaPath, edPath, now.Add(time.Hour).Format(time.RFC3339))
+	suite.Require().NoError(auth.SetupSigningKeys(spec, window))
+
+	resp := suite.makeRequest("GET", "/.well-known/jwks.json", nil, nil)
+	suite.Require().Equal(http.StatusOK, resp.Code)
+	suite.Contains(resp.Header().Get("Cache-Control"), "public")
+
+	var set auth.JWKSet
+	suite.parseResponse(resp, &set)
+	suite.Require().Len(set.Keys, 2)
+	suite.Equal("rsa-1", set.Keys[0].KeyID)
+	suite.Equal("RSA", set.Keys[0].KeyType)
+	suite.Equal("RS256", set.Keys[0].Algorithm)
+	suite.Equal("AQAB", set.Keys[0].E)
+	suite.NotEmpty(set.Keys[0].N)
+	suite.Equal("ed-1", set.Keys[1].KeyID)
+	suite.Equal("OKP", set.Keys[1].KeyType)
+	suite.Equal("Ed25519", set.Keys[1].Curve)
+	suite.Equal("EdDSA", set.Keys[1].Algorithm)
+	suite.NotEmpty(set.Keys[1].X)
+	suite.Empty(set.Keys[1].N)
+
+	rsaSigned := suite.loginAdmin().Token
+	alg, kid := suite.tokenHeader(rsaSigned)
+	suite.Equal("RS256", alg)
+	suite.Equal("rsa-1", kid)
+
+	for _, token := range []string{rsaSigned, legacy} {
+		resp = suite.makeRequest("GET", "/api/v1/auth/profile", nil, map[string]string{
+			"Authorization": "Bearer " + token,
+		})
+		suite.Equal(http.StatusOK, resp.Code, resp.Body.String())
+	}
+
+	// Once the Ed25519 key is active it signs new tokens, and the RSA key still
+	// verifies the tokens it signed
+	spec = fmt.Sprintf("rsa-1=%s,ed-1=%s@%s", rsaPath, edPath, now.Add(-time.Minute).Format(time.RFC3339))
+	suite.Require().NoError(auth.SetupSigningKeys(spec, window))
+
+	edSigned := suite.loginAdmin().Token
+	alg, kid = suite.tokenHeader(edSigned)
+	suite.Equal("EdDSA", alg)
+	suite.Equal("ed-1", kid)
+
+	for _, token := range []string{edSigned, rsaSigned} {
+		resp = suite.makeRequest("GET", "/api/v1/auth/profile", nil, map[string]string{
+			"Authorization": "Bearer " + token,
+		})
+		suite.Equal(http.StatusOK, resp.Code, resp.Body.String())
+	}
+
+	// After the migration window HS256 tokens are rejected
+	suite.Require().NoError(auth.SetupSigningKeys(spec, now.Add(-time.Minute).Format(time.RFC3339)))
+	resp = suite.makeRequest("GET", "/api/v1/auth/profile", nil, map[string]string{
+		"Authorization": "Bearer " + legacy,
+	})
+	suite.assertErrorResponse(resp, http.StatusUnauthorized, "JWT token is invalid or expired")
+
+	// Tokens signed by a key that is no longer configured are rejected
+	suite.Require().NoError(auth.SetupSigningKeys("ed-1="+edPath, ""))
+	resp = suite.makeRequest("GET", "/api/v1/auth/profile", nil, map[string]string{
+		"Authorization": "Bearer " + rsaSigned,
+	})
+	suite.assertErrorResponse(resp, http.StatusUnauthorized, "JWT token is invalid or expired")
+}
+
+// TestSetupSigningKeysInvalid tests that malformed key configuration is refused
+func (suite *IntegrationTestSuite) TestSetupSigningKeysInvalid() {
+	defer auth.SetSigningKeys(nil, time.Time{})
+
+	notPEM := filepath.Join(suite.T().TempDir(), "key.txt")
+	suite.Require().NoError(os.WriteFile(notPEM, []byte("not a key"), 0o600))
+
+	for _, spec := range []string{
+		"missing-path",
+		"k1=" + filepath.Join(suite.T().TempDir(), "absent.pem"),
+		"k1=" + notPEM,
+		"k1=" + notPEM + "@tomorrow",
+	} {
+		suite.Error(auth.SetupSigningKeys(spec, ""), spec)
+	}
+	suite.Error(auth.SetupSigningKeys("", "soon"))
+
+	// A kid names one key only, even if both entries are valid
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+	edPath := suite.writeSigningKey("ed", edKey)
+	err = auth.SetupSigningKeys("k1="+edPath+",k1="+edPath+"@2030-01-01T00:00:00Z", "")
+	suite.ErrorContains(err, `duplicate signing key id "k1"`)
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/config"
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/router"
+)
+
+// useLoginPolicy is a helper function that routes the rest of a test through a
+// router protecting logins with the policy, with no failed logins counted yet.
+// The returned function restores the suite router.
+func (suite *IntegrationTestSuite) useLoginPolicy(policy config.LoginConfig) func() {
+	cfg := *suite.cfg
+	cfg.Login = policy
+
+	previous := suite.router
+	suite.router = router.Setup(suite.db, &cfg)
+	return func() { suite.router = previous }
+}
+
+// loginFrom is a helper function to log in from a client IP
+func (suite *IntegrationTestSuite) loginFrom(ip, username, password string) *httptest.ResponseRecorder {
+	body, err := json.Marshal(models.LoginRequest{Username: username, Password: password})
+	suite.Require().NoError(err)
+
+	req, err := http.NewRequest("POST", "/api/v1/auth/login", bytes.NewBuffer(body))
+	suite.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = ip + ":52000"
+	return suite.makeHTTPRequest(req)
+}
+
+// TestLoginLockout tests locking out a username after repeated failed logins
+// and lifting the lockout
+func (suite *IntegrationTestSuite) TestLoginLockout() {
+	registrar := suite.createTestUser("registrar", constants.RoleAdmin)
+	superAdmin := suite.getSuperAdminHeaders()
+	defer suite.useLoginPolicy(config.LoginConfig{MaxFailures: 3, LockoutDuration: time.Minute})()
+
+	// A successful login forgets earlier failures
+	for i := 0; i < 2; i++ {
+		suite.Equal(http.StatusUnauthorized, suite.loginFrom("203.0.113.7", "registrar", "wrong").Code)
+	}
+	suite.Equal(http.StatusOK, suite.loginFrom("203.0.113.7", "registrar", "correct horse battery").Code)
+
+	for i := 0; i < 3; i++ {
+		suite.Equal(http.StatusUnauthorized, suite.loginFrom("203.0.113.7", "registrar", "wrong").Code)
+	}
+
+	// Locked out even with the right password, from any IP
+	for _, ip := range []string{"203.0.113.7", "198.51.100.1"} {
+		recorder := suite.loginFrom(ip, "registrar", "correct horse battery")
+		suite.assertErrorResponse(recorder, http.StatusTooManyRequests, "Too many failed login attempts")
+		suite.Equal("60", recorder.Header().Get("Retry-After"))
+	}
+
+	// Other users can still log in from the same IP
+	suite.Equal(http.StatusOK, suite.loginFrom("203.0.113.7", "admin", "admin!dev").Code)
+
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/users/%s/unlock", registrar.ID), nil, suite.getAuthHeaders())
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "Permission user:manage is required")
+
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/users/%s/unlock", registrar.ID), nil, superAdmin)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	var unlocked models.UserResponse
+	suite.parseResponse(recorder, &unlocked)
+	suite.Equal("registrar", unlocked.Username)
+
+	suite.Equal(http.StatusOK, suite.loginFrom("203.0.113.7", "registrar", "correct horse battery").Code)
+
+	recorder = suite.makeRequest("POST", "/api/v1/admin/users/12345678-0000-0000-0000-000000000000/unlock", nil, superAdmin)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "User not found")
+}
+
+// TestLoginProgressiveDelay tests that repeated failed logins are delayed for
+// longer and longer
+func (suite *IntegrationTestSuite) TestLoginProgressiveDelay() {
+	defer suite.useLoginPolicy(config.LoginConfig{BaseDelay: 10 * time.Second})()
+
+	// The first failure costs nothing, the second a delay
+	suite.Equal(http.StatusUnauthorized, suite.loginFrom("203.0.113.7", "ghost", "wrong").Code)
+	suite.Equal(http.StatusUnauthorized, suite.loginFrom("203.0.113.7", "ghost", "wrong").Code)
+
+	recorder := suite.loginFrom("198.51.100.1", "ghost", "wrong")
+	suite.assertErrorResponse(recorder, http.StatusTooManyRequests, "try again in 10 seconds")
+	suite.Equal("10", recorder.Header().Get("Retry-After"))
+
+	// The delay covers the IP the failures came from as well
+	recorder = suite.loginFrom("203.0.113.7", "admin", "admin!dev")
+	suite.Equal(http.StatusTooManyRequests, recorder.Code)
+	suite.Equal(http.StatusOK, suite.loginFrom("198.51.100.1", "admin", "admin!dev").Code)
+}
+
+// TestLoginLockoutPerIP tests locking out a client IP that guesses at several usernames
+func (suite *IntegrationTestSuite) TestLoginLockoutPerIP() {
+	defer suite.useLoginPolicy(config.LoginConfig{IPMaxFailures: 3, LockoutDuration: 5 * time.Minute})()
+
+	for _, username := range []string{"alice", "bob", "admin"} {
+		suite.Equal(http.StatusUnauthorized, suite.loginFrom("203.0.113.7", username, "wrong").Code)
+	}
+
+	recorder := suite.loginFrom("203.0.113.7", "carol", "wrong")
+	suite.assertErrorResponse(recorder, http.StatusTooManyRequests, "try again in 300 seconds")
+	suite.Equal(http.StatusTooManyRequests, suite.loginFrom("203.0.113.7", "admin", "admin!dev").Code)
+
+	// Without a username threshold the usernames themselves are not locked
+	suite.Equal(http.StatusOK, suite.loginFrom("198.51.100.1", "admin", "admin!dev").Code)
+}
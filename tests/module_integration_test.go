@@ -0,0 +1,255 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// createTestModule is a helper function to add a module to a course through the API
+func (suite *IntegrationTestSuite) createTestModule(courseID uuid.UUID, title string) models.ModuleResponse {
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/modules", courseID), models.ModuleRequest{
+		Title: title,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	var module models.ModuleResponse
+	suite.parseResponse(recorder, &module)
+	return module
+}
+
+// createTestLesson is a helper function to add a lesson to a module through the API
+func (suite *IntegrationTestSuite) createTestLesson(courseID, moduleID uuid.UUID, title string, minutes int) models.LessonResponse {
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/modules/%s/lessons", courseID, moduleID), models.LessonRequest{
+		Title:           title,
+		Content:         "Lesson content",
+		DurationMinutes: minutes,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	var lesson models.LessonResponse
+	suite.parseResponse(recorder, &lesson)
+	return lesson
+}
+
+// TestCourseOutline tests building a course outline and reading it back
+func (suite *IntegrationTestSuite) TestCourseOutline() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+
+	intro := suite.createTestModule(course.ID, "Introduction")
+	suite.Equal(1, intro.Position)
+	basics := suite.createTestModule(course.ID, "Basics")
+	suite.Equal(2, basics.Position)
+
+	first := suite.createTestLesson(course.ID, intro.ID, "Welcome", 10)
+	suite.Equal(1, first.Position)
+	second := suite.createTestLesson(course.ID, intro.ID, "Setup", 20)
+	suite.Equal(2, second.Position)
+	suite.createTestLesson(course.ID, basics.ID, "Variables", 30)
+
+	recorder := suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/modules", course.ID), nil, nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var outline models.CourseOutline
+	suite.parseResponse(recorder, &outline)
+	suite.Equal(2, outline.ModuleCount)
+	suite.Equal(3, outline.LessonCount)
+	suite.Equal(60, outline.TotalDurationMinutes)
+	suite.Require().Len(outline.Modules, 2)
+	suite.Equal("Introduction", outline.Modules[0].Title)
+	suite.Equal(2, outline.Modules[0].LessonCount)
+	suite.Equal(30, outline.Modules[0].DurationMinutes)
+	suite.Equal([]string{"Welcome", "Setup"}, []string{outline.Modules[0].Lessons[0].Title, outline.Modules[0].Lessons[1].Title})
+
+	// A single lesson can be read on its own
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/modules/%s/lessons/%s", course.ID, intro.ID, second.ID), nil, nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var lesson models.LessonResponse
+	suite.parseResponse(recorder, &lesson)
+	suite.Equal("Setup", lesson.Title)
+	suite.Equal("Lesson content", lesson.Content)
+}
+
+// TestGetCourseIncludeOutline tests that the outline is only embedded in the course when requested
+func (suite *IntegrationTestSuite) TestGetCourseIncludeOutline() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+	module := suite.createTestModule(course.ID, "Introduction")
+	suite.createTestLesson(course.ID, module.ID, "Welcome", 15)
+
+	recorder := suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s", course.ID), nil, nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var response models.CourseResponse
+	suite.parseResponse(recorder, &response)
+	suite.Nil(response.Outline)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s?include=outline", course.ID), nil, nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	response = models.CourseResponse{}
+	suite.parseResponse(recorder, &response)
+	suite.Require().NotNil(response.Outline)
+	suite.Equal(1, response.Outline.ModuleCount)
+	suite.Equal(1, response.Outline.LessonCount)
+	suite.Equal(15, response.Outline.TotalDurationMinutes)
+}
+
+// TestUpdateAndDeleteModulesAndLessons tests editing the outline and renumbering after deletes
+func (suite *IntegrationTestSuite) TestUpdateAndDeleteModulesAndLessons() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+	first := suite.createTestModule(course.ID, "First")
+	second := suite.createTestModule(course.ID, "Second")
+	third := suite.createTestModule(course.ID, "Third")
+	lesson := suite.createTestLesson(course.ID, second.ID, "Lesson", 10)
+	other := suite.createTestLesson(course.ID, second.ID, "Other", 10)
+	headers := suite.getAuthHeaders()
+
+	description := "Renamed module"
+	recorder := suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s/modules/%s", course.ID, second.ID), models.ModuleRequest{
+		Title:       "Second (updated)",
+		Description: &description,
+	}, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var module models.ModuleResponse
+	suite.parseResponse(recorder, &module)
+	suite.Equal("Second (updated)", module.Title)
+	suite.Equal(2, module.Position)
+	suite.Equal(2, module.LessonCount)
+
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s/modules/%s/lessons/%s", course.ID, second.ID, lesson.ID), models.LessonRequest{
+		Title:           "Lesson (updated)",
+		Content:         "New content",
+		DurationMinutes: 25,
+	}, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var updated models.LessonResponse
+	suite.parseResponse(recorder, &updated)
+	suite.Equal(25, updated.DurationMinutes)
+	suite.Equal(1, updated.Position)
+
+	// Deleting a lesson renumbers the rest of the module
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/courses/%s/modules/%s/lessons/%s", course.ID, second.ID, lesson.ID), nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+
+	var remaining models.Lesson
+	suite.Require().NoError(suite.db.First(&remaining, "id = ?", other.ID).Error)
+	suite.Equal(1, remaining.Position)
+
+	// Deleting a module removes its lessons and renumbers the rest of the course
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/courses/%s/modules/%s", course.ID, second.ID), nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+
+	var lessonCount int64
+	suite.db.Model(&models.Lesson{}).Where("module_id = ?", second.ID).Count(&lessonCount)
+	suite.Equal(int64(0), lessonCount)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/modules", course.ID), nil, nil)
+	var outline models.CourseOutline
+	suite.parseResponse(recorder, &outline)
+	suite.Require().Len(outline.Modules, 2)
+	suite.Equal(first.ID, outline.Modules[0].ID)
+	suite.Equal(third.ID, outline.Modules[1].ID)
+	suite.Equal(2, outline.Modules[1].Position)
+
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/courses/%s/modules/%s", course.ID, second.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "Module not found")
+}
+
+// TestReorderModulesAndLessons tests replacing the order of modules and lessons
+func (suite *IntegrationTestSuite) TestReorderModulesAndLessons() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+	first := suite.createTestModule(course.ID, "First")
+	second := suite.createTestModule(course.ID, "Second")
+	a := suite.createTestLesson(course.ID, first.ID, "A", 5)
+	b := suite.createTestLesson(course.ID, first.ID, "B", 5)
+	headers := suite.getAuthHeaders()
+
+	recorder := suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s/modules", course.ID), models.ModuleReorderRequest{
+		ModuleIDs: []uuid.UUID{second.ID, first.ID},
+	}, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var outline models.CourseOutline
+	suite.parseResponse(recorder, &outline)
+	suite.Equal(second.ID, outline.Modules[0].ID)
+	suite.Equal(first.ID, outline.Modules[1].ID)
+	suite.Equal(2, outline.Modules[1].Position)
+
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s/modules/%s/lessons", course.ID, first.ID), models.LessonReorderRequest{
+		LessonIDs: []uuid.UUID{b.ID, a.ID},
+	}, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var module models.ModuleResponse
+	suite.parseResponse(recorder, &module)
+	suite.Equal(b.ID, module.Lessons[0].ID)
+	suite.Equal(a.ID, module.Lessons[1].ID)
+
+	// Orders that leave out, repeat or invent IDs are rejected
+	for _, ids := range [][]uuid.UUID{
+		{first.ID},
+		{first.ID, first.ID},
+		{first.ID, uuid.New()},
+	} {
+		recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s/modules", course.ID), models.ModuleReorderRequest{
+			ModuleIDs: ids,
+		}, headers)
+		suite.assertErrorResponse(recorder, http.StatusBadRequest, "exactly once")
+	}
+
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s/modules/%s/lessons", course.ID, first.ID), models.LessonReorderRequest{
+		LessonIDs: []uuid.UUID{a.ID},
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "exactly once")
+}
+
+// TestModuleValidationAndNotFound tests validation errors and lookups of missing resources
+func (suite *IntegrationTestSuite) TestModuleValidationAndNotFound() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+	other := suite.createTestCourse("Other Course", "Test Description", "Beginner")
+	module := suite.createTestModule(course.ID, "Introduction")
+	headers := suite.getAuthHeaders()
+
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/modules", course.ID), models.ModuleRequest{}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Title is required")
+
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/modules/%s/lessons", course.ID, module.ID), models.LessonRequest{
+		Title:   "Lesson",
+		Content: "Content",
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Duration must be a positive number of minutes")
+
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/modules", uuid.New()), models.ModuleRequest{Title: "Module"}, headers)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "Course not found")
+
+	recorder = suite.makeRequest("GET", "/api/v1/courses/invalid-id/modules", nil, nil)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Invalid course ID format")
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/modules/invalid-id", course.ID), nil, nil)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Invalid module ID format")
+
+	// A module is only reachable through its own course
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/modules/%s", other.ID, module.ID), nil, nil)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "Module not found")
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/modules/%s/lessons/%s", course.ID, module.ID, uuid.New()), nil, nil)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "Lesson not found")
+}
+
+// TestModuleWritesRequireAdmin tests that the outline can only be changed by an admin
+func (suite *IntegrationTestSuite) TestModuleWritesRequireAdmin() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/modules", course.ID), models.ModuleRequest{Title: "Module"}, nil)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+
+	var count int64
+	suite.db.Model(&models.CourseModule{}).Where("course_id = ?", course.ID).Count(&count)
+	suite.Equal(int64(0), count)
+}
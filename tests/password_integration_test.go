@@ -0,0 +1,270 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/config"
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/database"
+	"sonic-labs/course-enrollment-service/internal/models"
+	"sonic-labs/course-enrollment-service/internal/router"
+)
+
+// usePasswordPolicy is a helper function that routes the rest of a test
+// through a router enforcing the password policy. The returned function
+// restores the suite router.
+func (suite *IntegrationTestSuite) usePasswordPolicy(policy config.PasswordConfig) func() {
+	cfg := *suite.cfg
+	cfg.Password = policy
+
+	previous := suite.router
+	suite.router = router.Setup(suite.db, &cfg)
+	return func() { suite.router = previous }
+}
+
+// changePassword is a helper function to change the password of the signed-in user
+func (suite *IntegrationTestSuite) changePassword(headers map[string]string, current, next string) (int, models.LoginResponse) {
+	recorder := suite.makeRequest("POST", "/api/v1/auth/change-password", models.ChangePasswordRequest{
+		CurrentPassword: current,
+		NewPassword:     next,
+	}, headers)
+
+	var login models.LoginResponse
+	if recorder.Code == http.StatusOK {
+		suite.parseResponse(recorder, &login)
+	}
+	return recorder.Code, login
+}
+
+// TestMustChangePassword tests that users with a temporary password can do
+// nothing else until they change it
+func (suite *IntegrationTestSuite) TestMustChangePassword() {
+	recorder := suite.makeRequest("POST", "/api/v1/admin/users", models.CreateUserRequest{
+		Username:           "registrar",
+		Password:           "correct horse battery",
+		Role:               constants.RoleAdmin,
+		MustChangePassword: true,
+	}, suite.getSuperAdminHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+	var registrar models.UserResponse
+	suite.parseResponse(recorder, &registrar)
+	suite.True(registrar.MustChangePassword)
+	key := suite.createTestAPIKey(registrar.ID, constants.PermissionStudentRead)
+
+	recorder = suite.makeRequest("POST", "/api/v1/auth/login", models.LoginRequest{
+		Username: "registrar",
+		Password: "correct horse battery",
+	}, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var login models.LoginResponse
+	suite.parseResponse(recorder, &login)
+	suite.True(login.User.MustChangePassword)
+	headers := map[string]string{"Authorization": "Bearer " + login.Token}
+
+	// Everything but the profile, logout and changing the password is refused
+	for _, caller := range []map[string]string{headers, {"X-API-Key": key.Key}} {
+		recorder = suite.makeRequest("GET", "/api/v1/admin/students", nil, caller)
+		suite.assertErrorResponse(recorder, http.StatusForbidden, constants.MsgPasswordChangeRequired)
+		recorder = suite.makeRequest("GET", "/api/v1/students/student@example.com/enrollments", nil, caller)
+		suite.assertErrorResponse(recorder, http.StatusForbidden, constants.MsgPasswordChangeRequired)
+	}
+
+	recorder = suite.makeRequest("GET", "/api/v1/auth/profile", nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var profile models.UserResponse
+	suite.parseResponse(recorder, &profile)
+	suite.True(profile.MustChangePassword)
+
+	code, _ := suite.changePassword(headers, "wrong", "a new secret phrase")
+	suite.Equal(http.StatusBadRequest, code)
+	recorder = suite.makeRequest("POST", "/api/v1/auth/change-password", models.ChangePasswordRequest{
+		CurrentPassword: "correct horse battery",
+		NewPassword:     "correct horse battery",
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "must differ")
+	recorder = suite.makeRequest("POST", "/api/v1/auth/change-password", models.ChangePasswordRequest{
+		CurrentPassword: "correct horse battery",
+		NewPassword:     "short",
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Password must be at least 8 characters")
+
+	code, changed := suite.changePassword(headers, "correct horse battery", "a new secret phrase")
+	suite.Require().Equal(http.StatusOK, code)
+	suite.False(changed.User.MustChangePassword)
+	newHeaders := map[string]string{"Authorization": "Bearer " + changed.Token}
+
+	recorder = suite.makeRequest("GET", "/api/v1/admin/students", nil, newHeaders)
+	suite.Equal(http.StatusOK, recorder.Code)
+	recorder = suite.makeRequest("GET", "/api/v1/admin/students", nil, map[string]string{"X-API-Key": key.Key})
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	// The session the password was changed in is over, and its refresh token too
+	recorder = suite.makeRequest("GET", "/api/v1/auth/profile", nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusUnauthorized, constants.MsgJWTTokenRevoked)
+	recorder = suite.makeRequest("POST", "/api/v1/auth/refresh", models.RefreshRequest{RefreshToken: login.RefreshToken}, nil)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+
+	suite.Equal(http.StatusUnauthorized, suite.loginFrom("203.0.113.7", "registrar", "correct horse battery").Code)
+	suite.Equal(http.StatusOK, suite.loginFrom("203.0.113.7", "registrar", "a new secret phrase").Code)
+}
+
+// TestPasswordPolicy tests that new passwords must meet the configured policy
+func (suite *IntegrationTestSuite) TestPasswordPolicy() {
+	defer suite.usePasswordPolicy(config.PasswordConfig{
+		MinLength:     12,
+		RequireUpper:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	})()
+
+	cases := []struct {
+		password string
+		message  string
+	}{
+		{"Short1!", "Password must be at least 12 characters"},
+		{"lowercase only 1", "Password must contain an uppercase letter"},
+		{"No digits at all", "Password must contain a digit"},
+		{"NoSymbolsHere12", "Password must contain a symbol"},
+	}
+	for _, tc := range cases {
+		recorder := suite.makeRequest("POST", "/api/v1/auth/register", models.RegisterRequest{
+			Email:    "student@example.com",
+			Password: tc.password,
+		}, nil)
+		suite.assertErrorResponse(recorder, http.StatusBadRequest, tc.message)
+
+		recorder = suite.makeRequest("POST", "/api/v1/admin/users", models.CreateUserRequest{
+			Username: "registrar",
+			Password: tc.password,
+			Role:     constants.RoleAdmin,
+		}, suite.getSuperAdminHeaders())
+		suite.assertErrorResponse(recorder, http.StatusBadRequest, tc.message)
+	}
+
+	recorder := suite.makeRequest("POST", "/api/v1/auth/register", models.RegisterRequest{
+		Email:    "student@example.com",
+		Password: "Correct horse 42",
+	}, nil)
+	suite.Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+}
+
+// TestPasswordReset tests admins issuing reset tokens and users setting a new
+// password with them
+func (suite *IntegrationTestSuite) TestPasswordReset() {
+	headers := suite.getSuperAdminHeaders()
+	registrar := suite.createTestUser("registrar", constants.RoleAdmin)
+	url := fmt.Sprintf("/api/v1/admin/users/%s/password-reset", registrar.ID)
+
+	issue := func() models.PasswordResetResponse {
+		recorder := suite.makeRequest("POST", url, nil, headers)
+		suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+		var reset models.PasswordResetResponse
+		suite.parseResponse(recorder, &reset)
+		return reset
+	}
+
+	first := issue()
+	suite.True(strings.HasPrefix(first.Token, constants.PasswordResetTokenPrefix))
+	suite.Equal("registrar", first.User.Username)
+	suite.WithinDuration(time.Now().Add(constants.PasswordResetTokenExpiry), first.ExpiresAt, time.Minute)
+
+	// A new token replaces the first, and the password still works until it is used
+	reset := issue()
+	suite.NotEqual(first.Token, reset.Token)
+	recorder := suite.loginFrom("203.0.113.7", "registrar", "correct horse battery")
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var before models.LoginResponse
+	suite.parseResponse(recorder, &before)
+	// Token times are in whole seconds
+	time.Sleep(time.Second)
+
+	recorder = suite.makeRequest("POST", "/api/v1/auth/reset-password", models.ResetPasswordRequest{
+		Token:       first.Token,
+		NewPassword: "a new secret phrase",
+	}, nil)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Reset token is invalid")
+	recorder = suite.makeRequest("POST", "/api/v1/auth/reset-password", models.ResetPasswordRequest{
+		Token:       reset.Token,
+		NewPassword: "short",
+	}, nil)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Password must be at least 8 characters")
+
+	recorder = suite.makeRequest("POST", "/api/v1/auth/reset-password", models.ResetPasswordRequest{
+		Token:       reset.Token,
+		NewPassword: "a new secret phrase",
+	}, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	var login models.LoginResponse
+	suite.parseResponse(recorder, &login)
+	suite.Equal(registrar.ID, login.User.ID)
+	suite.NotEmpty(login.Token)
+
+	recorder = suite.makeRequest("POST", "/api/v1/auth/reset-password", models.ResetPasswordRequest{
+		Token:       reset.Token,
+		NewPassword: "another secret phrase",
+	}, nil)
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+
+	// Sessions started with the old password cannot be refreshed
+	recorder = suite.makeRequest("POST", "/api/v1/auth/refresh", models.RefreshRequest{RefreshToken: before.RefreshToken}, nil)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+	recorder = suite.makeRequest("POST", "/api/v1/auth/refresh", models.RefreshRequest{RefreshToken: login.RefreshToken}, nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	code, _ := suite.loginTestUser("registrar")
+	suite.Equal(http.StatusUnauthorized, code)
+	suite.Equal(http.StatusOK, suite.loginFrom("203.0.113.7", "registrar", "a new secret phrase").Code)
+
+	// Expired tokens do not work, and only super-admins can issue them
+	expired := issue()
+	suite.Require().NoError(suite.db.Model(&models.User{}).Where("id = ?", registrar.ID).
+		Update("password_reset_expires_at", time.Now().Add(-time.Minute)).Error)
+	recorder = suite.makeRequest("POST", "/api/v1/auth/reset-password", models.ResetPasswordRequest{
+		Token:       expired.Token,
+		NewPassword: "another secret phrase",
+	}, nil)
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+
+	recorder = suite.makeRequest("POST", url, nil, suite.getAuthHeaders())
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "Permission user:manage is required")
+	recorder = suite.makeRequest("POST", "/api/v1/admin/users/12345678-0000-0000-0000-000000000000/password-reset", nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "User not found")
+}
+
+// TestSeedAdminUser tests that the first admin gets the configured credentials
+// and must change the password
+func (suite *IntegrationTestSuite) TestSeedAdminUser() {
+	admin := config.AdminConfig{Username: "ops", Password: "first start secret"}
+	suite.Require().NoError(database.SeedAdminUser(suite.db, admin))
+
+	var ops models.User
+	suite.Require().NoError(suite.db.First(&ops, "username = ?", "ops").Error)
+	suite.Equal(constants.RoleSuperAdmin, ops.Role)
+	suite.True(ops.MustChangePassword)
+
+	// Seeding again adds no second super-admin
+	suite.Require().NoError(database.SeedAdminUser(suite.db, config.AdminConfig{Username: "ops2", Password: "first start secret"}))
+	var count int64
+	suite.db.Model(&models.User{}).Where("role = ?", constants.RoleSuperAdmin).Count(&count)
+	suite.Equal(int64(1), count)
+
+	recorder := suite.makeRequest("POST", "/api/v1/auth/login", models.LoginRequest{
+		Username: "ops",
+		Password: "first start secret",
+	}, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var login models.LoginResponse
+	suite.parseResponse(recorder, &login)
+	headers := map[string]string{"Authorization": "Bearer " + login.Token}
+
+	recorder = suite.makeRequest("GET", "/api/v1/admin/users", nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusForbidden, constants.MsgPasswordChangeRequired)
+
+	code, changed := suite.changePassword(headers, "first start secret", "a new secret phrase")
+	suite.Require().Equal(http.StatusOK, code)
+	recorder = suite.makeRequest("GET", "/api/v1/admin/users", nil, map[string]string{"Authorization": "Bearer " + changed.Token})
+	suite.Equal(http.StatusOK, recorder.Code)
+}
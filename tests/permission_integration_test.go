@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+)
+
+// getSuperAdminHeaders is a helper function to create a super-admin and log in
+// with it. The super-admin shares the password of the test admin.
+func (suite *IntegrationTestSuite) getSuperAdminHeaders() map[string]string {
+	err := suite.db.Exec(`
+		INSERT OR IGNORE INTO users (id, username, password, role)
+		SELECT '22345678-1234-1234-1234-123456789012', 'root', password, ? FROM users WHERE username = 'admin'
+	`, constants.RoleSuperAdmin).Error
+	suite.Require().NoError(err)
+
+	recorder := suite.makeRequest("POST", "/api/v1/auth/login", models.LoginRequest{
+		Username: "root",
+		Password: "admin!dev",
+	}, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var account models.LoginResponse
+	suite.parseResponse(recorder, &account)
+	return map[string]string{"Authorization": "Bearer " + account.Token}
+}
+
+// setRolePermissions is a helper function to replace the permissions of a role
+func (suite *IntegrationTestSuite) setRolePermissions(role string, permissions ...string) {
+	recorder := suite.makeRequest("PUT", fmt.Sprintf("/api/v1/admin/roles/%s/permissions", role), models.RolePermissionsRequest{
+		Permissions: append([]string{}, permissions...),
+	}, suite.getSuperAdminHeaders())
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+}
+
+// TestRoleManagement tests listing and editing the permissions of roles
+func (suite *IntegrationTestSuite) TestRoleManagement() {
+	headers := suite.getSuperAdminHeaders()
+
+	recorder := suite.makeRequest("GET", "/api/v1/admin/roles", nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var roles models.RoleListResponse
+	suite.parseResponse(recorder, &roles)
+	suite.Equal(constants.GrantablePermissions, roles.Permissions)
+	suite.Require().Len(roles.Roles, 4)
+	suite.Equal(constants.RoleSuperAdmin, roles.Roles[0].Role)
+	suite.False(roles.Roles[0].Editable)
+	suite.Contains(roles.Roles[0].Permissions, constants.PermissionRoleManage)
+	suite.Equal(constants.RoleInstructor, roles.Roles[2].Role)
+	suite.ElementsMatch(seededRolePermissions[constants.RoleInstructor], roles.Roles[2].Permissions)
+	suite.Equal(constants.RoleUser, roles.Roles[3].Role)
+	suite.Empty(roles.Roles[3].Permissions)
+
+	// Duplicates are dropped and the permissions come back sorted
+	recorder = suite.makeRequest("PUT", "/api/v1/admin/roles/user/permissions", models.RolePermissionsRequest{
+		Permissions: []string{"enrollment:read", "course:write", "enrollment:read"},
+	}, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var role models.RoleResponse
+	suite.parseResponse(recorder, &role)
+	suite.Equal([]string{"course:write", "enrollment:read"}, role.Permissions)
+
+	recorder = suite.makeRequest("PUT", "/api/v1/admin/roles/user/permissions", models.RolePermissionsRequest{
+		Permissions: []string{"course:fly"},
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "must be among")
+
+	// role:manage stays with super-admins
+	recorder = suite.makeRequest("PUT", "/api/v1/admin/roles/admin/permissions", models.RolePermissionsRequest{
+		Permissions: []string{constants.PermissionRoleManage},
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "must be among")
+
+	recorder = suite.makeRequest("PUT", "/api/v1/admin/roles/super_admin/permissions", models.RolePermissionsRequest{
+		Permissions: []string{},
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "cannot be edited")
+
+	recorder = suite.makeRequest("PUT", "/api/v1/admin/roles/janitor/permissions", models.RolePermissionsRequest{
+		Permissions: []string{},
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "Role not found")
+
+	recorder = suite.makeRequest("PUT", "/api/v1/admin/roles/user/permissions", map[string]interface{}{}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Permissions are required")
+
+	// Admins hold every grantable permission but cannot manage roles
+	recorder = suite.makeRequest("GET", "/api/v1/admin/roles", nil, suite.getAuthHeaders())
+	suite.assertErrorResponse(recorder, http.StatusForbidden, constants.PermissionRoleManage)
+
+	recorder = suite.makeRequest("GET", "/api/v1/admin/roles", nil, nil)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+}
+
+// TestRequirePermission tests that routes follow the stored permissions of roles
+func (suite *IntegrationTestSuite) TestRequirePermission() {
+	adminHeaders := suite.getAuthHeaders()
+	course := suite.createTestCourse("Compilers", "Parsing to code generation", "Advanced")
+
+	// Taking course:delete away from admins stops them deleting courses
+	suite.setRolePermissions(constants.RoleAdmin, constants.PermissionCourseWrite)
+
+	recorder := suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/courses/%s", course.ID), nil, adminHeaders)
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "Permission course:delete is required")
+
+	recorder = suite.makeRequest("GET", "/api/v1/admin/students", nil, adminHeaders)
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "student:read")
+
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s", course.ID), models.CourseRequest{
+		Title:       "Compilers II",
+		Description: "Parsing to code generation",
+		Difficulty:  "Advanced",
+	}, adminHeaders)
+	suite.Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+
+	// Super-admins hold every permission whatever is stored
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/courses/%s", course.ID), nil, suite.getSuperAdminHeaders())
+	suite.Equal(http.StatusNoContent, recorder.Code, recorder.Body.String())
+}
+
+// TestInstructorPermissions tests that instructors use the permissions of their
+// role only on the courses they teach
+func (suite *IntegrationTestSuite) TestInstructorPermissions() {
+	ada := suite.createTestInstructor("Ada Lovelace", "ada@example.com")
+	course := suite.createTestCourse("Analytical Engines", "Notes on the engine", "Advanced")
+	suite.setCourseInstructors(course.ID, ada.ID)
+	headers := suite.signInTestInstructor(ada)
+
+	recorder := suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/students", course.ID), nil, headers)
+	suite.Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+
+	// enrollment:read does not reach the enrollments of every course
+	recorder = suite.makeRequest("GET", "/api/v1/admin/enrollments", nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "enrollment:read")
+
+	// Nor are they allowed what their role was not granted
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/courses/%s", course.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "course:delete")
+
+	suite.setRolePermissions(constants.RoleInstructor, constants.PermissionCourseWrite)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/students", course.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "enrollment:read")
+
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/modules", course.ID), models.ModuleRequest{
+		Title: "The Engine",
+	}, headers)
+	suite.Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+}
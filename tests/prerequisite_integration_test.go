@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// TestPrerequisiteManagement tests adding, listing, replacing and removing course prerequisites
+func (suite *IntegrationTestSuite) TestPrerequisiteManagement() {
+	sql := suite.createTestCourse("Database Design and SQL", "Relational modelling", "Beginner")
+	data := suite.createTestCourse("Advanced Data Engineering", "Pipelines at scale", "Advanced")
+	headers := suite.getAuthHeaders()
+	url := fmt.Sprintf("/api/v1/courses/%s/prerequisites", data.ID)
+
+	recorder := suite.makeRequest("POST", url, models.PrerequisiteRequest{PrerequisiteID: sql.ID}, headers)
+	suite.Equal(http.StatusCreated, recorder.Code)
+
+	recorder = suite.makeRequest("POST", url, models.PrerequisiteRequest{PrerequisiteID: sql.ID}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "already exists")
+
+	// Reading prerequisites is public
+	recorder = suite.makeRequest("GET", url, nil, nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+	var prerequisites models.PrerequisitesResponse
+	suite.parseResponse(recorder, &prerequisites)
+	suite.Require().Len(prerequisites.Prerequisites, 1)
+	suite.Equal("Database Design and SQL", prerequisites.Prerequisites[0].Title)
+
+	recorder = suite.makeRequest("POST", url, models.PrerequisiteRequest{PrerequisiteID: uuid.New()}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "does not exist")
+
+	recorder = suite.makeRequest("PUT", url, models.PrerequisitesReplaceRequest{PrerequisiteIDs: []uuid.UUID{}}, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.parseResponse(recorder, &prerequisites)
+	suite.Equal(0, prerequisites.Total)
+
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("%s/%s", url, sql.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "Prerequisite not found")
+}
+
+// TestPrerequisiteCycleDetection tests that prerequisite edges forming a cycle are rejected
+func (suite *IntegrationTestSuite) TestPrerequisiteCycleDetection() {
+	a := suite.createTestCourse("Course A", "First", "Beginner")
+	b := suite.createTestCourse("Course B", "Second", "Intermediate")
+	c := suite.createTestCourse("Course C", "Third", "Advanced")
+	headers := suite.getAuthHeaders()
+
+	// C requires B, B requires A
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/prerequisites", c.ID), models.PrerequisiteRequest{PrerequisiteID: b.ID}, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/prerequisites", b.ID), models.PrerequisiteRequest{PrerequisiteID: a.ID}, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	// A requiring C would close the loop
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/prerequisites", a.ID), models.PrerequisiteRequest{PrerequisiteID: c.ID}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "cycle")
+
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s/prerequisites", a.ID), models.PrerequisitesReplaceRequest{PrerequisiteIDs: []uuid.UUID{b.ID}}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "cycle")
+
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/prerequisites", a.ID), models.PrerequisiteRequest{PrerequisiteID: a.ID}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "own prerequisite")
+}
+
+// TestEnrollmentRequiresPrerequisites tests that enrollment checks completed prerequisites and records overrides
+func (suite *IntegrationTestSuite) TestEnrollmentRequiresPrerequisites() {
+	sql := suite.createTestCourse("Database Design and SQL", "Relational modelling", "Beginner")
+	data := suite.createTestCourse("Advanced Data Engineering", "Pipelines at scale", "Advanced")
+	headers := suite.getAuthHeaders()
+
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/prerequisites", data.ID), models.PrerequisiteRequest{PrerequisiteID: sql.ID}, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     data.ID,
+	}, headers)
+	suite.Equal(http.StatusUnprocessableEntity, recorder.Code)
+	var rejected struct {
+		MissingPrerequisites []models.CourseResponse `json:"missing_prerequisites"`
+	}
+	suite.parseResponse(recorder, &rejected)
+	suite.Require().Len(rejected.MissingPrerequisites, 1)
+	suite.Equal(sql.ID, rejected.MissingPrerequisites[0].ID)
+
+	// Completing the prerequisite unlocks the course
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     sql.ID,
+	}, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+	var enrollment models.EnrollmentResponse
+	suite.parseResponse(recorder, &enrollment)
+	recorder = suite.makeRequest("PATCH", fmt.Sprintf("/api/v1/admin/enrollments/%s/status", enrollment.ID), models.EnrollmentStatusRequest{Status: "completed"}, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     data.ID,
+	}, headers)
+	suite.Equal(http.StatusCreated, recorder.Code)
+
+	// An admin override skips the check and is recorded
+	reason := "Took an equivalent course elsewhere"
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail:          "transfer@example.com",
+		CourseID:              data.ID,
+		OverridePrerequisites: true,
+		OverrideReason:        &reason,
+	}, headers)
+	suite.Equal(http.StatusCreated, recorder.Code)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/prerequisites/overrides", data.ID), nil, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+	var overrides models.PrerequisiteOverridesResponse
+	suite.parseResponse(recorder, &overrides)
+	suite.Require().Len(overrides.Overrides, 1)
+	suite.Equal("transfer@example.com", overrides.Overrides[0].StudentEmail)
+	suite.Equal("admin", overrides.Overrides[0].OverriddenBy)
+	suite.Equal([]uuid.UUID{sql.ID}, overrides.Overrides[0].MissingPrerequisiteIDs)
+	suite.Equal(reason, *overrides.Overrides[0].Reason)
+}
@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// enrollTestStudent is a helper function to enroll a student through the API
+func (suite *IntegrationTestSuite) enrollTestStudent(email string, courseID uuid.UUID) models.EnrollmentResponse {
+	recorder := suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: email,
+		CourseID:     courseID,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	var enrollment models.EnrollmentResponse
+	suite.parseResponse(recorder, &enrollment)
+	return enrollment
+}
+
+// recordProgress is a helper function to mark a lesson as started or completed
+func (suite *IntegrationTestSuite) recordProgress(enrollmentID, lessonID uuid.UUID, status string) *httptest.ResponseRecorder {
+	return suite.makeRequest("PUT", fmt.Sprintf("/api/v1/admin/enrollments/%s/progress/%s", enrollmentID, lessonID), models.LessonProgressRequest{
+		Status: status,
+	}, suite.getAuthHeaders())
+}
+
+// TestLessonProgressCompletesEnrollment tests progress tracking through to automatic completion
+func (suite *IntegrationTestSuite) TestLessonProgressCompletesEnrollment() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+	module := suite.createTestModule(course.ID, "Introduction")
+	first := suite.createTestLesson(course.ID, module.ID, "First", 10)
+	second := suite.createTestLesson(course.ID, module.ID, "Second", 10)
+	suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/modules/%s/lessons", course.ID, module.ID), models.LessonRequest{
+		Title:           "Bonus",
+		Content:         "Extra reading",
+		DurationMinutes: 5,
+		Optional:        true,
+	}, suite.getAuthHeaders())
+	enrollment := suite.enrollTestStudent("student@example.com", course.ID)
+
+	recorder := suite.recordProgress(enrollment.ID, first.ID, constants.LessonProgressStarted)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var progress models.EnrollmentProgressResponse
+	suite.parseResponse(recorder, &progress)
+	suite.Equal(2, progress.RequiredLessons)
+	suite.Equal(1, progress.LessonsStarted)
+	suite.Equal(0, progress.CompletionPercentage)
+	suite.Require().Len(progress.Lessons, 3)
+	suite.Equal(constants.LessonProgressStarted, progress.Lessons[0].Status)
+	suite.Equal(constants.LessonProgressNotStarted, progress.Lessons[1].Status)
+	suite.True(progress.Lessons[2].Optional)
+
+	recorder = suite.recordProgress(enrollment.ID, first.ID, constants.LessonProgressCompleted)
+	suite.Equal(http.StatusOK, recorder.Code)
+	progress = models.EnrollmentProgressResponse{}
+	suite.parseResponse(recorder, &progress)
+	suite.Equal(50, progress.CompletionPercentage)
+	suite.Equal(0, progress.LessonsStarted)
+	suite.Equal(1, progress.LessonsCompleted)
+	suite.Equal(constants.EnrollmentStatusActive, progress.Status)
+
+	// Starting a completed lesson again does not undo it
+	recorder = suite.recordProgress(enrollment.ID, first.ID, constants.LessonProgressStarted)
+	progress = models.EnrollmentProgressResponse{}
+	suite.parseResponse(recorder, &progress)
+	suite.Equal(constants.LessonProgressCompleted, progress.Lessons[0].Status)
+	suite.NotNil(progress.Lessons[0].CompletedAt)
+
+	// Completing the last required lesson completes the enrollment; the optional one is not needed
+	recorder = suite.recordProgress(enrollment.ID, second.ID, constants.LessonProgressCompleted)
+	suite.Equal(http.StatusOK, recorder.Code)
+	progress = models.EnrollmentProgressResponse{}
+	suite.parseResponse(recorder, &progress)
+	suite.Equal(100, progress.CompletionPercentage)
+	suite.Equal(constants.EnrollmentStatusCompleted, progress.Status)
+
+	var stored models.Enrollment
+	suite.Require().NoError(suite.db.First(&stored, "id = ?", enrollment.ID).Error)
+	suite.Equal(constants.EnrollmentStatusCompleted, stored.Status)
+
+	var change models.EnrollmentStatusChange
+	suite.Require().NoError(suite.db.Where("enrollment_id = ? AND to_status = ?", enrollment.ID, constants.EnrollmentStatusCompleted).First(&change).Error)
+	suite.Equal("admin", change.ChangedBy)
+	suite.Require().NotNil(change.Reason)
+	suite.Equal("All required lessons completed", *change.Reason)
+
+	// Completed enrollments take no further progress
+	recorder = suite.recordProgress(enrollment.ID, first.ID, constants.LessonProgressCompleted)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "active enrollments")
+}
+
+// TestStudentEnrollmentsIncludeProgress tests that listing a student's enrollments reports progress
+func (suite *IntegrationTestSuite) TestStudentEnrollmentsIncludeProgress() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+	other := suite.createTestCourse("Other Course", "Test Description", "Beginner")
+	module := suite.createTestModule(course.ID, "Introduction")
+	lessons := []models.LessonResponse{
+		suite.createTestLesson(course.ID, module.ID, "One", 10),
+		suite.createTestLesson(course.ID, module.ID, "Two", 10),
+		suite.createTestLesson(course.ID, module.ID, "Three", 10),
+	}
+	enrollment := suite.enrollTestStudent("student@example.com", course.ID)
+	suite.enrollTestStudent("student@example.com", other.ID)
+
+	suite.Equal(http.StatusOK, suite.recordProgress(enrollment.ID, lessons[0].ID, constants.LessonProgressCompleted).Code)
+	suite.Equal(http.StatusOK, suite.recordProgress(enrollment.ID, lessons[1].ID, constants.LessonProgressStarted).Code)
+
+	recorder := suite.makeRequest("GET", "/api/v1/students/student@example.com/enrollments", nil, suite.getAuthHeaders())
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var response models.StudentEnrollmentsResponse
+	suite.parseResponse(recorder, &response)
+	suite.Require().Len(response.Enrollments, 2)
+	for _, e := range response.Enrollments {
+		suite.Require().NotNil(e.Progress)
+		if e.CourseID == course.ID {
+			suite.Equal(3, e.Progress.RequiredLessons)
+			suite.Equal(1, e.Progress.RequiredLessonsCompleted)
+			suite.Equal(1, e.Progress.LessonsStarted)
+			suite.Equal(33, e.Progress.CompletionPercentage)
+		} else {
+			suite.Equal(0, e.Progress.RequiredLessons)
+			suite.Equal(0, e.Progress.CompletionPercentage)
+		}
+	}
+}
+
+// TestLessonProgressErrors tests validation and lookups when recording progress
+func (suite *IntegrationTestSuite) TestLessonProgressErrors() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+	other := suite.createTestCourse("Other Course", "Test Description", "Beginner")
+	lesson := suite.createTestLesson(course.ID, suite.createTestModule(course.ID, "Introduction").ID, "One", 10)
+	foreign := suite.createTestLesson(other.ID, suite.createTestModule(other.ID, "Elsewhere").ID, "Other", 10)
+	enrollment := suite.enrollTestStudent("student@example.com", course.ID)
+
+	recorder := suite.recordProgress(enrollment.ID, lesson.ID, "finished")
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "started, completed")
+
+	recorder = suite.recordProgress(enrollment.ID, foreign.ID, constants.LessonProgressCompleted)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "Lesson not found")
+
+	recorder = suite.recordProgress(uuid.New(), lesson.ID, constants.LessonProgressCompleted)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "Enrollment not found")
+
+	recorder = suite.makeRequest("GET", "/api/v1/admin/enrollments/invalid-id/progress", nil, suite.getAuthHeaders())
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Invalid enrollment ID format")
+
+	// Dropped enrollments take no progress
+	recorder = suite.makeRequest("PATCH", fmt.Sprintf("/api/v1/admin/enrollments/%s/status", enrollment.ID), models.EnrollmentStatusRequest{
+		Status: constants.EnrollmentStatusDropped,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	recorder = suite.recordProgress(enrollment.ID, lesson.ID, constants.LessonProgressStarted)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "active enrollments")
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/admin/enrollments/%s/progress", enrollment.ID), nil, nil)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+}
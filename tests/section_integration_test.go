@@ -0,0 +1,229 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"sonic-labs/course-enrollment-service/internal/handler"
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// createTestSection is a helper function to add a section to a course through the API
+func (suite *IntegrationTestSuite) createTestSection(courseID uuid.UUID, offeringID *uuid.UUID, name string, meetings ...models.SectionMeetingRequest) models.SectionResponse {
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/sections", courseID), models.SectionRequest{
+		OfferingID: offeringID,
+		Name:       name,
+		Meetings:   meetings,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	var section models.SectionResponse
+	suite.parseResponse(recorder, &section)
+	return section
+}
+
+// meeting is a helper function to build a weekly meeting slot
+func meeting(day, start, end, timeZone string) models.SectionMeetingRequest {
+	return models.SectionMeetingRequest{Day: day, StartTime: start, EndTime: end, TimeZone: timeZone}
+}
+
+// enrollInSection is a helper function to enroll a student in a section through the API
+func (suite *IntegrationTestSuite) enrollInSection(email string, courseID, sectionID uuid.UUID, override bool) *httptest.ResponseRecorder {
+	return suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail:              email,
+		CourseID:                  courseID,
+		SectionID:                 &sectionID,
+		OverrideScheduleConflicts: override,
+	}, suite.getAuthHeaders())
+}
+
+// TestSectionLifecycle tests creating, listing, updating and deleting sections
+func (suite *IntegrationTestSuite) TestSectionLifecycle() {
+	headers := suite.getAuthHeaders()
+	course := suite.createTestCourse("Sectioned Course", "Description", "beginner")
+
+	section := suite.createTestSection(course.ID, nil, "A01",
+		meeting("Wednesday", "14:00", "15:30", "UTC"),
+		meeting("monday", "10:00", "11:00", "UTC"),
+	)
+	suite.Equal("A01", section.Name)
+	suite.Nil(section.Term)
+	suite.Require().Len(section.Meetings, 2)
+	suite.Equal("monday", section.Meetings[0].Day)
+	suite.Equal("wednesday", section.Meetings[1].Day)
+
+	// Names are unique within an offering
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/sections", course.ID), models.SectionRequest{
+		Name: "A01",
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "already exists")
+
+	// Meetings are validated
+	for _, invalid := range []models.SectionMeetingRequest{
+		meeting("someday", "10:00", "11:00", "UTC"),
+		meeting("monday", "25:00", "26:00", "UTC"),
+		meeting("monday", "11:00", "10:00", "UTC"),
+		meeting("monday", "10:00", "11:00", "Mars/Olympus"),
+	} {
+		recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/sections", course.ID), models.SectionRequest{
+			Name:     "B01",
+			Meetings: []models.SectionMeetingRequest{invalid},
+		}, headers)
+		suite.Equal(http.StatusBadRequest, recorder.Code)
+	}
+
+	// Updating replaces every meeting
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s/sections/%s", course.ID, section.ID), models.SectionRequest{
+		Name:     "A02",
+		Meetings: []models.SectionMeetingRequest{meeting("friday", "09:00", "10:00", "UTC")},
+	}, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	suite.parseResponse(recorder, &section)
+	suite.Equal("A02", section.Name)
+	suite.Require().Len(section.Meetings, 1)
+	suite.Equal("friday", section.Meetings[0].Day)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/sections", course.ID), nil, nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+	var list models.SectionListResponse
+	suite.parseResponse(recorder, &list)
+	suite.Equal(1, list.Total)
+
+	// Sections with enrollments cannot be deleted
+	suite.Require().Equal(http.StatusCreated, suite.enrollInSection("student@example.com", course.ID, section.ID, false).Code)
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/courses/%s/sections/%s", course.ID, section.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "enrolled")
+
+	empty := suite.createTestSection(course.ID, nil, "Z99")
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/courses/%s/sections/%s", course.ID, empty.ID), nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/sections/%s", course.ID, empty.ID), nil, nil)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "Section not found")
+}
+
+// TestSectionEnrollmentConflicts tests that overlapping sections are rejected
+// with the conflicting sections unless the conflict is overridden
+func (suite *IntegrationTestSuite) TestSectionEnrollmentConflicts() {
+	email := "busy@example.com"
+	algebra := suite.createTestCourse("Algebra", "Description", "beginner")
+	biology := suite.createTestCourse("Biology", "Description", "beginner")
+	chemistry := suite.createTestCourse("Chemistry", "Description", "beginner")
+
+	algebraSection := suite.createTestSection(algebra.ID, nil, "A01", meeting("monday", "10:00", "11:30", "Europe/Amsterdam"))
+	// 09:00 UTC is 10:00 or 11:00 in Amsterdam, so this clashes with algebra
+	biologySection := suite.createTestSection(biology.ID, nil, "B01", meeting("monday", "09:00", "10:00", "UTC"))
+	// Ends exactly when algebra starts in Amsterdam
+	chemistrySection := suite.createTestSection(chemistry.ID, nil, "C01", meeting("monday", "09:00", "10:00", "Europe/Amsterdam"))
+
+	recorder := suite.enrollInSection(email, algebra.ID, algebraSection.ID, false)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+	var enrollment models.EnrollmentResponse
+	suite.parseResponse(recorder, &enrollment)
+	suite.Require().NotNil(enrollment.SectionID)
+	suite.Equal(algebraSection.ID, *enrollment.SectionID)
+
+	recorder = suite.enrollInSection(email, biology.ID, biologySection.ID, false)
+	suite.Require().Equal(http.StatusConflict, recorder.Code)
+	var conflict handler.ScheduleConflictErrorResponse
+	suite.parseResponse(recorder, &conflict)
+	suite.Equal("Schedule conflict", conflict.Error)
+	suite.Require().Len(conflict.Conflicts, 1)
+	suite.Equal(algebraSection.ID, conflict.Conflicts[0].ID)
+
+	recorder = suite.enrollInSection(email, chemistry.ID, chemistrySection.ID, false)
+	suite.Equal(http.StatusCreated, recorder.Code)
+
+	recorder = suite.enrollInSection(email, biology.ID, biologySection.ID, true)
+	suite.Equal(http.StatusCreated, recorder.Code)
+
+	// A section from another course is not found
+	recorder = suite.enrollInSection("other@example.com", biology.ID, algebraSection.ID, false)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "section does not exist")
+}
+
+// TestSectionConflictsRequireOverlappingTerms tests that sections in terms that do
+// not overlap never conflict
+func (suite *IntegrationTestSuite) TestSectionConflictsRequireOverlappingTerms() {
+	email := "planner@example.com"
+	start, end := upcomingTerm()
+	fall := suite.createTestTerm("Fall", start, end)
+	spring := suite.createTestTerm("Spring", end.AddDate(0, 1, 0), end.AddDate(0, 5, 0))
+
+	history := suite.createTestCourse("History", "Description", "beginner")
+	music := suite.createTestCourse("Music", "Description", "beginner")
+	historyFall := suite.createTestOffering(history.ID, fall.ID, nil)
+	musicSpring := suite.createTestOffering(music.ID, spring.ID, nil)
+
+	historySection := suite.createTestSection(history.ID, &historyFall.ID, "H01", meeting("tuesday", "13:00", "14:00", "UTC"))
+	musicSection := suite.createTestSection(music.ID, &musicSpring.ID, "M01", meeting("tuesday", "13:00", "14:00", "UTC"))
+	suite.Require().NotNil(historySection.Term)
+	suite.Equal(fall.ID, historySection.Term.ID)
+
+	suite.Require().Equal(http.StatusCreated, suite.enrollInSection(email, history.ID, historySection.ID, false).Code)
+	suite.Equal(http.StatusCreated, suite.enrollInSection(email, music.ID, musicSection.ID, false).Code)
+
+	// The section decides the offering
+	recorder := suite.makeRequest("GET", fmt.Sprintf("/api/v1/students/%s/enrollments", email), nil, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var enrollments models.StudentEnrollmentsResponse
+	suite.parseResponse(recorder, &enrollments)
+	offerings := map[uuid.UUID]uuid.UUID{}
+	for _, enrollment := range enrollments.Enrollments {
+		offerings[enrollment.CourseID] = enrollment.OfferingID
+	}
+	suite.Equal(historyFall.ID, offerings[history.ID])
+	suite.Equal(musicSpring.ID, offerings[music.ID])
+
+	// A section must belong to the requested offering
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "other@example.com",
+		CourseID:     history.ID,
+		OfferingID:   &musicSpring.ID,
+		SectionID:    &historySection.ID,
+	}, suite.getAuthHeaders())
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+}
+
+// TestStudentTimetable tests that the timetable lists meetings in weekly order
+// and can be limited to a term
+func (suite *IntegrationTestSuite) TestStudentTimetable() {
+	email := "timetable@example.com"
+	start, end := upcomingTerm()
+	fall := suite.createTestTerm("Fall", start, end)
+
+	art := suite.createTestCourse("Art", "Description", "beginner")
+	drama := suite.createTestCourse("Drama", "Description", "beginner")
+	dramaFall := suite.createTestOffering(drama.ID, fall.ID, nil)
+
+	artSection := suite.createTestSection(art.ID, nil, "A01",
+		meeting("thursday", "09:00", "10:00", "UTC"),
+		meeting("monday", "15:00", "16:00", "UTC"),
+	)
+	dramaSection := suite.createTestSection(drama.ID, &dramaFall.ID, "D01", meeting("monday", "08:00", "09:00", "UTC"))
+
+	suite.Require().Equal(http.StatusCreated, suite.enrollInSection(email, art.ID, artSection.ID, false).Code)
+	suite.Require().Equal(http.StatusCreated, suite.enrollInSection(email, drama.ID, dramaSection.ID, false).Code)
+
+	recorder := suite.makeRequest("GET", fmt.Sprintf("/api/v1/students/%s/timetable", email), nil, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var timetable models.TimetableResponse
+	suite.parseResponse(recorder, &timetable)
+	suite.Require().Equal(3, timetable.Total)
+	suite.Equal("Drama", timetable.Entries[0].CourseTitle)
+	suite.Equal("08:00", timetable.Entries[0].StartTime)
+	suite.Equal("Art", timetable.Entries[1].CourseTitle)
+	suite.Equal("monday", timetable.Entries[1].Day)
+	suite.Equal("thursday", timetable.Entries[2].Day)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/students/%s/timetable?term_id=%s", email, fall.ID), nil, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	suite.parseResponse(recorder, &timetable)
+	suite.Require().Equal(1, timetable.Total)
+	suite.Equal("Drama", timetable.Entries[0].CourseTitle)
+
+	recorder = suite.makeRequest("GET", "/api/v1/students/not-an-email/timetable", nil, suite.getAuthHeaders())
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Invalid email format")
+}
@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// registerTestStudent is a helper function to create a student account and return
+// headers authenticating as that student
+func (suite *IntegrationTestSuite) registerTestStudent(email string) (models.LoginResponse, map[string]string) {
+	recorder := suite.makeRequest("POST", "/api/v1/auth/register", models.RegisterRequest{
+		Email:    email,
+		Password: "correct horse battery",
+	}, nil)
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	var account models.LoginResponse
+	suite.parseResponse(recorder, &account)
+	return account, map[string]string{"Authorization": "Bearer " + account.Token}
+}
+
+// TestStudentRegistration tests creating a student account and logging in with it
+func (suite *IntegrationTestSuite) TestStudentRegistration() {
+	account, headers := suite.registerTestStudent("Learner@Example.com")
+	suite.NotEmpty(account.Token)
+	suite.Equal("learner@example.com", account.User.Username)
+	suite.Equal("user", account.User.Role)
+	suite.Require().NotNil(account.User.StudentID)
+
+	recorder := suite.makeRequest("POST", "/api/v1/auth/register", models.RegisterRequest{
+		Email:    "learner@example.com",
+		Password: "another password",
+	}, nil)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "already exists")
+	recorder = suite.makeRequest("POST", "/api/v1/auth/register", models.RegisterRequest{
+		Email:    "short@example.com",
+		Password: "short",
+	}, nil)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "at least 8 characters")
+	recorder = suite.makeRequest("POST", "/api/v1/auth/register", models.RegisterRequest{
+		Email:    "not-an-email",
+		Password: "long enough",
+	}, nil)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Invalid email format")
+
+	// Registering alone does not hand over a student an admin already enrolled
+	course := suite.createTestCourse("Registration Course", "Description", "beginner")
+	existing := suite.enrollTestStudent("enrolled@example.com", course.ID)
+	recorder = suite.makeRequest("POST", "/api/v1/auth/register", models.RegisterRequest{
+		Email:    "Enrolled@Example.com",
+		Password: "correct horse battery",
+	}, nil)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "invite_token")
+	var users int64
+	suite.Require().NoError(suite.db.Model(&models.User{}).Where("student_id = ?", existing.StudentID).Count(&users).Error)
+	suite.Zero(users)
+
+	// An invite an admin issued for the student lets them claim it
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/students/%s/invite", existing.StudentID), nil, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+	var invite models.StudentInviteResponse
+	suite.parseResponse(recorder, &invite)
+	suite.Equal("enrolled@example.com", invite.StudentEmail)
+	suite.NotEmpty(invite.Token)
+
+	for _, claim := range []models.RegisterRequest{
+		{Email: "enrolled@example.com", Password: "correct horse battery", InviteToken: "inv_forged"},
+		{Email: "learner2@example.com", Password: "correct horse battery", InviteToken: invite.Token},
+	} {
+		recorder = suite.makeRequest("POST", "/api/v1/auth/register", claim, nil)
+		suite.assertErrorResponse(recorder, http.StatusBadRequest, "Invite token is invalid")
+	}
+	recorder = suite.makeRequest("POST", "/api/v1/auth/register", models.RegisterRequest{
+		Email:       "Enrolled@Example.com",
+		Password:    "correct horse battery",
+		InviteToken: invite.Token,
+	}, nil)
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+	var claimed models.LoginResponse
+	suite.parseResponse(recorder, &claimed)
+	suite.Require().NotNil(claimed.User.StudentID)
+	suite.Equal(existing.StudentID, *claimed.User.StudentID)
+
+	// The invite works once, and students with an account cannot be invited
+	recorder = suite.makeRequest("POST", "/api/v1/auth/register", models.RegisterRequest{
+		Email:       "enrolled@example.com",
+		Password:    "correct horse battery",
+		InviteToken: invite.Token,
+	}, nil)
+	suite.Equal(http.StatusConflict, recorder.Code)
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/students/%s/invite", existing.StudentID), nil, suite.getAuthHeaders())
+	suite.assertErrorResponse(recorder, http.StatusConflict, "already has an account")
+
+	// The email logs in in any case
+	recorder = suite.makeRequest("POST", "/api/v1/auth/login", models.LoginRequest{
+		Username: "LEARNER@example.com",
+		Password: "correct horse battery",
+	}, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	recorder = suite.makeRequest("GET", "/api/v1/auth/profile", nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var profile models.UserResponse
+	suite.parseResponse(recorder, &profile)
+	suite.Require().NotNil(profile.StudentID)
+	suite.Equal(*account.User.StudentID, *profile.StudentID)
+
+	// Students cannot use admin routes
+	recorder = suite.makeRequest("POST", "/api/v1/courses", models.CourseRequest{
+		Title:       "Student Course",
+		Description: "Description",
+		Difficulty:  "Beginner",
+	}, headers)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	recorder = suite.makeRequest("GET", "/api/v1/admin/students", nil, headers)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+}
+
+// TestStudentSelfEnrollment tests students enrolling in and dropping courses
+// under the same rules as admin enrollments
+func (suite *IntegrationTestSuite) TestStudentSelfEnrollment() {
+	basics := suite.createTestCourse("Basics", "Description", "beginner")
+	advanced := suite.createTestCourse("Advanced", "Description", "advanced")
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/prerequisites", advanced.ID), models.PrerequisiteRequest{
+		PrerequisiteID: basics.ID,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	_, headers := suite.registerTestStudent("self@example.com")
+
+	recorder = suite.makeRequest("POST", "/api/v1/me/enrollments", models.SelfEnrollmentRequest{CourseID: basics.ID}, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+	var enrollment models.EnrollmentResponse
+	suite.parseResponse(recorder, &enrollment)
+	suite.Equal("self@example.com", enrollment.StudentEmail)
+
+	recorder = suite.makeRequest("POST", "/api/v1/me/enrollments", models.SelfEnrollmentRequest{CourseID: basics.ID}, headers)
+	suite.Equal(http.StatusConflict, recorder.Code)
+	recorder = suite.makeRequest("POST", "/api/v1/me/enrollments", models.SelfEnrollmentRequest{CourseID: advanced.ID}, headers)
+	suite.Equal(http.StatusUnprocessableEntity, recorder.Code)
+
+	// Students only see their own enrollments
+	suite.enrollTestStudent("someone.else@example.com", basics.ID)
+	recorder = suite.makeRequest("GET", "/api/v1/me/enrollments", nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var enrollments models.StudentEnrollmentsResponse
+	suite.parseResponse(recorder, &enrollments)
+	suite.Equal("self@example.com", enrollments.StudentEmail)
+	suite.Equal(1, enrollments.Total)
+
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/me/enrollments/%s", basics.ID), nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/me/enrollments/%s", basics.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "not enrolled")
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/me/enrollments/%s", uuid.New()), nil, headers)
+	suite.Equal(http.StatusNotFound, recorder.Code)
+
+	recorder = suite.makeRequest("GET", "/api/v1/me/enrollments?status=dropped", nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var dropped models.StudentEnrollmentsResponse
+	suite.parseResponse(recorder, &dropped)
+	suite.Require().Equal(1, dropped.Total)
+	suite.Equal(basics.ID, dropped.Enrollments[0].CourseID)
+
+	// The self-service routes are for student accounts only
+	recorder = suite.makeRequest("GET", "/api/v1/me/enrollments", nil, suite.getAuthHeaders())
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "Only student accounts")
+	recorder = suite.makeRequest("GET", "/api/v1/me/enrollments", nil, nil)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+}
@@ -0,0 +1,228 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// createTestStudent is a helper function to create a student through the API
+func (suite *IntegrationTestSuite) createTestStudent(req models.StudentRequest) models.StudentResponse {
+	recorder := suite.makeRequest("POST", "/api/v1/admin/students", req, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	var student models.StudentResponse
+	suite.parseResponse(recorder, &student)
+	return student
+}
+
+// TestStudentCreatedOnFirstEnrollment tests that enrolling an unknown email creates
+// the student and links the enrollment to it
+func (suite *IntegrationTestSuite) TestStudentCreatedOnFirstEnrollment() {
+	first := suite.createTestCourse("First Course", "Description", "beginner")
+	second := suite.createTestCourse("Second Course", "Description", "beginner")
+
+	enrollment := suite.enrollTestStudent("new.student@example.com", first.ID)
+	suite.NotEqual(uuid.Nil, enrollment.StudentID)
+	again := suite.enrollTestStudent("new.student@example.com", second.ID)
+	suite.Equal(enrollment.StudentID, again.StudentID)
+
+	recorder := suite.makeRequest("GET", "/api/v1/admin/students", nil, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var list models.AllStudentsResponse
+	suite.parseResponse(recorder, &list)
+	suite.Require().Equal(1, list.Total)
+	suite.Equal(enrollment.StudentID, list.Students[0].ID)
+	suite.Equal("new.student@example.com", list.Students[0].Email)
+	suite.Equal(2, list.Students[0].EnrollmentCount)
+	suite.NotEmpty(list.Students[0].LastEnrolledAt)
+}
+
+// TestStudentCRUD tests creating, reading, updating and deleting students
+func (suite *IntegrationTestSuite) TestStudentCRUD() {
+	headers := suite.getAuthHeaders()
+	firstName, lastName, number := "Ada", "Lovelace", "S1001"
+
+	student := suite.createTestStudent(models.StudentRequest{
+		Email:         "ada@example.com",
+		FirstName:     &firstName,
+		LastName:      &lastName,
+		StudentNumber: &number,
+	})
+	suite.Equal("ada@example.com", student.Email)
+	suite.Require().NotNil(student.StudentNumber)
+	suite.Equal("S1001", *student.StudentNumber)
+	suite.Equal(0, student.EnrollmentCount)
+
+	// Emails and student numbers are unique
+	recorder := suite.makeRequest("POST", "/api/v1/admin/students", models.StudentRequest{Email: "ada@example.com"}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "email already exists")
+	recorder = suite.makeRequest("POST", "/api/v1/admin/students", models.StudentRequest{
+		Email:         "other@example.com",
+		StudentNumber: &number,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "student number already exists")
+	recorder = suite.makeRequest("POST", "/api/v1/admin/students", models.StudentRequest{Email: "not-an-email"}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Invalid email format")
+
+	// Enrolling with the email uses the existing student
+	course := suite.createTestCourse("Analytical Engines", "Description", "advanced")
+	enrollment := suite.enrollTestStudent("ada@example.com", course.ID)
+	suite.Equal(student.ID, enrollment.StudentID)
+
+	// Changing the email carries the enrollments along
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/admin/students/%s", student.ID), models.StudentRequest{
+		Email:     "countess@example.com",
+		FirstName: &firstName,
+	}, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var updated models.StudentResponse
+	suite.parseResponse(recorder, &updated)
+	suite.Equal("countess@example.com", updated.Email)
+	suite.Nil(updated.LastName)
+	suite.Nil(updated.StudentNumber)
+	suite.Equal(1, updated.EnrollmentCount)
+
+	recorder = suite.makeRequest("GET", "/api/v1/students/countess@example.com/enrollments", nil, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var enrollments models.StudentEnrollmentsResponse
+	suite.parseResponse(recorder, &enrollments)
+	suite.Equal(1, enrollments.Total)
+
+	// Students with enrollments cannot be deleted
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/admin/students/%s", student.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "enrollments")
+
+	unused := suite.createTestStudent(models.StudentRequest{Email: "unused@example.com"})
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/admin/students/%s", unused.ID), nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/admin/students/%s", unused.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "Student not found")
+	recorder = suite.makeRequest("GET", "/api/v1/admin/students/not-a-uuid", nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Invalid student ID format")
+}
+
+// TestStudentEmailsAreCaseInsensitive tests that emails differing only in case and
+// surrounding spaces belong to the same student
+func (suite *IntegrationTestSuite) TestStudentEmailsAreCaseInsensitive() {
+	course := suite.createTestCourse("Case Study", "Description", "beginner")
+
+	enrollment := suite.enrollTestStudent("  Mixed.Case@Example.com ", course.ID)
+	suite.Equal("mixed.case@example.com", enrollment.StudentEmail)
+
+	recorder := suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "mixed.case@example.com",
+		CourseID:     course.ID,
+	}, suite.getAuthHeaders())
+	suite.Equal(http.StatusConflict, recorder.Code)
+
+	recorder = suite.makeRequest("GET", "/api/v1/students/MIXED.case@example.COM/enrollments", nil, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var enrollments models.StudentEnrollmentsResponse
+	suite.parseResponse(recorder, &enrollments)
+	suite.Equal("mixed.case@example.com", enrollments.StudentEmail)
+	suite.Equal(1, enrollments.Total)
+
+	recorder = suite.makeRequest("POST", "/api/v1/admin/students", models.StudentRequest{Email: "Mixed.Case@example.com"}, suite.getAuthHeaders())
+	suite.assertErrorResponse(recorder, http.StatusConflict, "email already exists")
+
+	recorder = suite.makeRequest("GET", "/api/v1/admin/students", nil, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var list models.AllStudentsResponse
+	suite.parseResponse(recorder, &list)
+	suite.Equal(1, list.Total)
+}
+
+// TestMergeStudents tests merging one student into another, resolving duplicate
+// enrollments and waitlist entries and recording the merge
+func (suite *IntegrationTestSuite) TestMergeStudents() {
+	headers := suite.getAuthHeaders()
+	firstName := "Ada"
+	target := suite.createTestStudent(models.StudentRequest{Email: "ada@example.com"})
+	source := suite.createTestStudent(models.StudentRequest{Email: "ada.lovelace@example.com", FirstName: &firstName})
+
+	shared := suite.createTestCourse("Shared Course", "Description", "beginner")
+	extra := suite.createTestCourse("Extra Course", "Description", "beginner")
+	full := suite.createTestCourseWithCapacity("Full Course", 1)
+
+	suite.enrollTestStudent(target.Email, shared.ID)
+	duplicate := suite.enrollTestStudent(source.Email, shared.ID)
+	moved := suite.enrollTestStudent(source.Email, extra.ID)
+	suite.enrollTestStudent(target.Email, full.ID)
+	recorder := suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: source.Email,
+		CourseID:     full.ID,
+	}, headers)
+	suite.Require().Equal(http.StatusAccepted, recorder.Code)
+
+	reason := "Same person"
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/students/%s/merge", target.ID), models.StudentMergeRequest{
+		SourceStudentID: source.ID,
+		Reason:          &reason,
+	}, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	var merge models.StudentMergeResponse
+	suite.parseResponse(recorder, &merge)
+	suite.Equal(source.Email, merge.SourceEmail)
+	suite.Equal(target.ID, merge.TargetStudentID)
+	suite.Equal(1, merge.MovedEnrollments)
+	suite.Equal([]uuid.UUID{duplicate.ID}, merge.DiscardedEnrollmentIDs)
+	suite.Equal(0, merge.MovedWaitlistEntries)
+	suite.Equal(1, merge.DiscardedWaitlistEntries)
+	suite.Equal("admin", merge.MergedBy)
+
+	// The source is gone and its profile filled in the blanks of the target
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/admin/students/%s", source.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "Student not found")
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/admin/students/%s", target.ID), nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var merged models.StudentResponse
+	suite.parseResponse(recorder, &merged)
+	suite.Require().NotNil(merged.FirstName)
+	suite.Equal("Ada", *merged.FirstName)
+	suite.Equal(3, merged.EnrollmentCount)
+
+	recorder = suite.makeRequest("GET", "/api/v1/students/ada@example.com/enrollments", nil, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var enrollments models.StudentEnrollmentsResponse
+	suite.parseResponse(recorder, &enrollments)
+	suite.Equal(3, enrollments.Total)
+	ids := map[uuid.UUID]bool{}
+	for _, enrollment := range enrollments.Enrollments {
+		ids[enrollment.ID] = true
+	}
+	suite.True(ids[moved.ID])
+	suite.False(ids[duplicate.ID])
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/waitlist", full.ID), nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var waitlist models.WaitlistResponse
+	suite.parseResponse(recorder, &waitlist)
+	suite.Equal(0, waitlist.Total)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/admin/students/%s/merges", target.ID), nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var merges models.StudentMergesResponse
+	suite.parseResponse(recorder, &merges)
+	suite.Require().Equal(1, merges.Total)
+	suite.Equal(merge.ID, merges.Merges[0].ID)
+	suite.Require().NotNil(merges.Merges[0].Reason)
+	suite.Equal("Same person", *merges.Merges[0].Reason)
+
+	// Merging needs two different, existing students
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/students/%s/merge", target.ID), models.StudentMergeRequest{
+		SourceStudentID: target.ID,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "into itself")
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/students/%s/merge", target.ID), models.StudentMergeRequest{
+		SourceStudentID: source.ID,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Source student does not exist")
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/students/%s/merge", source.ID), models.StudentMergeRequest{
+		SourceStudentID: target.ID,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "Student not found")
+}
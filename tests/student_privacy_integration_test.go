@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/auth"
+	"sonic-labs/course-enrollment-service/internal/models"
+)
+
+// TestStudentRecordsRequireAccess tests that student records are only shown to
+// admins and the student themselves, and that other callers cannot tell whether
+// the student exists
+func (suite *IntegrationTestSuite) TestStudentRecordsRequireAccess() {
+	course := suite.createTestCourse("Private Course", "Description", "beginner")
+	_, ownHeaders := suite.registerTestStudent("private@example.com")
+	suite.enrollTestStudent("private@example.com", course.ID)
+	_, otherHeaders := suite.registerTestStudent("nosy@example.com")
+
+	existing := suite.makeRequest("GET", "/api/v1/students/private@example.com/enrollments", nil, nil)
+	suite.assertErrorResponse(existing, http.StatusForbidden, "share link")
+	missing := suite.makeRequest("GET", "/api/v1/students/nobody@example.com/enrollments", nil, nil)
+	suite.Equal(http.StatusForbidden, missing.Code)
+	suite.Equal(existing.Body.String(), missing.Body.String())
+
+	for _, headers := range []map[string]string{otherHeaders, {"Authorization": "Bearer invalid-token"}} {
+		recorder := suite.makeRequest("GET", "/api/v1/students/private@example.com/enrollments", nil, headers)
+		suite.Equal(http.StatusForbidden, recorder.Code)
+		recorder = suite.makeRequest("GET", "/api/v1/students/private@example.com/timetable", nil, headers)
+		suite.Equal(http.StatusForbidden, recorder.Code)
+	}
+
+	recorder := suite.makeRequest("GET", "/api/v1/students/Private@Example.com/enrollments", nil, ownHeaders)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var enrollments models.StudentEnrollmentsResponse
+	suite.parseResponse(recorder, &enrollments)
+	suite.Equal(1, enrollments.Total)
+	recorder = suite.makeRequest("GET", "/api/v1/students/private@example.com/timetable", nil, ownHeaders)
+	suite.Equal(http.StatusOK, recorder.Code)
+}
+
+// TestStudentRecordsShareLink tests that a share link generated by an admin opens
+// the records of that student until it expires
+func (suite *IntegrationTestSuite) TestStudentRecordsShareLink() {
+	course := suite.createTestCourse("Shared Records", "Description", "beginner")
+	enrollment := suite.enrollTestStudent("shared@example.com", course.ID)
+
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/students/%s/share-link", enrollment.StudentID), models.ShareLinkRequest{
+		ExpiresInHours: 2,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+	var link models.ShareLinkResponse
+	suite.parseResponse(recorder, &link)
+	suite.Equal("shared@example.com", link.StudentEmail)
+	suite.WithinDuration(time.Now().Add(2*time.Hour), link.ExpiresAt, time.Minute)
+
+	recorder = suite.makeRequest("GET", link.EnrollmentsURL, nil, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var enrollments models.StudentEnrollmentsResponse
+	suite.parseResponse(recorder, &enrollments)
+	suite.Equal(1, enrollments.Total)
+	recorder = suite.makeRequest("GET", link.TimetableURL, nil, nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	// The signature only fits this email and expiry
+	parsed, err := url.Parse(link.EnrollmentsURL)
+	suite.Require().NoError(err)
+	query := parsed.Query()
+	recorder = suite.makeRequest("GET", "/api/v1/students/other@example.com/enrollments?"+query.Encode(), nil, nil)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	tampered := url.Values{"expires": {query.Get("expires") + "0"}, "signature": {query.Get("signature")}}
+	recorder = suite.makeRequest("GET", "/api/v1/students/shared@example.com/enrollments?"+tampered.Encode(), nil, nil)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+
+	expiresAt := time.Now().Add(-time.Minute)
+	expired := url.Values{
+		"expires":   {fmt.Sprint(expiresAt.Unix())},
+		"signature": {auth.SignShareLink("shared@example.com", expiresAt)},
+	}
+	recorder = suite.makeRequest("GET", "/api/v1/students/shared@example.com/enrollments?"+expired.Encode(), nil, nil)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/students/%s/share-link", enrollment.StudentID), models.ShareLinkRequest{
+		ExpiresInHours: 10000,
+	}, suite.getAuthHeaders())
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "720 hours")
+}
+
+// TestStudentRecordsRateLimit tests that each client can only look up student
+// records a limited number of times per minute
+func (suite *IntegrationTestSuite) TestStudentRecordsRateLimit() {
+	lookup := func(clientIP string) int {
+		req, err := http.NewRequest("GET", "/api/v1/students/someone@example.com/enrollments", strings.NewReader(""))
+		suite.Require().NoError(err)
+		req.RemoteAddr = clientIP + ":40000"
+		return suite.makeHTTPRequest(req).Code
+	}
+
+	for i := 0; i < 60; i++ {
+		suite.Require().Equal(http.StatusForbidden, lookup("198.51.100.7"))
+	}
+	suite.Equal(http.StatusTooManyRequests, lookup("198.51.100.7"))
+
+	// Other clients have their own allowance
+	suite.Equal(http.StatusForbidden, lookup("198.51.100.8"))
+}
@@ -0,0 +1,211 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// createTestCategory is a helper function to create a category, under parent if it is not nil
+func (suite *IntegrationTestSuite) createTestCategory(name string, parentID *uuid.UUID) models.CategoryResponse {
+	recorder := suite.makeRequest("POST", "/api/v1/categories", models.CategoryRequest{
+		Name:     name,
+		ParentID: parentID,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	var category models.CategoryResponse
+	suite.parseResponse(recorder, &category)
+	return category
+}
+
+// createTestCourseWithTaxonomy is a helper function to create a course in categories and with tags
+func (suite *IntegrationTestSuite) createTestCourseWithTaxonomy(title string, categoryIDs []uuid.UUID, tags []string) models.CourseResponse {
+	recorder := suite.makeRequest("POST", "/api/v1/courses", models.CourseRequest{
+		Title:       title,
+		Description: "Test Description",
+		Difficulty:  "Beginner",
+		CategoryIDs: categoryIDs,
+		Tags:        tags,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	var course models.CourseResponse
+	suite.parseResponse(recorder, &course)
+	return course
+}
+
+// TestCategoryTree tests creating, moving and deleting categories
+func (suite *IntegrationTestSuite) TestCategoryTree() {
+	headers := suite.getAuthHeaders()
+	programming := suite.createTestCategory("Programming", nil)
+	suite.Equal("programming", programming.Slug)
+	golang := suite.createTestCategory("Go & Concurrency", &programming.ID)
+	suite.Equal("go-concurrency", golang.Slug)
+	design := suite.createTestCategory("Design", nil)
+
+	recorder := suite.makeRequest("GET", "/api/v1/categories", nil, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var tree models.CategoryListResponse
+	suite.parseResponse(recorder, &tree)
+	suite.Equal(3, tree.Total)
+	suite.Require().Len(tree.Categories, 2)
+	suite.Equal("Design", tree.Categories[0].Name)
+	suite.Equal("Programming", tree.Categories[1].Name)
+	suite.Require().Len(tree.Categories[1].Children, 1)
+	suite.Equal(golang.ID, tree.Categories[1].Children[0].ID)
+
+	// Slugs are unique across the tree
+	recorder = suite.makeRequest("POST", "/api/v1/categories", models.CategoryRequest{Name: "Programming"}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "slug")
+
+	// A category cannot end up under itself
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/categories/%s", programming.ID), models.CategoryRequest{
+		Name:     "Programming",
+		ParentID: &golang.ID,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "under itself")
+
+	// Moving a category takes its subcategories along
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/categories/%s", programming.ID), models.CategoryRequest{
+		Name:     "Programming",
+		ParentID: &design.ID,
+	}, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/categories/%s", design.ID), nil, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var moved models.CategoryResponse
+	suite.parseResponse(recorder, &moved)
+	suite.Require().Len(moved.Children, 1)
+	suite.Require().Len(moved.Children[0].Children, 1)
+	suite.Equal(golang.ID, moved.Children[0].Children[0].ID)
+
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/categories/%s", programming.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "subcategories")
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/categories/%s", golang.ID), nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/categories/%s", golang.ID), nil, nil)
+	suite.Equal(http.StatusNotFound, recorder.Code)
+
+	recorder = suite.makeRequest("POST", "/api/v1/categories", models.CategoryRequest{Name: "Unauthorized"}, nil)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+}
+
+// TestCourseTaxonomy tests giving courses categories and tags and filtering by them
+func (suite *IntegrationTestSuite) TestCourseTaxonomy() {
+	headers := suite.getAuthHeaders()
+	programming := suite.createTestCategory("Programming", nil)
+	golang := suite.createTestCategory("Go", &programming.ID)
+	design := suite.createTestCategory("Design", nil)
+
+	goCourse := suite.createTestCourseWithTaxonomy("Go Basics", []uuid.UUID{golang.ID}, []string{"Backend", " concurrency "})
+	suite.Require().Len(goCourse.Categories, 1)
+	suite.Equal("go", goCourse.Categories[0].Slug)
+	suite.Equal([]string{"backend", "concurrency"}, goCourse.Tags)
+	suite.createTestCourseWithTaxonomy("Algorithms", []uuid.UUID{programming.ID}, []string{"backend"})
+	suite.createTestCourseWithTaxonomy("Typography", []uuid.UUID{design.ID}, nil)
+
+	filter := func(query string) []string {
+		recorder := suite.makeRequest("GET", "/api/v1/courses?"+query, nil, nil)
+		suite.Require().Equal(http.StatusOK, recorder.Code)
+		var list models.CourseListResponse
+		suite.parseResponse(recorder, &list)
+		var titles []string
+		for _, course := range list.Data {
+			titles = append(titles, course.Title)
+		}
+		return titles
+	}
+	suite.ElementsMatch([]string{"Go Basics", "Algorithms"}, filter("category=programming"))
+	suite.ElementsMatch([]string{"Go Basics"}, filter("category=Go"))
+	suite.ElementsMatch([]string{"Go Basics", "Typography"}, filter("category=go,design"))
+	suite.ElementsMatch([]string{"Go Basics", "Algorithms"}, filter("tag=BACKEND"))
+	suite.ElementsMatch([]string{"Go Basics"}, filter("category=programming&tag=concurrency"))
+	suite.Empty(filter("tag=unknown"))
+
+	recorder := suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s", goCourse.ID), nil, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var fetched models.CourseResponse
+	suite.parseResponse(recorder, &fetched)
+	suite.Equal(goCourse.Categories, fetched.Categories)
+	suite.Equal(goCourse.Tags, fetched.Tags)
+
+	// Omitted lists keep the current categories and tags, empty lists clear them
+	update := models.CourseRequest{Title: "Go Basics", Description: "Updated", Difficulty: "Beginner"}
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s", goCourse.ID), update, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	var kept models.CourseResponse
+	suite.parseResponse(recorder, &kept)
+	suite.Len(kept.Categories, 1)
+	suite.Len(kept.Tags, 2)
+
+	update.CategoryIDs = []uuid.UUID{}
+	update.Tags = []string{"systems"}
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s", goCourse.ID), update, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	suite.ElementsMatch([]string{"Algorithms"}, filter("category=programming"))
+	suite.ElementsMatch([]string{"Go Basics"}, filter("tag=systems"))
+
+	update.CategoryIDs = []uuid.UUID{uuid.New()}
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s", goCourse.ID), update, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "existing categories")
+
+	recorder = suite.makeRequest("POST", "/api/v1/courses", models.CourseRequest{
+		Title:       "Bad Tags",
+		Description: "Test Description",
+		Difficulty:  "Beginner",
+		Tags:        []string{"  "},
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Tags")
+
+	// Deleting a category takes it off its courses
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/categories/%s", design.ID), nil, headers)
+	suite.Require().Equal(http.StatusNoContent, recorder.Code)
+	suite.Empty(filter("category=design"))
+}
+
+// TestTagManagement tests listing, renaming and deleting tags
+func (suite *IntegrationTestSuite) TestTagManagement() {
+	headers := suite.getAuthHeaders()
+	course := suite.createTestCourseWithTaxonomy("Tagged Course", nil, []string{"web", "backend"})
+
+	recorder := suite.makeRequest("POST", "/api/v1/tags", models.TagRequest{Name: " Unused  Tag "}, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+	var unused models.TagResponse
+	suite.parseResponse(recorder, &unused)
+	suite.Equal("unused tag", unused.Name)
+	recorder = suite.makeRequest("POST", "/api/v1/tags", models.TagRequest{Name: "WEB"}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "already exists")
+
+	recorder = suite.makeRequest("GET", "/api/v1/tags", nil, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var list models.TagListResponse
+	suite.parseResponse(recorder, &list)
+	suite.Require().Equal(3, list.Total)
+	suite.Equal("backend", list.Tags[0].Name)
+	suite.Equal(1, list.Tags[0].CourseCount)
+	suite.Equal("unused tag", list.Tags[1].Name)
+	suite.Equal(0, list.Tags[1].CourseCount)
+
+	web := list.Tags[2]
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/tags/%s", web.ID), models.TagRequest{Name: "Frontend"}, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var renamed models.TagResponse
+	suite.parseResponse(recorder, &renamed)
+	suite.Equal("frontend", renamed.Name)
+	suite.Equal(1, renamed.CourseCount)
+
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/tags/%s", list.Tags[0].ID), nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/tags/%s", list.Tags[0].ID), nil, headers)
+	suite.Equal(http.StatusNotFound, recorder.Code)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s", course.ID), nil, nil)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+	var fetched models.CourseResponse
+	suite.parseResponse(recorder, &fetched)
+	suite.Equal([]string{"frontend"}, fetched.Tags)
+}
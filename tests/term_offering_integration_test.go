@@ -0,0 +1,263 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/handler"
+	"sonic-labs/course-enrollment-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// createTestTerm is a helper function to create a term through the API
+func (suite *IntegrationTestSuite) createTestTerm(name string, start, end time.Time) models.TermResponse {
+	recorder := suite.makeRequest("POST", "/api/v1/terms", models.TermRequest{
+		Name:      name,
+		StartDate: start,
+		EndDate:   end,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	var term models.TermResponse
+	suite.parseResponse(recorder, &term)
+	return term
+}
+
+// createTestOffering is a helper function to offer a course in a term through the API
+func (suite *IntegrationTestSuite) createTestOffering(courseID, termID uuid.UUID, capacity *int) models.OfferingResponse {
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/offerings", courseID), models.OfferingRequest{
+		TermID:   termID,
+		Capacity: capacity,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+
+	var offering models.OfferingResponse
+	suite.parseResponse(recorder, &offering)
+	return offering
+}
+
+// upcomingTerm returns the dates of a term that starts next month and lasts four months
+func upcomingTerm() (time.Time, time.Time) {
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 1, 0)
+	return start, start.AddDate(0, 4, 0)
+}
+
+// TestTermLifecycle tests creating, listing, updating and deleting terms
+func (suite *IntegrationTestSuite) TestTermLifecycle() {
+	headers := suite.getAuthHeaders()
+	start, end := upcomingTerm()
+
+	term := suite.createTestTerm("Fall", start, end)
+	suite.Equal("Fall", term.Name)
+
+	// Names are unique
+	recorder := suite.makeRequest("POST", "/api/v1/terms", models.TermRequest{
+		Name:      "Fall",
+		StartDate: start,
+		EndDate:   end,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "already exists")
+
+	// A term cannot end before it starts
+	recorder = suite.makeRequest("POST", "/api/v1/terms", models.TermRequest{
+		Name:      "Backwards",
+		StartDate: end,
+		EndDate:   start,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "must end after it starts")
+
+	suite.createTestTerm("Spring", end.AddDate(0, 1, 0), end.AddDate(0, 5, 0))
+
+	recorder = suite.makeRequest("GET", "/api/v1/terms", nil, nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+	var list models.TermListResponse
+	suite.parseResponse(recorder, &list)
+	suite.Require().Equal(2, list.Total)
+	suite.Equal("Fall", list.Terms[0].Name)
+	suite.Equal("Spring", list.Terms[1].Name)
+
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/terms/%s", term.ID), models.TermRequest{
+		Name:      "Fall Semester",
+		StartDate: start,
+		EndDate:   end,
+	}, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+	var updated models.TermResponse
+	suite.parseResponse(recorder, &updated)
+	suite.Equal("Fall Semester", updated.Name)
+
+	// A term with offerings cannot be deleted
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+	offering := suite.createTestOffering(course.ID, term.ID, nil)
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/terms/%s", term.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "courses are offered")
+
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/courses/%s/offerings/%s", course.ID, offering.ID), nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/terms/%s", term.ID), nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/terms/%s", term.ID), nil, nil)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "Term not found")
+}
+
+// TestOfferingsHaveTheirOwnCapacity tests that seats are counted per offering
+func (suite *IntegrationTestSuite) TestOfferingsHaveTheirOwnCapacity() {
+	course := suite.createTestCourseWithCapacity("Small Course", 1)
+	headers := suite.getAuthHeaders()
+	start, end := upcomingTerm()
+	term := suite.createTestTerm("Fall", start, end)
+	capacity := 2
+	offering := suite.createTestOffering(course.ID, term.ID, &capacity)
+
+	// The course can only be offered once per term
+	recorder := suite.makeRequest("POST", fmt.Sprintf("/api/v1/courses/%s/offerings", course.ID), models.OfferingRequest{
+		TermID: term.ID,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "already offered")
+
+	// The default offering uses the course capacity
+	suite.enrollTestStudent("first@example.com", course.ID)
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "second@example.com",
+		CourseID:     course.ID,
+	}, headers)
+	suite.Equal(http.StatusAccepted, recorder.Code)
+
+	// The term offering still has seats
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "third@example.com",
+		CourseID:     course.ID,
+		OfferingID:   &offering.ID,
+	}, headers)
+	suite.Require().Equal(http.StatusCreated, recorder.Code)
+	var enrollment models.EnrollmentResponse
+	suite.parseResponse(recorder, &enrollment)
+	suite.Equal(offering.ID, enrollment.OfferingID)
+	suite.Require().NotNil(enrollment.Term)
+	suite.Equal("Fall", enrollment.Term.Name)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/offerings", course.ID), nil, nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+	var list models.OfferingListResponse
+	suite.parseResponse(recorder, &list)
+	suite.Require().Equal(2, list.Total)
+	suite.Nil(list.Offerings[0].Term)
+	suite.Equal(1, list.Offerings[0].EnrolledCount)
+	suite.Equal(offering.ID, list.Offerings[1].ID)
+	suite.Equal(1, list.Offerings[1].EnrolledCount)
+
+	// An offering with enrollments cannot be deleted
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/courses/%s/offerings/%s", course.ID, offering.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "enrolled or waitlisted")
+}
+
+// TestRaisingOfferingCapacityPromotesWaitlist tests that new seats in an offering go to students waiting for it
+func (suite *IntegrationTestSuite) TestRaisingOfferingCapacityPromotesWaitlist() {
+	course := suite.createTestCourseWithCapacity("Small Course", 5)
+	headers := suite.getAuthHeaders()
+	start, end := upcomingTerm()
+	term := suite.createTestTerm("Fall", start, end)
+	capacity := 1
+	offering := suite.createTestOffering(course.ID, term.ID, &capacity)
+
+	for _, email := range []string{"first@example.com", "second@example.com"} {
+		suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+			StudentEmail: email,
+			CourseID:     course.ID,
+			OfferingID:   &offering.ID,
+		}, headers)
+	}
+
+	capacity = 2
+	recorder := suite.makeRequest("PUT", fmt.Sprintf("/api/v1/courses/%s/offerings/%s", course.ID, offering.ID), models.OfferingUpdateRequest{
+		Capacity: &capacity,
+	}, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+	var updated models.OfferingResponse
+	suite.parseResponse(recorder, &updated)
+	suite.Equal(2, updated.EnrolledCount)
+
+	var promoted models.Enrollment
+	err := suite.db.Where("student_email = ? AND course_id = ?", "second@example.com", course.ID).First(&promoted).Error
+	suite.Require().NoError(err)
+	suite.Equal(offering.ID, promoted.OfferingID)
+	suite.Equal(constants.EnrollmentStatusActive, promoted.Status)
+}
+
+// TestEnrollInOfferingRejected tests enrolling in unknown offerings and in terms that have ended
+func (suite *IntegrationTestSuite) TestEnrollInOfferingRejected() {
+	course := suite.createTestCourse("Test Course", "Test Description", "Beginner")
+	other := suite.createTestCourse("Other Course", "Test Description", "Beginner")
+	headers := suite.getAuthHeaders()
+
+	past := suite.createTestTerm("Spring 2020", time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2020, 5, 15, 0, 0, 0, 0, time.UTC))
+	ended := suite.createTestOffering(course.ID, past.ID, nil)
+	start, end := upcomingTerm()
+	otherOffering := suite.createTestOffering(other.ID, suite.createTestTerm("Fall", start, end).ID, nil)
+
+	recorder := suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     course.ID,
+		OfferingID:   &ended.ID,
+	}, headers)
+	suite.Equal(http.StatusUnprocessableEntity, recorder.Code)
+	var windowErr handler.EnrollmentWindowErrorResponse
+	suite.parseResponse(recorder, &windowErr)
+	suite.Equal(constants.EnrollmentWindowClosed, windowErr.Code)
+
+	// An offering of another course is not an offering of this one
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "student@example.com",
+		CourseID:     course.ID,
+		OfferingID:   &otherOffering.ID,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "offering does not exist")
+}
+
+// TestListingsFilterByTerm tests the term_id filter of the listing endpoints
+func (suite *IntegrationTestSuite) TestListingsFilterByTerm() {
+	termCourse := suite.createTestCourse("Term Course", "Test Description", "Beginner")
+	otherCourse := suite.createTestCourse("Other Course", "Test Description", "Beginner")
+	headers := suite.getAuthHeaders()
+	start, end := upcomingTerm()
+	term := suite.createTestTerm("Fall", start, end)
+	offering := suite.createTestOffering(termCourse.ID, term.ID, nil)
+
+	email := "student@example.com"
+	suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: email,
+		CourseID:     termCourse.ID,
+		OfferingID:   &offering.ID,
+	}, headers)
+	suite.enrollTestStudent(email, otherCourse.ID)
+
+	recorder := suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses?term_id=%s", term.ID), nil, nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+	var courses models.CourseListResponse
+	suite.parseResponse(recorder, &courses)
+	suite.Require().Len(courses.Data, 1)
+	suite.Equal(termCourse.ID, courses.Data[0].ID)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/students/%s/enrollments?term_id=%s", email, term.ID), nil, suite.getAuthHeaders())
+	suite.Equal(http.StatusOK, recorder.Code)
+	var studentEnrollments models.StudentEnrollmentsResponse
+	suite.parseResponse(recorder, &studentEnrollments)
+	suite.Require().Equal(1, studentEnrollments.Total)
+	suite.Equal(termCourse.ID, studentEnrollments.Enrollments[0].CourseID)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/admin/enrollments?term_id=%s", term.ID), nil, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+	var allEnrollments models.AllEnrollmentsResponse
+	suite.parseResponse(recorder, &allEnrollments)
+	suite.Require().Equal(1, allEnrollments.Total)
+	suite.Equal(offering.ID, allEnrollments.Enrollments[0].OfferingID)
+	suite.Require().NotNil(allEnrollments.Enrollments[0].Term)
+	suite.Equal(term.ID, allEnrollments.Enrollments[0].Term.ID)
+
+	recorder = suite.makeRequest("GET", "/api/v1/courses?term_id=not-a-uuid", nil, nil)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Invalid term ID format")
+}
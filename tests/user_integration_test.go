@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/constants"
+	"sonic-labs/course-enrollment-service/internal/models"
+)
+
+// superAdminID is the ID of the super-admin getSuperAdminHeaders creates
+const superAdminID = "22345678-1234-1234-1234-123456789012"
+
+// createTestUser is a helper function to create an admin or super-admin account
+func (suite *IntegrationTestSuite) createTestUser(username, role string) models.UserResponse {
+	recorder := suite.makeRequest("POST", "/api/v1/admin/users", models.CreateUserRequest{
+		Username: username,
+		Password: "correct horse battery",
+		Role:     role,
+	}, suite.getSuperAdminHeaders())
+	suite.Require().Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	var user models.UserResponse
+	suite.parseResponse(recorder, &user)
+	return user
+}
+
+// loginTestUser is a helper function to log in with the password createTestUser sets
+func (suite *IntegrationTestSuite) loginTestUser(username string) (int, map[string]string) {
+	recorder := suite.makeRequest("POST", "/api/v1/auth/login", models.LoginRequest{
+		Username: username,
+		Password: "correct horse battery",
+	}, nil)
+	if recorder.Code != http.StatusOK {
+		return recorder.Code, nil
+	}
+
+	var account models.LoginResponse
+	suite.parseResponse(recorder, &account)
+	return recorder.Code, map[string]string{"Authorization": "Bearer " + account.Token}
+}
+
+// TestUserManagement tests creating, promoting, disabling and deleting users
+func (suite *IntegrationTestSuite) TestUserManagement() {
+	headers := suite.getSuperAdminHeaders()
+	registrar := suite.createTestUser(" registrar ", constants.RoleAdmin)
+	suite.Equal("registrar", registrar.Username)
+	suite.Equal(constants.RoleAdmin, registrar.Role)
+	suite.Nil(registrar.DisabledAt)
+
+	recorder := suite.makeRequest("POST", "/api/v1/admin/users", models.CreateUserRequest{
+		Username: "registrar",
+		Password: "correct horse battery",
+		Role:     constants.RoleAdmin,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "already exists")
+
+	recorder = suite.makeRequest("POST", "/api/v1/admin/users", models.CreateUserRequest{
+		Username: "bursar",
+		Password: "short",
+		Role:     constants.RoleAdmin,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "at least")
+
+	// Student and instructor accounts are linked to their records elsewhere
+	recorder = suite.makeRequest("POST", "/api/v1/admin/users", models.CreateUserRequest{
+		Username: "bursar",
+		Password: "correct horse battery",
+		Role:     constants.RoleInstructor,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Role must be one of")
+
+	recorder = suite.makeRequest("GET", "/api/v1/admin/users?role=admin", nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	var users models.UserListResponse
+	suite.parseResponse(recorder, &users)
+	suite.Equal(2, users.Total)
+	suite.Equal("admin", users.Users[0].Username)
+	suite.Equal("registrar", users.Users[1].Username)
+
+	// The new admin holds the admin permissions, but cannot manage users
+	status, registrarHeaders := suite.loginTestUser("registrar")
+	suite.Require().Equal(http.StatusOK, status)
+	recorder = suite.makeRequest("GET", "/api/v1/admin/students", nil, registrarHeaders)
+	suite.Equal(http.StatusOK, recorder.Code)
+	recorder = suite.makeRequest("GET", "/api/v1/admin/users", nil, registrarHeaders)
+	suite.assertErrorResponse(recorder, http.StatusForbidden, constants.PermissionUserManage)
+	recorder = suite.makeRequest("GET", "/api/v1/admin/users", nil, suite.getAuthHeaders())
+	suite.assertErrorResponse(recorder, http.StatusForbidden, constants.PermissionUserManage)
+
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/admin/users/%s/role", registrar.ID), models.UpdateUserRoleRequest{
+		Role: constants.RoleSuperAdmin,
+	}, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	var promoted models.UserResponse
+	suite.parseResponse(recorder, &promoted)
+	suite.Equal(constants.RoleSuperAdmin, promoted.Role)
+
+	// Disabled users cannot log in until they are enabled again
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/users/%s/disable", registrar.ID), nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	var disabled models.UserResponse
+	suite.parseResponse(recorder, &disabled)
+	suite.NotNil(disabled.DisabledAt)
+
+	recorder = suite.makeRequest("POST", "/api/v1/auth/login", models.LoginRequest{
+		Username: "registrar",
+		Password: "correct horse battery",
+	}, nil)
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "disabled")
+
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/users/%s/enable", registrar.ID), nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	status, _ = suite.loginTestUser("registrar")
+	suite.Equal(http.StatusOK, status)
+
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/admin/users/%s", registrar.ID), nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code, recorder.Body.String())
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/admin/users/%s", registrar.ID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "User not found")
+	status, _ = suite.loginTestUser("registrar")
+	suite.Equal(http.StatusUnauthorized, status)
+}
+
+// TestUserManagementStudentAccounts tests that student accounts keep their role
+func (suite *IntegrationTestSuite) TestUserManagementStudentAccounts() {
+	headers := suite.getSuperAdminHeaders()
+	account, _ := suite.registerTestStudent("mallory@example.com")
+
+	recorder := suite.makeRequest("PUT", fmt.Sprintf("/api/v1/admin/users/%s/role", account.User.ID), models.UpdateUserRoleRequest{
+		Role: constants.RoleAdmin,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "keep their role")
+
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/users/%s/disable", account.User.ID), nil, headers)
+	suite.Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	recorder = suite.makeRequest("POST", "/api/v1/auth/login", models.LoginRequest{
+		Username: "mallory@example.com",
+		Password: "correct horse battery",
+	}, nil)
+	suite.assertErrorResponse(recorder, http.StatusForbidden, "disabled")
+
+	recorder = suite.makeRequest("PUT", "/api/v1/admin/users/not-a-uuid/role", models.UpdateUserRoleRequest{
+		Role: constants.RoleAdmin,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "Invalid user ID")
+}
+
+// TestLastSuperAdmin tests that the last enabled super-admin cannot be removed
+func (suite *IntegrationTestSuite) TestLastSuperAdmin() {
+	headers := suite.getSuperAdminHeaders()
+
+	recorder := suite.makeRequest("PUT", fmt.Sprintf("/api/v1/admin/users/%s/role", superAdminID), models.UpdateUserRoleRequest{
+		Role: constants.RoleAdmin,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "last enabled super-admin")
+
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/users/%s/disable", superAdminID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "last enabled super-admin")
+
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/admin/users/%s", superAdminID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "last enabled super-admin")
+
+	// A disabled super-admin does not count
+	deputy := suite.createTestUser("deputy", constants.RoleSuperAdmin)
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/users/%s/disable", deputy.ID), nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/admin/users/%s", superAdminID), nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "last enabled super-admin")
+
+	// Once another super-admin is enabled the first one can step down
+	recorder = suite.makeRequest("POST", fmt.Sprintf("/api/v1/admin/users/%s/enable", deputy.ID), nil, headers)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	recorder = suite.makeRequest("PUT", fmt.Sprintf("/api/v1/admin/users/%s/role", superAdminID), models.UpdateUserRoleRequest{
+		Role: constants.RoleAdmin,
+	}, headers)
+	suite.Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+
+	_, deputyHeaders := suite.loginTestUser("deputy")
+	recorder = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/admin/users/%s", deputy.ID), nil, deputyHeaders)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "last enabled super-admin")
+}
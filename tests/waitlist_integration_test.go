@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"sonic-labs/course-enrollment-service/internal/models"
+)
+
+// createTestCourseWithCapacity is a helper function to create a test course with a seat limit
+func (suite *IntegrationTestSuite) createTestCourseWithCapacity(title string, capacity int) *models.Course {
+	course := &models.Course{
+		Title:       title,
+		Description: "Test Description",
+		Difficulty:  "Beginner",
+		Capacity:    &capacity,
+	}
+
+	err := suite.db.Create(course).Error
+	suite.Require().NoError(err)
+
+	return course
+}
+
+// TestEnrollStudentCourseFull tests that enrolling in a full course places the student on the waitlist
+func (suite *IntegrationTestSuite) TestEnrollStudentCourseFull() {
+	course := suite.createTestCourseWithCapacity("Small Course", 1)
+	headers := suite.getAuthHeaders()
+
+	recorder := suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "first@example.com",
+		CourseID:     course.ID,
+	}, headers)
+	suite.Equal(http.StatusCreated, recorder.Code)
+
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "second@example.com",
+		CourseID:     course.ID,
+	}, headers)
+	suite.Equal(http.StatusAccepted, recorder.Code)
+
+	var response struct {
+		Message string                       `json:"message"`
+		Data    models.WaitlistEntryResponse `json:"data"`
+	}
+	suite.parseResponse(recorder, &response)
+	suite.Equal("second@example.com", response.Data.StudentEmail)
+	suite.Equal(1, response.Data.Position)
+
+	// Waiting twice is a conflict
+	recorder = suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+		StudentEmail: "second@example.com",
+		CourseID:     course.ID,
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusConflict, "already on the waitlist")
+
+	var enrolled int64
+	suite.db.Model(&models.Enrollment{}).Where("course_id = ? AND status = ?", course.ID, "active").Count(&enrolled)
+	suite.Equal(int64(1), enrolled)
+}
+
+// TestWaitlistPromotionOnRemoval tests that removing a student promotes the head of the waitlist
+func (suite *IntegrationTestSuite) TestWaitlistPromotionOnRemoval() {
+	course := suite.createTestCourseWithCapacity("Small Course", 1)
+	headers := suite.getAuthHeaders()
+
+	for _, email := range []string{"first@example.com", "second@example.com", "third@example.com"} {
+		suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+			StudentEmail: email,
+			CourseID:     course.ID,
+		}, headers)
+	}
+
+	url := fmt.Sprintf("/api/v1/courses/%s/students/first@example.com", course.ID)
+	recorder := suite.makeRequest("DELETE", url, nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+
+	var enrollment models.Enrollment
+	err := suite.db.First(&enrollment, "course_id = ? AND status = ?", course.ID, "active").Error
+	suite.NoError(err)
+	suite.Equal("second@example.com", enrollment.StudentEmail)
+
+	recorder = suite.makeRequest("GET", fmt.Sprintf("/api/v1/courses/%s/waitlist", course.ID), nil, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var waitlist models.WaitlistResponse
+	suite.parseResponse(recorder, &waitlist)
+	suite.Equal(1, waitlist.EnrolledCount)
+	suite.Require().Len(waitlist.Entries, 1)
+	suite.Equal("third@example.com", waitlist.Entries[0].StudentEmail)
+	suite.Equal(1, waitlist.Entries[0].Position)
+}
+
+// TestReorderAndRemoveWaitlist tests the admin waitlist management endpoints
+func (suite *IntegrationTestSuite) TestReorderAndRemoveWaitlist() {
+	course := suite.createTestCourseWithCapacity("Full Course", 0)
+	headers := suite.getAuthHeaders()
+
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		recorder := suite.makeRequest("POST", "/api/v1/enrollments", models.EnrollmentRequest{
+			StudentEmail: email,
+			CourseID:     course.ID,
+		}, headers)
+		suite.Equal(http.StatusAccepted, recorder.Code)
+	}
+
+	url := fmt.Sprintf("/api/v1/courses/%s/waitlist", course.ID)
+
+	// Incomplete order is rejected
+	recorder := suite.makeRequest("PUT", url, models.WaitlistReorderRequest{
+		StudentEmails: []string{"c@example.com", "a@example.com"},
+	}, headers)
+	suite.assertErrorResponse(recorder, http.StatusBadRequest, "exactly once")
+
+	recorder = suite.makeRequest("PUT", url, models.WaitlistReorderRequest{
+		StudentEmails: []string{"c@example.com", "a@example.com", "b@example.com"},
+	}, headers)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var waitlist models.WaitlistResponse
+	suite.parseResponse(recorder, &waitlist)
+	suite.Require().Len(waitlist.Entries, 3)
+	suite.Equal("c@example.com", waitlist.Entries[0].StudentEmail)
+	suite.Equal("b@example.com", waitlist.Entries[2].StudentEmail)
+
+	recorder = suite.makeRequest("DELETE", url+"/c@example.com", nil, headers)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+
+	recorder = suite.makeRequest("DELETE", url+"/c@example.com", nil, headers)
+	suite.assertErrorResponse(recorder, http.StatusNotFound, "not on the waitlist")
+
+	recorder = suite.makeRequest("GET", url, nil, headers)
+	suite.parseResponse(recorder, &waitlist)
+	suite.Require().Len(waitlist.Entries, 2)
+	suite.Equal("a@example.com", waitlist.Entries[0].StudentEmail)
+	suite.Equal(1, waitlist.Entries[0].Position)
+	suite.Equal(2, waitlist.Entries[1].Position)
+}